@@ -0,0 +1,171 @@
+package nominatim
+
+import (
+	"strings"
+)
+
+// Templates of the one-line addresses, by the ISO 3166-1 alpha-2 codes of their countries, with their components
+// between braces. The components left empty are dropped, along with the separators around them.
+const (
+	// templateNumberFirst puts the house number before the street, and the postcode after the state, as in the US.
+	templateNumberFirst = "{house_number} {road}, {locality}, {state} {postcode}"
+
+	// templateNumberFirstNoState puts the house number before the street, and the postcode after the locality, as in
+	// the UK.
+	templateNumberFirstNoState = "{house_number} {road}, {locality} {postcode}"
+
+	// templateNumberFirstPostcodeFirst puts the house number before the street, and the postcode before the
+	// locality, as in France.
+	templateNumberFirstPostcodeFirst = "{house_number} {road}, {postcode} {locality}"
+
+	// templateNumberSeparated puts the house number after the street, separated by a comma, and the postcode before
+	// the locality, as in Spain.
+	templateNumberSeparated = "{road}, {house_number}, {postcode} {locality}"
+
+	// templateNumberAfter puts the house number after the street, and the postcode before the locality, as in most
+	// European countries.
+	templateNumberAfter = "{road} {house_number}, {postcode} {locality}"
+)
+
+// addressTemplates maps the countries to the templates of their one-line addresses, templateNumberAfter by default.
+var addressTemplates = map[string]string{
+	"au": templateNumberFirst,
+	"ca": templateNumberFirst,
+	"us": templateNumberFirst,
+	"gb": templateNumberFirstNoState,
+	"ie": templateNumberFirstNoState,
+	"nz": templateNumberFirstNoState,
+	"fr": templateNumberFirstPostcodeFirst,
+	"lu": templateNumberFirstPostcodeFirst,
+	"ar": templateNumberSeparated,
+	"br": templateNumberSeparated,
+	"es": templateNumberSeparated,
+	"mx": templateNumberSeparated,
+}
+
+// FormatAddress formats the given address as a human-readable single line, shorter than the display names, for UIs,
+// following the conventions of the given country, by its ISO 3166-1 alpha-2 code, as the order of the house number and
+// the street, and the placement of the postcode, or of the country of the address if empty. The locality is the city,
+// the town or the village of the address, and its country ends the line.
+func FormatAddress(addr Address, countryCode string) string {
+	if countryCode == "" {
+		countryCode = addr.CountryCode
+	}
+	template, ok := addressTemplates[strings.ToLower(strings.TrimSpace(countryCode))]
+	if !ok {
+		template = templateNumberAfter
+	}
+	replacer := strings.NewReplacer(
+		"{house_number}", addr.HouseNumber,
+		"{road}", addr.Road,
+		"{locality}", addr.locality(),
+		"{state}", addr.State,
+		"{postcode}", addr.Postcode,
+	)
+	parts := make([]string, 0, 4)
+	for _, part := range append(strings.Split(replacer.Replace(template), ","), addr.Country) {
+		part = strings.Join(strings.Fields(part), " ")
+		if part != "" && !containsFold(parts, part) {
+			parts = append(parts, part)
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// locality returns the city, the town or the village of the address, the first one filled.
+func (a Address) locality() string {
+	for _, locality := range []string{a.City, a.Town, a.Village} {
+		if locality = strings.TrimSpace(locality); locality != "" {
+			return locality
+		}
+	}
+	return ""
+}
+
+// containsFold checks if the given values contain the given one, ignoring their case.
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// addressComponents holds the components compared by CompareAddresses, along with their weights in the similarity.
+var addressComponents = []struct {
+	name   string
+	weight float64
+	value  func(a Address) string
+}{
+	{name: "house_number", weight: 2, value: func(a Address) string { return a.HouseNumber }},
+	{name: "road", weight: 2, value: func(a Address) string { return a.Road }},
+	{name: "neighbourhood", weight: 0.5, value: func(a Address) string { return a.Neighbourhood }},
+	{name: "suburb", weight: 0.5, value: func(a Address) string { return a.Suburb }},
+	{name: "city_district", weight: 0.5, value: func(a Address) string { return a.CityDistrict }},
+	{name: "locality", weight: 2, value: Address.locality},
+	{name: "postcode", weight: 2, value: func(a Address) string { return a.Postcode }},
+	{name: "state", weight: 1, value: func(a Address) string { return a.State }},
+	{name: "country", weight: 1, value: func(a Address) string { return a.Country }},
+	{name: "country_code", weight: 1, value: func(a Address) string { return a.CountryCode }},
+}
+
+// AddressDiff holds a component differing between two addresses.
+type AddressDiff struct {
+
+	// Component is the name of the component, as sent by Nominatim, as "road", except for "locality", which is the
+	// city, the town or the village of the addresses, the first one filled.
+	Component string
+
+	// A and B are the values of the component in each address, either of them empty when missing.
+	A string
+	B string
+
+	// Similarity is the similarity of the values, from 0 to 1, by the words they share, regardless of their case and
+	// diacritics.
+	Similarity float64
+}
+
+// AddressComparison holds the outcome of CompareAddresses.
+type AddressComparison struct {
+
+	// Similarity is the overall similarity of the addresses, from 0 to 1, weighting their components, the street,
+	// the house number, the locality and the postcode above the others.
+	Similarity float64
+
+	// Diffs holds the components differing between the addresses, in the order of the address, from the house
+	// number up to the country.
+	Diffs []AddressDiff
+}
+
+// CompareAddresses compares the given addresses component by component, regardless of their case, diacritics and
+// punctuation, as for validating the addresses entered by users against the geocoded ones, or for deduplicating
+// customer records. The components filled in one address only are reported as differing, weighting half in the
+// similarity, while the ones missing in both are ignored. Empty addresses are similar.
+func CompareAddresses(a, b Address) AddressComparison {
+	comparison := AddressComparison{Diffs: make([]AddressDiff, 0)}
+	total, score := 0.0, 0.0
+	for _, component := range addressComponents {
+		valueA, valueB := strings.TrimSpace(component.value(a)), strings.TrimSpace(component.value(b))
+		if valueA == "" && valueB == "" {
+			continue
+		}
+		if valueA == "" || valueB == "" {
+			total += component.weight / 2
+			comparison.Diffs = append(comparison.Diffs, AddressDiff{Component: component.name, A: valueA, B: valueB})
+			continue
+		}
+		termsA, termsB := matchTerms(valueA), matchTerms(valueB)
+		similar := (similarity(termsA, termsB) + similarity(termsB, termsA)) / 2
+		total += component.weight
+		score += component.weight * similar
+		if similar < 1 {
+			comparison.Diffs = append(comparison.Diffs, AddressDiff{Component: component.name, A: valueA, B: valueB, Similarity: similar})
+		}
+	}
+	comparison.Similarity = 1
+	if total > 0 {
+		comparison.Similarity = score / total
+	}
+	return comparison
+}
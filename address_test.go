@@ -0,0 +1,134 @@
+package nominatim_test
+
+import (
+	"github.com/diegohordi/nominatim"
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestFormatAddress(t *testing.T) {
+	lisbon := nominatim.Address{
+		HouseNumber: "1", Road: "Rua Augusta", Suburb: "Baixa", City: "Lisboa", State: "Lisboa", Postcode: "1100-053",
+		Country: "Portugal", CountryCode: "pt",
+	}
+	tests := []struct {
+		name        string
+		address     nominatim.Address
+		countryCode string
+		want        string
+	}{
+		{
+			name:    "should follow the country of the address",
+			address: lisbon,
+			want:    "Rua Augusta 1, 1100-053 Lisboa, Portugal",
+		},
+		{
+			name:        "should put the house number first and the postcode after the state",
+			address:     nominatim.Address{HouseNumber: "350", Road: "5th Avenue", City: "New York", State: "NY", Postcode: "10118", Country: "United States"},
+			countryCode: "US",
+			want:        "350 5th Avenue, New York, NY 10118, United States",
+		},
+		{
+			name:        "should put the postcode after the locality",
+			address:     nominatim.Address{HouseNumber: "10", Road: "Downing Street", City: "London", Postcode: "SW1A 2AA", Country: "United Kingdom"},
+			countryCode: "gb",
+			want:        "10 Downing Street, London SW1A 2AA, United Kingdom",
+		},
+		{
+			name:        "should separate the house number by a comma",
+			address:     nominatim.Address{HouseNumber: "3", Road: "Calle de Alcalá", City: "Madrid", Postcode: "28014", Country: "España"},
+			countryCode: "es",
+			want:        "Calle de Alcalá, 3, 28014 Madrid, España",
+		},
+		{
+			name:        "should follow the given country over the one of the address",
+			address:     lisbon,
+			countryCode: "fr",
+			want:        "1 Rua Augusta, 1100-053 Lisboa, Portugal",
+		},
+		{
+			name:    "should drop the missing components",
+			address: nominatim.Address{Village: "Monsaraz", Country: "Portugal", CountryCode: "pt"},
+			want:    "Monsaraz, Portugal",
+		},
+		{
+			name:    "should drop the repeated components",
+			address: nominatim.Address{Road: "Rua Augusta", Town: "Lisboa", State: "Lisboa", CountryCode: "us"},
+			want:    "Rua Augusta, Lisboa",
+		},
+		{
+			name: "should be empty for empty addresses",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := nominatim.FormatAddress(tt.address, tt.countryCode); got != tt.want {
+				t.Errorf("FormatAddress() got = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompareAddresses(t *testing.T) {
+	geocoded := nominatim.Address{
+		HouseNumber: "1", Road: "Rua Augusta", Suburb: "Baixa", City: "Lisboa", Postcode: "1100-053", Country: "Portugal",
+		CountryCode: "pt",
+	}
+	tests := []struct {
+		name           string
+		a              nominatim.Address
+		b              nominatim.Address
+		wantSimilarity float64
+		wantDiffs      []nominatim.AddressDiff
+	}{
+		{
+			name:           "should be similar regardless of case, diacritics and punctuation",
+			a:              nominatim.Address{HouseNumber: "1", Road: "RUA AUGUSTA", Town: "lisboa", Postcode: "1100 053"},
+			b:              nominatim.Address{HouseNumber: "1", Road: "Rua Augusta", City: "Lisboa", Postcode: "1100-053"},
+			wantSimilarity: 1,
+			wantDiffs:      []nominatim.AddressDiff{},
+		},
+		{
+			name:           "should report the differing components",
+			a:              geocoded,
+			b:              nominatim.Address{HouseNumber: "10", Road: "Rua Augusta", Suburb: "Baixa", City: "Lisboa", Postcode: "1100-053", Country: "Portugal", CountryCode: "pt"},
+			wantSimilarity: 8.5 / 10.5,
+			wantDiffs:      []nominatim.AddressDiff{{Component: "house_number", A: "1", B: "10"}},
+		},
+		{
+			name:           "should score the partially matching components",
+			a:              nominatim.Address{Road: "Rua Augusta", City: "Lisboa"},
+			b:              nominatim.Address{Road: "Rua Áurea", City: "Lisboa"},
+			wantSimilarity: 0.75,
+			wantDiffs:      []nominatim.AddressDiff{{Component: "road", A: "Rua Augusta", B: "Rua Áurea", Similarity: 0.5}},
+		},
+		{
+			name:           "should weight half the components missing on one side",
+			a:              nominatim.Address{Road: "Rua Augusta", City: "Lisboa"},
+			b:              nominatim.Address{Road: "Rua Augusta", City: "Lisboa", Postcode: "1100-053"},
+			wantSimilarity: 0.8,
+			wantDiffs:      []nominatim.AddressDiff{{Component: "postcode", B: "1100-053"}},
+		},
+		{
+			name:           "should be similar when empty",
+			wantSimilarity: 1,
+			wantDiffs:      []nominatim.AddressDiff{},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got := nominatim.CompareAddresses(tt.a, tt.b)
+			if math.Abs(got.Similarity-tt.wantSimilarity) > 1e-9 {
+				t.Errorf("CompareAddresses() similarity = %v, want %v", got.Similarity, tt.wantSimilarity)
+			}
+			if !reflect.DeepEqual(got.Diffs, tt.wantDiffs) {
+				t.Errorf("CompareAddresses() diffs = %+v, want %+v", got.Diffs, tt.wantDiffs)
+			}
+		})
+	}
+}
@@ -0,0 +1,117 @@
+package nominatim
+
+import (
+	"hash/fnv"
+	"sync"
+	"time"
+)
+
+const (
+	// sketchDepth and sketchWidth are the dimensions of the count-min sketch counting the queries seen.
+	sketchDepth = 4
+	sketchWidth = 1 << 12
+)
+
+// WithCacheAdmission makes the client cache only the responses to the queries seen at least the given number of
+// times within the given window, protecting the cache from being flooded by one-off queries, as unbounded free-form
+// ones, and keeping its hit rate high. The queries are counted through a fixed-size sketch, so the memory used does not
+// grow with them, at the cost of rarely admitting a query seen fewer times.
+func WithCacheAdmission(minSeen int, window time.Duration) Option {
+	return func(d *defaultClient) {
+		d.cacheAdmission = nil
+		if minSeen > 1 && window > 0 {
+			d.cacheAdmission = newCacheAdmission(minSeen, window)
+		}
+	}
+}
+
+// cacheAdmission decides which responses are cached, by how many times their queries were seen within a window,
+// counted through a pair of count-min sketches: the current window and the previous one.
+type cacheAdmission struct {
+	mu       sync.Mutex
+	minSeen  uint32
+	window   time.Duration
+	rotateAt time.Time
+	current  *countMinSketch
+	previous *countMinSketch
+	now      func() time.Time
+}
+
+// newCacheAdmission creates a cacheAdmission for the given number of times and window.
+func newCacheAdmission(minSeen int, window time.Duration) *cacheAdmission {
+	return &cacheAdmission{
+		minSeen:  uint32(minSeen),
+		window:   window,
+		rotateAt: time.Now().Add(window),
+		current:  &countMinSketch{},
+		previous: &countMinSketch{},
+		now:      time.Now,
+	}
+}
+
+// admit counts the given key as seen, checking if it was seen enough times to be cached.
+func (a *cacheAdmission) admit(key string) bool {
+	if a == nil {
+		return true
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if now := a.now(); !now.Before(a.rotateAt) {
+		a.previous, a.current = a.current, a.previous
+		a.current.reset()
+		if now.Sub(a.rotateAt) >= a.window {
+			a.previous.reset()
+		}
+		a.rotateAt = now.Add(a.window)
+	}
+	seen := a.current.add(key) + a.previous.estimate(key)
+	return seen >= a.minSeen
+}
+
+// countMinSketch estimates how many times keys were seen, never underestimating them.
+type countMinSketch struct {
+	counters [sketchDepth][sketchWidth]uint32
+}
+
+// add counts the given key as seen, returning its estimated count.
+func (s *countMinSketch) add(key string) uint32 {
+	least := ^uint32(0)
+	for row, column := range sketchColumns(key) {
+		if s.counters[row][column] < ^uint32(0) {
+			s.counters[row][column]++
+		}
+		if s.counters[row][column] < least {
+			least = s.counters[row][column]
+		}
+	}
+	return least
+}
+
+// estimate returns the estimated count of the given key.
+func (s *countMinSketch) estimate(key string) uint32 {
+	least := ^uint32(0)
+	for row, column := range sketchColumns(key) {
+		if s.counters[row][column] < least {
+			least = s.counters[row][column]
+		}
+	}
+	return least
+}
+
+// reset clears the counts.
+func (s *countMinSketch) reset() {
+	s.counters = [sketchDepth][sketchWidth]uint32{}
+}
+
+// sketchColumns returns the column of the given key on each row of a sketch, through double hashing.
+func sketchColumns(key string) [sketchDepth]uint32 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(key))
+	sum := h.Sum64()
+	h1, h2 := uint32(sum), uint32(sum>>32)|1
+	var columns [sketchDepth]uint32
+	for i := range columns {
+		columns[i] = (h1 + uint32(i)*h2) % sketchWidth
+	}
+	return columns
+}
@@ -0,0 +1,48 @@
+package nominatim_test
+
+import (
+	"context"
+	"github.com/diegohordi/nominatim"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func Test_WithCacheAdmission(t *testing.T) {
+	tests := []struct {
+		name         string
+		window       time.Duration
+		pause        time.Duration
+		wantRequests int32
+	}{
+		{name: "should cache the queries seen twice within the window", window: time.Hour, wantRequests: 2},
+		{name: "should forget the queries seen in older windows", window: 10 * time.Millisecond, pause: 30 * time.Millisecond, wantRequests: 3},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			var requests int32
+			transport := TransportFunc(func(req *http.Request) (*http.Response, error) {
+				atomic.AddInt32(&requests, 1)
+				return statusFixture(http.StatusOK, mustLoadValidSearchResults(t))()
+			})
+			d := nominatim.NewClient("http://localhost:8080", nil, nominatim.WithTransport(transport),
+				nominatim.WithCache(nominatim.NewLRUCache(10), time.Hour), nominatim.WithCacheAdmission(2, tt.window))
+			query := nominatim.NewSearchQuery()
+			query.FreeFormQuery = "lisboa"
+			for i := 0; i < 4; i++ {
+				if _, err := d.Search(context.TODO(), *query); err != nil {
+					t.Fatal(err)
+				}
+				if i == 0 {
+					time.Sleep(tt.pause)
+				}
+			}
+			if got := atomic.LoadInt32(&requests); got != tt.wantRequests {
+				t.Errorf("Search() requests = %d, want %d", got, tt.wantRequests)
+			}
+		})
+	}
+}
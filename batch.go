@@ -0,0 +1,118 @@
+package nominatim
+
+import (
+	"context"
+	"sync"
+)
+
+// defaultBatchWorkers is the number of calls made concurrently by the batch functions, by default.
+const defaultBatchWorkers = 4
+
+// BatchOption configures the batch functions, as SearchMany.
+type BatchOption func(config *batchConfig)
+
+// batchConfig holds the configuration of the batch functions.
+type batchConfig struct {
+	workers     int
+	callOptions []CallOption
+}
+
+// WithWorkers makes the batch functions make up to the given number of calls concurrently, 4 by default. The calls
+// remain subject to the rate limit and to the maximum concurrency of the client, so the workers never exceed them.
+// Non-positive values mean the default.
+func WithWorkers(n int) BatchOption {
+	return func(config *batchConfig) {
+		config.workers = n
+	}
+}
+
+// WithBatchCallOptions makes the batch functions make every call with the given options.
+func WithBatchCallOptions(opts ...CallOption) BatchOption {
+	return func(config *batchConfig) {
+		config.callOptions = append(config.callOptions, opts...)
+	}
+}
+
+// newBatchConfig creates the batch configuration from the given options.
+func newBatchConfig(opts []BatchOption) batchConfig {
+	config := batchConfig{}
+	for _, opt := range opts {
+		opt(&config)
+	}
+	if config.workers <= 0 {
+		config.workers = defaultBatchWorkers
+	}
+	return config
+}
+
+// runBatch calls the given function for every index up to n, through the given number of workers, returning once
+// every call returned. Once the context is done, the remaining indexes are given to skip instead.
+func runBatch(ctx context.Context, n, workers int, call func(i int), skip func(i int)) {
+	if workers > n {
+		workers = n
+	}
+	indexes := make(chan int)
+	wg := sync.WaitGroup{}
+	wg.Add(workers)
+	for w := 0; w < workers; w++ {
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				if ctx.Err() != nil {
+					skip(i)
+					continue
+				}
+				call(i)
+			}
+		}()
+	}
+	for i := 0; i < n; i++ {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+}
+
+// SearchResult holds the outcome of one of the queries of SearchMany.
+type SearchResult struct {
+	Query   SearchQuery
+	Results []Result
+	Err     error
+}
+
+// SearchMany searches the given queries through the given client concurrently, returning their outcomes in the
+// order of the queries. A query failing doesn't stop the others, while the queries not searched yet once the context
+// is done fail with its error.
+func SearchMany(ctx context.Context, client SearchHandler, queries []SearchQuery, opts ...BatchOption) []SearchResult {
+	config := newBatchConfig(opts)
+	outcomes := make([]SearchResult, len(queries))
+	runBatch(ctx, len(queries), config.workers, func(i int) {
+		results, err := client.Search(ctx, queries[i], config.callOptions...)
+		outcomes[i] = SearchResult{Query: queries[i], Results: results, Err: err}
+	}, func(i int) {
+		outcomes[i] = SearchResult{Query: queries[i], Err: ctx.Err()}
+	})
+	return outcomes
+}
+
+// ReverseResult holds the outcome of one of the queries of ReverseMany.
+type ReverseResult struct {
+	Query  ReverseQuery
+	Result Result
+	Err    error
+}
+
+// ReverseMany reverse geocodes the given queries, as a set of GPS points, through the given client concurrently,
+// returning their outcomes in the order of the queries. A query failing doesn't stop the others, while the queries
+// not geocoded yet once the context is done fail with its error.
+func ReverseMany(ctx context.Context, client ReverseHandler, queries []ReverseQuery, opts ...BatchOption) []ReverseResult {
+	config := newBatchConfig(opts)
+	outcomes := make([]ReverseResult, len(queries))
+	runBatch(ctx, len(queries), config.workers, func(i int) {
+		result, err := client.Reverse(ctx, queries[i], config.callOptions...)
+		outcomes[i] = ReverseResult{Query: queries[i], Result: result, Err: err}
+	}, func(i int) {
+		outcomes[i] = ReverseResult{Query: queries[i], Err: ctx.Err()}
+	})
+	return outcomes
+}
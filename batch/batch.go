@@ -0,0 +1,309 @@
+// Package batch implements batch geocoding pipelines built on top of the nominatim package, as geocoding the addresses
+// of a CSV file into an augmented copy of it, holding their coordinates.
+package batch
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/diegohordi/nominatim"
+)
+
+// Columns added to the output CSV by GeocodeCSV.
+const (
+	ColumnLatitude    = "lat"
+	ColumnLongitude   = "lon"
+	ColumnDisplayName = "display_name"
+	ColumnConfidence  = "confidence"
+	ColumnOutcome     = "outcome"
+)
+
+// defaultChunkSize is the number of rows geocoded together, before being written, by default.
+const defaultChunkSize = 100
+
+// errNoAddress is the error of the rows holding no address at all.
+var errNoAddress = fmt.Errorf("%w: no address", nominatim.ErrInvalidQuery)
+
+// Columns maps the columns of the input CSV, by their header names, to the fields of the search queries. Either the
+// free-form Address column or some of the structured ones must be given, and the Address column takes precedence over
+// the others when both are given and filled.
+type Columns struct {
+	Address    string
+	Street     string
+	City       string
+	County     string
+	State      string
+	Country    string
+	PostalCode string
+}
+
+// Option configures GeocodeCSV and Job.Run.
+type Option func(config *config)
+
+// config holds the configuration of GeocodeCSV and Job.Run.
+type config struct {
+	searchOptions  []nominatim.SearchOption
+	batchOptions   []nominatim.BatchOption
+	chunkSize      int
+	comma          rune
+	resumeInterval time.Duration
+}
+
+// WithSearchOptions makes GeocodeCSV create the search queries with the given options, as the languages of the
+// results. Only the best result of each query is kept, so the queries are limited to 1 result, by default.
+func WithSearchOptions(opts ...nominatim.SearchOption) Option {
+	return func(config *config) {
+		config.searchOptions = append(config.searchOptions, opts...)
+	}
+}
+
+// WithBatchOptions makes GeocodeCSV geocode the rows with the given options, as the number of workers.
+func WithBatchOptions(opts ...nominatim.BatchOption) Option {
+	return func(config *config) {
+		config.batchOptions = append(config.batchOptions, opts...)
+	}
+}
+
+// WithChunkSize makes GeocodeCSV geocode the given number of rows together before writing them, 100 by default.
+// Non-positive values mean the default.
+func WithChunkSize(n int) Option {
+	return func(config *config) {
+		config.chunkSize = n
+	}
+}
+
+// WithComma makes GeocodeCSV read and write the CSV with the given field delimiter, instead of a comma.
+func WithComma(comma rune) Option {
+	return func(config *config) {
+		config.comma = comma
+	}
+}
+
+// GeocodeCSV reads the addresses from the CSV given by r, whose first row is its header, geocodes them through the
+// given client and writes to w a copy of the CSV augmented with the lat, lon, display_name, confidence and outcome
+// columns, from the best result of each address. The confidence is the importance of the result, and the outcome is
+// its nominatim.Outcome. The rows failing to geocode are written without coordinates, and summarized along with the
+// others in the returned nominatim.BatchSummary.
+//
+// The rows are geocoded concurrently, in chunks, and written in their order. Rate limiting and retries are up to the
+// given client, which should be configured through nominatim.WithRateLimit and nominatim.WithRetry, as in the public
+// API. An error is returned when the CSV can't be read or written, or the given columns are not in its header.
+func GeocodeCSV(ctx context.Context, client nominatim.SearchHandler, r io.Reader, w io.Writer, columns Columns, opts ...Option) (*nominatim.BatchSummary, error) {
+	config := newConfig(opts)
+	reader, writer := config.reader(r), config.writer(w)
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("batch: reading the header: %w", err)
+	}
+	mapping, err := columns.indexes(header)
+	if err != nil {
+		return nil, err
+	}
+	if err := writer.Write(augmentHeader(header)); err != nil {
+		return nil, fmt.Errorf("batch: writing the header: %w", err)
+	}
+	summary := nominatim.NewBatchSummary()
+	for {
+		rows, readErr := readChunk(reader, config.chunkSize)
+		if len(rows) > 0 {
+			if err := geocodeChunk(ctx, client, writer, rows, mapping, config, summary); err != nil {
+				return summary, err
+			}
+		}
+		if errors.Is(readErr, io.EOF) {
+			return summary, nil
+		}
+		if readErr != nil {
+			return summary, fmt.Errorf("batch: reading the rows: %w", readErr)
+		}
+	}
+}
+
+// newConfig creates the configuration from the given options.
+func newConfig(opts []Option) config {
+	config := config{chunkSize: defaultChunkSize, comma: ',', resumeInterval: defaultResumeInterval}
+	for _, opt := range opts {
+		opt(&config)
+	}
+	if config.chunkSize <= 0 {
+		config.chunkSize = defaultChunkSize
+	}
+	return config
+}
+
+// reader creates a CSV reader of the given input.
+func (c config) reader(r io.Reader) *csv.Reader {
+	reader := csv.NewReader(r)
+	reader.Comma, reader.FieldsPerRecord = c.comma, -1
+	return reader
+}
+
+// writer creates a CSV writer of the given output.
+func (c config) writer(w io.Writer) *csv.Writer {
+	writer := csv.NewWriter(w)
+	writer.Comma = c.comma
+	return writer
+}
+
+// augmentHeader returns the given header augmented with the columns added by GeocodeCSV.
+func augmentHeader(header []string) []string {
+	return append(header, ColumnLatitude, ColumnLongitude, ColumnDisplayName, ColumnConfidence, ColumnOutcome)
+}
+
+// readChunk reads up to the given number of rows, returning the ones read along with the error that stopped it, if
+// any.
+func readChunk(reader *csv.Reader, size int) ([][]string, error) {
+	rows := make([][]string, 0, size)
+	for len(rows) < size {
+		row, err := reader.Read()
+		if err != nil {
+			return rows, err
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// geocodeChunk geocodes the given rows, writing them augmented with their best results, and adding their outcomes to
+// the given summary.
+func geocodeChunk(ctx context.Context, client nominatim.SearchHandler, writer *csv.Writer, rows [][]string, mapping columnIndexes, config config, summary *nominatim.BatchSummary) error {
+	for _, row := range geocodeRows(ctx, client, rows, mapping, config) {
+		if err := writer.Write(row.augment(summary.Add(row.err))); err != nil {
+			return fmt.Errorf("batch: writing the rows: %w", err)
+		}
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return fmt.Errorf("batch: writing the rows: %w", err)
+	}
+	return nil
+}
+
+// geocodedRow is a row of the input CSV along with its best result, or the error failing to geocode it.
+type geocodedRow struct {
+	row  []string
+	best nominatim.Result
+	err  error
+}
+
+// augment returns the row augmented with the columns added by GeocodeCSV, given its outcome.
+func (r geocodedRow) augment(outcome nominatim.Outcome) []string {
+	lat, lon, displayName, confidence := "", "", "", ""
+	if r.err == nil {
+		lat, lon, displayName = r.best.Lat, r.best.Lon, r.best.DisplayName
+		confidence = strconv.FormatFloat(r.best.Importance, 'f', -1, 64)
+	}
+	return append(r.row, lat, lon, displayName, confidence, string(outcome))
+}
+
+// geocodeRows geocodes the given rows concurrently, returning them along with their best results, in their order.
+func geocodeRows(ctx context.Context, client nominatim.SearchHandler, rows [][]string, mapping columnIndexes, config config) []geocodedRow {
+	queries := make([]nominatim.SearchQuery, 0, len(rows))
+	positions := make([]int, len(rows))
+	for i, row := range rows {
+		query, ok := mapping.query(row, config.searchOptions)
+		positions[i] = -1
+		if ok {
+			positions[i] = len(queries)
+			queries = append(queries, query)
+		}
+	}
+	outcomes := nominatim.SearchMany(ctx, client, queries, config.batchOptions...)
+	geocoded := make([]geocodedRow, len(rows))
+	for i, row := range rows {
+		var results []nominatim.Result
+		err := errNoAddress
+		if positions[i] >= 0 {
+			results, err = outcomes[positions[i]].Results, outcomes[positions[i]].Err
+		}
+		if err == nil && len(results) == 0 {
+			err = nominatim.ErrNoResults
+		}
+		geocoded[i] = geocodedRow{row: row, err: err}
+		if err == nil {
+			geocoded[i].best = results[0]
+		}
+	}
+	return geocoded
+}
+
+// columnIndexes holds the indexes of the mapped columns in the header, or -1 for the ones not mapped.
+type columnIndexes struct {
+	address    int
+	street     int
+	city       int
+	county     int
+	state      int
+	country    int
+	postalCode int
+}
+
+// indexes finds the indexes of the mapped columns in the given header.
+func (c Columns) indexes(header []string) (columnIndexes, error) {
+	if c == (Columns{}) {
+		return columnIndexes{}, errors.New("batch: no address columns given")
+	}
+	positions := make(map[string]int, len(header))
+	for i, name := range header {
+		if _, ok := positions[strings.TrimSpace(name)]; !ok {
+			positions[strings.TrimSpace(name)] = i
+		}
+	}
+	var err error
+	index := func(name string) int {
+		if name == "" {
+			return -1
+		}
+		i, ok := positions[name]
+		if !ok {
+			if err == nil {
+				err = fmt.Errorf("batch: column %q not found in the header", name)
+			}
+			return -1
+		}
+		return i
+	}
+	indexes := columnIndexes{
+		address:    index(c.Address),
+		street:     index(c.Street),
+		city:       index(c.City),
+		county:     index(c.County),
+		state:      index(c.State),
+		country:    index(c.Country),
+		postalCode: index(c.PostalCode),
+	}
+	if err != nil {
+		return columnIndexes{}, err
+	}
+	return indexes, nil
+}
+
+// query creates the search query for the given row, if it holds any address.
+func (c columnIndexes) query(row []string, opts []nominatim.SearchOption) (nominatim.SearchQuery, bool) {
+	field := func(i int) string {
+		if i < 0 || i >= len(row) {
+			return ""
+		}
+		return strings.TrimSpace(row[i])
+	}
+	query := nominatim.NewSearchQuery(append([]nominatim.SearchOption{nominatim.WithLimit(1)}, opts...)...)
+	if address := field(c.address); address != "" {
+		query.FreeFormQuery = address
+		return *query, true
+	}
+	query.SearchStructuredQuery = nominatim.SearchStructuredQuery{
+		Street:     field(c.street),
+		City:       field(c.city),
+		County:     field(c.county),
+		State:      field(c.state),
+		Country:    field(c.country),
+		PostalCode: field(c.postalCode),
+	}
+	return *query, query.SearchStructuredQuery != nominatim.SearchStructuredQuery{}
+}
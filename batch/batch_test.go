@@ -0,0 +1,90 @@
+package batch_test
+
+import (
+	"bytes"
+	"context"
+	"github.com/diegohordi/nominatim"
+	"github.com/diegohordi/nominatim/batch"
+	"github.com/diegohordi/nominatim/nominatimtest"
+	"strings"
+	"testing"
+)
+
+func TestGeocodeCSV(t *testing.T) {
+	server := nominatimtest.NewServer()
+	t.Cleanup(server.Close)
+	client := nominatim.NewClient(server.URL, nil)
+	tests := []struct {
+		name        string
+		input       string
+		columns     batch.Columns
+		opts        []batch.Option
+		want        string
+		wantSuccess int
+		wantFailed  int
+		wantErr     bool
+	}{
+		{
+			name:    "should geocode the free-form addresses in their order",
+			input:   "id,address\n1,torre de belém\n2,hospital\n3,avenida dos aliados\n",
+			columns: batch.Columns{Address: "address"},
+			opts:    []batch.Option{batch.WithChunkSize(2), batch.WithBatchOptions(nominatim.WithWorkers(2))},
+			want: "id,address,lat,lon,display_name,confidence,outcome\n" +
+				"1,torre de belém,38.6916,-9.2160,\"Torre de Belém, Belém, Lisboa, 1400-038, Portugal\",0.6,success\n" +
+				"2,hospital,,,,,no-result\n" +
+				"3,avenida dos aliados,41.1486,-8.6110,\"Avenida dos Aliados, Santo Ildefonso, Porto, 4000-064, Portugal\",0.45,success\n",
+			wantSuccess: 2,
+			wantFailed:  1,
+		},
+		{
+			name:    "should geocode the structured addresses with another delimiter",
+			input:   "street;city\nAvenida da República;Lisboa\n;\n",
+			columns: batch.Columns{Street: "street", City: "city"},
+			opts:    []batch.Option{batch.WithComma(';')},
+			want: "street;city;lat;lon;display_name;confidence;outcome\n" +
+				"Avenida da República;Lisboa;38.7429985;-9.1467899;Avenida da República, Avenidas Novas, Lisboa, 1000-078, Portugal;0.4;success\n" +
+				";;;;;;client-error\n",
+			wantSuccess: 1,
+			wantFailed:  1,
+		},
+		{
+			name:    "should fail due to a missing column",
+			input:   "id,address\n1,torre de belém\n",
+			columns: batch.Columns{Address: "street"},
+			wantErr: true,
+		},
+		{
+			name:    "should fail due to no columns",
+			input:   "id,address\n1,torre de belém\n",
+			wantErr: true,
+		},
+		{
+			name:    "should fail due to an empty input",
+			columns: batch.Columns{Address: "address"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			output := &bytes.Buffer{}
+			summary, err := batch.GeocodeCSV(context.TODO(), client, strings.NewReader(tt.input), output, tt.columns, tt.opts...)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GeocodeCSV() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if output.String() != tt.want {
+				t.Errorf("GeocodeCSV() output = %q, want %q", output.String(), tt.want)
+			}
+			if summary.Counts[nominatim.OutcomeSuccess] != tt.wantSuccess {
+				t.Errorf("GeocodeCSV() successes = %v, want %v", summary.Counts[nominatim.OutcomeSuccess], tt.wantSuccess)
+			}
+			if summary.Failed() != tt.wantFailed {
+				t.Errorf("GeocodeCSV() failed = %v, want %v", summary.Failed(), tt.wantFailed)
+			}
+		})
+	}
+}
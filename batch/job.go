@@ -0,0 +1,292 @@
+package batch
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/diegohordi/nominatim"
+)
+
+// defaultResumeInterval is the time waited, by default, between the last request of an interrupted job and the first
+// one of its resumption, as the usage policy of the public API.
+const defaultResumeInterval = time.Second
+
+// checkpointSuffix is appended to the path of the output of a Job to name its checkpoint, by default.
+const checkpointSuffix = ".checkpoint"
+
+// WithResumeInterval makes Job.Run, when resuming an interrupted job, wait until the given time passed since its last
+// checkpoint, 1 second by default, so restarting right away doesn't exceed the rate limit of the upstream instance.
+func WithResumeInterval(d time.Duration) Option {
+	return func(config *config) {
+		config.resumeInterval = d
+	}
+}
+
+// Job geocodes the addresses of a CSV file into an augmented copy of it, as GeocodeCSV does, while checkpointing its
+// progress to disk, so long-running jobs can be interrupted and resumed without geocoding the same rows again.
+type Job struct {
+
+	// Input is the path of the CSV file holding the addresses, whose first row is its header.
+	Input string
+
+	// Output is the path of the augmented CSV file.
+	Output string
+
+	// Checkpoint is the path of the checkpoint file, the path of the output followed by .checkpoint by default.
+	Checkpoint string
+
+	// Columns maps the columns of the input CSV to the fields of the search queries.
+	Columns Columns
+}
+
+// Checkpoint holds the progress of a Job, saved to disk after every chunk of rows geocoded.
+type Checkpoint struct {
+
+	// Input is the path of the input CSV file of the job.
+	Input string `json:"input"`
+
+	// Rows is the number of rows of the input processed so far, not counting its header.
+	Rows int `json:"rows"`
+
+	// OutputSize is the size of the output written so far, up to which the output is kept when resuming.
+	OutputSize int64 `json:"output_size"`
+
+	// Summary summarizes the outcomes of the rows processed so far.
+	Summary *nominatim.BatchSummary `json:"summary"`
+
+	// Failures holds the rows failed to geocode so far, so they can be retried apart.
+	Failures []Failure `json:"failures,omitempty"`
+
+	// Done tells whether the whole input was processed.
+	Done bool `json:"done"`
+
+	// UpdatedAt is when the checkpoint was saved.
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Failure holds a row failed to geocode.
+type Failure struct {
+
+	// Row is the index of the row in the input, from 0 for the first one after the header.
+	Row int `json:"row"`
+
+	// Outcome is the category of the failure.
+	Outcome nominatim.Outcome `json:"outcome"`
+
+	// Error is the message of the error failing the row.
+	Error string `json:"error"`
+}
+
+// ReadCheckpoint reads the checkpoint saved to the given path.
+func ReadCheckpoint(path string) (*Checkpoint, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("batch: reading the checkpoint: %w", err)
+	}
+	checkpoint := &Checkpoint{}
+	if err := json.Unmarshal(data, checkpoint); err != nil {
+		return nil, fmt.Errorf("batch: decoding the checkpoint: %w", err)
+	}
+	if checkpoint.Summary == nil {
+		checkpoint.Summary = nominatim.NewBatchSummary()
+	}
+	return checkpoint, nil
+}
+
+// Run runs the job through the given client, returning its final checkpoint. When a checkpoint of the job is found, the
+// job is resumed from it: the output is truncated to what was checkpointed, dropping any row written afterwards, and
+// the rows already processed are skipped, while a job already done returns right away.
+//
+// Once the context is done, the rows geocoded so far are checkpointed and the job returns the error of the context,
+// wrapped, so it can be resumed later by running it again. Rate limiting and retries are up to the given client, as
+// in GeocodeCSV.
+func (j Job) Run(ctx context.Context, client nominatim.SearchHandler, opts ...Option) (*Checkpoint, error) {
+	config := newConfig(opts)
+	checkpoint, err := ReadCheckpoint(j.checkpointPath())
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		checkpoint = nil
+	case err != nil:
+		return nil, err
+	case checkpoint.Input != j.Input:
+		return nil, fmt.Errorf("batch: the checkpoint belongs to another input, %s", checkpoint.Input)
+	case checkpoint.Done:
+		return checkpoint, nil
+	}
+	input, err := os.Open(j.Input)
+	if err != nil {
+		return nil, fmt.Errorf("batch: opening the input: %w", err)
+	}
+	defer input.Close()
+	reader := config.reader(input)
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("batch: reading the header: %w", err)
+	}
+	mapping, err := j.Columns.indexes(header)
+	if err != nil {
+		return nil, err
+	}
+	var output *os.File
+	if checkpoint == nil {
+		output, checkpoint, err = j.start(header, config)
+	} else {
+		output, err = j.resume(ctx, reader, checkpoint, config)
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer output.Close()
+	writer := config.writer(output)
+	for {
+		rows, readErr := readChunk(reader, config.chunkSize)
+		if len(rows) > 0 {
+			geocoded := geocodeRows(ctx, client, rows, mapping, config)
+			n := completed(ctx, geocoded)
+			for i, row := range geocoded[:n] {
+				outcome := checkpoint.Summary.Add(row.err)
+				if outcome != nominatim.OutcomeSuccess {
+					checkpoint.Failures = append(checkpoint.Failures, Failure{Row: checkpoint.Rows + i, Outcome: outcome, Error: row.err.Error()})
+				}
+				if err := writer.Write(row.augment(outcome)); err != nil {
+					return checkpoint, fmt.Errorf("batch: writing the rows: %w", err)
+				}
+			}
+			checkpoint.Rows += n
+			if err := j.save(output, writer, checkpoint); err != nil {
+				return checkpoint, err
+			}
+			if n < len(geocoded) {
+				return checkpoint, fmt.Errorf("batch: job interrupted after %d rows: %w", checkpoint.Rows, ctx.Err())
+			}
+		}
+		if errors.Is(readErr, io.EOF) {
+			checkpoint.Done = true
+			return checkpoint, j.save(output, writer, checkpoint)
+		}
+		if readErr != nil {
+			return checkpoint, fmt.Errorf("batch: reading the rows: %w", readErr)
+		}
+	}
+}
+
+// checkpointPath returns the path of the checkpoint of the job.
+func (j Job) checkpointPath() string {
+	if j.Checkpoint != "" {
+		return j.Checkpoint
+	}
+	return j.Output + checkpointSuffix
+}
+
+// start creates the output of a job not started yet, holding just its header, and its first checkpoint.
+func (j Job) start(header []string, config config) (*os.File, *Checkpoint, error) {
+	output, err := os.Create(j.Output)
+	if err != nil {
+		return nil, nil, fmt.Errorf("batch: creating the output: %w", err)
+	}
+	checkpoint := &Checkpoint{Input: j.Input, Summary: nominatim.NewBatchSummary()}
+	writer := config.writer(output)
+	if err := writer.Write(augmentHeader(header)); err != nil {
+		output.Close()
+		return nil, nil, fmt.Errorf("batch: writing the header: %w", err)
+	}
+	if err := j.save(output, writer, checkpoint); err != nil {
+		output.Close()
+		return nil, nil, err
+	}
+	return output, checkpoint, nil
+}
+
+// resume opens the output of an interrupted job, truncated to its checkpoint, and skips the rows already processed,
+// once the resume interval passed since the checkpoint.
+func (j Job) resume(ctx context.Context, reader *csv.Reader, checkpoint *Checkpoint, config config) (*os.File, error) {
+	for i := 0; i < checkpoint.Rows; i++ {
+		if _, err := reader.Read(); err != nil {
+			return nil, fmt.Errorf("batch: skipping the %d rows processed: %w", checkpoint.Rows, err)
+		}
+	}
+	if wait := config.resumeInterval - time.Since(checkpoint.UpdatedAt); wait > 0 {
+		timer := time.NewTimer(wait)
+		defer timer.Stop()
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("batch: job interrupted after %d rows: %w", checkpoint.Rows, ctx.Err())
+		case <-timer.C:
+		}
+	}
+	output, err := os.OpenFile(j.Output, os.O_WRONLY, 0)
+	if err != nil {
+		return nil, fmt.Errorf("batch: opening the output: %w", err)
+	}
+	if err := output.Truncate(checkpoint.OutputSize); err != nil {
+		output.Close()
+		return nil, fmt.Errorf("batch: truncating the output: %w", err)
+	}
+	if _, err := output.Seek(checkpoint.OutputSize, io.SeekStart); err != nil {
+		output.Close()
+		return nil, fmt.Errorf("batch: truncating the output: %w", err)
+	}
+	return output, nil
+}
+
+// save flushes the rows written to the output to disk and then saves the checkpoint, replacing the previous one
+// atomically, so a crash leaves either of them in place.
+func (j Job) save(output *os.File, writer *csv.Writer, checkpoint *Checkpoint) error {
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return fmt.Errorf("batch: writing the rows: %w", err)
+	}
+	if err := output.Sync(); err != nil {
+		return fmt.Errorf("batch: syncing the output: %w", err)
+	}
+	size, err := output.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return fmt.Errorf("batch: syncing the output: %w", err)
+	}
+	checkpoint.OutputSize, checkpoint.UpdatedAt = size, time.Now()
+	data, err := json.Marshal(checkpoint)
+	if err != nil {
+		return fmt.Errorf("batch: encoding the checkpoint: %w", err)
+	}
+	path := j.checkpointPath()
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return fmt.Errorf("batch: saving the checkpoint: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	_, err = tmp.Write(data)
+	if err == nil {
+		err = tmp.Sync()
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), path)
+	}
+	if err != nil {
+		return fmt.Errorf("batch: saving the checkpoint: %w", err)
+	}
+	return nil
+}
+
+// completed returns the number of leading rows geocoded before the context was done, so the ones skipped due to it
+// are geocoded again when resuming, instead of being recorded as failures.
+func completed(ctx context.Context, rows []geocodedRow) int {
+	if ctx.Err() == nil {
+		return len(rows)
+	}
+	for i, row := range rows {
+		if nominatim.ClassifyOutcome(row.err) == nominatim.OutcomeCancelled {
+			return i
+		}
+	}
+	return len(rows)
+}
@@ -0,0 +1,141 @@
+package batch_test
+
+import (
+	"context"
+	"errors"
+	"github.com/diegohordi/nominatim"
+	"github.com/diegohordi/nominatim/batch"
+	"github.com/diegohordi/nominatim/nominatimtest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+const (
+	jobInput = "id,address\n1,torre de belém\n2,hospital\n3,avenida dos aliados\n"
+	jobWant  = "id,address,lat,lon,display_name,confidence,outcome\n" +
+		"1,torre de belém,38.6916,-9.2160,\"Torre de Belém, Belém, Lisboa, 1400-038, Portugal\",0.6,success\n" +
+		"2,hospital,,,,,no-result\n" +
+		"3,avenida dos aliados,41.1486,-8.6110,\"Avenida dos Aliados, Santo Ildefonso, Porto, 4000-064, Portugal\",0.45,success\n"
+)
+
+// cancellingClient cancels the context of the job once the given number of searches are made.
+type cancellingClient struct {
+	nominatim.SearchHandler
+	mu     sync.Mutex
+	after  int
+	cancel context.CancelFunc
+}
+
+func (c *cancellingClient) Search(ctx context.Context, query nominatim.SearchQuery, opts ...nominatim.CallOption) ([]nominatim.Result, error) {
+	results, err := c.SearchHandler.Search(ctx, query, opts...)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.after--; c.after == 0 {
+		c.cancel()
+	}
+	return results, err
+}
+
+func newJob(t *testing.T) batch.Job {
+	dir := t.TempDir()
+	input := filepath.Join(dir, "input.csv")
+	if err := os.WriteFile(input, []byte(jobInput), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return batch.Job{Input: input, Output: filepath.Join(dir, "output.csv"), Columns: batch.Columns{Address: "address"}}
+}
+
+func TestJob_Run(t *testing.T) {
+	t.Parallel()
+	server := nominatimtest.NewServer()
+	t.Cleanup(server.Close)
+	job := newJob(t)
+	checkpoint, err := job.Run(context.TODO(), nominatim.NewClient(server.URL, nil))
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if output, _ := os.ReadFile(job.Output); string(output) != jobWant {
+		t.Errorf("Run() output = %q, want %q", output, jobWant)
+	}
+	if !checkpoint.Done || checkpoint.Rows != 3 || checkpoint.Summary.Failed() != 1 {
+		t.Errorf("Run() checkpoint = %+v, want 3 rows done, 1 failed", checkpoint)
+	}
+	failures := checkpoint.Failures
+	if len(failures) != 1 || failures[0].Row != 1 || failures[0].Outcome != nominatim.OutcomeNoResult || !strings.HasSuffix(failures[0].Error, "no results") {
+		t.Errorf("Run() failures = %+v, want row 1 with no results", failures)
+	}
+	saved, err := batch.ReadCheckpoint(job.Output + ".checkpoint")
+	if err != nil {
+		t.Fatalf("ReadCheckpoint() error = %v", err)
+	}
+	if !saved.Done || saved.Summary.Total != 3 {
+		t.Errorf("ReadCheckpoint() checkpoint = %+v, want 3 rows done", saved)
+	}
+	if _, err := job.Run(context.TODO(), nominatim.NewClient(server.URL, nil)); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if server.Requests() != 3 {
+		t.Errorf("Run() requests = %v, want 3, the job being already done", server.Requests())
+	}
+}
+
+func TestJob_Run_resume(t *testing.T) {
+	t.Parallel()
+	server := nominatimtest.NewServer()
+	t.Cleanup(server.Close)
+	job := newJob(t)
+	opts := []batch.Option{batch.WithChunkSize(1), batch.WithBatchOptions(nominatim.WithWorkers(1)), batch.WithResumeInterval(50 * time.Millisecond)}
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+	client := &cancellingClient{SearchHandler: nominatim.NewClient(server.URL, nil), after: 2, cancel: cancel}
+	checkpoint, err := job.Run(ctx, client, opts...)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("Run() error = %v, want %v", err, context.Canceled)
+	}
+	if checkpoint.Done || checkpoint.Rows != 2 {
+		t.Errorf("Run() checkpoint = %+v, want 2 rows not done", checkpoint)
+	}
+	output, err := os.OpenFile(job.Output, os.O_APPEND|os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := output.WriteString("3,avenida dos"); err != nil {
+		t.Fatal(err)
+	}
+	output.Close()
+	start := time.Now()
+	checkpoint, err = job.Run(context.TODO(), nominatim.NewClient(server.URL, nil), opts...)
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 40*time.Millisecond {
+		t.Errorf("Run() resumed after %v, want the resume interval", elapsed)
+	}
+	if data, _ := os.ReadFile(job.Output); string(data) != jobWant {
+		t.Errorf("Run() output = %q, want %q", data, jobWant)
+	}
+	if !checkpoint.Done || checkpoint.Summary.Total != 3 || len(checkpoint.Failures) != 1 {
+		t.Errorf("Run() checkpoint = %+v, want 3 rows done, 1 failed", checkpoint)
+	}
+	if server.Requests() != 3 {
+		t.Errorf("Run() requests = %v, want 3, no row geocoded twice", server.Requests())
+	}
+}
+
+func TestJob_Run_anotherInput(t *testing.T) {
+	t.Parallel()
+	server := nominatimtest.NewServer()
+	t.Cleanup(server.Close)
+	job := newJob(t)
+	if _, err := job.Run(context.TODO(), nominatim.NewClient(server.URL, nil)); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	job.Input = filepath.Join(filepath.Dir(job.Input), "another.csv")
+	if _, err := job.Run(context.TODO(), nominatim.NewClient(server.URL, nil)); err == nil {
+		t.Errorf("Run() error = %v, wantErr true", err)
+	}
+}
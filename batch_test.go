@@ -0,0 +1,169 @@
+package nominatim_test
+
+import (
+	"context"
+	"errors"
+	"github.com/diegohordi/nominatim"
+	"github.com/diegohordi/nominatim/nominatimtest"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func Test_SearchMany(t *testing.T) {
+	server := nominatimtest.NewServer()
+	t.Cleanup(server.Close)
+	client := nominatim.NewClient(server.URL, nil)
+	cancelled, cancel := context.WithCancel(context.TODO())
+	cancel()
+	tests := []struct {
+		name      string
+		ctx       context.Context
+		queries   []string
+		opts      []nominatim.BatchOption
+		wantNames []string
+		wantErrs  []error
+	}{
+		{
+			name:      "should return the outcomes in the order of the queries",
+			ctx:       context.TODO(),
+			queries:   []string{"torre de belém", "hospital", "avenida dos aliados", "praça do comércio"},
+			opts:      []nominatim.BatchOption{nominatim.WithWorkers(2)},
+			wantNames: []string{"Torre de Belém", "", "Avenida dos Aliados", "Praça do Comércio"},
+			wantErrs:  []error{nil, nominatim.ErrNoResults, nil, nil},
+		},
+		{
+			name:      "should apply the call options to every call",
+			ctx:       context.TODO(),
+			queries:   []string{"torre de belém", "praça do comércio"},
+			opts:      []nominatim.BatchOption{nominatim.WithBatchCallOptions(nominatim.WithParam("dedupe", "0"))},
+			wantNames: []string{"Torre de Belém", "Praça do Comércio"},
+			wantErrs:  []error{nil, nil},
+		},
+		{
+			name:      "should fail the queries once the context is done",
+			ctx:       cancelled,
+			queries:   []string{"torre de belém", "praça do comércio"},
+			wantNames: []string{"", ""},
+			wantErrs:  []error{context.Canceled, context.Canceled},
+		},
+		{
+			name: "should accept no queries",
+			ctx:  context.TODO(),
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			queries := make([]nominatim.SearchQuery, 0, len(tt.queries))
+			for _, q := range tt.queries {
+				queries = append(queries, *nominatim.NewSearchQuery(nominatim.WithFreeForm(q)))
+			}
+			outcomes := nominatim.SearchMany(tt.ctx, client, queries, tt.opts...)
+			if len(outcomes) != len(queries) {
+				t.Fatalf("SearchMany() outcomes = %v, want %v", len(outcomes), len(queries))
+			}
+			for i, outcome := range outcomes {
+				if outcome.Query.FreeFormQuery != tt.queries[i] {
+					t.Errorf("SearchMany() query = %v, want %v", outcome.Query.FreeFormQuery, tt.queries[i])
+				}
+				if !errors.Is(outcome.Err, tt.wantErrs[i]) {
+					t.Errorf("SearchMany() error = %v, want %v", outcome.Err, tt.wantErrs[i])
+				}
+				name := ""
+				if len(outcome.Results) > 0 {
+					name = outcome.Results[0].Name
+				}
+				if name != tt.wantNames[i] {
+					t.Errorf("SearchMany() name = %v, want %v", name, tt.wantNames[i])
+				}
+			}
+		})
+	}
+}
+
+func Test_SearchMany_Workers(t *testing.T) {
+	var inFlight, maxInFlight int32
+	transport := TransportFunc(func(req *http.Request) (*http.Response, error) {
+		current := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+		for {
+			max := atomic.LoadInt32(&maxInFlight)
+			if current <= max || atomic.CompareAndSwapInt32(&maxInFlight, max, current) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		return statusFixture(http.StatusOK, mustLoadValidSearchResults(t))()
+	})
+	client := nominatim.NewClient("http://localhost:8080", nil, nominatim.WithTransport(transport))
+	queries := make([]nominatim.SearchQuery, 12)
+	for i := range queries {
+		queries[i] = *nominatim.NewSearchQuery(nominatim.WithFreeForm("lisboa"))
+	}
+	for _, outcome := range nominatim.SearchMany(context.TODO(), client, queries, nominatim.WithWorkers(3)) {
+		if outcome.Err != nil {
+			t.Errorf("SearchMany() error = %v", outcome.Err)
+		}
+	}
+	if max := atomic.LoadInt32(&maxInFlight); max > 3 || max < 2 {
+		t.Errorf("SearchMany() max in flight = %v, want up to 3", max)
+	}
+}
+
+func Test_ReverseMany(t *testing.T) {
+	server := nominatimtest.NewServer()
+	t.Cleanup(server.Close)
+	client := nominatim.NewClient(server.URL, nil)
+	cancelled, cancel := context.WithCancel(context.TODO())
+	cancel()
+	tests := []struct {
+		name      string
+		ctx       context.Context
+		points    [][2]string
+		wantNames []string
+		wantErrs  []error
+	}{
+		{
+			name:      "should return the outcomes in the order of the points",
+			ctx:       context.TODO(),
+			points:    [][2]string{{"41.1487", "-8.6111"}, {"38.6917", "-9.2161"}, {"test", "testing"}, {"38.7076", "-9.1365"}},
+			wantNames: []string{"Avenida dos Aliados", "Torre de Belém", "", "Praça do Comércio"},
+			wantErrs:  []error{nil, nil, nominatim.ErrInvalidQuery, nil},
+		},
+		{
+			name:      "should fail the points once the context is done",
+			ctx:       cancelled,
+			points:    [][2]string{{"41.1487", "-8.6111"}, {"38.6917", "-9.2161"}},
+			wantNames: []string{"", ""},
+			wantErrs:  []error{context.Canceled, context.Canceled},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			queries := make([]nominatim.ReverseQuery, 0, len(tt.points))
+			for _, point := range tt.points {
+				queries = append(queries, *nominatim.NewReverseQuery(point[0], point[1]))
+			}
+			outcomes := nominatim.ReverseMany(tt.ctx, client, queries, nominatim.WithWorkers(2))
+			if len(outcomes) != len(queries) {
+				t.Fatalf("ReverseMany() outcomes = %v, want %v", len(outcomes), len(queries))
+			}
+			for i, outcome := range outcomes {
+				if outcome.Query.Latitude != tt.points[i][0] {
+					t.Errorf("ReverseMany() query = %v, want %v", outcome.Query.Latitude, tt.points[i][0])
+				}
+				if !errors.Is(outcome.Err, tt.wantErrs[i]) {
+					t.Errorf("ReverseMany() error = %v, want %v", outcome.Err, tt.wantErrs[i])
+				}
+				if outcome.Result.Name != tt.wantNames[i] {
+					t.Errorf("ReverseMany() name = %v, want %v", outcome.Result.Name, tt.wantNames[i])
+				}
+			}
+		})
+	}
+}
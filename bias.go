@@ -0,0 +1,49 @@
+package nominatim
+
+import (
+	"math"
+)
+
+// defaultBiasRadius is the radius, in meters, of the viewbox used to bias the results when none is given.
+const defaultBiasRadius = 10000.0
+
+// LocationBias holds the user location used to bias the search results towards it, as needed by autocomplete. The
+// location is snapped to a coarse grid, sized after the radius, before building the viewbox, so nearby users send the
+// very same query and share the cached responses, while the results remain locally relevant.
+type LocationBias struct {
+	Latitude  float64
+	Longitude float64
+
+	// Radius is the radius, in meters, of the area the results are biased to. Defaults to 10km.
+	Radius float64
+}
+
+// bucket snaps the location to the center of its grid cell, whose size is half the radius. The longitude step is
+// derived from the snapped latitude, so every location in the cell gets the same center.
+func (b LocationBias) bucket() (float64, float64) {
+	snap := func(value, step float64) float64 {
+		return math.Floor(value/step)*step + step/2
+	}
+	latStep := b.radius() / (2 * metersPerDegree)
+	latitude := math.Max(math.Min(snap(b.Latitude, latStep), 90), -90)
+	lonStep := latStep
+	if cos := math.Cos(latitude * math.Pi / 180); cos > 0.01 {
+		lonStep = latStep / cos
+	}
+	longitude := math.Max(math.Min(snap(b.Longitude, lonStep), 180), -180)
+	return latitude, longitude
+}
+
+// radius returns the bias radius, or its default.
+func (b LocationBias) radius() float64 {
+	if b.Radius <= 0 {
+		return defaultBiasRadius
+	}
+	return b.Radius
+}
+
+// viewbox builds the viewbox around the bucket of the location.
+func (b LocationBias) viewbox() string {
+	latitude, longitude := b.bucket()
+	return viewboxAround(latitude, longitude, b.radius())
+}
@@ -0,0 +1,58 @@
+package nominatim_test
+
+import (
+	"github.com/diegohordi/nominatim"
+	"testing"
+)
+
+func Test_LocationBias(t *testing.T) {
+	viewbox := func(query *nominatim.SearchQuery) string {
+		return query.Encode().Get("viewbox")
+	}
+	tests := []struct {
+		name      string
+		a         *nominatim.SearchQuery
+		b         *nominatim.SearchQuery
+		wantEqual bool
+	}{
+		{
+			name:      "should share the viewbox between nearby users",
+			a:         nominatim.NewAutocompleteQuery("pharmacy", nominatim.LocationBias{Latitude: 38.72231, Longitude: -9.13931}),
+			b:         nominatim.NewAutocompleteQuery("pharmacy", nominatim.LocationBias{Latitude: 38.72289, Longitude: -9.13987}),
+			wantEqual: true,
+		},
+		{
+			name:      "should not share the viewbox between distant users",
+			a:         nominatim.NewAutocompleteQuery("pharmacy", nominatim.LocationBias{Latitude: 38.72231, Longitude: -9.13931}),
+			b:         nominatim.NewAutocompleteQuery("pharmacy", nominatim.LocationBias{Latitude: 41.14961, Longitude: -8.61099}),
+			wantEqual: false,
+		},
+		{
+			name:      "should use smaller buckets for smaller radius",
+			a:         nominatim.NewAutocompleteQuery("pharmacy", nominatim.LocationBias{Latitude: 38.72231, Longitude: -9.13931, Radius: 100}),
+			b:         nominatim.NewAutocompleteQuery("pharmacy", nominatim.LocationBias{Latitude: 38.72289, Longitude: -9.13987, Radius: 100}),
+			wantEqual: false,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			a, b := viewbox(tt.a), viewbox(tt.b)
+			if a == "" || b == "" {
+				t.Fatalf("Encode() got no viewbox")
+			}
+			if (a == b) != tt.wantEqual {
+				t.Errorf("Encode() got viewboxes %v and %v, want equal %v", a, b, tt.wantEqual)
+			}
+		})
+	}
+}
+
+func Test_LocationBias_ExplicitViewbox(t *testing.T) {
+	query := nominatim.NewAutocompleteQuery("pharmacy", nominatim.LocationBias{Latitude: 38.72231, Longitude: -9.13931})
+	query.Viewbox = "-9.2,38.7,-9.1,38.8"
+	if got := query.Encode().Get("viewbox"); got != query.Viewbox {
+		t.Errorf("Encode() got = %v, want %v", got, query.Viewbox)
+	}
+}
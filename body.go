@@ -0,0 +1,64 @@
+package nominatim
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// maxDrainSize is the number of bytes drained from the unread remainder of a response body before closing it, so its
+// connection can be reused. Larger remainders are cheaper to give up along with their connection.
+const maxDrainSize = 256 << 10
+
+// WithMaxResponseSize makes the client fail the responses whose bodies are larger than the given number of bytes
+// with ErrResponseTooLarge, so a misbehaving server or proxy can't exhaust the memory of the application. Zero, the
+// default, means no limit.
+func WithMaxResponseSize(bytes int64) Option {
+	return func(d *defaultClient) {
+		d.maxResponseSize = bytes
+	}
+}
+
+// readBody reads the body of the given response, decompressing it, if compressed, up to the maximum response size of
+// the client, if any.
+func (d defaultClient) readBody(resp *http.Response) ([]byte, error) {
+	compressed := isCompressed(resp)
+	if d.maxResponseSize > 0 && resp.ContentLength > d.maxResponseSize && !compressed {
+		return nil, d.responseTooLarge()
+	}
+	reader := io.Reader(resp.Body)
+	if compressed {
+		gzipReader, err := gzip.NewReader(resp.Body)
+		if err != nil {
+			return nil, err
+		}
+		defer func(reader io.ReadCloser) {
+			_ = reader.Close()
+		}(gzipReader)
+		reader = gzipReader
+	}
+	if d.maxResponseSize <= 0 {
+		return io.ReadAll(reader)
+	}
+	body, err := io.ReadAll(io.LimitReader(reader, d.maxResponseSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(body)) > d.maxResponseSize {
+		return nil, d.responseTooLarge()
+	}
+	return body, nil
+}
+
+// responseTooLarge returns the error failing the responses larger than the maximum response size of the client.
+func (d defaultClient) responseTooLarge() error {
+	return fmt.Errorf("%w, over %d bytes", ErrResponseTooLarge, d.maxResponseSize)
+}
+
+// closeBody drains the unread remainder of the given response body, up to maxDrainSize, and closes it, so the
+// connection it was read from can be reused by the transport.
+func closeBody(body io.ReadCloser) {
+	_, _ = io.CopyN(io.Discard, body, maxDrainSize)
+	_ = body.Close()
+}
@@ -0,0 +1,202 @@
+package nominatim_test
+
+import (
+	"context"
+	"errors"
+	"github.com/diegohordi/nominatim"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func Test_WithMaxResponseSize(t *testing.T) {
+	body := mustLoadValidStatus(t)
+	tests := []struct {
+		name          string
+		opts          []nominatim.Option
+		contentLength bool
+		wantErr       error
+	}{
+		{
+			name: "should read the whole body by default",
+		},
+		{
+			name: "should read the bodies within the limit",
+			opts: []nominatim.Option{nominatim.WithMaxResponseSize(int64(len(body)))},
+		},
+		{
+			name:    "should fail the bodies over the limit",
+			opts:    []nominatim.Option{nominatim.WithMaxResponseSize(int64(len(body)) - 1)},
+			wantErr: nominatim.ErrResponseTooLarge,
+		},
+		{
+			name:          "should fail the bodies over the limit by their length",
+			opts:          []nominatim.Option{nominatim.WithMaxResponseSize(int64(len(body)) - 1)},
+			contentLength: true,
+			wantErr:       nominatim.ErrResponseTooLarge,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			transport := TransportFunc(func(req *http.Request) (*http.Response, error) {
+				resp := httptest.NewRecorder()
+				if tt.contentLength {
+					resp.Header().Set("Content-Length", strconv.Itoa(len(body)))
+				}
+				resp.Body.Write(body)
+				return resp.Result(), nil
+			})
+			d := nominatim.NewClient("http://localhost:8080", nil, append(tt.opts, nominatim.WithTransport(transport))...)
+			got, err := d.CheckStatus(context.TODO())
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("CheckStatus() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				if outcome := nominatim.ClassifyOutcome(err); outcome != nominatim.OutcomeDataError {
+					t.Errorf("CheckStatus() outcome = %v, want %v", outcome, nominatim.OutcomeDataError)
+				}
+				return
+			}
+			if got.Message != "OK" {
+				t.Errorf("CheckStatus() got = %+v, want the status decoded", got)
+			}
+		})
+	}
+}
+
+// trackedBody records whether the response body was read to its end and closed.
+type trackedBody struct {
+	io.Reader
+	drained bool
+	closed  bool
+}
+
+func (b *trackedBody) Read(p []byte) (int, error) {
+	n, err := b.Reader.Read(p)
+	if err == io.EOF {
+		b.drained = true
+	}
+	return n, err
+}
+
+func (b *trackedBody) Close() error {
+	b.closed = true
+	return nil
+}
+
+func Test_ResponseBodies(t *testing.T) {
+	tests := []struct {
+		name       string
+		statusCode int
+		body       string
+		opts       []nominatim.Option
+	}{
+		{
+			name:       "should drain and close the bodies decoded",
+			statusCode: http.StatusOK,
+			body:       string(mustLoadValidStatus(t)),
+		},
+		{
+			name:       "should drain and close the bodies that can't be decoded",
+			statusCode: http.StatusOK,
+			body:       `{"status": "invalid"}`,
+		},
+		{
+			name:       "should drain and close the bodies of the errors sent by the server",
+			statusCode: http.StatusBadGateway,
+			body:       "<html></html>",
+		},
+		{
+			name:       "should drain and close the bodies too large",
+			statusCode: http.StatusOK,
+			body:       string(mustLoadValidStatus(t)),
+			opts:       []nominatim.Option{nominatim.WithMaxResponseSize(10)},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			body := &trackedBody{Reader: strings.NewReader(tt.body)}
+			transport := TransportFunc(func(req *http.Request) (*http.Response, error) {
+				return &http.Response{StatusCode: tt.statusCode, Header: http.Header{}, Body: body, ContentLength: int64(len(tt.body))}, nil
+			})
+			d := nominatim.NewClient("http://localhost:8080", nil, append(tt.opts, nominatim.WithTransport(transport))...)
+			_, _ = d.CheckStatus(context.TODO())
+			if !body.drained || !body.closed {
+				t.Errorf("CheckStatus() body drained = %v, closed = %v, want both", body.drained, body.closed)
+			}
+		})
+	}
+}
+
+func Test_ConnectionReuse(t *testing.T) {
+	tests := []struct {
+		name       string
+		statusCode int
+		body       string
+		opts       []nominatim.Option
+		wantErr    bool
+	}{
+		{
+			name:       "should reuse the connections of the responses decoded",
+			statusCode: http.StatusOK,
+			body:       string(mustLoadValidStatus(t)),
+		},
+		{
+			name:       "should reuse the connections of the responses that can't be decoded",
+			statusCode: http.StatusOK,
+			body:       `{"status": "invalid"}` + strings.Repeat(" ", 64<<10),
+			wantErr:    true,
+		},
+		{
+			name:       "should reuse the connections of the errors sent by the server",
+			statusCode: http.StatusBadGateway,
+			body:       strings.Repeat("<html></html>", 8<<10),
+			wantErr:    true,
+		},
+		{
+			name:       "should reuse the connections of the responses too large",
+			statusCode: http.StatusOK,
+			body:       string(mustLoadValidStatus(t)) + strings.Repeat(" ", 64<<10),
+			opts:       []nominatim.Option{nominatim.WithMaxResponseSize(1 << 10)},
+			wantErr:    true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			connections := int32(0)
+			server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.statusCode)
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			server.Config.ConnState = func(conn net.Conn, state http.ConnState) {
+				if state == http.StateNew {
+					atomic.AddInt32(&connections, 1)
+				}
+			}
+			server.Start()
+			t.Cleanup(server.Close)
+			httpClient := &http.Client{Transport: &http.Transport{}}
+			t.Cleanup(httpClient.CloseIdleConnections)
+			d := nominatim.NewClient(server.URL, httpClient, tt.opts...)
+			for i := 0; i < 5; i++ {
+				if _, err := d.CheckStatus(context.TODO()); (err != nil) != tt.wantErr {
+					t.Fatalf("CheckStatus() error = %v, wantErr %v", err, tt.wantErr)
+				}
+			}
+			if got := atomic.LoadInt32(&connections); got != 1 {
+				t.Errorf("CheckStatus() opened %d connections, want 1", got)
+			}
+		})
+	}
+}
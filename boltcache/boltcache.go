@@ -0,0 +1,154 @@
+// Package boltcache implements nominatim.Cache on top of bbolt, persisting the responses from Nominatim API on disk,
+// so long-running batch jobs survive restarts without geocoding everything again.
+package boltcache
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/diegohordi/nominatim"
+	bolt "go.etcd.io/bbolt"
+)
+
+// SchemaVersion is the version of the layout of the cache file. Files written with a different version are reset
+// when opened, as the cached responses can always be fetched again.
+const SchemaVersion = 1
+
+var (
+	metaBucket    = []byte("meta")
+	entriesBucket = []byte("entries")
+	versionKey    = []byte("version")
+)
+
+// expiresAtSize is the size of the expiration timestamp prefixing the values.
+const expiresAtSize = 8
+
+// ErrSchemaVersion is returned when the cache file was written by a newer, unknown, schema version.
+var ErrSchemaVersion = errors.New("boltcache: invalid schema version")
+
+// Cache is a nominatim.Cache persisted on disk through bbolt.
+type Cache struct {
+	db  *bolt.DB
+	now func() time.Time
+}
+
+var _ nominatim.Cache = (*Cache)(nil)
+
+// Open opens the cache stored in the given file, creating it if needed.
+func Open(path string) (*Cache, error) {
+	db, err := bolt.Open(path, 0o600, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, err
+	}
+	if err := migrate(db); err != nil {
+		_ = db.Close()
+		return nil, err
+	}
+	return &Cache{db: db, now: time.Now}, nil
+}
+
+// migrate makes sure the file holds the buckets of the current schema version, resetting the cached entries of any
+// previous version.
+func migrate(db *bolt.DB) error {
+	return db.Update(func(tx *bolt.Tx) error {
+		meta, err := tx.CreateBucketIfNotExists(metaBucket)
+		if err != nil {
+			return err
+		}
+		if current := meta.Get(versionKey); current != nil {
+			if len(current) != 8 || binary.BigEndian.Uint64(current) > SchemaVersion {
+				return fmt.Errorf("%w: %x", ErrSchemaVersion, current)
+			}
+			if binary.BigEndian.Uint64(current) < SchemaVersion && tx.Bucket(entriesBucket) != nil {
+				if err := tx.DeleteBucket(entriesBucket); err != nil {
+					return err
+				}
+			}
+		}
+		if _, err := tx.CreateBucketIfNotExists(entriesBucket); err != nil {
+			return err
+		}
+		version := make([]byte, 8)
+		binary.BigEndian.PutUint64(version, SchemaVersion)
+		return meta.Put(versionKey, version)
+	})
+}
+
+// Close closes the cache file.
+func (c *Cache) Close() error {
+	return c.db.Close()
+}
+
+// Get retrieves the value cached under the given key, if present and not expired.
+func (c *Cache) Get(_ context.Context, key string) ([]byte, bool, error) {
+	var value []byte
+	err := c.db.View(func(tx *bolt.Tx) error {
+		entry := tx.Bucket(entriesBucket).Get([]byte(key))
+		if len(entry) < expiresAtSize || c.expired(entry) {
+			return nil
+		}
+		value = append([]byte{}, entry[expiresAtSize:]...)
+		return nil
+	})
+	if err != nil || value == nil {
+		return nil, false, err
+	}
+	return value, true, nil
+}
+
+// Set caches the given value under the given key for the given TTL.
+func (c *Cache) Set(_ context.Context, key string, value []byte, ttl time.Duration) error {
+	if ttl <= 0 {
+		return nil
+	}
+	entry := make([]byte, expiresAtSize, expiresAtSize+len(value))
+	binary.BigEndian.PutUint64(entry, uint64(c.now().Add(ttl).UnixNano()))
+	entry = append(entry, value...)
+	return c.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(entriesBucket).Put([]byte(key), entry)
+	})
+}
+
+// Delete removes the value cached under the given key, if any.
+func (c *Cache) Delete(_ context.Context, key string) error {
+	return c.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(entriesBucket).Delete([]byte(key))
+	})
+}
+
+// Purge removes the expired entries, returning how many were removed. bbolt reuses the freed pages, so running it
+// periodically keeps the file from growing with responses that will never be served again.
+func (c *Cache) Purge(ctx context.Context) (int, error) {
+	removed := 0
+	err := c.db.Update(func(tx *bolt.Tx) error {
+		cursor := tx.Bucket(entriesBucket).Cursor()
+		for key, entry := cursor.First(); key != nil; {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			if len(entry) >= expiresAtSize && !c.expired(entry) {
+				key, entry = cursor.Next()
+				continue
+			}
+			if err := cursor.Delete(); err != nil {
+				return err
+			}
+			removed++
+			key, entry = cursor.Seek(key)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return removed, nil
+}
+
+// expired checks if the given entry is expired.
+func (c *Cache) expired(entry []byte) bool {
+	expiresAt := time.Unix(0, int64(binary.BigEndian.Uint64(entry[:expiresAtSize])))
+	return !expiresAt.After(c.now())
+}
@@ -0,0 +1,105 @@
+package boltcache_test
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/diegohordi/nominatim/boltcache"
+	bolt "go.etcd.io/bbolt"
+)
+
+func mustOpen(t *testing.T, path string) *boltcache.Cache {
+	t.Helper()
+	cache, err := boltcache.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cache
+}
+
+func mustWriteVersion(t *testing.T, path string, version uint64) {
+	t.Helper()
+	db, err := bolt.Open(path, 0o600, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	err = db.Update(func(tx *bolt.Tx) error {
+		meta, err := tx.CreateBucketIfNotExists([]byte("meta"))
+		if err != nil {
+			return err
+		}
+		value := make([]byte, 8)
+		binary.BigEndian.PutUint64(value, version)
+		return meta.Put([]byte("version"), value)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func Test_Cache(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache.db")
+	cache := mustOpen(t, path)
+	ctx := context.TODO()
+
+	if _, ok, err := cache.Get(ctx, "missing"); ok || err != nil {
+		t.Errorf("Get() got = %v, %v, want a miss", ok, err)
+	}
+	if err := cache.Set(ctx, "key", []byte("value"), time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	if err := cache.Set(ctx, "short", []byte("value"), time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(5 * time.Millisecond)
+	if _, ok, _ := cache.Get(ctx, "short"); ok {
+		t.Errorf("Get() got a hit, want it expired")
+	}
+	if err := cache.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	cache = mustOpen(t, path)
+	defer cache.Close()
+	if value, ok, err := cache.Get(ctx, "key"); !ok || err != nil || string(value) != "value" {
+		t.Errorf("Get() got = %s, %v, %v, want value persisted across restarts", value, ok, err)
+	}
+	if removed, err := cache.Purge(ctx); removed != 1 || err != nil {
+		t.Errorf("Purge() got = %v, %v, want 1 expired entry removed", removed, err)
+	}
+	if err := cache.Delete(ctx, "key"); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok, _ := cache.Get(ctx, "key"); ok {
+		t.Errorf("Get() got a hit, want it deleted")
+	}
+}
+
+func Test_Open_SchemaVersion(t *testing.T) {
+	t.Run("should reset the entries of an older schema version", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "cache.db")
+		cache := mustOpen(t, path)
+		if err := cache.Set(context.TODO(), "key", []byte("value"), time.Hour); err != nil {
+			t.Fatal(err)
+		}
+		cache.Close()
+		mustWriteVersion(t, path, 0)
+		cache = mustOpen(t, path)
+		defer cache.Close()
+		if _, ok, _ := cache.Get(context.TODO(), "key"); ok {
+			t.Errorf("Get() got a hit, want the entries reset")
+		}
+	})
+	t.Run("should refuse a newer schema version", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "cache.db")
+		mustWriteVersion(t, path, boltcache.SchemaVersion+1)
+		if _, err := boltcache.Open(path); !errors.Is(err, boltcache.ErrSchemaVersion) {
+			t.Errorf("Open() error = %v, want %v", err, boltcache.ErrSchemaVersion)
+		}
+	})
+}
@@ -0,0 +1,354 @@
+package nominatim
+
+import (
+	"bytes"
+	"container/list"
+	"context"
+	"encoding/binary"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// Cache caches the responses from Nominatim API, keyed accordingly with the CachePolicy. Implementations must be safe
+// for concurrent use. Failing to get a value is handled as a miss, while failing to set or delete it is ignored, so a
+// failing cache never fails the requests.
+type Cache interface {
+
+	// Get retrieves the value cached under the given key, if present and not expired.
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+
+	// Set caches the given value under the given key for the given TTL.
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+
+	// Delete removes the value cached under the given key, if any.
+	Delete(ctx context.Context, key string) error
+}
+
+// CacheStats holds the counters of a cache. The sizes are approximate, accounting for the keys, the values and a fixed
+// overhead per entry.
+type CacheStats struct {
+	Hits         uint64
+	Misses       uint64
+	Evictions    uint64
+	EvictedBytes uint64
+	Entries      int
+	Bytes        int64
+}
+
+// lruEntryOverhead is the approximate size of the bookkeeping of each entry of a LRUCache, besides its key and value.
+const lruEntryOverhead = 96
+
+// LRUCache is an in-memory cache of responses, evicting the least recently used entries when full, either by the
+// number of entries or by their approximate size in bytes. It is safe for concurrent use.
+type LRUCache struct {
+	mu       sync.Mutex
+	size     int
+	maxBytes int64
+	bytes    int64
+	entries  map[string]*list.Element
+	order    *list.List
+	stats    CacheStats
+	now      func() time.Time
+}
+
+// lruEntry holds a cached response.
+type lruEntry struct {
+	key       string
+	value     []byte
+	expiresAt time.Time
+}
+
+// NewLRUCache creates a LRUCache holding up to the given number of entries.
+func NewLRUCache(size int) *LRUCache {
+	if size <= 0 {
+		size = 1
+	}
+	return &LRUCache{size: size, entries: make(map[string]*list.Element), order: list.New(), now: time.Now}
+}
+
+// NewLRUCacheWithMaxBytes creates a LRUCache holding up to the given number of entries, if positive, and up to the
+// given approximate size in bytes, so it can be safely enabled in memory-constrained containers. The values larger
+// than the whole cache are not cached.
+func NewLRUCacheWithMaxBytes(size int, maxBytes int64) *LRUCache {
+	c := NewLRUCache(size)
+	if size <= 0 {
+		c.size = 0
+	}
+	c.maxBytes = maxBytes
+	return c
+}
+
+// Get retrieves the value cached under the given key, if not expired.
+func (c *LRUCache) Get(_ context.Context, key string) ([]byte, bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	element, ok := c.entries[key]
+	if !ok {
+		c.stats.Misses++
+		return nil, false, nil
+	}
+	entry := element.Value.(*lruEntry)
+	if !entry.expiresAt.After(c.now()) {
+		c.remove(element)
+		c.stats.Misses++
+		return nil, false, nil
+	}
+	c.order.MoveToFront(element)
+	c.stats.Hits++
+	return entry.value, true, nil
+}
+
+// Set caches the given value under the given key for the given TTL, evicting the least recently used entry if full.
+func (c *LRUCache) Set(_ context.Context, key string, value []byte, ttl time.Duration) error {
+	if ttl <= 0 {
+		return nil
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.maxBytes > 0 && lruEntrySize(key, value) > c.maxBytes {
+		if element, ok := c.entries[key]; ok {
+			c.remove(element)
+		}
+		return nil
+	}
+	if element, ok := c.entries[key]; ok {
+		entry := element.Value.(*lruEntry)
+		c.bytes += int64(len(value) - len(entry.value))
+		entry.value, entry.expiresAt = value, c.now().Add(ttl)
+		c.order.MoveToFront(element)
+	} else {
+		c.entries[key] = c.order.PushFront(&lruEntry{key: key, value: value, expiresAt: c.now().Add(ttl)})
+		c.bytes += lruEntrySize(key, value)
+	}
+	for c.full() {
+		back := c.order.Back()
+		c.stats.EvictedBytes += uint64(lruEntrySize(back.Value.(*lruEntry).key, back.Value.(*lruEntry).value))
+		c.remove(back)
+		c.stats.Evictions++
+	}
+	return nil
+}
+
+// full checks if the cache holds more entries or bytes than allowed.
+func (c *LRUCache) full() bool {
+	return (c.size > 0 && c.order.Len() > c.size) || (c.maxBytes > 0 && c.bytes > c.maxBytes)
+}
+
+// lruEntrySize returns the approximate size of an entry holding the given key and value.
+func lruEntrySize(key string, value []byte) int64 {
+	return int64(len(key)+len(value)) + lruEntryOverhead
+}
+
+// Delete removes the value cached under the given key, if any.
+func (c *LRUCache) Delete(_ context.Context, key string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if element, ok := c.entries[key]; ok {
+		c.remove(element)
+	}
+	return nil
+}
+
+// Stats returns the cache counters.
+func (c *LRUCache) Stats() CacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	stats := c.stats
+	stats.Entries = c.order.Len()
+	stats.Bytes = c.bytes
+	return stats
+}
+
+// remove removes the given element from the cache.
+func (c *LRUCache) remove(element *list.Element) {
+	entry := element.Value.(*lruEntry)
+	c.order.Remove(element)
+	delete(c.entries, entry.key)
+	c.bytes -= lruEntrySize(entry.key, entry.value)
+}
+
+// WithCache makes the client cache the successful responses in the given Cache, as a LRUCache, for the given TTL,
+// keyed by the request URL. The responses are served from the cache, if present, without sending the request.
+func WithCache(cache Cache, ttl time.Duration) Option {
+	return func(d *defaultClient) {
+		d.cache = cache
+		d.cachePolicy = DefaultCachePolicy{MaxAge: ttl}
+	}
+}
+
+// WithCachePolicy replaces the policy deciding whether and for how long the responses are cached, when a cache is
+// given through WithCache.
+func WithCachePolicy(policy CachePolicy) Option {
+	return func(d *defaultClient) {
+		d.cachePolicy = policy
+	}
+}
+
+// cacheKey builds the key under which the response to the given query is cached, if cacheable.
+func (d defaultClient) cacheKey(ctx context.Context, query QueryEncoder) string {
+	if d.cache == nil || d.cachePolicy == nil {
+		return ""
+	}
+	if o := callOptionsFrom(ctx); o != nil && o.noCache {
+		return ""
+	}
+	req, err := NewRequest(ctx, d.baseURL, anonymousQuery{query})
+	if err != nil {
+		return ""
+	}
+	return d.cachePolicy.CacheKey(req)
+}
+
+// anonymousQuery encodes a query without the parameters identifying the client, the email and the secretParams, so
+// they are never stored by the caches and rotating them doesn't invalidate the cached responses.
+type anonymousQuery struct {
+	QueryEncoder
+}
+
+// Encode encodes the parameters of the query, but the identifying ones.
+func (q anonymousQuery) Encode() url.Values {
+	values := q.QueryEncoder.Encode()
+	values.Del(keyEmail)
+	for param := range secretParams {
+		values.Del(param)
+	}
+	return values
+}
+
+// getCached decodes into v the response to the given query from the cache, if present. A stale response is served
+// while refreshed in the background, when stale-while-revalidate is enabled. Otherwise, it is returned to be
+// revalidated through a conditional request, if enabled.
+func (d defaultClient) getCached(ctx context.Context, key string, query QueryEncoder, v interface{}) (*cacheEntry, bool, error) {
+	if key == "" {
+		return nil, false, nil
+	}
+	value, ok, err := d.cache.Get(ctx, key)
+	if err != nil || !ok {
+		return nil, false, nil
+	}
+	if d.cacheCodec != nil {
+		if value, err = d.cacheCodec.Decode(value); err != nil {
+			return nil, false, nil
+		}
+	}
+	entry := decodeCacheEntry(value)
+	stale := !entry.fresh(time.Now())
+	if stale && d.staleWhileRevalidate == nil {
+		if d.conditionalKeep > 0 && entry.conditional() {
+			return &entry, false, nil
+		}
+		return nil, false, nil
+	}
+	if stale {
+		d.revalidate(key, query, v, entry)
+	}
+	if metadata := responseMetadata(ctx); metadata != nil {
+		metadata.Cached = true
+		metadata.Stale = stale
+	}
+	resp := &http.Response{StatusCode: http.StatusOK, Header: http.Header{}}
+	return nil, true, d.decoder.Decode(resp, entry.body, v)
+}
+
+// store caches the given response to the given query, accordingly with the cache policy. The cached entry holds
+// until when it is fresh, as well as its validators, when stale-while-revalidate or conditional requests are enabled,
+// being kept after expired as long as it can still be served or revalidated.
+func (d defaultClient) store(ctx context.Context, query QueryEncoder, resp *http.Response, body []byte) {
+	key := d.cacheKey(ctx, query)
+	if key == "" {
+		return
+	}
+	ttl := d.cachePolicy.TTL(resp)
+	if ttl <= 0 || !d.cacheAdmission.admit(key) {
+		return
+	}
+	value, ttl := d.cacheValue(resp, body, ttl)
+	if d.cacheCodec != nil {
+		var err error
+		if value, err = d.cacheCodec.Encode(value); err != nil {
+			return
+		}
+	}
+	_ = d.cache.Set(ctx, key, value, ttl)
+}
+
+// cacheValue builds the value under which the given response is cached, and for how long, given its TTL.
+func (d defaultClient) cacheValue(resp *http.Response, body []byte, ttl time.Duration) ([]byte, time.Duration) {
+	if d.staleWhileRevalidate == nil && d.conditionalKeep <= 0 {
+		return body, ttl
+	}
+	entry := cacheEntry{
+		body:         body,
+		freshUntil:   time.Now().Add(ttl),
+		etag:         resp.Header.Get(headerETag),
+		lastModified: resp.Header.Get(headerLastModified),
+	}
+	keep := time.Duration(0)
+	if d.staleWhileRevalidate != nil {
+		keep = d.staleWhileRevalidate.maxStale
+	}
+	if entry.conditional() && d.conditionalKeep > keep {
+		keep = d.conditionalKeep
+	}
+	return entry.encode(), ttl + keep
+}
+
+// cacheEntryMagic prefixes the cached values holding, besides the response body, until when it is fresh and its
+// validators.
+var cacheEntryMagic = []byte("nce1")
+
+// cacheEntry holds a cached response body, until when it is fresh and its validators, if any.
+type cacheEntry struct {
+	body         []byte
+	freshUntil   time.Time
+	etag         string
+	lastModified string
+}
+
+// fresh checks if the entry is still fresh at the given time. Values cached as plain bodies are always fresh, as the
+// cache takes care of their expiration.
+func (e cacheEntry) fresh(now time.Time) bool {
+	return e.freshUntil.IsZero() || now.Before(e.freshUntil)
+}
+
+// conditional checks if the entry holds validators, so it can be revalidated through a conditional request.
+func (e cacheEntry) conditional() bool {
+	return e.etag != "" || e.lastModified != ""
+}
+
+// encode encodes the entry as the magic prefix, followed by until when it is fresh, the length prefixed validators
+// and the body.
+func (e cacheEntry) encode() []byte {
+	buf := make([]byte, binary.MaxVarintLen64)
+	value := make([]byte, 0, len(cacheEntryMagic)+8+2*binary.MaxVarintLen64+len(e.etag)+len(e.lastModified)+len(e.body))
+	value = append(value, cacheEntryMagic...)
+	binary.BigEndian.PutUint64(buf, uint64(e.freshUntil.UnixNano()))
+	value = append(value, buf[:8]...)
+	for _, validator := range []string{e.etag, e.lastModified} {
+		value = append(value, buf[:binary.PutUvarint(buf, uint64(len(validator)))]...)
+		value = append(value, validator...)
+	}
+	return append(value, e.body...)
+}
+
+// decodeCacheEntry decodes the given cached value, which is taken as a plain body if not encoded as a cacheEntry.
+func decodeCacheEntry(value []byte) cacheEntry {
+	if !bytes.HasPrefix(value, cacheEntryMagic) || len(value) < len(cacheEntryMagic)+8 {
+		return cacheEntry{body: value}
+	}
+	rest := value[len(cacheEntryMagic):]
+	entry := cacheEntry{freshUntil: time.Unix(0, int64(binary.BigEndian.Uint64(rest)))}
+	rest = rest[8:]
+	validators := make([]string, 2)
+	for i := range validators {
+		size, n := binary.Uvarint(rest)
+		if n <= 0 || uint64(len(rest)-n) < size {
+			return cacheEntry{body: value}
+		}
+		validators[i], rest = string(rest[n:n+int(size)]), rest[n+int(size):]
+	}
+	entry.etag, entry.lastModified, entry.body = validators[0], validators[1], rest
+	return entry
+}
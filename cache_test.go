@@ -0,0 +1,151 @@
+package nominatim_test
+
+import (
+	"context"
+	"github.com/diegohordi/nominatim"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func Test_LRUCache(t *testing.T) {
+	var _ nominatim.Cache = nominatim.NewLRUCache(1)
+	cache := nominatim.NewLRUCache(2)
+	cache.Set(context.TODO(), "a", []byte("a"), time.Hour)
+	cache.Set(context.TODO(), "b", []byte("b"), time.Hour)
+	if _, ok, _ := cache.Get(context.TODO(), "a"); !ok {
+		t.Errorf("Get() got no value for a")
+	}
+	cache.Set(context.TODO(), "c", []byte("c"), time.Hour)
+	if _, ok, _ := cache.Get(context.TODO(), "b"); ok {
+		t.Errorf("Get() got a value for b, want it evicted as the least recently used")
+	}
+	if value, ok, _ := cache.Get(context.TODO(), "c"); !ok || string(value) != "c" {
+		t.Errorf("Get() got = %s, want c", value)
+	}
+	cache.Set(context.TODO(), "d", []byte("d"), time.Millisecond)
+	time.Sleep(5 * time.Millisecond)
+	if _, ok, _ := cache.Get(context.TODO(), "d"); ok {
+		t.Errorf("Get() got a value for d, want it expired")
+	}
+	cache.Delete(context.TODO(), "c")
+	if _, ok, _ := cache.Get(context.TODO(), "c"); ok {
+		t.Errorf("Get() got a value for c, want it deleted")
+	}
+	want := nominatim.CacheStats{Hits: 2, Misses: 3, Evictions: 2, EvictedBytes: 196, Entries: 0}
+	if got := cache.Stats(); got != want {
+		t.Errorf("Stats() got = %+v, want %+v", got, want)
+	}
+}
+
+func Test_LRUCacheWithMaxBytes(t *testing.T) {
+	// Each entry takes 1 byte of key, 100 bytes of value and 96 bytes of overhead.
+	value := make([]byte, 100)
+	cache := nominatim.NewLRUCacheWithMaxBytes(0, 500)
+	for _, key := range []string{"a", "b", "c"} {
+		cache.Set(context.TODO(), key, value, time.Hour)
+	}
+	if _, ok, _ := cache.Get(context.TODO(), "a"); ok {
+		t.Errorf("Get() got a value for a, want it evicted to fit the max bytes")
+	}
+	if stats := cache.Stats(); stats.Entries != 2 || stats.Bytes != 394 || stats.Evictions != 1 || stats.EvictedBytes != 197 {
+		t.Errorf("Stats() got = %+v, want 2 entries of 394 bytes and 1 eviction of 197 bytes", stats)
+	}
+	cache.Set(context.TODO(), "b", make([]byte, 1000), time.Hour)
+	if _, ok, _ := cache.Get(context.TODO(), "b"); ok {
+		t.Errorf("Get() got a value for b, want values larger than the cache skipped")
+	}
+	cache.Set(context.TODO(), "c", make([]byte, 10), time.Hour)
+	if stats := cache.Stats(); stats.Entries != 1 || stats.Bytes != 107 {
+		t.Errorf("Stats() got = %+v, want 1 entry of 107 bytes", stats)
+	}
+	cache.Delete(context.TODO(), "c")
+	if stats := cache.Stats(); stats.Entries != 0 || stats.Bytes != 0 {
+		t.Errorf("Stats() got = %+v, want it empty", stats)
+	}
+}
+
+func Test_WithCache(t *testing.T) {
+	var requests int32
+	transport := TransportFunc(func(req *http.Request) (*http.Response, error) {
+		atomic.AddInt32(&requests, 1)
+		if req.URL.Query().Get("q") == "failure" {
+			return statusFixture(http.StatusInternalServerError, nil)()
+		}
+		return statusFixture(http.StatusOK, mustLoadValidSearchResults(t))()
+	})
+	cache := nominatim.NewLRUCache(10)
+	d := nominatim.NewClient("http://localhost:8080", nil, nominatim.WithTransport(transport), nominatim.WithCache(cache, time.Hour))
+	search := func(q string) (*nominatim.ResponseMetadata, error) {
+		metadata := &nominatim.ResponseMetadata{}
+		query := nominatim.NewSearchQuery()
+		query.FreeFormQuery = q
+		_, err := d.Search(nominatim.WithResponseMetadata(context.TODO(), metadata), *query)
+		return metadata, err
+	}
+	tests := []struct {
+		name         string
+		q            string
+		wantCached   bool
+		wantRequests int32
+		wantErr      bool
+	}{
+		{name: "should send the first query", q: "lisboa", wantCached: false, wantRequests: 1},
+		{name: "should serve the same query from the cache", q: "lisboa", wantCached: true, wantRequests: 1},
+		{name: "should send a different query", q: "porto", wantCached: false, wantRequests: 2},
+		{name: "should not cache failures", q: "failure", wantCached: false, wantRequests: 3, wantErr: true},
+		{name: "should send a failed query again", q: "failure", wantCached: false, wantRequests: 4, wantErr: true},
+	}
+	for _, tt := range tests {
+		metadata, err := search(tt.q)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: Search() error = %v, wantErr %v", tt.name, err, tt.wantErr)
+		}
+		if metadata.Cached != tt.wantCached {
+			t.Errorf("%s: Search() cached = %v, want %v", tt.name, metadata.Cached, tt.wantCached)
+		}
+		if got := atomic.LoadInt32(&requests); got != tt.wantRequests {
+			t.Errorf("%s: Search() requests = %v, want %v", tt.name, got, tt.wantRequests)
+		}
+	}
+	if stats := cache.Stats(); stats.Hits != 1 || stats.Entries != 2 {
+		t.Errorf("Stats() got = %+v, want 1 hit and 2 entries", stats)
+	}
+}
+
+// keysCache records the keys the responses are cached under.
+type keysCache struct {
+	*nominatim.LRUCache
+	keys []string
+}
+
+func (c *keysCache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	c.keys = append(c.keys, key)
+	return c.LRUCache.Set(ctx, key, value, ttl)
+}
+
+func Test_WithCache_identity(t *testing.T) {
+	transport := TransportFunc(func(req *http.Request) (*http.Response, error) {
+		return statusFixture(http.StatusOK, mustLoadValidSearchResults(t))()
+	})
+	cache := &keysCache{LRUCache: nominatim.NewLRUCache(10)}
+	search := func(apiKey string) {
+		d := nominatim.NewClient("http://localhost:8080", nil, nominatim.WithTransport(transport),
+			nominatim.WithCache(cache, time.Hour), nominatim.WithEmail("ops@acme.example"), nominatim.WithAPIKey(apiKey))
+		if _, err := d.Search(context.TODO(), *nominatim.NewSearchQuery(nominatim.WithFreeForm("lisboa"))); err != nil {
+			t.Fatal(err)
+		}
+	}
+	search("pk.secret")
+	search("pk.rotated")
+	if len(cache.keys) != 1 {
+		t.Fatalf("Set() keys = %v, want a single key shared by both API keys", cache.keys)
+	}
+	for _, secret := range []string{"pk.secret", "ops", "key=", "email="} {
+		if strings.Contains(cache.keys[0], secret) {
+			t.Errorf("Set() key = %v, want it without %q", cache.keys[0], secret)
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package nominatim
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	headerCacheControl = "Cache-Control"
+	headerExpires      = "Expires"
+	headerDate         = "Date"
+)
+
+// HeaderCachePolicy caches the successful responses to GET requests, keyed by their URL, for as long as allowed by
+// their Cache-Control or Expires headers, as sent by the server or a caching proxy in front of it, as Varnish. The
+// responses without any of them are cached for the DefaultTTL. The TTLs are capped between MinTTL and MaxTTL, if
+// given, while the responses marked as no-store, no-cache or private are never cached.
+type HeaderCachePolicy struct {
+	DefaultTTL time.Duration
+	MinTTL     time.Duration
+	MaxTTL     time.Duration
+}
+
+// CacheKey returns the URL of GET requests.
+func (p HeaderCachePolicy) CacheKey(req *http.Request) string {
+	return DefaultCachePolicy{}.CacheKey(req)
+}
+
+// TTL returns for how long the given response may be cached, accordingly with its headers.
+func (p HeaderCachePolicy) TTL(resp *http.Response) time.Duration {
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return 0
+	}
+	ttl, ok := headerTTL(resp.Header)
+	if !ok {
+		ttl = p.DefaultTTL
+	}
+	if ttl <= 0 {
+		return 0
+	}
+	if ttl < p.MinTTL {
+		ttl = p.MinTTL
+	}
+	if p.MaxTTL > 0 && ttl > p.MaxTTL {
+		ttl = p.MaxTTL
+	}
+	return ttl
+}
+
+// headerTTL derives the TTL from the Cache-Control header, or from the Expires header, as long as any of them is
+// present. The s-maxage directive takes precedence over max-age, as the client cache is shared by its callers.
+func headerTTL(header http.Header) (time.Duration, bool) {
+	if cacheControl := header.Get(headerCacheControl); cacheControl != "" {
+		maxAge, sharedMaxAge := -1, -1
+		for _, directive := range strings.Split(cacheControl, ",") {
+			name, value := directive, ""
+			if i := strings.Index(directive, "="); i >= 0 {
+				name, value = directive[:i], strings.Trim(strings.TrimSpace(directive[i+1:]), `"`)
+			}
+			switch strings.ToLower(strings.TrimSpace(name)) {
+			case "no-store", "no-cache", "private":
+				return 0, true
+			case "max-age":
+				maxAge = parseSeconds(value)
+			case "s-maxage":
+				sharedMaxAge = parseSeconds(value)
+			}
+		}
+		if sharedMaxAge >= 0 {
+			return time.Duration(sharedMaxAge) * time.Second, true
+		}
+		if maxAge >= 0 {
+			return time.Duration(maxAge) * time.Second, true
+		}
+	}
+	expires := header.Get(headerExpires)
+	if expires == "" {
+		return 0, false
+	}
+	expiresAt, err := http.ParseTime(expires)
+	if err != nil {
+		return 0, true
+	}
+	now := time.Now()
+	if date, err := http.ParseTime(header.Get(headerDate)); err == nil {
+		now = date
+	}
+	return expiresAt.Sub(now), true
+}
+
+// parseSeconds parses the given non-negative number of seconds, returning -1 if invalid.
+func parseSeconds(value string) int {
+	seconds, err := strconv.Atoi(value)
+	if err != nil || seconds < 0 {
+		return -1
+	}
+	return seconds
+}
@@ -0,0 +1,49 @@
+package nominatim_test
+
+import (
+	"github.com/diegohordi/nominatim"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestHeaderCachePolicy_TTL(t *testing.T) {
+	policy := nominatim.HeaderCachePolicy{DefaultTTL: time.Hour, MinTTL: time.Minute, MaxTTL: 24 * time.Hour}
+	date := time.Date(2021, 11, 25, 17, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name       string
+		statusCode int
+		header     map[string]string
+		want       time.Duration
+	}{
+		{name: "should use the default TTL", statusCode: http.StatusOK, want: time.Hour},
+		{name: "should use max-age", statusCode: http.StatusOK, header: map[string]string{"Cache-Control": "public, max-age=600"}, want: 10 * time.Minute},
+		{name: "should prefer s-maxage", statusCode: http.StatusOK, header: map[string]string{"Cache-Control": "max-age=600, s-maxage=1200"}, want: 20 * time.Minute},
+		{name: "should cap to the min TTL", statusCode: http.StatusOK, header: map[string]string{"Cache-Control": "max-age=1"}, want: time.Minute},
+		{name: "should cap to the max TTL", statusCode: http.StatusOK, header: map[string]string{"Cache-Control": "max-age=604800"}, want: 24 * time.Hour},
+		{name: "should not cache no-store", statusCode: http.StatusOK, header: map[string]string{"Cache-Control": "no-store"}},
+		{name: "should not cache private", statusCode: http.StatusOK, header: map[string]string{"Cache-Control": "private, max-age=600"}},
+		{name: "should not cache max-age=0", statusCode: http.StatusOK, header: map[string]string{"Cache-Control": "max-age=0"}},
+		{
+			name:       "should use Expires relative to Date",
+			statusCode: http.StatusOK,
+			header:     map[string]string{"Date": date.Format(http.TimeFormat), "Expires": date.Add(2 * time.Hour).Format(http.TimeFormat)},
+			want:       2 * time.Hour,
+		},
+		{name: "should not cache invalid Expires", statusCode: http.StatusOK, header: map[string]string{"Expires": "0"}},
+		{name: "should not cache failures", statusCode: http.StatusInternalServerError, header: map[string]string{"Cache-Control": "max-age=600"}},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			resp := &http.Response{StatusCode: tt.statusCode, Header: http.Header{}}
+			for key, value := range tt.header {
+				resp.Header.Set(key, value)
+			}
+			if got := policy.TTL(resp); got != tt.want {
+				t.Errorf("TTL() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,133 @@
+package nominatim
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// CallOption configures a single call to the client, without changing the client-wide configuration.
+type CallOption func(*callOptions)
+
+// callOptions holds the configuration of a single call.
+type callOptions struct {
+	header       http.Header
+	params       url.Values
+	noCache      bool
+	timeout      time.Duration
+	resultFilter *ResultFilter
+}
+
+type callOptionsKey struct{}
+
+// WithHeader makes the call send the given header, besides the client-wide ones, overriding them if also given. The
+// headers are not part of the cache key.
+func WithHeader(key, value string) CallOption {
+	return func(o *callOptions) {
+		if o.header == nil {
+			o.header = http.Header{}
+		}
+		o.header.Add(key, value)
+	}
+}
+
+// WithParam makes the call send the given query parameter, as undocumented or newer ones, overriding the one encoded
+// from the query, if any.
+func WithParam(key, value string) CallOption {
+	return func(o *callOptions) {
+		if o.params == nil {
+			o.params = url.Values{}
+		}
+		o.params.Add(key, value)
+	}
+}
+
+// WithCallTimeout bounds the whole call, including its retries, to the given duration, independently of the context
+// given to it, overriding the client-wide default given through WithDefaultCallTimeout.
+func WithCallTimeout(timeout time.Duration) CallOption {
+	return func(o *callOptions) {
+		o.timeout = timeout
+	}
+}
+
+// WithDefaultCallTimeout bounds every call, including its retries, to the given duration, unless another one is
+// given through WithCallTimeout, so batch jobs can bound each lookup without creating contexts everywhere.
+// Non-positive values mean no timeout.
+func WithDefaultCallTimeout(timeout time.Duration) Option {
+	return func(d *defaultClient) {
+		d.callTimeout = timeout
+	}
+}
+
+// withoutCache makes the call bypass the cache, neither served from it nor stored in it.
+func withoutCache() CallOption {
+	return func(o *callOptions) {
+		o.noCache = true
+	}
+}
+
+// withCallOptions returns a copy of the given context holding the given call options, if any.
+func withCallOptions(ctx context.Context, opts []CallOption) context.Context {
+	if len(opts) == 0 {
+		return ctx
+	}
+	o := &callOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return context.WithValue(ctx, callOptionsKey{}, o)
+}
+
+// startCall returns a copy of the given context holding the given call options, if any, and the sampling decision of
+// the call, bounded by the call timeout, if any. The returned function must be called when the call is done.
+func (d defaultClient) startCall(ctx context.Context, opts []CallOption) (context.Context, context.CancelFunc) {
+	ctx = d.sample(withCallOptions(ctx, opts))
+	timeout := d.callTimeout
+	if o := callOptionsFrom(ctx); o != nil && o.timeout > 0 {
+		timeout = o.timeout
+	}
+	if timeout <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
+// callOptionsFrom retrieves the call options held by the given context, if any.
+func callOptionsFrom(ctx context.Context) *callOptions {
+	o, _ := ctx.Value(callOptionsKey{}).(*callOptions)
+	return o
+}
+
+// query returns the given query with the call parameters, if any.
+func (o *callOptions) query(query QueryEncoder) QueryEncoder {
+	if o == nil || len(o.params) == 0 {
+		return query
+	}
+	return paramsQuery{QueryEncoder: query, params: o.params}
+}
+
+// setHeaders sets the call headers on the given request, if any.
+func (o *callOptions) setHeaders(req *http.Request) {
+	if o == nil {
+		return
+	}
+	for key, values := range o.header {
+		req.Header[key] = append([]string(nil), values...)
+	}
+}
+
+// paramsQuery encodes a query with extra parameters.
+type paramsQuery struct {
+	QueryEncoder
+	params url.Values
+}
+
+// Encode encodes the parameters of the query, overridden by the extra ones.
+func (q paramsQuery) Encode() url.Values {
+	values := q.QueryEncoder.Encode()
+	for key, params := range q.params {
+		values[key] = append([]string(nil), params...)
+	}
+	return values
+}
@@ -0,0 +1,134 @@
+package nominatim_test
+
+import (
+	"context"
+	"errors"
+	"github.com/diegohordi/nominatim"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func Test_CallOptions(t *testing.T) {
+	var header http.Header
+	var params map[string][]string
+	transport := TransportFunc(func(req *http.Request) (*http.Response, error) {
+		header, params = req.Header, req.URL.Query()
+		switch req.URL.Path {
+		case "/search":
+			return statusFixture(http.StatusOK, mustLoadValidSearchResults(t))()
+		case "/reverse":
+			return statusFixture(http.StatusOK, mustLoadValidReverseResult(t))()
+		}
+		return statusFixture(http.StatusOK, mustLoadValidStatus(t))()
+	})
+	d := nominatim.NewClient("http://localhost:8080", nil, nominatim.WithTransport(transport),
+		nominatim.WithDefaultHeaders(http.Header{"X-Api-Key": {"client"}}), nominatim.WithCache(nominatim.NewLRUCache(10), time.Hour))
+	tests := []struct {
+		name       string
+		call       func(opts ...nominatim.CallOption) error
+		opts       []nominatim.CallOption
+		wantHeader string
+		wantParam  string
+	}{
+		{
+			name: "should search without call options",
+			call: func(opts ...nominatim.CallOption) error {
+				query := nominatim.NewSearchQuery()
+				query.FreeFormQuery = "lisboa"
+				_, err := d.Search(context.TODO(), *query, opts...)
+				return err
+			},
+			wantHeader: "client",
+		},
+		{
+			name: "should search with the call header and param",
+			call: func(opts ...nominatim.CallOption) error {
+				query := nominatim.NewSearchQuery()
+				query.FreeFormQuery = "lisboa"
+				_, err := d.Search(context.TODO(), *query, opts...)
+				return err
+			},
+			opts:       []nominatim.CallOption{nominatim.WithHeader("X-Api-Key", "call"), nominatim.WithParam("dedupe", "0")},
+			wantHeader: "call",
+			wantParam:  "0",
+		},
+		{
+			name: "should reverse with the call header and param",
+			call: func(opts ...nominatim.CallOption) error {
+				_, err := d.Reverse(context.TODO(), *nominatim.NewReverseQuery("38.6945252", "-9.3221278"), opts...)
+				return err
+			},
+			opts:       []nominatim.CallOption{nominatim.WithHeader("X-Api-Key", "call"), nominatim.WithParam("dedupe", "1")},
+			wantHeader: "call",
+			wantParam:  "1",
+		},
+		{
+			name: "should check the status with the call header and param",
+			call: func(opts ...nominatim.CallOption) error {
+				_, err := d.CheckStatus(context.TODO(), opts...)
+				return err
+			},
+			opts:       []nominatim.CallOption{nominatim.WithHeader("X-Api-Key", "call"), nominatim.WithParam("dedupe", "2")},
+			wantHeader: "call",
+			wantParam:  "2",
+		},
+	}
+	for _, tt := range tests {
+		header, params = nil, nil
+		if err := tt.call(tt.opts...); err != nil {
+			t.Fatalf("%s: error = %v", tt.name, err)
+		}
+		if got := header.Get("X-Api-Key"); got != tt.wantHeader {
+			t.Errorf("%s: sent X-Api-Key = %q, want %q", tt.name, got, tt.wantHeader)
+		}
+		if got := params["dedupe"]; (tt.wantParam == "" && got != nil) || (tt.wantParam != "" && (len(got) != 1 || got[0] != tt.wantParam)) {
+			t.Errorf("%s: sent dedupe = %v, want %q", tt.name, got, tt.wantParam)
+		}
+	}
+}
+
+func Test_WithCallTimeout(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    []nominatim.Option
+		callOpt []nominatim.CallOption
+		wantErr error
+	}{
+		{name: "should not time out by default"},
+		{
+			name:    "should time out by the client-wide default",
+			opts:    []nominatim.Option{nominatim.WithDefaultCallTimeout(10 * time.Millisecond)},
+			wantErr: context.DeadlineExceeded,
+		},
+		{
+			name:    "should time out by the call timeout",
+			callOpt: []nominatim.CallOption{nominatim.WithCallTimeout(10 * time.Millisecond)},
+			wantErr: context.DeadlineExceeded,
+		},
+		{
+			name:    "should override the client-wide default",
+			opts:    []nominatim.Option{nominatim.WithDefaultCallTimeout(10 * time.Millisecond)},
+			callOpt: []nominatim.CallOption{nominatim.WithCallTimeout(time.Second)},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			transport := TransportFunc(func(req *http.Request) (*http.Response, error) {
+				select {
+				case <-time.After(50 * time.Millisecond):
+					return statusFixture(http.StatusOK, mustLoadValidStatus(t))()
+				case <-req.Context().Done():
+					return nil, req.Context().Err()
+				}
+			})
+			d := nominatim.NewClient("http://localhost:8080", nil, append(tt.opts, nominatim.WithTransport(transport))...)
+			_, err := d.CheckStatus(context.TODO(), tt.callOpt...)
+			if !errors.Is(err, tt.wantErr) || (tt.wantErr == nil && err != nil) {
+				t.Errorf("CheckStatus() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
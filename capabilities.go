@@ -0,0 +1,68 @@
+package nominatim
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// Capabilities holds the endpoints detected as unavailable by the client, as in deployments where /search is
+// disabled, along with when they were detected. An endpoint becomes available again as soon as it responds.
+type Capabilities struct {
+	Unavailable map[string]time.Time
+}
+
+// Available checks if the given endpoint, as EndpointSearch, wasn't detected as unavailable.
+func (c Capabilities) Available(endpoint string) bool {
+	_, ok := c.Unavailable[endpoint]
+	return !ok
+}
+
+// CapabilitiesReporter is implemented by the clients able to report the Capabilities of the server, as the one
+// created by NewClient.
+type CapabilitiesReporter interface {
+
+	// Capabilities returns the Capabilities detected so far.
+	Capabilities() Capabilities
+}
+
+// capabilities tracks the availability of the endpoints.
+type capabilities struct {
+	mu          sync.RWMutex
+	unavailable map[string]time.Time
+}
+
+// newCapabilities creates a capabilities with every endpoint available.
+func newCapabilities() *capabilities {
+	return &capabilities{unavailable: make(map[string]time.Time)}
+}
+
+// track updates the availability of the given endpoint accordingly with the outcome of a request to it.
+func (c *capabilities) track(endpoint string, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	switch {
+	case errors.Is(err, ErrEndpointUnavailable):
+		if _, ok := c.unavailable[endpoint]; !ok {
+			c.unavailable[endpoint] = time.Now()
+		}
+	case err == nil || errors.Is(err, ErrNoResults) || errors.Is(err, ErrInvalidQuery):
+		delete(c.unavailable, endpoint)
+	}
+}
+
+// snapshot copies the current Capabilities.
+func (c *capabilities) snapshot() Capabilities {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	unavailable := make(map[string]time.Time, len(c.unavailable))
+	for endpoint, since := range c.unavailable {
+		unavailable[endpoint] = since
+	}
+	return Capabilities{Unavailable: unavailable}
+}
+
+// Capabilities returns the Capabilities detected so far.
+func (d defaultClient) Capabilities() Capabilities {
+	return d.capabilities.snapshot()
+}
@@ -0,0 +1,67 @@
+package nominatim_test
+
+import (
+	"context"
+	"errors"
+	"github.com/diegohordi/nominatim"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func Test_Capabilities(t *testing.T) {
+	var searchEnabled int32
+	transport := TransportFunc(func(req *http.Request) (*http.Response, error) {
+		switch {
+		case strings.HasSuffix(req.URL.Path, "/search") && atomic.LoadInt32(&searchEnabled) == 0:
+			return statusFixture(http.StatusNotFound, []byte("<html>Not Found</html>"))()
+		case strings.HasSuffix(req.URL.Path, "/search"):
+			return statusFixture(http.StatusOK, mustLoadValidSearchResults(t))()
+		default:
+			return statusFixture(http.StatusOK, mustLoadValidReverseResult(t))()
+		}
+	})
+	d := nominatim.NewClient("http://localhost:8080", nil, nominatim.WithTransport(transport))
+	capabilities := func() nominatim.Capabilities {
+		return d.(nominatim.CapabilitiesReporter).Capabilities()
+	}
+	query := nominatim.NewSearchQuery()
+	query.FreeFormQuery = "test"
+
+	_, err := d.Search(context.TODO(), *query)
+	if !errors.Is(err, nominatim.ErrEndpointUnavailable) || errors.Is(err, nominatim.ErrInvalidQuery) {
+		t.Fatalf("Search() error = %v, want %v", err, nominatim.ErrEndpointUnavailable)
+	}
+	if _, err := d.Reverse(context.TODO(), *nominatim.NewReverseQuery("38.6945252", "-9.3221278")); err != nil {
+		t.Fatalf("Reverse() error = %v", err)
+	}
+	if capabilities().Available(nominatim.EndpointSearch) {
+		t.Errorf("Capabilities() got search available, want unavailable")
+	}
+	if !capabilities().Available(nominatim.EndpointReverse) {
+		t.Errorf("Capabilities() got reverse unavailable, want available")
+	}
+
+	atomic.StoreInt32(&searchEnabled, 1)
+	if _, err := d.Search(context.TODO(), *query); err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+	if !capabilities().Available(nominatim.EndpointSearch) {
+		t.Errorf("Capabilities() got search unavailable, want available again")
+	}
+}
+
+func Test_Capabilities_NoResults(t *testing.T) {
+	transport := TransportFunc(func(req *http.Request) (*http.Response, error) {
+		return statusFixture(http.StatusNotFound, []byte(`{"error":"Unable to geocode"}`))()
+	})
+	d := nominatim.NewClient("http://localhost:8080", nil, nominatim.WithTransport(transport))
+	_, err := d.Search(context.TODO(), *nominatim.NewSearchQuery(nominatim.WithFreeForm("nowhere")))
+	if !errors.Is(err, nominatim.ErrNoResults) || errors.Is(err, nominatim.ErrEndpointUnavailable) {
+		t.Fatalf("Search() error = %v, want %v only", err, nominatim.ErrNoResults)
+	}
+	if capabilities := d.(nominatim.CapabilitiesReporter).Capabilities(); !capabilities.Available(nominatim.EndpointSearch) {
+		t.Errorf("Capabilities() got search unavailable, want it untouched by a search with no results")
+	}
+}
@@ -0,0 +1,86 @@
+package nominatim
+
+// Clone returns a deep copy of the SearchQuery, sharing no slices nor its bias with it, so a base query can be reused
+// as a template across goroutines.
+func (q SearchQuery) Clone() SearchQuery {
+	q.AcceptLanguage = cloneStrings(q.AcceptLanguage)
+	q.ExcludedPlaces = cloneStrings(q.ExcludedPlaces)
+	q.Layers = cloneStrings(q.Layers)
+	q.CountryCodes = cloneStrings(q.CountryCodes)
+	if q.Bias != nil {
+		bias := *q.Bias
+		q.Bias = &bias
+	}
+	return q
+}
+
+// With returns a copy of the SearchQuery, as in Clone, with the given options applied, leaving the SearchQuery
+// untouched, as in query.With(nominatim.WithLimit(5), nominatim.WithCountryCodes("pt")).
+func (q SearchQuery) With(opts ...SearchOption) SearchQuery {
+	clone := q.Clone()
+	for _, opt := range opts {
+		opt(&clone)
+	}
+	return clone
+}
+
+// WithFreeForm returns a copy of the SearchQuery searching for the given free-form text, as in WithFreeForm.
+func (q SearchQuery) WithFreeForm(text string) SearchQuery {
+	return q.With(WithFreeForm(text))
+}
+
+// WithLimit returns a copy of the SearchQuery limited to the given number of results, as in WithLimit.
+func (q SearchQuery) WithLimit(limit int) SearchQuery {
+	return q.With(WithLimit(limit))
+}
+
+// WithLanguages returns a copy of the SearchQuery using the given languages, as in WithLanguages.
+func (q SearchQuery) WithLanguages(languages ...string) SearchQuery {
+	return q.With(WithLanguages(languages...))
+}
+
+// WithCountryCodes returns a copy of the SearchQuery also restricted to the given countries, as in WithCountryCodes.
+func (q SearchQuery) WithCountryCodes(codes ...string) SearchQuery {
+	return q.With(WithCountryCodes(codes...))
+}
+
+// WithExcludedPlaces returns a copy of the SearchQuery also excluding the places with the given IDs, as in
+// WithExcludedPlaces.
+func (q SearchQuery) WithExcludedPlaces(placeIDs ...string) SearchQuery {
+	return q.With(WithExcludedPlaces(placeIDs...))
+}
+
+// Clone returns a deep copy of the ReverseQuery, sharing no slices with it.
+func (q ReverseQuery) Clone() ReverseQuery {
+	q.AcceptLanguage = cloneStrings(q.AcceptLanguage)
+	return q
+}
+
+// WithLanguages returns a copy of the ReverseQuery using the given languages, in order of preference.
+func (q ReverseQuery) WithLanguages(languages ...string) ReverseQuery {
+	clone := q.Clone()
+	clone.AcceptLanguage = cloneStrings(languages)
+	return clone
+}
+
+// Clone returns a deep copy of the LookupQuery, sharing no slices with it.
+func (q LookupQuery) Clone() LookupQuery {
+	q.OSMIDs = cloneStrings(q.OSMIDs)
+	q.AcceptLanguage = cloneStrings(q.AcceptLanguage)
+	return q
+}
+
+// WithLanguages returns a copy of the LookupQuery using the given languages, in order of preference.
+func (q LookupQuery) WithLanguages(languages ...string) LookupQuery {
+	clone := q.Clone()
+	clone.AcceptLanguage = cloneStrings(languages)
+	return clone
+}
+
+// cloneStrings returns a copy of the given strings, keeping nil as nil.
+func cloneStrings(values []string) []string {
+	if values == nil {
+		return nil
+	}
+	return append(make([]string, 0, len(values)), values...)
+}
@@ -0,0 +1,116 @@
+package nominatim_test
+
+import (
+	"github.com/diegohordi/nominatim"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+func TestSearchQuery_Clone(t *testing.T) {
+	base := nominatim.SearchQuery{
+		AcceptLanguage: []string{"pt"},
+		ExcludedPlaces: []string{"1"},
+		Layers:         []string{"poi"},
+		CountryCodes:   []string{"pt"},
+		Bias:           &nominatim.LocationBias{Latitude: 38.7, Longitude: -9.1, Radius: 500},
+	}
+	clone := base.Clone()
+	if !reflect.DeepEqual(clone, base) {
+		t.Fatalf("Clone() = %+v, want %+v", clone, base)
+	}
+	clone.AcceptLanguage[0], clone.ExcludedPlaces[0], clone.Layers[0], clone.CountryCodes[0] = "en", "2", "address", "es"
+	clone.Bias.Radius = 1000
+	if base.AcceptLanguage[0] != "pt" || base.ExcludedPlaces[0] != "1" || base.Layers[0] != "poi" || base.CountryCodes[0] != "pt" || base.Bias.Radius != 500 {
+		t.Errorf("Clone() shares its fields with the original, got %+v", base)
+	}
+}
+
+func TestSearchQuery_With(t *testing.T) {
+	base := *nominatim.NewSearchQuery(nominatim.WithLanguages("pt", "en"), nominatim.WithCountryCodes("pt"))
+	tests := []struct {
+		name  string
+		query func() nominatim.SearchQuery
+		want  func(q *nominatim.SearchQuery)
+	}{
+		{
+			name:  "should set the free-form query and limit",
+			query: func() nominatim.SearchQuery { return base.WithFreeForm("lisboa").WithLimit(5) },
+			want: func(q *nominatim.SearchQuery) {
+				q.FreeFormQuery, q.Limit = "lisboa", 5
+			},
+		},
+		{
+			name:  "should replace the languages",
+			query: func() nominatim.SearchQuery { return base.WithLanguages("es") },
+			want: func(q *nominatim.SearchQuery) {
+				q.AcceptLanguage = []string{"es"}
+			},
+		},
+		{
+			name:  "should append the country codes and excluded places",
+			query: func() nominatim.SearchQuery { return base.WithCountryCodes("es").WithExcludedPlaces("1", "2") },
+			want: func(q *nominatim.SearchQuery) {
+				q.CountryCodes, q.ExcludedPlaces = []string{"pt", "es"}, []string{"1", "2"}
+			},
+		},
+		{
+			name:  "should apply the given options",
+			query: func() nominatim.SearchQuery { return base.With(nominatim.WithDetails(true, true, true)) },
+			want: func(q *nominatim.SearchQuery) {
+				q.ExtraTags, q.NameDetails = true, true
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			want := base.Clone()
+			tt.want(&want)
+			if got := tt.query(); !reflect.DeepEqual(got, want) {
+				t.Errorf("With() = %+v, want %+v", got, want)
+			}
+			if !reflect.DeepEqual(base.AcceptLanguage, []string{"pt", "en"}) || !reflect.DeepEqual(base.CountryCodes, []string{"pt"}) {
+				t.Errorf("With() mutated the template, got %+v", base)
+			}
+		})
+	}
+}
+
+func TestSearchQuery_With_concurrent(t *testing.T) {
+	base := *nominatim.NewSearchQuery(nominatim.WithCountryCodes("pt"))
+	base.CountryCodes = append(make([]string, 0, 8), base.CountryCodes...)
+	codes := []string{"es", "fr", "it", "de"}
+	queries := make([]nominatim.SearchQuery, len(codes))
+	wg := sync.WaitGroup{}
+	for i, code := range codes {
+		wg.Add(1)
+		go func(i int, code string) {
+			defer wg.Done()
+			queries[i] = base.WithCountryCodes(code)
+		}(i, code)
+	}
+	wg.Wait()
+	for i, code := range codes {
+		if want := []string{"pt", code}; !reflect.DeepEqual(queries[i].CountryCodes, want) {
+			t.Errorf("WithCountryCodes() = %v, want %v", queries[i].CountryCodes, want)
+		}
+	}
+}
+
+func TestReverseQuery_Clone(t *testing.T) {
+	base := nominatim.ReverseQuery{AcceptLanguage: []string{"pt"}}
+	clone := base.WithLanguages("en")
+	clone.Clone().AcceptLanguage[0] = "es"
+	if base.AcceptLanguage[0] != "pt" || !reflect.DeepEqual(clone.AcceptLanguage, []string{"en"}) {
+		t.Errorf("WithLanguages() = %v, template %v, want [en] and [pt]", clone.AcceptLanguage, base.AcceptLanguage)
+	}
+}
+
+func TestLookupQuery_Clone(t *testing.T) {
+	base := nominatim.LookupQuery{OSMIDs: []string{"R1"}, AcceptLanguage: []string{"pt"}}
+	clone := base.WithLanguages("en")
+	clone.OSMIDs[0] = "W2"
+	if base.OSMIDs[0] != "R1" || base.AcceptLanguage[0] != "pt" || !reflect.DeepEqual(clone.AcceptLanguage, []string{"en"}) {
+		t.Errorf("WithLanguages() = %+v, template %+v", clone, base)
+	}
+}
@@ -0,0 +1,320 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/diegohordi/nominatim"
+)
+
+const (
+	// minAutocompleteLength is the minimum length of the text searched as the user types.
+	minAutocompleteLength = 3
+
+	// autocompleteCacheSize is the number of responses cached by the interactive mode.
+	autocompleteCacheSize = 256
+
+	// autocompleteCacheTTL is for how long the responses are cached by the interactive mode.
+	autocompleteCacheTTL = 10 * time.Minute
+)
+
+// Keys handled by the interactive mode.
+const (
+	keyInterrupt = 0x03
+	keyEOF       = 0x04
+	keyBackspace = 0x08
+	keyEnter     = '\n'
+	keyReturn    = '\r'
+	keyEscape    = 0x1b
+	keyDelete    = 0x7f
+)
+
+// ansiClear clears the terminal, moving the cursor to its top.
+const ansiClear = "\033[H\033[2J"
+
+// interactiveFlags registers the flags specific to the interactive subcommand.
+func interactiveFlags(set *flag.FlagSet, flags *commonFlags) {
+	set.DurationVar(&flags.debounce, "debounce", 300*time.Millisecond, "how long to wait for the user to stop typing before searching")
+	set.Float64Var(&flags.rate, "rate", nominatim.DefaultRateLimit, "maximum number of searches per second")
+	set.StringVar(&flags.near, "near", "", "location the candidates are biased to, as lat,lon")
+}
+
+// interactiveOptions returns the client options of the interactive subcommand, caching the responses, so erasing
+// and typing again doesn't search again, and limiting the rate of the searches.
+func interactiveOptions(flags *commonFlags) []nominatim.Option {
+	return []nominatim.Option{
+		nominatim.WithCache(nominatim.NewLRUCache(autocompleteCacheSize), autocompleteCacheTTL),
+		nominatim.WithRateLimit(flags.rate, 1),
+	}
+}
+
+// autocomplete holds the state of the interactive subcommand.
+type autocomplete struct {
+	client     nominatim.SearchHandler
+	flags      *commonFlags
+	bias       *nominatim.LocationBias
+	out        io.Writer
+	terminal   bool
+	text       []rune
+	candidates []nominatim.Result
+	searched   string
+	err        error
+	cancel     context.CancelFunc
+}
+
+// searchOutcome holds the outcome of a search of the given text.
+type searchOutcome struct {
+	text    string
+	results []nominatim.Result
+	err     error
+}
+
+// runInteractive runs the interactive subcommand, searching the text typed by the user once they stop typing for a
+// while, through nominatim.NewAutocompleteQuery, showing the candidates ranked by their importance. Enter selects the
+// best candidate, written in the output format, while Escape or the end of the input quits without selecting any.
+func runInteractive(ctx context.Context, client nominatim.Client, flags *commonFlags, args []string, stdin io.Reader, stdout io.Writer) error {
+	if len(args) > 0 {
+		return fmt.Errorf("%w: no arguments are accepted", errUsage)
+	}
+	a := &autocomplete{client: client, flags: flags, out: stdout}
+	if flags.near != "" {
+		bias, err := parseNear(flags.near)
+		if err != nil {
+			return err
+		}
+		a.bias = &bias
+	}
+	restore, terminal := rawTerminal(stdin)
+	defer restore()
+	a.terminal = terminal
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	return a.run(ctx, readKeys(ctx, stdin))
+}
+
+// run handles the given keys until the user selects a candidate or quits.
+func (a *autocomplete) run(ctx context.Context, keys <-chan rune) error {
+	defer a.cancelSearch()
+	outcomes := make(chan searchOutcome, 1)
+	debounce := time.NewTimer(time.Hour)
+	debounce.Stop()
+	pending, searching := false, false
+	a.render()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case key, ok := <-keys:
+			if !ok {
+				if string(a.text) != a.searched {
+					a.update(a.search(ctx, string(a.text)))
+					a.render()
+				}
+				return nil
+			}
+			switch key {
+			case keyEnter, keyReturn:
+				if string(a.text) != a.searched {
+					a.update(a.search(ctx, string(a.text)))
+				}
+				return a.selectBest()
+			case keyEscape, keyInterrupt, keyEOF:
+				return nil
+			case keyDelete, keyBackspace:
+				if len(a.text) > 0 {
+					a.text = a.text[:len(a.text)-1]
+				}
+			default:
+				if !unicode.IsPrint(key) {
+					continue
+				}
+				a.text = append(a.text, key)
+			}
+			pending = true
+			resetTimer(debounce, a.flags.debounce)
+			a.render()
+		case <-debounce.C:
+			pending = false
+			text := string(a.text)
+			if text == a.searched || searching {
+				pending = searching
+				continue
+			}
+			searching = true
+			go func(ctx context.Context, text string) {
+				outcomes <- a.search(ctx, text)
+			}(a.startSearch(ctx), text)
+		case outcome := <-outcomes:
+			searching = false
+			if outcome.text == string(a.text) {
+				a.update(outcome)
+				a.render()
+			}
+			if pending || outcome.text != string(a.text) {
+				resetTimer(debounce, 0)
+			}
+		}
+	}
+}
+
+// startSearch cancels the search in progress, if any, returning the context of a new one.
+func (a *autocomplete) startSearch(ctx context.Context) context.Context {
+	a.cancelSearch()
+	ctx, a.cancel = context.WithCancel(ctx)
+	return ctx
+}
+
+// cancelSearch cancels the search in progress, if any.
+func (a *autocomplete) cancelSearch() {
+	if a.cancel != nil {
+		a.cancel()
+	}
+}
+
+// search searches the given text, unless it is too short, ranking the results by their importance.
+func (a *autocomplete) search(ctx context.Context, text string) searchOutcome {
+	if len([]rune(strings.TrimSpace(text))) < minAutocompleteLength {
+		return searchOutcome{text: text}
+	}
+	query := nominatim.NewAutocompleteQuery(strings.TrimSpace(text), nominatim.LocationBias{})
+	query.Bias = a.bias
+	query.AcceptLanguage = a.flags.languages()
+	results, err := a.client.Search(ctx, *query)
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Importance > results[j].Importance
+	})
+	return searchOutcome{text: text, results: results, err: err}
+}
+
+// update updates the candidates with the given outcome.
+func (a *autocomplete) update(outcome searchOutcome) {
+	a.searched, a.candidates, a.err = outcome.text, outcome.results, outcome.err
+	if errors.Is(outcome.err, nominatim.ErrNoResults) {
+		a.err = nil
+	}
+}
+
+// render shows the typed text followed by the candidates, clearing the terminal first, if any.
+func (a *autocomplete) render() {
+	var b strings.Builder
+	if a.terminal {
+		b.WriteString(ansiClear)
+	}
+	b.WriteString("> " + string(a.text) + "\n")
+	if a.err != nil {
+		b.WriteString("  " + a.err.Error() + "\n")
+	}
+	for i, candidate := range a.candidates {
+		b.WriteString("  " + strconv.Itoa(i+1) + ". " + candidate.DisplayName + "\n")
+	}
+	if a.terminal {
+		b.WriteString("\033[1;" + strconv.Itoa(len(a.text)+3) + "H")
+	}
+	fmt.Fprint(a.out, b.String())
+}
+
+// selectBest writes the best candidate in the output format.
+func (a *autocomplete) selectBest() error {
+	if a.err != nil {
+		return a.err
+	}
+	if len(a.candidates) == 0 {
+		return nominatim.ErrNoResults
+	}
+	if a.terminal {
+		fmt.Fprint(a.out, ansiClear)
+	}
+	return writeResult(a.out, a.flags.output, a.candidates[0])
+}
+
+// resetTimer resets the given timer to the given duration, dropping its expiration if not received yet.
+func resetTimer(timer *time.Timer, d time.Duration) {
+	if !timer.Stop() {
+		select {
+		case <-timer.C:
+		default:
+		}
+	}
+	timer.Reset(d)
+}
+
+// readKeys reads the keys from the given input, closing the channel at its end, or once the context is done.
+func readKeys(ctx context.Context, in io.Reader) <-chan rune {
+	keys := make(chan rune)
+	go func() {
+		defer close(keys)
+		reader := bufio.NewReader(in)
+		for {
+			key, _, err := reader.ReadRune()
+			if err != nil {
+				return
+			}
+			select {
+			case keys <- key:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return keys
+}
+
+// parseNear parses the given location, as lat,lon.
+func parseNear(near string) (nominatim.LocationBias, error) {
+	coordinates := strings.Split(near, ",")
+	if len(coordinates) != 2 {
+		return nominatim.LocationBias{}, fmt.Errorf("%w: invalid location %q, as lat,lon", errUsage, near)
+	}
+	query := nominatim.NewReverseQuery(strings.TrimSpace(coordinates[0]), strings.TrimSpace(coordinates[1]))
+	if err := query.Validate(); err != nil {
+		return nominatim.LocationBias{}, fmt.Errorf("%w: %v", errUsage, err)
+	}
+	point, err := query.Point()
+	if err != nil {
+		return nominatim.LocationBias{}, fmt.Errorf("%w: %v", errUsage, err)
+	}
+	return nominatim.LocationBias{Latitude: point.Lat, Longitude: point.Lon}, nil
+}
+
+// rawTerminal switches the given input, when it is a terminal, to unbuffered input without echo, through stty, so
+// every key is read as it is typed, returning the function restoring it and whether it is a terminal.
+func rawTerminal(in io.Reader) (func(), bool) {
+	noop := func() {}
+	file, ok := in.(*os.File)
+	if !ok {
+		return noop, false
+	}
+	info, err := file.Stat()
+	if err != nil || info.Mode()&os.ModeCharDevice == 0 {
+		return noop, false
+	}
+	saved, err := stty(file, "-g")
+	if err != nil {
+		return noop, true
+	}
+	if _, err := stty(file, "-icanon", "-echo", "min", "1"); err != nil {
+		return noop, true
+	}
+	return func() {
+		_, _ = stty(file, strings.TrimSpace(saved))
+	}, true
+}
+
+// stty runs stty with the given arguments on the given terminal.
+func stty(terminal *os.File, args ...string) (string, error) {
+	cmd := exec.Command("stty", args...)
+	cmd.Stdin = terminal
+	out, err := cmd.Output()
+	return string(out), err
+}
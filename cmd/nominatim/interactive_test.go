@@ -0,0 +1,129 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"github.com/diegohordi/nominatim"
+	"github.com/diegohordi/nominatim/nominatimtest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func Test_run_interactive(t *testing.T) {
+	tests := []struct {
+		name         string
+		args         []string
+		input        string
+		wantCode     int
+		wantOutput   []string
+		wantSelected string
+		wantRequests int
+	}{
+		{
+			name:         "should search once the typing is done and select the best candidate",
+			input:        "torre de belém\n",
+			wantOutput:   []string{"> torre de belém\n"},
+			wantSelected: "Torre de Belém",
+			wantRequests: 1,
+		},
+		{
+			name:         "should handle the deleted keys",
+			args:         []string{"--near", "38.7223,-9.1393"},
+			input:        "aliadox\x7f\x7fos\r",
+			wantOutput:   []string{"> aliadox\n", "> aliad\n", "> aliados\n"},
+			wantSelected: "Avenida dos Aliados",
+			wantRequests: 1,
+		},
+		{
+			name:         "should show the candidates ranked by their importance at the end of the input",
+			input:        "lisboa",
+			wantOutput:   []string{"> lisboa\n  1. Torre de Belém", "  2. Praça do Comércio", "  3. Avenida da República"},
+			wantRequests: 1,
+		},
+		{
+			name:     "should quit without selecting any candidate",
+			input:    "lisboa\x1b",
+			wantCode: exitOK,
+		},
+		{
+			name:     "should fail to select nothing found",
+			input:    "hospital\n",
+			wantCode: exitError,
+		},
+		{
+			name:     "should not search a short text",
+			input:    "li\n",
+			wantCode: exitError,
+		},
+		{
+			name:     "should fail due to an invalid location",
+			args:     []string{"--near", "38.7223"},
+			wantCode: exitUsage,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			server := nominatimtest.NewServer()
+			t.Cleanup(server.Close)
+			stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+			args := append([]string{"interactive", "--base-url", server.URL, "--debounce", "1h"}, tt.args...)
+			if code := run(context.TODO(), args, strings.NewReader(tt.input), stdout, stderr); code != tt.wantCode {
+				t.Fatalf("run() code = %v, want %v, stderr = %s", code, tt.wantCode, stderr)
+			}
+			for _, want := range tt.wantOutput {
+				if !strings.Contains(stdout.String(), want) {
+					t.Errorf("run() output = %q, want %q", stdout.String(), want)
+				}
+			}
+			if tt.wantRequests > 0 && server.Requests() != tt.wantRequests {
+				t.Errorf("run() requests = %v, want %v", server.Requests(), tt.wantRequests)
+			}
+			if tt.wantSelected == "" {
+				return
+			}
+			selected := stdout.String()[strings.Index(stdout.String(), "\n{\n")+1:]
+			result := nominatim.Result{}
+			if err := json.Unmarshal([]byte(selected), &result); err != nil {
+				t.Fatalf("run() selected = %s, error = %v", selected, err)
+			}
+			if result.Name != tt.wantSelected {
+				t.Errorf("run() selected = %v, want %v", result.Name, tt.wantSelected)
+			}
+		})
+	}
+}
+
+func Test_autocomplete_debounce(t *testing.T) {
+	server := nominatimtest.NewServer()
+	t.Cleanup(server.Close)
+	client := nominatim.NewClient(server.URL, nil)
+	stdout := &bytes.Buffer{}
+	flags := &commonFlags{debounce: 20 * time.Millisecond, output: outputJSON}
+	a := &autocomplete{client: client, flags: flags, out: stdout}
+	keys := make(chan rune)
+	done := make(chan error, 1)
+	go func() {
+		done <- a.run(context.TODO(), keys)
+	}()
+	for _, key := range "porto" {
+		keys <- key
+	}
+	deadline := time.Now().Add(time.Second)
+	for server.Requests() == 0 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	close(keys)
+	if err := <-done; err != nil {
+		t.Fatalf("run() error = %v", err)
+	}
+	if server.Requests() != 1 {
+		t.Errorf("run() requests = %v, want 1", server.Requests())
+	}
+	if !strings.Contains(stdout.String(), "  1. Avenida dos Aliados") {
+		t.Errorf("run() output = %q, want the candidates", stdout.String())
+	}
+}
@@ -0,0 +1,228 @@
+// Command nominatim queries a Nominatim API from the command line, through the search, reverse, lookup and status
+// subcommands, writing the results as JSON, an aligned table, CSV or a GeoJSON FeatureCollection, as given through
+// --output, so they can be piped into spreadsheets or dropped onto map tools. Failing with a non-zero exit code
+// whenever the server does, it also serves as a smoke test for self-hosted instances:
+//
+//	nominatim search --base-url http://localhost:8080 --limit 5 --countrycodes pt "avenida da república, lisboa"
+//	nominatim reverse --output table 38.6945252 -9.3221278
+//	nominatim lookup W683827991 N455680276
+//	nominatim status
+//
+// The interactive subcommand autocompletes the text as it is typed, showing the candidates found once the typing
+// pauses, and writing the best one when enter is pressed:
+//
+//	nominatim interactive --near 38.7223,-9.1393
+//
+// The base URL can also be given through the NOMINATIM_URL environment variable, and the public Nominatim API is used
+// when none is given.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"github.com/diegohordi/nominatim"
+)
+
+const (
+	exitOK    = 0
+	exitError = 1
+	exitUsage = 2
+)
+
+// envBaseURL is the environment variable holding the base URL used when none is given through the flags.
+const envBaseURL = "NOMINATIM_URL"
+
+// usage describes the subcommands.
+const usage = `Usage: nominatim <command> [flags] [arguments]
+
+Commands:
+  search   [flags] <query>          looks up a location from a textual description or address
+  reverse  [flags] <lat> <lon>      generates an address from a latitude and longitude
+  lookup   [flags] <osm id>...      retrieves the details of OSM objects, as N123, W456 or R789
+  status   [flags]                  checks if the server and its database are running
+  interactive [flags]               autocompletes the text typed, selecting the best candidate on enter
+
+Run "nominatim <command> -h" for the flags of each command.
+`
+
+// command runs a subcommand with the given client and arguments, reading its input, if any, from stdin and writing
+// its output to stdout.
+type command func(ctx context.Context, client nominatim.Client, flags *commonFlags, args []string, stdin io.Reader, stdout io.Writer) error
+
+// commands holds the subcommands by name, along with the functions registering their specific flags and client
+// options, if any.
+var commands = map[string]struct {
+	flags   func(set *flag.FlagSet, flags *commonFlags)
+	options func(flags *commonFlags) []nominatim.Option
+	run     command
+}{
+	"search":      {flags: searchFlags, run: runSearch},
+	"reverse":     {run: runReverse},
+	"lookup":      {run: runLookup},
+	"status":      {run: runStatus},
+	"interactive": {flags: interactiveFlags, options: interactiveOptions, run: runInteractive},
+}
+
+// errUsage is returned when the arguments of a subcommand are invalid.
+var errUsage = errors.New("invalid arguments")
+
+// commonFlags holds the flags of the subcommands.
+type commonFlags struct {
+	baseURL      string
+	userAgent    string
+	timeout      time.Duration
+	lang         string
+	output       string
+	limit        int
+	countryCodes string
+	debounce     time.Duration
+	rate         float64
+	near         string
+}
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	code := run(ctx, os.Args[1:], os.Stdin, os.Stdout, os.Stderr)
+	stop()
+	os.Exit(code)
+}
+
+// run runs the subcommand given by the arguments, returning the exit code.
+func run(ctx context.Context, args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	if len(args) == 0 || args[0] == "-h" || args[0] == "--help" || args[0] == "help" {
+		fmt.Fprint(stderr, usage)
+		return exitUsage
+	}
+	cmd, ok := commands[args[0]]
+	if !ok {
+		fmt.Fprintf(stderr, "nominatim: unknown command %q\n\n%s", args[0], usage)
+		return exitUsage
+	}
+	set := flag.NewFlagSet("nominatim "+args[0], flag.ContinueOnError)
+	set.SetOutput(stderr)
+	flags := &commonFlags{}
+	set.StringVar(&flags.baseURL, "base-url", os.Getenv(envBaseURL), "base URL of the Nominatim API, the public one by default")
+	set.StringVar(&flags.userAgent, "user-agent", "", "User-Agent identifying the application, as required by the public API")
+	set.DurationVar(&flags.timeout, "timeout", 10*time.Second, "timeout of each request")
+	set.StringVar(&flags.lang, "lang", "en", "comma-separated languages of the results, in order of preference")
+	set.StringVar(&flags.output, "output", outputJSON, "output format: json, table, csv or geojson")
+	if cmd.flags != nil {
+		cmd.flags(set, flags)
+	}
+	if err := set.Parse(args[1:]); err != nil {
+		return exitUsage
+	}
+	if !validOutput(flags.output) {
+		fmt.Fprintf(stderr, "nominatim %s: unknown output format %q\n", args[0], flags.output)
+		set.Usage()
+		return exitUsage
+	}
+	opts := []nominatim.Option{nominatim.WithUserAgent(flags.userAgent), nominatim.WithTimeout(flags.timeout),
+		nominatim.WithQueryValidation()}
+	if cmd.options != nil {
+		opts = append(opts, cmd.options(flags)...)
+	}
+	client := nominatim.NewClientWithOptions(flags.baseURL, opts...)
+	err := cmd.run(ctx, client, flags, set.Args(), stdin, stdout)
+	var validationErr nominatim.ValidationError
+	switch {
+	case errors.Is(err, errUsage) || errors.As(err, &validationErr):
+		fmt.Fprintf(stderr, "nominatim %s: %v\n", args[0], err)
+		set.Usage()
+		return exitUsage
+	case err != nil:
+		fmt.Fprintf(stderr, "nominatim %s: %v\n", args[0], err)
+		return exitError
+	}
+	return exitOK
+}
+
+// searchFlags registers the flags specific to the search subcommand.
+func searchFlags(set *flag.FlagSet, flags *commonFlags) {
+	set.IntVar(&flags.limit, "limit", 10, "maximum number of results, up to 50")
+	set.StringVar(&flags.countryCodes, "countrycodes", "", "comma-separated country codes the results are restricted to, as pt,es")
+}
+
+// languages returns the languages given through the flags.
+func (f *commonFlags) languages() []string {
+	return splitList(f.lang)
+}
+
+// runSearch runs the search subcommand.
+func runSearch(ctx context.Context, client nominatim.Client, flags *commonFlags, args []string, _ io.Reader, stdout io.Writer) error {
+	if len(args) == 0 {
+		return fmt.Errorf("%w: a query is required", errUsage)
+	}
+	query := nominatim.NewSearchQuery(
+		nominatim.WithFreeForm(strings.Join(args, " ")),
+		nominatim.WithLimit(flags.limit),
+		nominatim.WithLanguages(flags.languages()...),
+		nominatim.WithCountryCodes(splitList(flags.countryCodes)...),
+	)
+	results, err := client.Search(ctx, *query)
+	if err != nil {
+		return err
+	}
+	return writeResults(stdout, flags.output, results)
+}
+
+// runReverse runs the reverse subcommand.
+func runReverse(ctx context.Context, client nominatim.Client, flags *commonFlags, args []string, _ io.Reader, stdout io.Writer) error {
+	if len(args) != 2 {
+		return fmt.Errorf("%w: a latitude and a longitude are required", errUsage)
+	}
+	query := nominatim.NewReverseQuery(args[0], args[1])
+	query.AcceptLanguage = flags.languages()
+	result, err := client.Reverse(ctx, *query)
+	if err != nil {
+		return err
+	}
+	return writeResult(stdout, flags.output, result)
+}
+
+// runLookup runs the lookup subcommand, looking up any number of OSM IDs through nominatim.LookupMany.
+func runLookup(ctx context.Context, client nominatim.Client, flags *commonFlags, args []string, _ io.Reader, stdout io.Writer) error {
+	if len(args) == 0 {
+		return fmt.Errorf("%w: at least one OSM ID is required", errUsage)
+	}
+	results, err := nominatim.LookupMany(ctx, client, args)
+	if err != nil {
+		return err
+	}
+	return writeResults(stdout, flags.output, results)
+}
+
+// runStatus runs the status subcommand, failing when the server reports a non-zero status.
+func runStatus(ctx context.Context, client nominatim.Client, flags *commonFlags, args []string, _ io.Reader, stdout io.Writer) error {
+	if len(args) > 0 {
+		return fmt.Errorf("%w: no arguments are accepted", errUsage)
+	}
+	status, err := client.CheckStatus(ctx)
+	var statusErr nominatim.StatusError
+	if err != nil && !errors.As(err, &statusErr) {
+		return err
+	}
+	if err := writeStatus(stdout, flags.output, status); err != nil {
+		return err
+	}
+	return err
+}
+
+// splitList splits the given comma-separated list, skipping the empty items.
+func splitList(list string) []string {
+	items := make([]string, 0)
+	for _, item := range strings.Split(list, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
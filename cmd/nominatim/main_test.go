@@ -0,0 +1,115 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"github.com/diegohordi/nominatim"
+	"github.com/diegohordi/nominatim/nominatimtest"
+	"testing"
+)
+
+func Test_run(t *testing.T) {
+	server := nominatimtest.NewServer()
+	t.Cleanup(server.Close)
+	tests := []struct {
+		name      string
+		args      []string
+		wantCode  int
+		wantNames []string
+	}{
+		{
+			name:      "should search",
+			args:      []string{"search", "--base-url", server.URL, "--limit", "1", "--countrycodes", "pt", "praça", "do", "comércio"},
+			wantNames: []string{"Praça do Comércio"},
+		},
+		{
+			name:     "should fail to search nothing",
+			args:     []string{"search", "--base-url", server.URL},
+			wantCode: exitUsage,
+		},
+		{
+			name:     "should fail to search an invalid country code",
+			args:     []string{"search", "--base-url", server.URL, "--countrycodes", "prt", "lisboa"},
+			wantCode: exitUsage,
+		},
+		{
+			name:     "should fail to search not finding anything",
+			args:     []string{"search", "--base-url", server.URL, "--countrycodes", "es", "lisboa"},
+			wantCode: exitError,
+		},
+		{
+			name:      "should reverse geocode",
+			args:      []string{"reverse", "--base-url", server.URL, "--lang", "pt,en", "41.1486", "-8.6110"},
+			wantNames: []string{"Avenida dos Aliados"},
+		},
+		{
+			name:     "should fail to reverse geocode invalid coordinates",
+			args:     []string{"reverse", "--base-url", server.URL, "98", "-8.6110"},
+			wantCode: exitUsage,
+		},
+		{
+			name:      "should lookup",
+			args:      []string{"lookup", "--base-url", server.URL, "N455680276", "W24961587"},
+			wantNames: []string{"Farmácia Estácio", "Torre de Belém"},
+		},
+		{
+			name:     "should fail to lookup invalid IDs",
+			args:     []string{"lookup", "--base-url", server.URL, "X1"},
+			wantCode: exitUsage,
+		},
+		{
+			name: "should check the status",
+			args: []string{"status", "--base-url", server.URL},
+		},
+		{
+			name:     "should fail to reach the server",
+			args:     []string{"status", "--base-url", "http://127.0.0.1:1"},
+			wantCode: exitError,
+		},
+		{
+			name:     "should fail due to an unknown flag",
+			args:     []string{"status", "--unknown"},
+			wantCode: exitUsage,
+		},
+		{
+			name:     "should fail due to an unknown command",
+			args:     []string{"details"},
+			wantCode: exitUsage,
+		},
+		{
+			name:     "should fail due to no command",
+			wantCode: exitUsage,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+			if code := run(context.TODO(), tt.args, nil, stdout, stderr); code != tt.wantCode {
+				t.Fatalf("run() code = %v, want %v, stderr = %s", code, tt.wantCode, stderr)
+			}
+			if len(tt.wantNames) == 0 {
+				return
+			}
+			results := make([]nominatim.Result, 0)
+			if len(tt.wantNames) == 1 && tt.args[0] == "reverse" {
+				results = append(results, nominatim.Result{})
+				if err := json.Unmarshal(stdout.Bytes(), &results[0]); err != nil {
+					t.Fatalf("run() output = %s, error = %v", stdout, err)
+				}
+			} else if err := json.Unmarshal(stdout.Bytes(), &results); err != nil {
+				t.Fatalf("run() output = %s, error = %v", stdout, err)
+			}
+			if len(results) != len(tt.wantNames) {
+				t.Fatalf("run() results = %v, want %v", len(results), len(tt.wantNames))
+			}
+			for i, result := range results {
+				if result.Name != tt.wantNames[i] {
+					t.Errorf("run() name = %v, want %v", result.Name, tt.wantNames[i])
+				}
+			}
+		})
+	}
+}
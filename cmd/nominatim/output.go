@@ -0,0 +1,123 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/diegohordi/nominatim"
+)
+
+// Output formats of the subcommands.
+const (
+	outputJSON    = "json"
+	outputTable   = "table"
+	outputCSV     = "csv"
+	outputGeoJSON = "geojson"
+)
+
+// resultWriters holds the functions writing the results by output format.
+var resultWriters = map[string]func(w io.Writer, results []nominatim.Result) error{
+	outputJSON: func(w io.Writer, results []nominatim.Result) error {
+		return writeJSON(w, results)
+	},
+	outputTable:   writeResultsTable,
+	outputCSV:     writeResultsCSV,
+	outputGeoJSON: writeResultsGeoJSON,
+}
+
+// validOutput checks if the given output format is supported.
+func validOutput(output string) bool {
+	_, ok := resultWriters[output]
+	return ok
+}
+
+// writeResults writes the given results in the given output format.
+func writeResults(w io.Writer, output string, results []nominatim.Result) error {
+	return resultWriters[output](w, results)
+}
+
+// writeResult writes the given result in the given output format, as JSON object rather than array.
+func writeResult(w io.Writer, output string, result nominatim.Result) error {
+	if output == outputJSON {
+		return writeJSON(w, result)
+	}
+	return writeResults(w, output, []nominatim.Result{result})
+}
+
+// writeStatus writes the given status in the given output format, either as JSON, a table or CSV.
+func writeStatus(w io.Writer, output string, status nominatim.Status) error {
+	header := []string{"status", "message", "data_updated", "software_version", "database_version"}
+	row := []string{strconv.Itoa(status.Status), status.Message, status.DataUpdatedRaw, status.SoftwareVersion,
+		status.DatabaseVersion}
+	switch output {
+	case outputTable:
+		return writeTable(w, header, [][]string{row})
+	case outputCSV:
+		return writeCSV(w, header, [][]string{row})
+	case outputGeoJSON:
+		return fmt.Errorf("%w: the status can't be written as %s", errUsage, output)
+	}
+	return writeJSON(w, status)
+}
+
+// writeJSON writes the given value as indented JSON.
+func writeJSON(w io.Writer, v interface{}) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
+
+// writeResultsTable writes the given results as a table aligned by tabs, with the same columns as CSV.
+func writeResultsTable(w io.Writer, results []nominatim.Result) error {
+	rows := make([][]string, 0, len(results))
+	for _, result := range results {
+		row, err := nominatim.ResultRecord(result)
+		if err != nil {
+			return err
+		}
+		rows = append(rows, row)
+	}
+	return writeTable(w, nominatim.DefaultResultColumns, rows)
+}
+
+// writeResultsCSV writes the given results as CSV, with a header.
+func writeResultsCSV(w io.Writer, results []nominatim.Result) error {
+	return nominatim.WriteResultsCSV(w, results)
+}
+
+// writeTable writes the given header and rows as a table aligned by tabs.
+func writeTable(w io.Writer, header []string, rows [][]string) error {
+	table := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	for _, row := range append([][]string{header}, rows...) {
+		for i, cell := range row {
+			if i > 0 {
+				fmt.Fprint(table, "\t")
+			}
+			fmt.Fprint(table, cell)
+		}
+		fmt.Fprintln(table)
+	}
+	return table.Flush()
+}
+
+// writeCSV writes the given header and rows as CSV.
+func writeCSV(w io.Writer, header []string, rows [][]string) error {
+	writer := csv.NewWriter(w)
+	if err := writer.WriteAll(append([][]string{header}, rows...)); err != nil {
+		return err
+	}
+	return writer.Error()
+}
+
+// writeResultsGeoJSON writes the given results as a GeoJSON FeatureCollection, with a feature per result.
+func writeResultsGeoJSON(w io.Writer, results []nominatim.Result) error {
+	collection, err := nominatim.ResultsToGeoJSON(results)
+	if err != nil {
+		return err
+	}
+	return writeJSON(w, json.RawMessage(collection))
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"github.com/diegohordi/nominatim/nominatimtest"
+	"strings"
+	"testing"
+)
+
+func Test_run_output(t *testing.T) {
+	server := nominatimtest.NewServer()
+	t.Cleanup(server.Close)
+	tests := []struct {
+		name     string
+		args     []string
+		wantCode int
+		want     string
+	}{
+		{
+			name: "should write the results as a table",
+			args: []string{"search", "--base-url", server.URL, "--output", "table", "torre de belém"},
+			want: "place_id  osm_id     category  type        lat      lon      importance  display_name\n" +
+				"3         W24961587  tourism   attraction  38.6916  -9.2160  0.6         Torre de Belém, Belém, Lisboa, 1400-038, Portugal\n",
+		},
+		{
+			name: "should write the results as CSV",
+			args: []string{"lookup", "--base-url", server.URL, "--output", "csv", "W24961587"},
+			want: "place_id,osm_id,category,type,lat,lon,importance,display_name\n" +
+				"3,W24961587,tourism,attraction,38.6916,-9.2160,0.6,\"Torre de Belém, Belém, Lisboa, 1400-038, Portugal\"\n",
+		},
+		{
+			name: "should write the result as GeoJSON",
+			args: []string{"reverse", "--base-url", server.URL, "--output", "geojson", "38.6916", "-9.2160"},
+			want: `{"type":"FeatureCollection","features":[{"type":"Feature","geometry":{"type":"Point","coordinates":` +
+				`[-9.216,38.6916]},"properties":{"place_id":3,"osm_type":"way","osm_id":24961587,"place_rank":26,` +
+				`"category":"tourism","type":"attraction","importance":0.6,"name":"Torre de Belém",` +
+				`"display_name":"Torre de Belém, Belém, Lisboa, 1400-038, Portugal","address":{"city":"Lisboa",` +
+				`"country":"Portugal","country_code":"pt","postcode":"1400-038","suburb":"Belém"}}}]}`,
+		},
+		{
+			name: "should write the status as CSV",
+			args: []string{"status", "--base-url", server.URL, "--output", "csv"},
+			want: "status,message,data_updated,software_version,database_version\n" +
+				"0,OK,2021-11-25T17:16:32Z,4.2.3,4.2.3\n",
+		},
+		{
+			name:     "should fail to write the status as GeoJSON",
+			args:     []string{"status", "--base-url", server.URL, "--output", "geojson"},
+			wantCode: exitUsage,
+		},
+		{
+			name:     "should fail due to an unknown output format",
+			args:     []string{"search", "--base-url", server.URL, "--output", "xml", "lisboa"},
+			wantCode: exitUsage,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+			if code := run(context.TODO(), tt.args, nil, stdout, stderr); code != tt.wantCode {
+				t.Fatalf("run() code = %v, want %v, stderr = %s", code, tt.wantCode, stderr)
+			}
+			got := stdout.String()
+			if strings.HasPrefix(tt.want, "{") {
+				compacted := &bytes.Buffer{}
+				if err := json.Compact(compacted, stdout.Bytes()); err != nil {
+					t.Fatalf("run() output = %s, error = %v", stdout, err)
+				}
+				got = compacted.String()
+			}
+			if tt.want != "" && got != tt.want {
+				t.Errorf("run() output = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,65 @@
+package nominatim
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+)
+
+// CacheCodec encodes the responses before they are cached, and decodes them back, as compressing them, cutting the
+// memory used by caches holding large amounts of responses, as the ones with polygons. Failing to encode a response
+// skips caching it, while failing to decode it is handled as a miss.
+type CacheCodec interface {
+
+	// Encode encodes the given value to be cached.
+	Encode(value []byte) ([]byte, error)
+
+	// Decode decodes the given cached value.
+	Decode(value []byte) ([]byte, error)
+}
+
+// WithCacheCodec makes the client encode the responses through the given CacheCodec before caching them, when a
+// cache is given through WithCache. The responses cached through different codecs can't be read by each other.
+func WithCacheCodec(codec CacheCodec) Option {
+	return func(d *defaultClient) {
+		d.cacheCodec = codec
+	}
+}
+
+// GzipCodec is a CacheCodec compressing the cached responses through gzip, at the given compression level, or at
+// the default one, if zero.
+type GzipCodec struct {
+	Level int
+}
+
+// Encode compresses the given value.
+func (c GzipCodec) Encode(value []byte) ([]byte, error) {
+	level := c.Level
+	if level == 0 {
+		level = gzip.DefaultCompression
+	}
+	buf := &bytes.Buffer{}
+	writer, err := gzip.NewWriterLevel(buf, level)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := writer.Write(value); err != nil {
+		return nil, err
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Decode decompresses the given value.
+func (c GzipCodec) Decode(value []byte) ([]byte, error) {
+	reader, err := gzip.NewReader(bytes.NewReader(value))
+	if err != nil {
+		return nil, err
+	}
+	defer func(reader io.ReadCloser) {
+		_ = reader.Close()
+	}(reader)
+	return io.ReadAll(reader)
+}
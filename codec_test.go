@@ -0,0 +1,63 @@
+package nominatim_test
+
+import (
+	"bytes"
+	"context"
+	"github.com/diegohordi/nominatim"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestGzipCodec(t *testing.T) {
+	value := bytes.Repeat([]byte(`{"lat":"38.7223","lon":"-9.1393"}`), 100)
+	tests := []struct {
+		name  string
+		codec nominatim.GzipCodec
+	}{
+		{name: "should compress at the default level", codec: nominatim.GzipCodec{}},
+		{name: "should compress at the given level", codec: nominatim.GzipCodec{Level: 9}},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			encoded, err := tt.codec.Encode(value)
+			if err != nil || len(encoded) >= len(value) {
+				t.Fatalf("Encode() got %d bytes, %v, want less than %d", len(encoded), err, len(value))
+			}
+			decoded, err := tt.codec.Decode(encoded)
+			if err != nil || !bytes.Equal(decoded, value) {
+				t.Errorf("Decode() got = %s, %v, want the original value", decoded, err)
+			}
+		})
+	}
+	if _, err := (nominatim.GzipCodec{}).Decode(value); err == nil {
+		t.Errorf("Decode() error = nil, want an error for invalid values")
+	}
+}
+
+func Test_WithCacheCodec(t *testing.T) {
+	transport := TransportFunc(func(req *http.Request) (*http.Response, error) {
+		return statusFixture(http.StatusOK, mustLoadValidSearchResults(t))()
+	})
+	cache := nominatim.NewLRUCache(10)
+	d := nominatim.NewClient("http://localhost:8080", nil, nominatim.WithTransport(transport),
+		nominatim.WithCache(cache, time.Hour), nominatim.WithCacheCodec(nominatim.GzipCodec{}))
+	query := nominatim.NewSearchQuery(nominatim.WithLanguages("en"))
+	query.FreeFormQuery = "lisboa"
+	want, err := d.Search(context.TODO(), *query)
+	if err != nil {
+		t.Fatal(err)
+	}
+	key := "http://localhost:8080/" + nominatim.EndpointSearch + "?" + nominatim.EncodeQuery(query.Encode())
+	value, ok, _ := cache.Get(context.TODO(), key)
+	if !ok || bytes.Equal(value, mustLoadValidSearchResults(t)) {
+		t.Fatalf("Get() got = %v, want the compressed response", ok)
+	}
+	metadata := &nominatim.ResponseMetadata{}
+	got, err := d.Search(nominatim.WithResponseMetadata(context.TODO(), metadata), *query)
+	if err != nil || !metadata.Cached || len(got) != len(want) {
+		t.Errorf("Search() got = %v results, %v, cached %v, want %v results from the cache", len(got), err, metadata.Cached, len(want))
+	}
+}
@@ -0,0 +1,58 @@
+// Package compresscodec implements nominatim.CacheCodec on top of zstd and snappy, compressing the cached responses
+// from Nominatim API, so caches holding millions of them, as the ones with polygons, need less memory.
+package compresscodec
+
+import (
+	"github.com/diegohordi/nominatim"
+	"github.com/klauspost/compress/s2"
+	"github.com/klauspost/compress/zstd"
+)
+
+// Zstd is a nominatim.CacheCodec compressing the cached responses through zstd, trading some CPU for the best
+// compression ratio. It is safe for concurrent use.
+type Zstd struct {
+	encoder *zstd.Encoder
+	decoder *zstd.Decoder
+}
+
+var _ nominatim.CacheCodec = (*Zstd)(nil)
+
+// NewZstd creates a Zstd codec, compressing at the given level.
+func NewZstd(level zstd.EncoderLevel) (*Zstd, error) {
+	encoder, err := zstd.NewWriter(nil, zstd.WithEncoderLevel(level))
+	if err != nil {
+		return nil, err
+	}
+	decoder, err := zstd.NewReader(nil)
+	if err != nil {
+		_ = encoder.Close()
+		return nil, err
+	}
+	return &Zstd{encoder: encoder, decoder: decoder}, nil
+}
+
+// Encode compresses the given value.
+func (c *Zstd) Encode(value []byte) ([]byte, error) {
+	return c.encoder.EncodeAll(value, nil), nil
+}
+
+// Decode decompresses the given value.
+func (c *Zstd) Decode(value []byte) ([]byte, error) {
+	return c.decoder.DecodeAll(value, nil)
+}
+
+// Snappy is a nominatim.CacheCodec compressing the cached responses through snappy, trading some compression ratio
+// for speed. The values are compatible with any snappy implementation.
+type Snappy struct{}
+
+var _ nominatim.CacheCodec = Snappy{}
+
+// Encode compresses the given value.
+func (c Snappy) Encode(value []byte) ([]byte, error) {
+	return s2.EncodeSnappy(nil, value), nil
+}
+
+// Decode decompresses the given value.
+func (c Snappy) Decode(value []byte) ([]byte, error) {
+	return s2.Decode(nil, value)
+}
@@ -0,0 +1,50 @@
+package compresscodec_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/diegohordi/nominatim"
+	"github.com/diegohordi/nominatim/compresscodec"
+	"github.com/klauspost/compress/zstd"
+)
+
+func mustNewZstd(t *testing.T) *compresscodec.Zstd {
+	t.Helper()
+	codec, err := compresscodec.NewZstd(zstd.SpeedDefault)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return codec
+}
+
+func Test_Codecs(t *testing.T) {
+	value := bytes.Repeat([]byte(`{"type":"Polygon","coordinates":[[[-9.1393,38.7223],[-9.1394,38.7224]]]}`), 100)
+	tests := []struct {
+		name  string
+		codec nominatim.CacheCodec
+	}{
+		{name: "zstd", codec: mustNewZstd(t)},
+		{name: "snappy", codec: compresscodec.Snappy{}},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			encoded, err := tt.codec.Encode(value)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(encoded) >= len(value) {
+				t.Errorf("Encode() got %d bytes, want less than %d", len(encoded), len(value))
+			}
+			decoded, err := tt.codec.Decode(encoded)
+			if err != nil || !bytes.Equal(decoded, value) {
+				t.Errorf("Decode() got = %d bytes, %v, want the original value", len(decoded), err)
+			}
+			if _, err := tt.codec.Decode([]byte("not compressed")); err == nil {
+				t.Errorf("Decode() error = nil, want an error for invalid values")
+			}
+		})
+	}
+}
@@ -0,0 +1,10 @@
+module github.com/diegohordi/nominatim/compresscodec
+
+go 1.25
+
+require (
+	github.com/diegohordi/nominatim v0.0.0
+	github.com/klauspost/compress v1.20.1
+)
+
+replace github.com/diegohordi/nominatim => ../
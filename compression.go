@@ -0,0 +1,36 @@
+package nominatim
+
+import (
+	"net/http"
+	"strings"
+)
+
+const (
+	headerAcceptEncoding  = "Accept-Encoding"
+	headerContentEncoding = "Content-Encoding"
+	encodingGzip          = "gzip"
+)
+
+// WithCompression makes the client ask for gzip compressed responses, decompressing them itself, as the polygons and
+// the name details compress well, which matters across slow links. The http.Transport does it already unless its
+// DisableCompression is set, though custom Transports may not. The gzip compressed responses are decompressed
+// regardless of this option, and the limit of WithMaxResponseSize applies to their decompressed bodies.
+func WithCompression() Option {
+	return func(d *defaultClient) {
+		d.compression = true
+	}
+}
+
+// setAcceptEncoding asks for gzip compressed responses on the given request, if enabled, unless another encoding
+// was given through the headers.
+func (d defaultClient) setAcceptEncoding(req *http.Request) {
+	if d.compression && req.Header.Get(headerAcceptEncoding) == "" {
+		req.Header.Set(headerAcceptEncoding, encodingGzip)
+	}
+}
+
+// isCompressed reports whether the body of the given response is gzip compressed, as the http.Transport removes the
+// Content-Encoding of the responses it decompresses.
+func isCompressed(resp *http.Response) bool {
+	return strings.EqualFold(strings.TrimSpace(resp.Header.Get(headerContentEncoding)), encodingGzip)
+}
@@ -0,0 +1,105 @@
+package nominatim_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"github.com/diegohordi/nominatim"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func mustCompress(t *testing.T, body []byte) []byte {
+	t.Helper()
+	buf := &bytes.Buffer{}
+	writer := gzip.NewWriter(buf)
+	if _, err := writer.Write(body); err != nil {
+		t.Fatal(err)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func Test_WithCompression(t *testing.T) {
+	status := mustLoadValidStatus(t)
+	tests := []struct {
+		name               string
+		opts               []nominatim.Option
+		wantAcceptEncoding string
+		wantErr            error
+	}{
+		{
+			name: "should decompress the compressed responses by default",
+		},
+		{
+			name:               "should ask for compressed responses",
+			opts:               []nominatim.Option{nominatim.WithCompression()},
+			wantAcceptEncoding: "gzip",
+		},
+		{
+			name: "should keep the encoding given through the headers",
+			opts: []nominatim.Option{
+				nominatim.WithCompression(),
+				nominatim.WithDefaultHeaders(http.Header{"Accept-Encoding": {"gzip, deflate"}}),
+			},
+			wantAcceptEncoding: "gzip, deflate",
+		},
+		{
+			name:               "should limit the decompressed size",
+			opts:               []nominatim.Option{nominatim.WithCompression(), nominatim.WithMaxResponseSize(int64(len(status)) - 1)},
+			wantAcceptEncoding: "gzip",
+			wantErr:            nominatim.ErrResponseTooLarge,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			acceptEncoding := ""
+			transport := TransportFunc(func(req *http.Request) (*http.Response, error) {
+				acceptEncoding = req.Header.Get("Accept-Encoding")
+				resp := httptest.NewRecorder()
+				resp.Header().Set("Content-Encoding", "gzip")
+				resp.Body.Write(mustCompress(t, status))
+				return resp.Result(), nil
+			})
+			d := nominatim.NewClient("http://localhost:8080", nil, append(tt.opts, nominatim.WithTransport(transport))...)
+			got, err := d.CheckStatus(context.TODO())
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("CheckStatus() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if acceptEncoding != tt.wantAcceptEncoding {
+				t.Errorf("CheckStatus() sent Accept-Encoding = %q, want %q", acceptEncoding, tt.wantAcceptEncoding)
+			}
+			if tt.wantErr == nil && got.Message != "OK" {
+				t.Errorf("CheckStatus() got = %+v, want the status decoded", got)
+			}
+		})
+	}
+}
+
+func Test_WithCompression_DisabledTransportCompression(t *testing.T) {
+	status := mustLoadValidStatus(t)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Accept-Encoding") != "gzip" {
+			_, _ = w.Write(status)
+			return
+		}
+		w.Header().Set("Content-Encoding", "gzip")
+		_, _ = w.Write(mustCompress(t, status))
+	}))
+	t.Cleanup(server.Close)
+	httpClient := &http.Client{Transport: &http.Transport{DisableCompression: true}}
+	d := nominatim.NewClient(server.URL, httpClient, nominatim.WithCompression())
+	got, err := d.CheckStatus(context.TODO())
+	if err != nil {
+		t.Fatalf("CheckStatus() error = %v", err)
+	}
+	if got.Message != "OK" {
+		t.Errorf("CheckStatus() got = %+v, want the status decoded", got)
+	}
+}
@@ -0,0 +1,35 @@
+package nominatim
+
+import (
+	"context"
+)
+
+// WithMaxConcurrency limits the number of requests in flight from the client to the given number, independently of
+// the rate limit, protecting small self-hosted instances from connection floods caused by bursty traffic. Waiting for
+// a slot respects the context cancellation. Non-positive values mean no limit.
+func WithMaxConcurrency(n int) Option {
+	return func(d *defaultClient) {
+		d.semaphore = nil
+		if n > 0 {
+			d.semaphore = make(semaphore, n)
+		}
+	}
+}
+
+// semaphore limits the number of concurrent requests.
+type semaphore chan struct{}
+
+// acquire blocks until a slot is available or the context is done.
+func (s semaphore) acquire(ctx context.Context) error {
+	select {
+	case s <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// release frees a slot.
+func (s semaphore) release() {
+	<-s
+}
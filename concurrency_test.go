@@ -0,0 +1,61 @@
+package nominatim_test
+
+import (
+	"context"
+	"errors"
+	"github.com/diegohordi/nominatim"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func Test_MaxConcurrency(t *testing.T) {
+	var inFlight, maxInFlight int32
+	transport := TransportFunc(func(req *http.Request) (*http.Response, error) {
+		current := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+		for {
+			max := atomic.LoadInt32(&maxInFlight)
+			if current <= max || atomic.CompareAndSwapInt32(&maxInFlight, max, current) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+		return statusFixture(http.StatusOK, mustLoadValidStatus(t))()
+	})
+	d := nominatim.NewClient("http://localhost:8080", nil, nominatim.WithTransport(transport), nominatim.WithMaxConcurrency(2))
+
+	wg := sync.WaitGroup{}
+	for i := 0; i < 6; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := d.CheckStatus(context.TODO()); err != nil {
+				t.Errorf("CheckStatus() error = %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+	if got := atomic.LoadInt32(&maxInFlight); got != 2 {
+		t.Errorf("CheckStatus() max in flight = %v, want 2", got)
+	}
+
+	blocking := make(chan struct{})
+	defer close(blocking)
+	d = nominatim.NewClient("http://localhost:8080", nil, nominatim.WithMaxConcurrency(1), nominatim.WithTransport(
+		TransportFunc(func(req *http.Request) (*http.Response, error) {
+			<-blocking
+			return statusFixture(http.StatusOK, mustLoadValidStatus(t))()
+		})))
+	go func() {
+		_, _ = d.CheckStatus(context.TODO())
+	}()
+	time.Sleep(10 * time.Millisecond)
+	ctx, cancelFn := context.WithTimeout(context.TODO(), 10*time.Millisecond)
+	defer cancelFn()
+	if _, err := d.CheckStatus(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("CheckStatus() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+}
@@ -0,0 +1,77 @@
+package nominatim
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+const (
+	headerETag            = "ETag"
+	headerLastModified    = "Last-Modified"
+	headerIfNoneMatch     = "If-None-Match"
+	headerIfModifiedSince = "If-Modified-Since"
+)
+
+type conditionalEntryKey struct{}
+
+// WithConditionalRequests makes the client keep the cached responses holding validators, as ETag or Last-Modified,
+// for the given period after they expire, revalidating them through conditional requests. A 304 Not Modified
+// response refreshes the cached one, saving the bandwidth of sending it again. It requires a cache, given through
+// WithCache.
+func WithConditionalRequests(keep time.Duration) Option {
+	return func(d *defaultClient) {
+		d.conditionalKeep = keep
+	}
+}
+
+// withConditionalEntry returns a copy of the given context that makes the request conditional on the given entry.
+func withConditionalEntry(ctx context.Context, entry cacheEntry) context.Context {
+	return context.WithValue(ctx, conditionalEntryKey{}, entry)
+}
+
+// conditionalEntry retrieves the entry the request is conditional on, if any.
+func conditionalEntry(ctx context.Context) (cacheEntry, bool) {
+	entry, ok := ctx.Value(conditionalEntryKey{}).(cacheEntry)
+	return entry, ok
+}
+
+// setConditionalHeaders makes the given request conditional on the entry held by its context, if any.
+func setConditionalHeaders(req *http.Request) {
+	entry, ok := conditionalEntry(req.Context())
+	if !ok {
+		return
+	}
+	if entry.etag != "" {
+		req.Header.Set(headerIfNoneMatch, entry.etag)
+	}
+	if entry.lastModified != "" {
+		req.Header.Set(headerIfModifiedSince, entry.lastModified)
+	}
+}
+
+// notModified turns a 304 Not Modified response to a conditional request into a successful response holding the
+// cached body, keeping the validators of the cached entry unless new ones were sent.
+func notModified(ctx context.Context, resp *http.Response, body []byte) (*http.Response, []byte) {
+	entry, ok := conditionalEntry(ctx)
+	if !ok || resp.StatusCode != http.StatusNotModified {
+		return resp, body
+	}
+	header := resp.Header.Clone()
+	if header == nil {
+		header = http.Header{}
+	}
+	if header.Get(headerETag) == "" && entry.etag != "" {
+		header.Set(headerETag, entry.etag)
+	}
+	if header.Get(headerLastModified) == "" && entry.lastModified != "" {
+		header.Set(headerLastModified, entry.lastModified)
+	}
+	if metadata := responseMetadata(ctx); metadata != nil {
+		metadata.Cached = true
+		metadata.Revalidated = true
+	}
+	revalidated := *resp
+	revalidated.StatusCode, revalidated.Header = http.StatusOK, header
+	return &revalidated, entry.body
+}
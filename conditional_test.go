@@ -0,0 +1,86 @@
+package nominatim_test
+
+import (
+	"context"
+	"github.com/diegohordi/nominatim"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func Test_WithConditionalRequests(t *testing.T) {
+	tests := []struct {
+		name            string
+		opts            []nominatim.Option
+		validator       string
+		conditional     string
+		wantRevalidated bool
+	}{
+		{
+			name:            "should revalidate through If-None-Match",
+			opts:            []nominatim.Option{nominatim.WithConditionalRequests(time.Hour)},
+			validator:       "ETag",
+			conditional:     "If-None-Match",
+			wantRevalidated: true,
+		},
+		{
+			name:            "should revalidate through If-Modified-Since",
+			opts:            []nominatim.Option{nominatim.WithConditionalRequests(time.Hour)},
+			validator:       "Last-Modified",
+			conditional:     "If-Modified-Since",
+			wantRevalidated: true,
+		},
+		{
+			name:        "should not revalidate unless enabled",
+			validator:   "ETag",
+			conditional: "If-None-Match",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			const validator = `"v1"`
+			var requests, conditionalRequests int32
+			transport := TransportFunc(func(req *http.Request) (*http.Response, error) {
+				atomic.AddInt32(&requests, 1)
+				if req.Header.Get(tt.conditional) == validator {
+					atomic.AddInt32(&conditionalRequests, 1)
+					return &http.Response{StatusCode: http.StatusNotModified, Header: http.Header{}, Body: http.NoBody}, nil
+				}
+				resp, err := statusFixture(http.StatusOK, mustLoadValidSearchResults(t))()
+				if err == nil {
+					resp.Header.Set(tt.validator, validator)
+				}
+				return resp, err
+			})
+			opts := append([]nominatim.Option{nominatim.WithTransport(transport),
+				nominatim.WithCache(nominatim.NewLRUCache(10), time.Millisecond)}, tt.opts...)
+			d := nominatim.NewClient("http://localhost:8080", nil, opts...)
+			query := nominatim.NewSearchQuery()
+			query.FreeFormQuery = "lisboa"
+			for i := 0; i < 3; i++ {
+				metadata := &nominatim.ResponseMetadata{}
+				results, err := d.Search(nominatim.WithResponseMetadata(context.TODO(), metadata), *query)
+				if err != nil || len(results) == 0 {
+					t.Fatalf("Search() got = %v, %v, want results", results, err)
+				}
+				if wantRevalidated := tt.wantRevalidated && i > 0; metadata.Revalidated != wantRevalidated {
+					t.Errorf("Search() revalidated = %v, want %v", metadata.Revalidated, wantRevalidated)
+				}
+				time.Sleep(5 * time.Millisecond)
+			}
+			wantConditional := int32(0)
+			if tt.wantRevalidated {
+				wantConditional = 2
+			}
+			if got := atomic.LoadInt32(&requests); got != 3 {
+				t.Errorf("requests = %v, want 3", got)
+			}
+			if got := atomic.LoadInt32(&conditionalRequests); got != wantConditional {
+				t.Errorf("conditional requests = %v, want %v", got, wantConditional)
+			}
+		})
+	}
+}
@@ -0,0 +1,96 @@
+package nominatim
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// DefaultResultColumns holds the columns written by WriteResultsCSV when none is given.
+var DefaultResultColumns = []string{"place_id", "osm_id", "category", "type", "lat", "lon", "importance", "display_name"}
+
+// resultColumns maps the columns accepted by WriteResultsCSV, named after the fields of the results as sent by
+// Nominatim, to the functions formatting them.
+var resultColumns = map[string]func(r Result) string{
+	"place_id":        func(r Result) string { return strconv.Itoa(r.PlaceId) },
+	"licence":         func(r Result) string { return r.Licence },
+	"osm_type":        func(r Result) string { return r.OsmType },
+	"osm_id":          Result.OSMID,
+	"lat":             func(r Result) string { return r.Lat },
+	"lon":             func(r Result) string { return r.Lon },
+	"place_rank":      func(r Result) string { return strconv.Itoa(r.PlaceRank) },
+	"category":        func(r Result) string { return r.Category },
+	"type":            func(r Result) string { return r.Type },
+	"importance":      func(r Result) string { return strconv.FormatFloat(r.Importance, 'f', -1, 64) },
+	"addresstype":     func(r Result) string { return r.AddressType },
+	"name":            func(r Result) string { return r.Name },
+	"display_name":    func(r Result) string { return r.DisplayName },
+	"house_number":    func(r Result) string { return r.Address.HouseNumber },
+	"construction":    func(r Result) string { return r.Address.Construction },
+	"public_building": func(r Result) string { return r.Address.PublicBuilding },
+	"road":            func(r Result) string { return r.Address.Road },
+	"neighbourhood":   func(r Result) string { return r.Address.Neighbourhood },
+	"suburb":          func(r Result) string { return r.Address.Suburb },
+	"city_district":   func(r Result) string { return r.Address.CityDistrict },
+	"village":         func(r Result) string { return r.Address.Village },
+	"town":            func(r Result) string { return r.Address.Town },
+	"city":            func(r Result) string { return r.Address.City },
+	"postcode":        func(r Result) string { return r.Address.Postcode },
+	"state":           func(r Result) string { return r.Address.State },
+	"country":         func(r Result) string { return r.Address.Country },
+	"country_code":    func(r Result) string { return r.Address.CountryCode },
+	"continent":       func(r Result) string { return r.Address.Continent },
+}
+
+// WriteResultsCSV writes the given results as CSV, with a header, holding the given columns, or the
+// DefaultResultColumns if none. The columns are named after the fields of the results as sent by Nominatim, as
+// "display_name", "lat" or "importance", including the ones of their address, as "city" or "postcode", except for
+// "osm_id", which is prefixed by the OSM type, as "W123", as accepted by the lookups. Unknown columns fail before
+// anything is written.
+func WriteResultsCSV(w io.Writer, results []Result, cols ...string) error {
+	if len(cols) == 0 {
+		cols = DefaultResultColumns
+	}
+	if err := validColumns(cols); err != nil {
+		return err
+	}
+	writer := csv.NewWriter(w)
+	if err := writer.Write(cols); err != nil {
+		return err
+	}
+	for _, result := range results {
+		record, _ := ResultRecord(result, cols...)
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// ResultRecord returns the given columns of the given result, as written by WriteResultsCSV, or the
+// DefaultResultColumns if none, as for writing them in other tabular formats.
+func ResultRecord(result Result, cols ...string) ([]string, error) {
+	if len(cols) == 0 {
+		cols = DefaultResultColumns
+	}
+	if err := validColumns(cols); err != nil {
+		return nil, err
+	}
+	record := make([]string, 0, len(cols))
+	for _, col := range cols {
+		record = append(record, resultColumns[col](result))
+	}
+	return record, nil
+}
+
+// validColumns checks if every given column is known.
+func validColumns(cols []string) error {
+	for _, col := range cols {
+		if _, ok := resultColumns[col]; !ok {
+			return fmt.Errorf("nominatim: unknown column %q", col)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+package nominatim_test
+
+import (
+	"bytes"
+	"github.com/diegohordi/nominatim"
+	"testing"
+)
+
+func TestWriteResultsCSV(t *testing.T) {
+	results := []nominatim.Result{
+		{
+			PlaceId:     3,
+			OsmType:     "way",
+			OsmId:       24961587,
+			Lat:         "38.6916",
+			Lon:         "-9.2160",
+			Category:    "tourism",
+			Type:        "attraction",
+			Importance:  0.6,
+			DisplayName: "Torre de Belém, Lisboa",
+			Address:     nominatim.Address{City: "Lisboa", Postcode: "1400-038"},
+		},
+		{PlaceId: 5, OsmType: "node", OsmId: 455680276, DisplayName: `Farmácia "Estácio"`},
+	}
+	tests := []struct {
+		name    string
+		cols    []string
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "should write the default columns",
+			want: "place_id,osm_id,category,type,lat,lon,importance,display_name\n" +
+				"3,W24961587,tourism,attraction,38.6916,-9.2160,0.6,\"Torre de Belém, Lisboa\"\n" +
+				"5,N455680276,,,,,0,\"Farmácia \"\"Estácio\"\"\"\n",
+		},
+		{
+			name: "should write the given columns, including the address ones",
+			cols: []string{"display_name", "city", "postcode", "osm_type"},
+			want: "display_name,city,postcode,osm_type\n" +
+				"\"Torre de Belém, Lisboa\",Lisboa,1400-038,way\n" +
+				"\"Farmácia \"\"Estácio\"\"\",,,node\n",
+		},
+		{
+			name:    "should fail on unknown columns before writing",
+			cols:    []string{"lat", "altitude"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			buf := &bytes.Buffer{}
+			err := nominatim.WriteResultsCSV(buf, results, tt.cols...)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("WriteResultsCSV() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got := buf.String(); got != tt.want {
+				t.Errorf("WriteResultsCSV() got = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package nominatim
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// DecodeError holds the failure to decode a single result from a list of results.
+type DecodeError struct {
+	Index int
+	Raw   json.RawMessage
+	Err   error
+}
+
+func (e DecodeError) Error() string {
+	return fmt.Sprintf("nominatim: unable to decode result %d: %v", e.Index, e.Err)
+}
+
+// Unwrap returns the underlying decoding error.
+func (e DecodeError) Unwrap() error {
+	return e.Err
+}
+
+// WithLenientDecoding makes the client skip the results that can't be decoded from a list of results, returning the
+// valid remainder instead of failing the whole call. The skipped results are reported through ResponseMetadata.
+func WithLenientDecoding() Option {
+	return func(d *defaultClient) {
+		d.lenientDecoding = true
+	}
+}
+
+// WithStrictDecoding makes the client fail the calls whose responses hold fields it doesn't know about, decoding them
+// through DecodeResponseStrict instead of DecodeResponse, which helps catching the mismatches between this library and
+// the version of a self-hosted Nominatim in CI. With WithLenientDecoding, the results holding unknown fields are the
+// ones skipped.
+func WithStrictDecoding() Option {
+	return func(d *defaultClient) {
+		d.decoder = DecoderFunc(DecodeResponseStrict)
+		d.strictDecoding = true
+	}
+}
+
+// unmarshal decodes the given data into v, failing on the fields v doesn't know about if strict.
+func unmarshal(data []byte, v interface{}, strict bool) error {
+	if !strict {
+		return json.Unmarshal(data, v)
+	}
+	target := v
+	if _, ok := v.(*Status); ok {
+		// The unknown fields of the types decoding themselves must be checked against their raw fields.
+		target = &statusFields{plainStatus: &plainStatus{}}
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(target); err != nil {
+		return err
+	}
+	if target != v {
+		return json.Unmarshal(data, v)
+	}
+	return nil
+}
+
+// decodeResultsLeniently decodes each of the given raw results, skipping the ones that fail.
+func decodeResultsLeniently(raw []json.RawMessage, strict bool) ([]Result, []DecodeError) {
+	results := make([]Result, 0, len(raw))
+	decodeErrs := make([]DecodeError, 0)
+	for i, item := range raw {
+		result := Result{}
+		if err := unmarshal(item, &result, strict); err != nil {
+			decodeErrs = append(decodeErrs, DecodeError{Index: i, Raw: item, Err: err})
+			continue
+		}
+		results = append(results, result)
+	}
+	return results, decodeErrs
+}
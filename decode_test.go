@@ -0,0 +1,177 @@
+package nominatim_test
+
+import (
+	"context"
+	"errors"
+	"github.com/diegohordi/nominatim"
+	"github.com/diegohordi/nominatim/nominatimtest"
+	"testing"
+)
+
+func Test_LenientDecoding(t *testing.T) {
+	tests := []struct {
+		name             string
+		body             string
+		opts             []nominatim.Option
+		wantResults      int
+		wantDecodeErrors []int
+		wantErr          bool
+	}{
+		{
+			name:    "should fail the whole call by default",
+			body:    `[{"place_id": 1}, {"place_id": "invalid"}, {"place_id": 3}]`,
+			wantErr: true,
+		},
+		{
+			name:             "should skip malformed results",
+			body:             `[{"place_id": 1}, {"place_id": "invalid"}, {"place_id": 3}]`,
+			opts:             []nominatim.Option{nominatim.WithLenientDecoding()},
+			wantResults:      2,
+			wantDecodeErrors: []int{1},
+		},
+		{
+			name:             "should fail when every result is malformed",
+			body:             `[{"place_id": "invalid"}]`,
+			opts:             []nominatim.Option{nominatim.WithLenientDecoding()},
+			wantDecodeErrors: []int{0},
+			wantErr:          true,
+		},
+		{
+			name:    "should fail due to unknown body",
+			body:    `{}`,
+			opts:    []nominatim.Option{nominatim.WithLenientDecoding()},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			server := nominatimtest.NewServer()
+			t.Cleanup(server.Close)
+			server.SetFixture(nominatim.EndpointSearch, nominatimtest.Fixture{Body: []byte(tt.body)})
+			d := nominatim.NewClient(server.URL, nil, tt.opts...)
+			metadata := &nominatim.ResponseMetadata{}
+			query := nominatim.NewSearchQuery()
+			query.FreeFormQuery = "test"
+			got, err := d.Search(nominatim.WithResponseMetadata(context.TODO(), metadata), *query)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Search() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if len(got) != tt.wantResults {
+				t.Errorf("Search() got %d results, want %d", len(got), tt.wantResults)
+			}
+			if len(metadata.DecodeErrors) != len(tt.wantDecodeErrors) {
+				t.Fatalf("Search() got %d decode errors, want %d", len(metadata.DecodeErrors), len(tt.wantDecodeErrors))
+			}
+			for i, decodeErr := range metadata.DecodeErrors {
+				if decodeErr.Index != tt.wantDecodeErrors[i] {
+					t.Errorf("Search() got decode error at %d, want %d", decodeErr.Index, tt.wantDecodeErrors[i])
+				}
+			}
+			var decodeErr nominatim.DecodeError
+			if tt.wantErr && len(tt.wantDecodeErrors) > 0 && !errors.As(err, &decodeErr) {
+				t.Errorf("Search() error = %v, want a DecodeError", err)
+			}
+		})
+	}
+}
+
+func Test_WithStrictDecoding(t *testing.T) {
+	tests := []struct {
+		name        string
+		body        string
+		opts        []nominatim.Option
+		wantResults int
+		wantErr     bool
+	}{
+		{
+			name:        "should ignore the unknown fields by default",
+			body:        `[{"place_id": 1, "osm_version": 3}]`,
+			wantResults: 1,
+		},
+		{
+			name:        "should decode the known fields",
+			body:        `[{"place_id": 1, "address": {"city": "Lisboa"}}]`,
+			opts:        []nominatim.Option{nominatim.WithStrictDecoding()},
+			wantResults: 1,
+		},
+		{
+			name:    "should fail on the unknown fields",
+			body:    `[{"place_id": 1, "osm_version": 3}]`,
+			opts:    []nominatim.Option{nominatim.WithStrictDecoding()},
+			wantErr: true,
+		},
+		{
+			name:    "should fail on the unknown nested fields",
+			body:    `[{"place_id": 1, "address": {"quarter": "Baixa"}}]`,
+			opts:    []nominatim.Option{nominatim.WithStrictDecoding()},
+			wantErr: true,
+		},
+		{
+			name:        "should skip the results with unknown fields when lenient",
+			body:        `[{"place_id": 1}, {"place_id": 2, "osm_version": 3}]`,
+			opts:        []nominatim.Option{nominatim.WithStrictDecoding(), nominatim.WithLenientDecoding()},
+			wantResults: 1,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			server := nominatimtest.NewServer()
+			t.Cleanup(server.Close)
+			server.SetFixture(nominatim.EndpointSearch, nominatimtest.Fixture{Body: []byte(tt.body)})
+			d := nominatim.NewClient(server.URL, nil, tt.opts...)
+			query := nominatim.NewSearchQuery()
+			query.FreeFormQuery = "test"
+			got, err := d.Search(context.TODO(), *query)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Search() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr && nominatim.ClassifyOutcome(err) != nominatim.OutcomeDataError {
+				t.Errorf("Search() outcome = %v, want %v", nominatim.ClassifyOutcome(err), nominatim.OutcomeDataError)
+			}
+			if len(got) != tt.wantResults {
+				t.Errorf("Search() got %d results, want %d", len(got), tt.wantResults)
+			}
+		})
+	}
+}
+
+func Test_WithStrictDecoding_Status(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		wantErr bool
+	}{
+		{
+			name: "should decode the known fields",
+			body: `{"status": 0, "message": "OK", "data_updated": "2021-11-25T17:16:32+00:00"}`,
+		},
+		{
+			name:    "should fail on the unknown fields",
+			body:    `{"status": 0, "message": "OK", "data_updated": "2021-11-25T17:16:32+00:00", "uptime": 10}`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			server := nominatimtest.NewServer()
+			t.Cleanup(server.Close)
+			server.SetFixture(nominatim.EndpointStatus, nominatimtest.Fixture{Body: []byte(tt.body)})
+			d := nominatim.NewClient(server.URL, nil, nominatim.WithStrictDecoding())
+			got, err := d.CheckStatus(context.TODO())
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("CheckStatus() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && (got.Message != "OK" || got.DataUpdated.IsZero()) {
+				t.Errorf("CheckStatus() got = %+v, want the status decoded", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,27 @@
+package nominatim_test
+
+import (
+	"bufio"
+	"os"
+	"strings"
+	"testing"
+)
+
+// Test_Dependencies asserts the core module keeps depending on the standard library only, leaving the integrations
+// with third-party dependencies to their own modules.
+func Test_Dependencies(t *testing.T) {
+	file, err := os.Open("go.mod")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if line := strings.TrimSpace(scanner.Text()); strings.HasPrefix(line, "require") {
+			t.Errorf("go.mod requires dependencies: %q", line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,207 @@
+package nominatim
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	schemeNominatim     = "nominatim"
+	schemeNominatimHTTP = "nominatim+http"
+)
+
+const (
+	dsnCacheMemory      = "memory"
+	dsnDefaultCacheSize = 1000
+	dsnDefaultTTL       = time.Hour
+)
+
+// dsnParam applies a parameter from a configuration URL to the client being configured.
+type dsnParam func(config *dsnConfig, values []string) error
+
+// dsnConfig holds the client configuration parsed from a configuration URL.
+type dsnConfig struct {
+	baseURL    string
+	scheme     string
+	httpClient *http.Client
+	rate       float64
+	burst      int
+	cache      string
+	cacheSize  int
+	ttl        time.Duration
+	opts       []Option
+}
+
+// dsnParams holds the parameters supported by configuration URLs.
+var dsnParams = map[string]dsnParam{
+	"rate": func(config *dsnConfig, values []string) error {
+		rate, err := strconv.ParseFloat(values[0], 64)
+		if err != nil || rate <= 0 {
+			return fmt.Errorf("invalid rate %q", values[0])
+		}
+		config.rate = rate
+		return nil
+	},
+	"burst": func(config *dsnConfig, values []string) error {
+		burst, err := strconv.Atoi(values[0])
+		if err != nil || burst <= 0 {
+			return fmt.Errorf("invalid burst %q", values[0])
+		}
+		config.burst = burst
+		return nil
+	},
+	"retries": func(config *dsnConfig, values []string) error {
+		retries, err := strconv.Atoi(values[0])
+		if err != nil || retries < 0 {
+			return fmt.Errorf("invalid retries %q", values[0])
+		}
+		policy := DefaultRetryPolicy()
+		policy.MaxAttempts = retries + 1
+		config.opts = append(config.opts, WithRetry(policy))
+		return nil
+	},
+	"timeout": func(config *dsnConfig, values []string) error {
+		timeout, err := time.ParseDuration(values[0])
+		if err != nil || timeout <= 0 {
+			return fmt.Errorf("invalid timeout %q", values[0])
+		}
+		config.httpClient.Timeout = timeout
+		return nil
+	},
+	"mirror": func(config *dsnConfig, values []string) error {
+		mirrors := make([]string, 0, len(values))
+		for _, value := range values {
+			for _, host := range strings.Split(value, ",") {
+				mirrors = append(mirrors, config.scheme+"://"+strings.TrimSpace(host))
+			}
+		}
+		config.opts = append(config.opts, WithMirrors(mirrors...))
+		return nil
+	},
+	"cache": func(config *dsnConfig, values []string) error {
+		if values[0] != dsnCacheMemory {
+			return fmt.Errorf("invalid cache %q", values[0])
+		}
+		config.cache = values[0]
+		return nil
+	},
+	"cachesize": func(config *dsnConfig, values []string) error {
+		size, err := strconv.Atoi(values[0])
+		if err != nil || size <= 0 {
+			return fmt.Errorf("invalid cachesize %q", values[0])
+		}
+		config.cacheSize = size
+		return nil
+	},
+	"ttl": func(config *dsnConfig, values []string) error {
+		ttl, err := time.ParseDuration(values[0])
+		if err != nil || ttl <= 0 {
+			return fmt.Errorf("invalid ttl %q", values[0])
+		}
+		config.ttl = ttl
+		return nil
+	},
+	"useragent": func(config *dsnConfig, values []string) error {
+		config.opts = append(config.opts, WithUserAgent(values[0]))
+		return nil
+	},
+	"lang": func(config *dsnConfig, values []string) error {
+		languages := make([]string, 0, len(values))
+		for _, value := range values {
+			for _, tag := range strings.Split(value, ",") {
+				language, err := CanonicalLanguage(tag)
+				if err != nil {
+					return fmt.Errorf("invalid lang %q", tag)
+				}
+				languages = append(languages, language)
+			}
+		}
+		config.opts = append(config.opts, WithDefaultLanguages(languages...))
+		return nil
+	},
+	"lenient": func(config *dsnConfig, values []string) error {
+		lenient, err := strconv.ParseBool(values[0])
+		if err != nil {
+			return fmt.Errorf("invalid lenient %q", values[0])
+		}
+		if lenient {
+			config.opts = append(config.opts, WithLenientDecoding())
+		}
+		return nil
+	},
+}
+
+// NewClientFromURL creates a Client from a configuration URL, as "nominatim://host?rate=1&lang=pt&cache=memory&ttl=1h",
+// so applications configuring their dependencies through connection strings can wire the client uniformly. The
+// nominatim scheme means HTTPS, while nominatim+http means plain HTTP. The supported parameters are:
+//
+//	rate       requests per second, as in WithRateLimit
+//	burst      rate limit burst, as in WithRateLimit
+//	retries    retries of transient failures, as in WithRetry with DefaultRetryPolicy
+//	timeout    timeout of the http.Client, as "5s"
+//	mirror     comma separated hosts to fail over to, as in WithMirrors
+//	lenient    whether to decode results leniently, as in WithLenientDecoding
+//	useragent  User-Agent identifying the application, as in WithUserAgent
+//	lang       comma separated languages to fall back to, as "pt,en", as in WithDefaultLanguages
+//	cache      cache to use, only "memory" for now, as in WithCache with a LRUCache
+//	cachesize  maximum number of cached entries, 1000 by default
+//	ttl        how long the entries are cached, as "1h", the default
+//
+// Unknown parameters are rejected. The given options are applied after the ones from the URL.
+func NewClientFromURL(dsn string, opts ...Option) (Client, error) {
+	config, err := parseDSN(dsn)
+	if err != nil {
+		return nil, err
+	}
+	return NewClient(config.baseURL, config.httpClient, append(config.opts, opts...)...), nil
+}
+
+// parseDSN parses the given configuration URL.
+func parseDSN(dsn string) (*dsnConfig, error) {
+	u, err := url.Parse(dsn)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidConfigURL, err)
+	}
+	config := &dsnConfig{httpClient: &http.Client{}}
+	switch u.Scheme {
+	case schemeNominatim, "https":
+		config.scheme = "https"
+	case schemeNominatimHTTP, "http":
+		config.scheme = "http"
+	default:
+		return nil, fmt.Errorf("%w: unknown scheme %q", ErrInvalidConfigURL, u.Scheme)
+	}
+	if u.Host == "" {
+		return nil, fmt.Errorf("%w: missing host", ErrInvalidConfigURL)
+	}
+	config.baseURL = strings.TrimSuffix(config.scheme+"://"+u.Host+u.Path, "/")
+	for key, values := range u.Query() {
+		param, ok := dsnParams[key]
+		if !ok {
+			return nil, fmt.Errorf("%w: unknown parameter %q", ErrInvalidConfigURL, key)
+		}
+		if err := param(config, values); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidConfigURL, err)
+		}
+	}
+	if config.rate > 0 || config.burst > 0 {
+		config.opts = append(config.opts, WithRateLimit(config.rate, config.burst))
+	}
+	if config.cache == "" && (config.cacheSize > 0 || config.ttl > 0) {
+		return nil, fmt.Errorf("%w: cachesize and ttl require cache", ErrInvalidConfigURL)
+	}
+	if config.cache == dsnCacheMemory {
+		if config.cacheSize <= 0 {
+			config.cacheSize = dsnDefaultCacheSize
+		}
+		if config.ttl <= 0 {
+			config.ttl = dsnDefaultTTL
+		}
+		config.opts = append(config.opts, WithCache(NewLRUCache(config.cacheSize), config.ttl))
+	}
+	return config, nil
+}
@@ -0,0 +1,117 @@
+package nominatim_test
+
+import (
+	"context"
+	"errors"
+	"github.com/diegohordi/nominatim"
+	"net/http"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+func Test_NewClientFromURL(t *testing.T) {
+	tests := []struct {
+		name     string
+		dsn      string
+		wantURLs []string
+		wantErr  error
+	}{
+		{
+			name:     "should use HTTPS for the nominatim scheme",
+			dsn:      "nominatim://nominatim.example.com",
+			wantURLs: []string{"https://nominatim.example.com/status?format=json"},
+		},
+		{
+			name:     "should use HTTP for the nominatim+http scheme keeping the path",
+			dsn:      "nominatim+http://localhost:8080/nominatim/",
+			wantURLs: []string{"http://localhost:8080/nominatim/status?format=json"},
+		},
+		{
+			name: "should accept every supported parameter",
+			dsn:  "nominatim+http://primary?rate=100&burst=2&retries=0&timeout=5s&lenient=true&useragent=acme-geocoder&mirror=mirror1,mirror2",
+			wantURLs: []string{
+				"http://primary/status?format=json",
+				"http://mirror1/status?format=json",
+				"http://mirror2/status?format=json",
+			},
+		},
+		{
+			name:     "should accept an in-memory cache",
+			dsn:      "nominatim://localhost?cache=memory&cachesize=10&ttl=1h",
+			wantURLs: []string{"https://localhost/status?format=json"},
+		},
+		{
+			name:    "should fail due to ttl without cache",
+			dsn:     "nominatim://localhost?ttl=1h",
+			wantErr: nominatim.ErrInvalidConfigURL,
+		},
+		{
+			name:    "should fail due to unknown scheme",
+			dsn:     "postgres://localhost",
+			wantErr: nominatim.ErrInvalidConfigURL,
+		},
+		{
+			name:    "should fail due to missing host",
+			dsn:     "nominatim:///search",
+			wantErr: nominatim.ErrInvalidConfigURL,
+		},
+		{
+			name:    "should fail due to unknown parameter",
+			dsn:     "nominatim://localhost?unknown=1",
+			wantErr: nominatim.ErrInvalidConfigURL,
+		},
+		{
+			name:    "should fail due to invalid rate",
+			dsn:     "nominatim://localhost?rate=fast",
+			wantErr: nominatim.ErrInvalidConfigURL,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			mu := sync.Mutex{}
+			urls := make([]string, 0)
+			transport := TransportFunc(func(req *http.Request) (*http.Response, error) {
+				mu.Lock()
+				urls = append(urls, req.URL.String())
+				mu.Unlock()
+				return statusFixture(http.StatusBadGateway, nil)()
+			})
+			d, err := nominatim.NewClientFromURL(tt.dsn, nominatim.WithTransport(transport))
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("NewClientFromURL() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err != nil {
+				return
+			}
+			_, _ = d.CheckStatus(context.TODO())
+			if !reflect.DeepEqual(urls, tt.wantURLs) {
+				t.Errorf("CheckStatus() urls = %v, want %v", urls, tt.wantURLs)
+			}
+		})
+	}
+}
+
+func Test_NewClientFromURL_Languages(t *testing.T) {
+	var gotLanguages string
+	transport := TransportFunc(func(req *http.Request) (*http.Response, error) {
+		gotLanguages = req.URL.Query().Get("accept-language")
+		return statusFixture(http.StatusOK, mustLoadValidSearchResults(t))()
+	})
+	d, err := nominatim.NewClientFromURL("nominatim://host?rate=1&lang=pt,EN-gb&cache=memory&ttl=1h", nominatim.WithTransport(transport))
+	if err != nil {
+		t.Fatalf("NewClientFromURL() error = %v", err)
+	}
+	if _, err := d.Search(context.TODO(), nominatim.SearchQuery{FreeFormQuery: "lisboa"}); err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+	if gotLanguages != "pt,en-GB" {
+		t.Errorf("Search() accept-language = %v, want pt,en-GB", gotLanguages)
+	}
+	if _, err := nominatim.NewClientFromURL("nominatim://host?lang=english-language-tag"); !errors.Is(err, nominatim.ErrInvalidConfigURL) {
+		t.Errorf("NewClientFromURL() error = %v, want %v", err, nominatim.ErrInvalidConfigURL)
+	}
+}
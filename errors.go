@@ -0,0 +1,193 @@
+package nominatim
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrNoResults is returned when the query was processed but nothing matched it.
+	ErrNoResults = errors.New("nominatim: no results")
+
+	// ErrRateLimited is returned when the server refuses the request due to its usage policy.
+	ErrRateLimited = errors.New("nominatim: rate limited")
+
+	// ErrUnableToGeocode is returned when the server is unable to geocode the given location.
+	ErrUnableToGeocode = errors.New("nominatim: unable to geocode")
+
+	// ErrServerError is returned when the server fails to process the request.
+	ErrServerError = errors.New("nominatim: server error")
+
+	// ErrInvalidQuery is returned when the query is rejected, either by the client or by the server.
+	ErrInvalidQuery = errors.New("nominatim: invalid query")
+
+	// ErrEndpointUnavailable is returned when the endpoint is disabled in the server, as in reverse-only deployments.
+	ErrEndpointUnavailable = errors.New("nominatim: endpoint unavailable")
+
+	// ErrResponseTooLarge is returned when the response body is larger than the limit set through
+	// WithMaxResponseSize.
+	ErrResponseTooLarge = errors.New("nominatim: response body too large")
+
+	// ErrNoDatabase is matched by the StatusError reporting StatusNoDatabase.
+	ErrNoDatabase = errors.New("nominatim: no database")
+
+	// ErrModuleFailed is matched by the StatusError reporting StatusModuleFailed.
+	ErrModuleFailed = errors.New("nominatim: module failed")
+
+	// ErrModuleCallFailed is matched by the StatusError reporting StatusModuleCallFailed.
+	ErrModuleCallFailed = errors.New("nominatim: module call failed")
+
+	// ErrQueryFailed is matched by the StatusError reporting StatusQueryFailed.
+	ErrQueryFailed = errors.New("nominatim: query failed")
+
+	// ErrNoValue is matched by the StatusError reporting StatusNoValue.
+	ErrNoValue = errors.New("nominatim: no value")
+
+	// ErrNoGeometry is returned when converting the geometry of a result not holding one, as when it wasn't
+	// requested through PolygonGeoJSON.
+	ErrNoGeometry = errors.New("nominatim: no geometry")
+
+	// ErrInvalidKey is returned when the API key sent through WithAPIKey is refused by the provider, as when it is
+	// wrong or not active.
+	ErrInvalidKey = errors.New("nominatim: invalid API key")
+
+	// ErrQuotaExceeded is returned when the daily quota of the plan is exhausted, as opposed to ErrRateLimited, which
+	// it also matches, retrying it being pointless until the quota is reset.
+	ErrQuotaExceeded = errors.New("nominatim: quota exceeded")
+
+	// ErrInvalidConfigURL is returned when the configuration URL given to NewClientFromURL can't be parsed.
+	ErrInvalidConfigURL = errors.New("nominatim: invalid configuration URL")
+)
+
+const messageUnableToGeocode = "unable to geocode"
+
+// Error holds the error information returned by Nominatim API. It matches the sentinel errors through errors.Is,
+// accordingly with its code and message.
+type Error struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e Error) Error() string {
+	if e.Code == 0 {
+		return e.Message
+	}
+	return fmt.Sprintf("%d: %s", e.Code, e.Message)
+}
+
+// Is reports whether the Error matches the given sentinel error.
+func (e Error) Is(target error) bool {
+	switch target {
+	case ErrUnableToGeocode, ErrNoResults:
+		return e.unableToGeocode()
+	case ErrRateLimited:
+		return e.Code == http.StatusTooManyRequests
+	case ErrServerError:
+		return e.Code >= http.StatusInternalServerError && e.Code < 600
+	case ErrEndpointUnavailable:
+		return isEndpointUnavailable(e.Code) && !e.unableToGeocode()
+	case ErrInvalidQuery:
+		return e.Code >= http.StatusBadRequest && e.Code < http.StatusInternalServerError &&
+			e.Code != http.StatusTooManyRequests && !isEndpointUnavailable(e.Code)
+	}
+	return false
+}
+
+// unableToGeocode checks if the Error reports that nothing matched the query, as sent by Nominatim, or with a 404 by
+// the providers as LocationIQ, which doesn't mean the endpoint is unavailable.
+func (e Error) unableToGeocode() bool {
+	return strings.EqualFold(strings.TrimSpace(e.Message), messageUnableToGeocode)
+}
+
+// isEndpointUnavailable checks if the given status code means the endpoint is disabled in the server.
+func isEndpointUnavailable(statusCode int) bool {
+	return statusCode == http.StatusNotFound || statusCode == http.StatusMethodNotAllowed
+}
+
+// UnmarshalJSON decodes the error either from its object form or from the plain message form, like
+// {"error": "Unable to geocode"}, used by some endpoints.
+func (e *Error) UnmarshalJSON(data []byte) error {
+	var message string
+	if err := json.Unmarshal(data, &message); err == nil {
+		*e = Error{Message: message}
+		return nil
+	}
+	type plain Error
+	return json.Unmarshal(data, (*plain)(e))
+}
+
+// RateLimitError is returned when the server refuses the request due to its usage policy or temporary unavailability,
+// holding how long to wait before trying again, as sent by the server through the Retry-After header, and when, so
+// job schedulers can requeue the work at the right time even after the client gave up retrying. It matches
+// ErrRateLimited through errors.Is, and unwraps to the Error sent by the server.
+type RateLimitError struct {
+	Err        Error
+	RetryAfter time.Duration
+	RetryAt    time.Time
+}
+
+func (e RateLimitError) Error() string {
+	if e.RetryAfter <= 0 {
+		return e.Err.Error()
+	}
+	return fmt.Sprintf("%s (retry after %s)", e.Err.Error(), e.RetryAfter)
+}
+
+// Is reports whether the given target is ErrRateLimited.
+func (e RateLimitError) Is(target error) bool {
+	return target == ErrRateLimited
+}
+
+// Unwrap returns the Error sent by the server.
+func (e RateLimitError) Unwrap() error {
+	return e.Err
+}
+
+// RetryDelay returns how long to wait from now before trying again, when the given error is a RateLimitError whose
+// wait was sent by the server. The delay is zero when the wait is already over.
+func RetryDelay(err error) (time.Duration, bool) {
+	var rateLimitErr RateLimitError
+	if !errors.As(err, &rateLimitErr) || rateLimitErr.RetryAt.IsZero() {
+		return 0, false
+	}
+	if delay := time.Until(rateLimitErr.RetryAt); delay > 0 {
+		return delay, true
+	}
+	return 0, true
+}
+
+// errorEnvelope holds the error information as it is sent by Nominatim API.
+type errorEnvelope struct {
+	Error *Error `json:"error"`
+}
+
+// newResponseError creates an error from a response with a non successful status code, using the error sent by the
+// server when available. A RateLimitError is created when the server asks the client to wait, through 429 or 503
+// with a Retry-After header.
+func newResponseError(resp *http.Response, body []byte) error {
+	e := Error{Code: resp.StatusCode, Message: strings.TrimSpace(string(body))}
+	envelope := &errorEnvelope{}
+	if err := json.Unmarshal(body, envelope); err == nil && envelope.Error != nil {
+		e.Message = envelope.Error.Message
+		if envelope.Error.Code != 0 {
+			e.Code = envelope.Error.Code
+		}
+	}
+	if e.Message == "" {
+		e.Message = http.StatusText(resp.StatusCode)
+	}
+	now := time.Now()
+	retryAfter, ok := parseRetryAfter(resp.Header.Get(headerRetryAfter), now)
+	if resp.StatusCode == http.StatusTooManyRequests || (resp.StatusCode == http.StatusServiceUnavailable && ok) {
+		rateLimitErr := RateLimitError{Err: e, RetryAfter: retryAfter}
+		if ok {
+			rateLimitErr.RetryAt = now.Add(retryAfter)
+		}
+		return rateLimitErr
+	}
+	return e
+}
@@ -0,0 +1,258 @@
+package nominatim_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/diegohordi/nominatim"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+)
+
+func mustLoadUnableToGeocodeReverseResult(t *testing.T) []byte {
+	t.Helper()
+	content, err := os.ReadFile("./test/testdata/unable_to_geocode_reverse_result.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return content
+}
+
+func Test_Error_Is(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    nominatim.Error
+		target error
+		want   bool
+	}{
+		{
+			name:   "should match invalid query for bad request",
+			err:    nominatim.Error{Code: http.StatusBadRequest, Message: "Need coordinates or OSM object to lookup."},
+			target: nominatim.ErrInvalidQuery,
+			want:   true,
+		},
+		{
+			name:   "should match rate limited for too many requests",
+			err:    nominatim.Error{Code: http.StatusTooManyRequests},
+			target: nominatim.ErrRateLimited,
+			want:   true,
+		},
+		{
+			name:   "should not match invalid query for too many requests",
+			err:    nominatim.Error{Code: http.StatusTooManyRequests},
+			target: nominatim.ErrInvalidQuery,
+			want:   false,
+		},
+		{
+			name:   "should match server error for internal server error",
+			err:    nominatim.Error{Code: http.StatusInternalServerError},
+			target: nominatim.ErrServerError,
+			want:   true,
+		},
+		{
+			name:   "should match unable to geocode",
+			err:    nominatim.Error{Message: "Unable to geocode"},
+			target: nominatim.ErrUnableToGeocode,
+			want:   true,
+		},
+		{
+			name:   "should match no results when unable to geocode",
+			err:    nominatim.Error{Message: "Unable to geocode"},
+			target: nominatim.ErrNoResults,
+			want:   true,
+		},
+		{
+			name:   "should match endpoint unavailable for not found",
+			err:    nominatim.Error{Code: http.StatusNotFound, Message: "Not Found"},
+			target: nominatim.ErrEndpointUnavailable,
+			want:   true,
+		},
+		{
+			name:   "should not match endpoint unavailable for not found when unable to geocode",
+			err:    nominatim.Error{Code: http.StatusNotFound, Message: "Unable to geocode"},
+			target: nominatim.ErrEndpointUnavailable,
+			want:   false,
+		},
+		{
+			name:   "should not match server error for bad request",
+			err:    nominatim.Error{Code: http.StatusBadRequest},
+			target: nominatim.ErrServerError,
+			want:   false,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := errors.Is(tt.err, tt.target); got != tt.want {
+				t.Errorf("errors.Is() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_Error_UnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    nominatim.Error
+		wantErr bool
+	}{
+		{
+			name: "should decode the object form",
+			data: `{"code": 400, "message": "Need coordinates or OSM object to lookup."}`,
+			want: nominatim.Error{Code: 400, Message: "Need coordinates or OSM object to lookup."},
+		},
+		{
+			name: "should decode the plain message form",
+			data: `"Unable to geocode"`,
+			want: nominatim.Error{Message: "Unable to geocode"},
+		},
+		{
+			name:    "should fail due to unknown body",
+			data:    `[]`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got := nominatim.Error{}
+			err := json.Unmarshal([]byte(tt.data), &got)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("UnmarshalJSON() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("UnmarshalJSON() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_Client_Errors(t *testing.T) {
+	newClient := func(statusCode int, body []byte) nominatim.Client {
+		return nominatim.NewClient("http://localhost:8080", &http.Client{
+			Transport: RoundTripFunc(func(req *http.Request) *http.Response {
+				resp := httptest.NewRecorder()
+				resp.WriteHeader(statusCode)
+				resp.Body.Write(body)
+				return resp.Result()
+			}),
+		})
+	}
+	tests := []struct {
+		name string
+		call func() error
+		want error
+	}{
+		{
+			name: "should return no results from an empty search",
+			call: func() error {
+				query := nominatim.NewSearchQuery()
+				query.FreeFormQuery = "test"
+				_, err := newClient(http.StatusOK, []byte("[]")).Search(context.TODO(), *query)
+				return err
+			},
+			want: nominatim.ErrNoResults,
+		},
+		{
+			name: "should return rate limited from a search",
+			call: func() error {
+				query := nominatim.NewSearchQuery()
+				query.FreeFormQuery = "test"
+				_, err := newClient(http.StatusTooManyRequests, nil).Search(context.TODO(), *query)
+				return err
+			},
+			want: nominatim.ErrRateLimited,
+		},
+		{
+			name: "should return unable to geocode from a reverse",
+			call: func() error {
+				query := nominatim.NewReverseQuery("0", "0")
+				_, err := newClient(http.StatusOK, mustLoadUnableToGeocodeReverseResult(t)).Reverse(context.TODO(), *query)
+				return err
+			},
+			want: nominatim.ErrUnableToGeocode,
+		},
+		{
+			name: "should return invalid query from a reverse",
+			call: func() error {
+				query := nominatim.NewReverseQuery("test", "testing")
+				_, err := newClient(http.StatusBadRequest, mustLoadInvalidReverseResult(t)).Reverse(context.TODO(), *query)
+				return err
+			},
+			want: nominatim.ErrInvalidQuery,
+		},
+		{
+			name: "should return server error from the status",
+			call: func() error {
+				_, err := newClient(http.StatusInternalServerError, []byte("internal error")).CheckStatus(context.TODO())
+				return err
+			},
+			want: nominatim.ErrServerError,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if err := tt.call(); !errors.Is(err, tt.want) {
+				t.Errorf("error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func Test_RetryDelay(t *testing.T) {
+	tests := []struct {
+		name    string
+		err     error
+		wantMin time.Duration
+		wantMax time.Duration
+		wantOk  bool
+	}{
+		{
+			name:    "should return the remaining wait",
+			err:     nominatim.RateLimitError{RetryAfter: time.Minute, RetryAt: time.Now().Add(time.Minute)},
+			wantMin: 50 * time.Second,
+			wantMax: time.Minute,
+			wantOk:  true,
+		},
+		{
+			name:    "should return the remaining wait of a wrapped error",
+			err:     fmt.Errorf("geocoding job: %w", nominatim.RateLimitError{RetryAt: time.Now().Add(time.Minute)}),
+			wantMin: 50 * time.Second,
+			wantMax: time.Minute,
+			wantOk:  true,
+		},
+		{
+			name:   "should return zero when the wait is over",
+			err:    nominatim.RateLimitError{RetryAfter: time.Second, RetryAt: time.Now().Add(-time.Second)},
+			wantOk: true,
+		},
+		{
+			name: "should not return the wait unless sent by the server",
+			err:  nominatim.RateLimitError{},
+		},
+		{
+			name: "should not return the wait of other errors",
+			err:  nominatim.ErrServerError,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, ok := nominatim.RetryDelay(tt.err)
+			if ok != tt.wantOk || got < tt.wantMin || got > tt.wantMax {
+				t.Errorf("RetryDelay() got = %v, %v, want between %v and %v, %v", got, ok, tt.wantMin, tt.wantMax, tt.wantOk)
+			}
+		})
+	}
+}
@@ -0,0 +1,220 @@
+package nominatim_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/diegohordi/nominatim"
+	"github.com/diegohordi/nominatim/geo"
+	"github.com/diegohordi/nominatim/nominatimtest"
+)
+
+func ExampleNewClient() {
+	server := nominatimtest.NewServer()
+	defer server.Close()
+
+	client := nominatim.NewClient(server.URL, nil)
+	status, err := client.CheckStatus(context.Background())
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Println(status.Message, status.SoftwareVersion)
+	// Output: OK 4.2.3
+}
+
+func ExampleClient_Search() {
+	server := nominatimtest.NewServer()
+	defer server.Close()
+
+	client := nominatim.NewClient(server.URL, nil)
+	query := nominatim.NewSearchQuery(nominatim.WithFreeForm("avenida lisboa"))
+	results, err := client.Search(context.Background(), *query)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	for _, result := range results {
+		fmt.Println(result.DisplayName)
+	}
+	// Output: Avenida da República, Avenidas Novas, Lisboa, 1000-078, Portugal
+}
+
+func ExampleClient_Search_structured() {
+	server := nominatimtest.NewServer()
+	defer server.Close()
+
+	client := nominatim.NewClient(server.URL, nil)
+	query := nominatim.NewSearchQuery(nominatim.WithStructured(nominatim.SearchStructuredQuery{
+		Street: "Avenida dos Aliados",
+		City:   "Porto",
+	}))
+	results, err := client.Search(context.Background(), *query)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Println(results[0].Lat, results[0].Lon)
+	// Output: 41.1486 -8.6110
+}
+
+func ExampleClient_Search_amenity() {
+	server := nominatimtest.NewServer()
+	defer server.Close()
+
+	client := nominatim.NewClient(server.URL, nil)
+	query := nominatim.NewSearchQuery(nominatim.WithStructured(nominatim.SearchStructuredQuery{
+		Amenity: "pharmacy",
+		City:    "Lisboa",
+	}))
+	results, err := client.Search(context.Background(), *query)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Println(results[0].Name)
+	// Output: Farmácia Estácio
+}
+
+func ExampleSearchNear() {
+	server := nominatimtest.NewServer()
+	defer server.Close()
+
+	client := nominatim.NewClient(server.URL, nil)
+	results, err := nominatim.SearchNear(context.Background(), client, "pharmacy", geo.Point{Lat: 38.7110, Lon: -9.1370}, 500)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Println(results[0].Name)
+	// Output: Farmácia Estácio
+}
+
+func ExampleNewNearPlaceQuery() {
+	server := nominatimtest.NewServer()
+	defer server.Close()
+
+	client := nominatim.NewClient(server.URL, nil)
+	results, err := client.Search(context.Background(), *nominatim.NewNearPlaceQuery("pharmacy", "Lisboa"))
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Println(results[0].Name)
+	// Output: Farmácia Estácio
+}
+
+func ExampleClient_Reverse() {
+	server := nominatimtest.NewServer()
+	defer server.Close()
+
+	client := nominatim.NewClient(server.URL, nil)
+	result, err := client.Reverse(context.Background(), *nominatim.NewReverseQuery("38.6918", "-9.2158"))
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Println(result.Name, result.Address.Suburb)
+	// Output: Torre de Belém Belém
+}
+
+func ExampleClient_Lookup() {
+	server := nominatimtest.NewServer()
+	defer server.Close()
+
+	client := nominatim.NewClient(server.URL, nil)
+	results, err := client.Lookup(context.Background(), *nominatim.NewLookupQuery("W4825402", "W24961587"))
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	for _, result := range results {
+		fmt.Println(result.OSMID(), result.Name)
+	}
+	// Output:
+	// W4825402 Avenida dos Aliados
+	// W24961587 Torre de Belém
+}
+
+func ExampleWithQueryValidation() {
+	server := nominatimtest.NewServer()
+	defer server.Close()
+
+	client := nominatim.NewClient(server.URL, nil, nominatim.WithQueryValidation())
+	_, err := client.Reverse(context.Background(), *nominatim.NewReverseQuery("91", "-9.2158"))
+	var validationErr nominatim.ValidationError
+	if errors.As(err, &validationErr) {
+		fmt.Println(validationErr)
+	}
+	fmt.Println("requests:", server.Requests())
+	// Output:
+	// nominatim: invalid query: Latitude: must be between -90 and 90
+	// requests: 0
+}
+
+func ExampleWithCache() {
+	server := nominatimtest.NewServer()
+	defer server.Close()
+
+	client := nominatim.NewClient(server.URL, nil, nominatim.WithCache(nominatim.NewLRUCache(100), time.Hour))
+	query := nominatim.NewSearchQuery(nominatim.WithFreeForm("praça do comércio"))
+	for i := 0; i < 3; i++ {
+		metadata := &nominatim.ResponseMetadata{}
+		if _, err := client.Search(nominatim.WithResponseMetadata(context.Background(), metadata), *query); err != nil {
+			fmt.Println(err)
+			return
+		}
+		fmt.Println("cached:", metadata.Cached)
+	}
+	fmt.Println("requests:", server.Requests())
+	// Output:
+	// cached: false
+	// cached: true
+	// cached: true
+	// requests: 1
+}
+
+func ExampleWithMirrors() {
+	primary := nominatimtest.NewServer()
+	primary.Close()
+	mirror := nominatimtest.NewServer()
+	defer mirror.Close()
+
+	client := nominatim.NewClient(primary.URL, nil, nominatim.WithMirrors(mirror.URL))
+	query := nominatim.NewSearchQuery(nominatim.WithFreeForm("torre de belém"))
+	results, err := client.Search(context.Background(), *query)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Println(results[0].Name, "served by the mirror:", mirror.Requests() == 1)
+	// Output: Torre de Belém served by the mirror: true
+}
+
+func ExampleSearchQuery_Validate() {
+	query := nominatim.NewSearchQuery(nominatim.WithFreeForm("lisboa"), nominatim.WithLimit(5))
+	query.City = "Porto"
+	var validationErr nominatim.ValidationError
+	if errors.As(query.Validate(), &validationErr) {
+		for _, problem := range validationErr.Problems {
+			fmt.Println(problem)
+		}
+	}
+	// Output: FreeFormQuery: can't be combined with the structured fields City, which would be ignored
+}
+
+func ExampleNewBatchSummary() {
+	server := nominatimtest.NewServer()
+	defer server.Close()
+
+	client := nominatim.NewClient(server.URL, nil)
+	summary := nominatim.NewBatchSummary()
+	for _, address := range []string{"avenida lisboa", "avenida porto", "avenida madrid"} {
+		_, err := client.Search(context.Background(), *nominatim.NewSearchQuery(nominatim.WithFreeForm(address)))
+		summary.Add(err)
+	}
+	fmt.Println(summary.Total, summary.Counts[nominatim.OutcomeSuccess], summary.Counts[nominatim.OutcomeNoResult])
+	// Output: 3 2 1
+}
@@ -0,0 +1,45 @@
+//go:build js && wasm
+
+// Command wasm geocodes the address typed in a browser page against a CORS enabled Nominatim API. Build it with:
+//
+//	GOOS=js GOARCH=wasm go build -o main.wasm ./examples/wasm
+//
+// and serve it along with index.html and $(go env GOROOT)/lib/wasm/wasm_exec.js.
+package main
+
+import (
+	"context"
+	"syscall/js"
+	"time"
+
+	"github.com/diegohordi/nominatim"
+)
+
+const apiURL = "http://localhost:8080"
+
+func main() {
+	transport := nominatim.NewFetchTransport(nominatim.FetchOptions{Mode: "cors", Credentials: "omit"})
+	client := nominatim.NewClient(apiURL, nil, nominatim.WithTransport(transport))
+	document := js.Global().Get("document")
+
+	geocode := js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		address := document.Call("getElementById", "address").Get("value").String()
+		output := document.Call("getElementById", "output")
+		go func() {
+			ctx, cancelFn := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancelFn()
+			query := nominatim.NewSearchQuery()
+			query.FreeFormQuery = address
+			results, err := client.Search(ctx, *query)
+			if err != nil {
+				output.Set("textContent", err.Error())
+				return
+			}
+			output.Set("textContent", results[0].DisplayName+" ("+results[0].Lat+", "+results[0].Lon+")")
+		}()
+		return nil
+	})
+	js.Global().Set("geocode", geocode)
+
+	select {}
+}
@@ -0,0 +1,55 @@
+package nominatim
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Extra tags sent in extratags, when requested through ExtraTags.
+const (
+	extraTagElevation  = "ele"
+	extraTagPopulation = "population"
+)
+
+const metersPerFoot = 0.3048
+
+var (
+	// elevationPattern matches the elevations, as "123", "123.4 m" or "400 ft".
+	elevationPattern = regexp.MustCompile(`^(-?[0-9]+(?:[.,][0-9]+)?)\s*(m|meters?|metres?|ft|feet|')?$`)
+
+	// thousandsPattern matches the numbers grouped by thousands, as "12,345", "12.345" or "12 345".
+	thousandsPattern = regexp.MustCompile(`^[0-9]{1,3}(?:[,. ][0-9]{3})+$`)
+)
+
+// Elevation returns the elevation of the place, in meters, from the extra tags of the result, requested through
+// ExtraTags. Elevations in feet are converted.
+func (r Result) Elevation() (float64, bool) {
+	matches := elevationPattern.FindStringSubmatch(strings.ToLower(strings.TrimSpace(r.ExtraTags[extraTagElevation])))
+	if matches == nil {
+		return 0, false
+	}
+	elevation, err := strconv.ParseFloat(strings.Replace(matches[1], ",", ".", 1), 64)
+	if err != nil {
+		return 0, false
+	}
+	switch matches[2] {
+	case "ft", "feet", "'":
+		elevation *= metersPerFoot
+	}
+	return elevation, true
+}
+
+// Population returns the population of the place, from the extra tags of the result, requested through ExtraTags.
+// Numbers grouped by thousands are accepted.
+func (r Result) Population() (int, bool) {
+	value := strings.TrimSpace(r.ExtraTags[extraTagPopulation])
+	if thousandsPattern.MatchString(value) {
+		value = strings.NewReplacer(",", "", ".", "", " ", "").Replace(value)
+	}
+	population, err := strconv.Atoi(value)
+	if err != nil || population < 0 {
+		return 0, false
+	}
+	return population, true
+}
@@ -0,0 +1,65 @@
+package nominatim_test
+
+import (
+	"github.com/diegohordi/nominatim"
+	"math"
+	"testing"
+)
+
+func TestResult_Elevation(t *testing.T) {
+	tests := []struct {
+		name   string
+		ele    string
+		want   float64
+		wantOk bool
+	}{
+		{name: "should parse meters", ele: "2", want: 2, wantOk: true},
+		{name: "should parse decimal meters with unit", ele: "123.5 m", want: 123.5, wantOk: true},
+		{name: "should parse decimal comma", ele: "123,5", want: 123.5, wantOk: true},
+		{name: "should parse negative elevations", ele: "-28", want: -28, wantOk: true},
+		{name: "should convert feet", ele: "1000 ft", want: 304.8, wantOk: true},
+		{name: "should not parse missing elevation"},
+		{name: "should not parse invalid elevation", ele: "high"},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			result := nominatim.Result{ExtraTags: map[string]string{}}
+			if tt.ele != "" {
+				result.ExtraTags["ele"] = tt.ele
+			}
+			got, ok := result.Elevation()
+			if ok != tt.wantOk || math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("Elevation() got = %v, %v, want %v, %v", got, ok, tt.want, tt.wantOk)
+			}
+		})
+	}
+}
+
+func TestResult_Population(t *testing.T) {
+	tests := []struct {
+		name       string
+		population string
+		want       int
+		wantOk     bool
+	}{
+		{name: "should parse plain numbers", population: "544851", want: 544851, wantOk: true},
+		{name: "should parse numbers grouped by commas", population: "1,234,567", want: 1234567, wantOk: true},
+		{name: "should parse numbers grouped by dots", population: "12.345", want: 12345, wantOk: true},
+		{name: "should parse numbers grouped by spaces", population: "12 345", want: 12345, wantOk: true},
+		{name: "should not parse missing population"},
+		{name: "should not parse invalid population", population: "about 500"},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			result := nominatim.Result{ExtraTags: map[string]string{"population": tt.population}}
+			got, ok := result.Population()
+			if ok != tt.wantOk || got != tt.want {
+				t.Errorf("Population() got = %v, %v, want %v, %v", got, ok, tt.want, tt.wantOk)
+			}
+		})
+	}
+}
@@ -0,0 +1,41 @@
+package nominatim
+
+import (
+	"context"
+	"errors"
+)
+
+// transportError marks the errors returned by the Transport, as opposed to the ones sent by the server.
+type transportError struct {
+	err error
+}
+
+func (e transportError) Error() string {
+	return e.err.Error()
+}
+
+// Unwrap returns the error returned by the Transport.
+func (e transportError) Unwrap() error {
+	return e.err
+}
+
+// WithMirrors makes the client fail over to the given base URLs, in order, whenever the request to the previous one
+// fails due to a connection error or a server error.
+func WithMirrors(baseURLs ...string) Option {
+	return func(d *defaultClient) {
+		d.mirrors = append(d.mirrors, baseURLs...)
+	}
+}
+
+// shouldFailover checks if the given error is due to the server being unreachable or failing, rather than to the
+// query or to the context.
+func shouldFailover(ctx context.Context, err error) bool {
+	if err == nil || ctx.Err() != nil {
+		return false
+	}
+	if errors.Is(err, ErrServerError) {
+		return true
+	}
+	var transportErr transportError
+	return errors.As(err, &transportErr)
+}
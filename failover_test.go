@@ -0,0 +1,103 @@
+package nominatim_test
+
+import (
+	"context"
+	"errors"
+	"github.com/diegohordi/nominatim"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"syscall"
+	"testing"
+)
+
+func Test_Mirrors(t *testing.T) {
+	tests := []struct {
+		name      string
+		responses map[string]func() (*http.Response, error)
+		mirrors   []string
+		wantHosts []string
+		wantErr   error
+	}{
+		{
+			name: "should not fail over when the primary succeeds",
+			responses: map[string]func() (*http.Response, error){
+				"primary": statusFixture(http.StatusOK, mustLoadValidStatus(t)),
+			},
+			mirrors:   []string{"http://mirror"},
+			wantHosts: []string{"primary"},
+		},
+		{
+			name: "should fail over on connection errors",
+			responses: map[string]func() (*http.Response, error){
+				"primary": func() (*http.Response, error) { return nil, syscall.ECONNREFUSED },
+				"mirror":  statusFixture(http.StatusOK, mustLoadValidStatus(t)),
+			},
+			mirrors:   []string{"http://mirror"},
+			wantHosts: []string{"primary", "mirror"},
+		},
+		{
+			name: "should fail over on server errors until a mirror succeeds",
+			responses: map[string]func() (*http.Response, error){
+				"primary": statusFixture(http.StatusBadGateway, nil),
+				"mirror1": statusFixture(http.StatusInternalServerError, nil),
+				"mirror2": statusFixture(http.StatusOK, mustLoadValidStatus(t)),
+			},
+			mirrors:   []string{"http://mirror1", "http://mirror2"},
+			wantHosts: []string{"primary", "mirror1", "mirror2"},
+		},
+		{
+			name: "should not fail over on invalid queries",
+			responses: map[string]func() (*http.Response, error){
+				"primary": statusFixture(http.StatusBadRequest, nil),
+			},
+			mirrors:   []string{"http://mirror"},
+			wantHosts: []string{"primary"},
+			wantErr:   nominatim.ErrInvalidQuery,
+		},
+		{
+			name: "should fail when every mirror fails",
+			responses: map[string]func() (*http.Response, error){
+				"primary": statusFixture(http.StatusBadGateway, nil),
+				"mirror":  statusFixture(http.StatusBadGateway, nil),
+			},
+			mirrors:   []string{"http://mirror"},
+			wantHosts: []string{"primary", "mirror"},
+			wantErr:   nominatim.ErrServerError,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			mu := sync.Mutex{}
+			hosts := make([]string, 0)
+			transport := TransportFunc(func(req *http.Request) (*http.Response, error) {
+				mu.Lock()
+				hosts = append(hosts, req.URL.Host)
+				mu.Unlock()
+				return tt.responses[req.URL.Host]()
+			})
+			d := nominatim.NewClient("http://primary", nil, nominatim.WithTransport(transport), nominatim.WithMirrors(tt.mirrors...))
+			_, err := d.CheckStatus(context.TODO())
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("CheckStatus() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(hosts, tt.wantHosts) {
+				t.Errorf("CheckStatus() hosts = %v, want %v", hosts, tt.wantHosts)
+			}
+		})
+	}
+}
+
+// statusFixture creates a response with the given status code and body.
+func statusFixture(statusCode int, body []byte) func() (*http.Response, error) {
+	return func() (*http.Response, error) {
+		resp := httptest.NewRecorder()
+		resp.WriteHeader(statusCode)
+		resp.Body.Write(body)
+		return resp.Result(), nil
+	}
+}
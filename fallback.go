@@ -0,0 +1,151 @@
+package nominatim
+
+import (
+	"context"
+	"errors"
+	"strings"
+)
+
+// FallbackStrategy is a strategy tried by GeocodeWithFallback.
+type FallbackStrategy string
+
+const (
+	// StrategyStructured searches the structured address as given.
+	StrategyStructured FallbackStrategy = "structured"
+
+	// StrategyFreeForm searches the components of the structured address concatenated as a free-form text.
+	StrategyFreeForm FallbackStrategy = "free-form"
+
+	// StrategyNoHouseNumber searches the structured address without its house number.
+	StrategyNoHouseNumber FallbackStrategy = "no-house-number"
+
+	// StrategyNoPostalCode searches the structured address without its house number nor its postal code.
+	StrategyNoPostalCode FallbackStrategy = "no-postal-code"
+)
+
+// FallbackResult holds the outcome of GeocodeWithFallback.
+type FallbackResult struct {
+
+	// Result is the match found, the BestMatch of the results of the strategy succeeding.
+	Result Result
+
+	// Strategy is the strategy succeeding.
+	Strategy FallbackStrategy
+
+	// Attempts is the number of strategies tried, including the one succeeding.
+	Attempts int
+}
+
+// FallbackOption configures GeocodeWithFallback.
+type FallbackOption func(config *fallbackConfig)
+
+// fallbackConfig holds the configuration of GeocodeWithFallback.
+type fallbackConfig struct {
+	searchOptions []SearchOption
+	callOptions   []CallOption
+	accept        func(result Result) bool
+}
+
+// WithFallbackSearchOptions makes GeocodeWithFallback create the search queries with the given options, as the
+// languages of the results.
+func WithFallbackSearchOptions(opts ...SearchOption) FallbackOption {
+	return func(config *fallbackConfig) {
+		config.searchOptions = append(config.searchOptions, opts...)
+	}
+}
+
+// WithFallbackCallOptions makes GeocodeWithFallback make every search with the given options.
+func WithFallbackCallOptions(opts ...CallOption) FallbackOption {
+	return func(config *fallbackConfig) {
+		config.callOptions = append(config.callOptions, opts...)
+	}
+}
+
+// WithAcceptance makes GeocodeWithFallback accept only the results the given function accepts, as the ones precise
+// enough, trying the next strategy when none is. Every result is accepted by default.
+func WithAcceptance(accept func(result Result) bool) FallbackOption {
+	return func(config *fallbackConfig) {
+		config.accept = accept
+	}
+}
+
+// fallbackAttempt is a query tried by GeocodeWithFallback.
+type fallbackAttempt struct {
+	strategy FallbackStrategy
+	query    SearchQuery
+}
+
+// GeocodeWithFallback geocodes the given structured address through the given client, trying progressively looser
+// strategies until one finds an acceptable match: the structured address as given, then its components as a
+// free-form text, then the structured address without its house number, and finally without its postal code either.
+// The strategies which wouldn't change the query, as dropping a house number not given, are skipped. It returns the
+// match found, the BestMatch of the results accepted, along with the strategy succeeding, or ErrNoResults if none did.
+// Other errors, as transport ones or rate limits, stop the fallback.
+func GeocodeWithFallback(ctx context.Context, client SearchHandler, structured SearchStructuredQuery, opts ...FallbackOption) (FallbackResult, error) {
+	config := fallbackConfig{}
+	for _, opt := range opts {
+		opt(&config)
+	}
+	attempts := fallbackAttempts(structured, config.searchOptions)
+	for i, attempt := range attempts {
+		results, err := client.Search(ctx, attempt.query, config.callOptions...)
+		if errors.Is(err, ErrNoResults) {
+			continue
+		}
+		if err != nil {
+			return FallbackResult{Strategy: attempt.strategy, Attempts: i + 1}, err
+		}
+		if config.accept != nil {
+			results = acceptedResults(results, config.accept)
+		}
+		if match, ok := BestMatch(results, attempt.query); ok {
+			return FallbackResult{Result: match, Strategy: attempt.strategy, Attempts: i + 1}, nil
+		}
+	}
+	return FallbackResult{Attempts: len(attempts)}, ErrNoResults
+}
+
+// fallbackAttempts builds the queries tried by GeocodeWithFallback for the given structured address, skipping the
+// ones not changing the query.
+func fallbackAttempts(structured SearchStructuredQuery, opts []SearchOption) []fallbackAttempt {
+	newQuery := func(opt SearchOption) SearchQuery {
+		return *NewSearchQuery(append(append([]SearchOption(nil), opts...), opt)...)
+	}
+	attempts := []fallbackAttempt{
+		{strategy: StrategyStructured, query: newQuery(WithStructured(structured))},
+		{strategy: StrategyFreeForm, query: newQuery(WithFreeForm(structured.freeForm()))},
+	}
+	relaxed := structured
+	if strings.TrimSpace(relaxed.HouseNumber) != "" {
+		relaxed.HouseNumber = ""
+		attempts = append(attempts, fallbackAttempt{strategy: StrategyNoHouseNumber, query: newQuery(WithStructured(relaxed))})
+	}
+	if strings.TrimSpace(relaxed.PostalCode) != "" {
+		relaxed.PostalCode = ""
+		attempts = append(attempts, fallbackAttempt{strategy: StrategyNoPostalCode, query: newQuery(WithStructured(relaxed))})
+	}
+	return attempts
+}
+
+// freeForm concatenates the components of the structured address as a free-form text, from the most to the least
+// specific one.
+func (q SearchStructuredQuery) freeForm() string {
+	parts := make([]string, 0, 7)
+	for _, part := range []string{q.Amenity, q.street(), q.City, q.County, q.State, q.PostalCode, q.Country} {
+		if part = strings.TrimSpace(part); part != "" {
+			parts = append(parts, part)
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// acceptedResults returns the given results accepted by the given function.
+func acceptedResults(results []Result, accept func(result Result) bool) []Result {
+	accepted := make([]Result, 0, len(results))
+	for _, result := range results {
+		if accept(result) {
+			accepted = append(accepted, result)
+		}
+	}
+	return accepted
+}
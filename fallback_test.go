@@ -0,0 +1,132 @@
+package nominatim_test
+
+import (
+	"context"
+	"errors"
+	"github.com/diegohordi/nominatim"
+	"sync"
+	"testing"
+)
+
+// searchFunc is a nominatim.SearchHandler calling itself, recording the queries searched.
+type searchFunc struct {
+	mu      sync.Mutex
+	queries []nominatim.SearchQuery
+	search  func(query nominatim.SearchQuery) ([]nominatim.Result, error)
+}
+
+func (f *searchFunc) Search(_ context.Context, query nominatim.SearchQuery, _ ...nominatim.CallOption) ([]nominatim.Result, error) {
+	f.mu.Lock()
+	f.queries = append(f.queries, query)
+	f.mu.Unlock()
+	return f.search(query)
+}
+
+func TestGeocodeWithFallback(t *testing.T) {
+	address := nominatim.SearchStructuredQuery{HouseNumber: "10", Street: "Rua Augusta", City: "Lisboa", PostalCode: "1100-053", Country: "Portugal"}
+	street := nominatim.Result{PlaceId: 1, PlaceRank: 26, DisplayName: "Rua Augusta, Lisboa, Portugal"}
+	house := nominatim.Result{PlaceId: 2, PlaceRank: 30, DisplayName: "10, Rua Augusta, Lisboa, 1100-053, Portugal"}
+	found := func(results ...nominatim.Result) func(query nominatim.SearchQuery) ([]nominatim.Result, error) {
+		return func(query nominatim.SearchQuery) ([]nominatim.Result, error) {
+			return results, nil
+		}
+	}
+	foundBy := func(match func(query nominatim.SearchQuery) bool, results ...nominatim.Result) func(query nominatim.SearchQuery) ([]nominatim.Result, error) {
+		return func(query nominatim.SearchQuery) ([]nominatim.Result, error) {
+			if match(query) {
+				return results, nil
+			}
+			return nil, nominatim.ErrNoResults
+		}
+	}
+	tests := []struct {
+		name         string
+		address      nominatim.SearchStructuredQuery
+		search       func(query nominatim.SearchQuery) ([]nominatim.Result, error)
+		opts         []nominatim.FallbackOption
+		wantID       int
+		wantStrategy nominatim.FallbackStrategy
+		wantAttempts int
+		wantErr      error
+	}{
+		{
+			name:         "should find the structured address",
+			address:      address,
+			search:       found(street, house),
+			wantID:       2,
+			wantStrategy: nominatim.StrategyStructured,
+			wantAttempts: 1,
+		},
+		{
+			name:    "should fall back to the free-form text",
+			address: address,
+			search: foundBy(func(query nominatim.SearchQuery) bool {
+				return query.FreeFormQuery == "10 Rua Augusta, Lisboa, 1100-053, Portugal"
+			}, house),
+			wantID:       2,
+			wantStrategy: nominatim.StrategyFreeForm,
+			wantAttempts: 2,
+		},
+		{
+			name:    "should fall back to the address without house number nor postal code",
+			address: address,
+			search: foundBy(func(query nominatim.SearchQuery) bool {
+				return query.FreeFormQuery == "" && query.HouseNumber == "" && query.PostalCode == ""
+			}, street),
+			wantID:       1,
+			wantStrategy: nominatim.StrategyNoPostalCode,
+			wantAttempts: 4,
+		},
+		{
+			name:    "should fall back when no result is accepted",
+			address: address,
+			search: func(query nominatim.SearchQuery) ([]nominatim.Result, error) {
+				if query.FreeFormQuery != "" {
+					return []nominatim.Result{house}, nil
+				}
+				return []nominatim.Result{street}, nil
+			},
+			opts: []nominatim.FallbackOption{nominatim.WithAcceptance(func(result nominatim.Result) bool {
+				return result.PlaceRank >= 30
+			})},
+			wantID:       2,
+			wantStrategy: nominatim.StrategyFreeForm,
+			wantAttempts: 2,
+		},
+		{
+			name:         "should skip the strategies not changing the address",
+			address:      nominatim.SearchStructuredQuery{Street: "Rua Augusta", City: "Lisboa"},
+			search:       foundBy(func(query nominatim.SearchQuery) bool { return false }),
+			wantAttempts: 2,
+			wantErr:      nominatim.ErrNoResults,
+		},
+		{
+			name:    "should stop on other errors",
+			address: address,
+			search: func(query nominatim.SearchQuery) ([]nominatim.Result, error) {
+				return nil, nominatim.ErrRateLimited
+			},
+			wantStrategy: nominatim.StrategyStructured,
+			wantAttempts: 1,
+			wantErr:      nominatim.ErrRateLimited,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			client := &searchFunc{search: tt.search}
+			got, err := nominatim.GeocodeWithFallback(context.TODO(), client, tt.address, tt.opts...)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("GeocodeWithFallback() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got.Result.PlaceId != tt.wantID || got.Strategy != tt.wantStrategy || got.Attempts != tt.wantAttempts {
+				t.Errorf("GeocodeWithFallback() got = %v by %q after %d attempts, want %v by %q after %d", got.Result.PlaceId,
+					got.Strategy, got.Attempts, tt.wantID, tt.wantStrategy, tt.wantAttempts)
+			}
+			if len(client.queries) != tt.wantAttempts {
+				t.Errorf("GeocodeWithFallback() searches = %v, want %v", len(client.queries), tt.wantAttempts)
+			}
+		})
+	}
+}
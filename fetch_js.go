@@ -0,0 +1,54 @@
+//go:build js && wasm
+
+package nominatim
+
+import (
+	"net/http"
+)
+
+const (
+	headerFetchMode        = "js.fetch:mode"
+	headerFetchCredentials = "js.fetch:credentials"
+	headerFetchRedirect    = "js.fetch:redirect"
+)
+
+// FetchOptions holds the options of the Fetch API used by FetchTransport, as documented at
+// https://developer.mozilla.org/en-US/docs/Web/API/fetch. Empty options keep the browser defaults.
+type FetchOptions struct {
+
+	// Mode is the request mode, as "cors" or "same-origin".
+	Mode string
+
+	// Credentials tells whether to send cookies, as "omit", "same-origin" or "include".
+	Credentials string
+
+	// Redirect tells how to handle redirects, as "follow", "error" or "manual".
+	Redirect string
+}
+
+// FetchTransport is a Transport backed by the browser Fetch API, meant to geocode against a CORS enabled Nominatim
+// API from js/wasm builds.
+type FetchTransport struct {
+	client  *http.Client
+	options FetchOptions
+}
+
+// NewFetchTransport creates a FetchTransport with the given options.
+func NewFetchTransport(options FetchOptions) *FetchTransport {
+	return &FetchTransport{client: &http.Client{Transport: &http.Transport{}}, options: options}
+}
+
+// Do sends the given request through the Fetch API.
+func (t *FetchTransport) Do(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	if t.options.Mode != "" {
+		req.Header.Set(headerFetchMode, t.options.Mode)
+	}
+	if t.options.Credentials != "" {
+		req.Header.Set(headerFetchCredentials, t.options.Credentials)
+	}
+	if t.options.Redirect != "" {
+		req.Header.Set(headerFetchRedirect, t.options.Redirect)
+	}
+	return t.client.Do(req)
+}
@@ -0,0 +1,24 @@
+//go:build js && wasm
+
+package nominatim_test
+
+import (
+	"context"
+	"github.com/diegohordi/nominatim"
+	"net/http"
+	"testing"
+)
+
+func Test_FetchTransport(t *testing.T) {
+	transport := nominatim.NewFetchTransport(nominatim.FetchOptions{Mode: "cors", Credentials: "omit"})
+	req, err := http.NewRequestWithContext(context.TODO(), http.MethodGet, "http://127.0.0.1:1/status", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := transport.Do(req); err == nil {
+		t.Errorf("Do() expected an error from an unreachable server")
+	}
+	if len(req.Header) > 0 {
+		t.Errorf("Do() mutated the given request headers: %v", req.Header)
+	}
+}
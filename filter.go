@@ -0,0 +1,116 @@
+package nominatim
+
+import (
+	"context"
+	"strings"
+
+	"github.com/diegohordi/nominatim/geo"
+)
+
+// ResultFilter keeps the results matching every one of its criteria, the empty ones matching every result.
+type ResultFilter struct {
+
+	// Categories holds the categories of the results kept, as "amenity" or "building", ignoring their case.
+	Categories []string
+
+	// Types holds the types of the results kept, as "pharmacy" or "residential", ignoring their case.
+	Types []string
+
+	// MinImportance is the minimum importance of the results kept.
+	MinImportance float64
+
+	// BBox is the bounding box the results kept are located within, if any.
+	BBox *geo.BBox
+}
+
+// Match checks if the given result matches every criterion of the filter.
+func (f ResultFilter) Match(result Result) bool {
+	return matchesAny(f.Categories, result.Category) && matchesAny(f.Types, result.Type) &&
+		result.Importance >= f.MinImportance && f.within(result)
+}
+
+// within checks if the given result is located within the bounding box of the filter, if any.
+func (f ResultFilter) within(result Result) bool {
+	if f.BBox == nil {
+		return true
+	}
+	point, err := result.Point()
+	return err == nil && f.BBox.Contains(point)
+}
+
+// Apply returns the given results matching the filter, in their order.
+func (f ResultFilter) Apply(results []Result) []Result {
+	filtered := make([]Result, 0, len(results))
+	for _, result := range results {
+		if f.Match(result) {
+			filtered = append(filtered, result)
+		}
+	}
+	return filtered
+}
+
+// matchesAny checks if the given value is one of the given ones, ignoring their case, or if none is given.
+func matchesAny(values []string, value string) bool {
+	if len(values) == 0 {
+		return true
+	}
+	for _, v := range values {
+		if strings.EqualFold(strings.TrimSpace(v), value) {
+			return true
+		}
+	}
+	return false
+}
+
+// WithDefaultResultFilter makes the client keep only the results of the searches and the lookups matching the given
+// filter, along with the one given to the call through WithResultFilter, if any. The searches left with no results
+// fail with ErrNoResults.
+func WithDefaultResultFilter(filter ResultFilter) Option {
+	return func(d *defaultClient) {
+		d.resultFilter = &filter
+	}
+}
+
+// WithResultFilter makes the call keep only the results matching the given filter, along with the client-wide one
+// given through WithDefaultResultFilter, if any. It applies to the searches and the lookups.
+func WithResultFilter(filter ResultFilter) CallOption {
+	return func(o *callOptions) {
+		o.resultFilter = &filter
+	}
+}
+
+// filterResults returns the given results matching the client-wide filter and the one of the call, if any.
+func (d defaultClient) filterResults(ctx context.Context, results []Result) []Result {
+	if d.resultFilter != nil {
+		results = d.resultFilter.Apply(results)
+	}
+	if o := callOptionsFrom(ctx); o != nil && o.resultFilter != nil {
+		results = o.resultFilter.Apply(results)
+	}
+	return results
+}
+
+// WithBoundedFiltering makes the client drop the results of the bounded searches located outside their viewbox, or
+// the one of their bias, for the servers not honoring the bounded parameter consistently, as older versions.
+func WithBoundedFiltering() Option {
+	return func(d *defaultClient) {
+		d.boundedFiltering = true
+	}
+}
+
+// filterBounded returns the given results of the given query located within its viewbox, if bounded and filtered
+// client-side.
+func (d defaultClient) filterBounded(query SearchQuery, results []Result) []Result {
+	if !d.boundedFiltering || !query.Bounded {
+		return results
+	}
+	viewbox := query.Viewbox
+	if viewbox == "" && query.Bias != nil {
+		viewbox = query.Bias.viewbox()
+	}
+	box, err := ParseViewbox(viewbox)
+	if err != nil {
+		return results
+	}
+	return ResultFilter{BBox: &box}.Apply(results)
+}
@@ -0,0 +1,165 @@
+package nominatim_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/diegohordi/nominatim"
+	"github.com/diegohordi/nominatim/geo"
+	"github.com/diegohordi/nominatim/nominatimtest"
+	"reflect"
+	"testing"
+)
+
+func TestResultFilter_Match(t *testing.T) {
+	result := nominatim.Result{Category: "amenity", Type: "pharmacy", Importance: 0.3, Lat: "38.7139", Lon: "-9.1394"}
+	lisbon := geo.NewBBox(geo.Point{Lat: 38.69, Lon: -9.23}, geo.Point{Lat: 38.8, Lon: -9.09})
+	porto := geo.NewBBox(geo.Point{Lat: 41.13, Lon: -8.69}, geo.Point{Lat: 41.18, Lon: -8.55})
+	tests := []struct {
+		name   string
+		filter nominatim.ResultFilter
+		want   bool
+	}{
+		{name: "should match every result when empty", want: true},
+		{name: "should match the category ignoring its case", filter: nominatim.ResultFilter{Categories: []string{"building", "Amenity"}}, want: true},
+		{name: "should not match another category", filter: nominatim.ResultFilter{Categories: []string{"building"}}},
+		{name: "should match the type", filter: nominatim.ResultFilter{Types: []string{"pharmacy"}}, want: true},
+		{name: "should not match another type", filter: nominatim.ResultFilter{Types: []string{"hospital"}}},
+		{name: "should match the minimum importance", filter: nominatim.ResultFilter{MinImportance: 0.3}, want: true},
+		{name: "should not match below the minimum importance", filter: nominatim.ResultFilter{MinImportance: 0.31}},
+		{name: "should match within the bounding box", filter: nominatim.ResultFilter{BBox: &lisbon}, want: true},
+		{name: "should not match outside the bounding box", filter: nominatim.ResultFilter{BBox: &porto}},
+		{
+			name:   "should match every criterion",
+			filter: nominatim.ResultFilter{Categories: []string{"amenity"}, Types: []string{"hospital"}, MinImportance: 0.1},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := tt.filter.Match(result); got != tt.want {
+				t.Errorf("Match() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_WithResultFilter(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     []nominatim.Option
+		callOpts []nominatim.CallOption
+		want     []int
+		wantErr  error
+	}{
+		{
+			name: "should keep every result by default",
+			want: []int{1, 2, 3, 5},
+		},
+		{
+			name: "should keep the results matching the client-wide filter",
+			opts: []nominatim.Option{nominatim.WithDefaultResultFilter(nominatim.ResultFilter{Categories: []string{"highway", "place"}})},
+			want: []int{1, 2},
+		},
+		{
+			name:     "should keep the results matching both filters",
+			opts:     []nominatim.Option{nominatim.WithDefaultResultFilter(nominatim.ResultFilter{Categories: []string{"highway", "place"}})},
+			callOpts: []nominatim.CallOption{nominatim.WithResultFilter(nominatim.ResultFilter{MinImportance: 0.5})},
+			want:     []int{2},
+		},
+		{
+			name:     "should fail the searches left with no results",
+			callOpts: []nominatim.CallOption{nominatim.WithResultFilter(nominatim.ResultFilter{Types: []string{"hospital"}})},
+			wantErr:  nominatim.ErrNoResults,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			server := nominatimtest.NewServer()
+			t.Cleanup(server.Close)
+			client := nominatim.NewClient(server.URL, nil, tt.opts...)
+			results, err := client.Search(context.TODO(), *nominatim.NewSearchQuery(nominatim.WithFreeForm("lisboa")), tt.callOpts...)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Search() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got := placeIDs(results); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Search() got = %v, want %v", got, tt.want)
+			}
+			lookup := nominatim.NewLookupQuery("W683827991", "W8127497", "W24961587", "N455680276")
+			results, err = client.Lookup(context.TODO(), *lookup, tt.callOpts...)
+			if err != nil {
+				t.Fatalf("Lookup() error = %v", err)
+			}
+			if got := placeIDs(results); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Lookup() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func placeIDs(results []nominatim.Result) []int {
+	var ids []int
+	for _, result := range results {
+		ids = append(ids, result.PlaceId)
+	}
+	return ids
+}
+
+func Test_WithBoundedFiltering(t *testing.T) {
+	lisbon := geo.NewBBox(geo.Point{Lat: 38.69, Lon: -9.23}, geo.Point{Lat: 38.8, Lon: -9.09})
+	tests := []struct {
+		name string
+		opts []nominatim.Option
+		bias bool
+		want []int
+	}{
+		{
+			name: "should keep the results outside the viewbox by default",
+			want: []int{1, 2, 3, 4, 5},
+		},
+		{
+			name: "should drop the results outside the viewbox",
+			opts: []nominatim.Option{nominatim.WithBoundedFiltering()},
+			want: []int{1, 2, 3, 5},
+		},
+		{
+			name: "should drop the results outside the viewbox of the bias",
+			opts: []nominatim.Option{nominatim.WithBoundedFiltering()},
+			bias: true,
+			want: []int{1, 2, 3, 5},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			server := nominatimtest.NewServer()
+			t.Cleanup(server.Close)
+			body, err := json.Marshal(nominatimtest.Places())
+			if err != nil {
+				t.Fatal(err)
+			}
+			server.SetFixture(nominatim.EndpointSearch, nominatimtest.Fixture{Body: body})
+			client := nominatim.NewClient(server.URL, nil, tt.opts...)
+			query := nominatim.NewSearchQuery(nominatim.WithFreeForm("portugal"), nominatim.WithViewboxBBox(lisbon, true))
+			if tt.bias {
+				query = nominatim.NewSearchQuery(nominatim.WithFreeForm("portugal"), nominatim.WithBias(nominatim.LocationBias{Latitude: 38.7223, Longitude: -9.1393, Radius: 20000}))
+				query.Bounded = true
+			}
+			results, err := client.Search(context.TODO(), *query)
+			if err != nil {
+				t.Fatalf("Search() error = %v", err)
+			}
+			if got := placeIDs(results); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Search() got = %v, want %v", got, tt.want)
+			}
+			unbounded := nominatim.NewSearchQuery(nominatim.WithFreeForm("portugal"), nominatim.WithViewboxBBox(lisbon, false))
+			if results, err := client.Search(context.TODO(), *unbounded); err != nil || len(results) != 5 {
+				t.Errorf("Search() got %d results, %v, want every result of the unbounded searches", len(results), err)
+			}
+		})
+	}
+}
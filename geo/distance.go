@@ -0,0 +1,44 @@
+package geo
+
+import "math"
+
+// EarthRadius is the mean radius of the Earth, in meters.
+const EarthRadius = 6371008.8
+
+// DistanceMeters returns the great-circle distance between the given points, in meters, through the haversine formula.
+func DistanceMeters(a, b Point) float64 {
+	lat1, lat2 := radians(a.Lat), radians(b.Lat)
+	dLat, dLon := lat2-lat1, radians(b.Lon-a.Lon)
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * EarthRadius * math.Asin(math.Sqrt(math.Min(1, h)))
+}
+
+// Bearing returns the initial bearing of the great-circle path from a to b, in degrees clockwise from the north, from
+// 0 up to 360.
+func Bearing(a, b Point) float64 {
+	lat1, lat2 := radians(a.Lat), radians(b.Lat)
+	dLon := radians(b.Lon - a.Lon)
+	y := math.Sin(dLon) * math.Cos(lat2)
+	x := math.Cos(lat1)*math.Sin(lat2) - math.Sin(lat1)*math.Cos(lat2)*math.Cos(dLon)
+	return math.Mod(degrees(math.Atan2(y, x))+360, 360)
+}
+
+// Midpoint returns the point halfway along the great-circle path between the given points.
+func Midpoint(a, b Point) Point {
+	lat1, lat2 := radians(a.Lat), radians(b.Lat)
+	lon1, dLon := radians(a.Lon), radians(b.Lon-a.Lon)
+	bx, by := math.Cos(lat2)*math.Cos(dLon), math.Cos(lat2)*math.Sin(dLon)
+	lat := math.Atan2(math.Sin(lat1)+math.Sin(lat2), math.Sqrt((math.Cos(lat1)+bx)*(math.Cos(lat1)+bx)+by*by))
+	lon := lon1 + math.Atan2(by, math.Cos(lat1)+bx)
+	return Point{Lat: degrees(lat), Lon: math.Mod(degrees(lon)+540, 360) - 180}
+}
+
+// radians converts the given degrees into radians.
+func radians(degrees float64) float64 {
+	return degrees * math.Pi / 180
+}
+
+// degrees converts the given radians into degrees.
+func degrees(radians float64) float64 {
+	return radians * 180 / math.Pi
+}
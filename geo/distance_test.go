@@ -0,0 +1,83 @@
+package geo_test
+
+import (
+	"github.com/diegohordi/nominatim/geo"
+	"math"
+	"testing"
+)
+
+var (
+	lisbon = geo.Point{Lat: 38.7223, Lon: -9.1393}
+	porto  = geo.Point{Lat: 41.1579, Lon: -8.6291}
+)
+
+func TestDistanceMeters(t *testing.T) {
+	tests := []struct {
+		name string
+		a    geo.Point
+		b    geo.Point
+		want float64
+	}{
+		{name: "should be zero between the same points", a: lisbon, b: lisbon, want: 0},
+		{name: "should measure a degree along the equator", a: geo.Point{}, b: geo.Point{Lon: 1}, want: 111195},
+		{name: "should measure between cities", a: lisbon, b: porto, want: 274400},
+		{name: "should be symmetric", a: porto, b: lisbon, want: 274400},
+		{name: "should measure across the antimeridian", a: geo.Point{Lon: 179.5}, b: geo.Point{Lon: -179.5}, want: 111195},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := geo.DistanceMeters(tt.a, tt.b); math.Abs(got-tt.want) > 500 {
+				t.Errorf("DistanceMeters() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBearing(t *testing.T) {
+	tests := []struct {
+		name string
+		b    geo.Point
+		want float64
+	}{
+		{name: "should head north", b: geo.Point{Lat: 1}, want: 0},
+		{name: "should head east", b: geo.Point{Lon: 1}, want: 90},
+		{name: "should head south", b: geo.Point{Lat: -1}, want: 180},
+		{name: "should head west", b: geo.Point{Lon: -1}, want: 270},
+		{name: "should head north-east", b: geo.Point{Lat: 1, Lon: 1}, want: 45},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := geo.Bearing(geo.Point{}, tt.b); math.Abs(got-tt.want) > 0.01 {
+				t.Errorf("Bearing() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMidpoint(t *testing.T) {
+	tests := []struct {
+		name string
+		a    geo.Point
+		b    geo.Point
+		want geo.Point
+	}{
+		{name: "should be the same point", a: lisbon, b: lisbon, want: lisbon},
+		{name: "should halve the equator", a: geo.Point{}, b: geo.Point{Lon: 90}, want: geo.Point{Lon: 45}},
+		{name: "should halve a meridian", a: geo.Point{Lat: -10}, b: geo.Point{Lat: 30}, want: geo.Point{Lat: 10}},
+		{name: "should cross the antimeridian", a: geo.Point{Lon: 170}, b: geo.Point{Lon: -170}, want: geo.Point{Lon: -180}},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got := geo.Midpoint(tt.a, tt.b)
+			if math.Abs(got.Lat-tt.want.Lat) > 1e-6 || math.Abs(got.Lon-tt.want.Lon) > 1e-6 {
+				t.Errorf("Midpoint() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
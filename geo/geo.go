@@ -0,0 +1,59 @@
+// Package geo holds the geographic types shared across the nominatim packages, as points and bounding boxes, in
+// decimal degrees.
+package geo
+
+// Point is a location, in decimal degrees.
+type Point struct {
+	Lat float64
+	Lon float64
+}
+
+// Valid checks if the Point is within the valid latitude and longitude ranges.
+func (p Point) Valid() bool {
+	return p.Lat >= -90 && p.Lat <= 90 && p.Lon >= -180 && p.Lon <= 180
+}
+
+// BBox is a bounding box, in decimal degrees.
+type BBox struct {
+	MinLat float64
+	MinLon float64
+	MaxLat float64
+	MaxLon float64
+}
+
+// NewBBox creates the BBox having the given points as opposite corners, in any order.
+func NewBBox(a, b Point) BBox {
+	box := BBox{MinLat: a.Lat, MinLon: a.Lon, MaxLat: b.Lat, MaxLon: b.Lon}
+	if box.MinLat > box.MaxLat {
+		box.MinLat, box.MaxLat = box.MaxLat, box.MinLat
+	}
+	if box.MinLon > box.MaxLon {
+		box.MinLon, box.MaxLon = box.MaxLon, box.MinLon
+	}
+	return box
+}
+
+// Valid checks if the corners of the BBox are valid points, in order.
+func (b BBox) Valid() bool {
+	return b.Min().Valid() && b.Max().Valid() && b.MinLat <= b.MaxLat && b.MinLon <= b.MaxLon
+}
+
+// Min returns the south-west corner of the BBox.
+func (b BBox) Min() Point {
+	return Point{Lat: b.MinLat, Lon: b.MinLon}
+}
+
+// Max returns the north-east corner of the BBox.
+func (b BBox) Max() Point {
+	return Point{Lat: b.MaxLat, Lon: b.MaxLon}
+}
+
+// Center returns the center of the BBox.
+func (b BBox) Center() Point {
+	return Point{Lat: (b.MinLat + b.MaxLat) / 2, Lon: (b.MinLon + b.MaxLon) / 2}
+}
+
+// Contains checks if the given point is within the BBox, including its edges.
+func (b BBox) Contains(p Point) bool {
+	return p.Lat >= b.MinLat && p.Lat <= b.MaxLat && p.Lon >= b.MinLon && p.Lon <= b.MaxLon
+}
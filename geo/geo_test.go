@@ -0,0 +1,72 @@
+package geo_test
+
+import (
+	"github.com/diegohordi/nominatim/geo"
+	"testing"
+)
+
+func TestNewBBox(t *testing.T) {
+	lisbon := geo.BBox{MinLat: 38.69, MinLon: -9.23, MaxLat: 38.8, MaxLon: -9.09}
+	tests := []struct {
+		name string
+		a    geo.Point
+		b    geo.Point
+		want geo.BBox
+	}{
+		{name: "should keep ordered corners", a: geo.Point{Lat: 38.69, Lon: -9.23}, b: geo.Point{Lat: 38.8, Lon: -9.09}, want: lisbon},
+		{name: "should order swapped corners", a: geo.Point{Lat: 38.8, Lon: -9.09}, b: geo.Point{Lat: 38.69, Lon: -9.23}, want: lisbon},
+		{name: "should order crossed corners", a: geo.Point{Lat: 38.8, Lon: -9.23}, b: geo.Point{Lat: 38.69, Lon: -9.09}, want: lisbon},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := geo.NewBBox(tt.a, tt.b); got != tt.want {
+				t.Errorf("NewBBox() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBBox_Contains(t *testing.T) {
+	lisbon := geo.BBox{MinLat: 38.69, MinLon: -9.23, MaxLat: 38.8, MaxLon: -9.09}
+	tests := []struct {
+		name  string
+		point geo.Point
+		want  bool
+	}{
+		{name: "should contain the center", point: lisbon.Center(), want: true},
+		{name: "should contain the corners", point: lisbon.Min(), want: true},
+		{name: "should not contain points outside", point: geo.Point{Lat: 41.15, Lon: -8.61}, want: false},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := lisbon.Contains(tt.point); got != tt.want {
+				t.Errorf("Contains() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBBox_Valid(t *testing.T) {
+	tests := []struct {
+		name string
+		box  geo.BBox
+		want bool
+	}{
+		{name: "should accept ordered corners", box: geo.BBox{MinLat: -10, MinLon: -10, MaxLat: 10, MaxLon: 10}, want: true},
+		{name: "should reject unordered corners", box: geo.BBox{MinLat: 10, MinLon: -10, MaxLat: -10, MaxLon: 10}, want: false},
+		{name: "should reject corners out of range", box: geo.BBox{MinLat: -91, MinLon: -10, MaxLat: 10, MaxLon: 10}, want: false},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := tt.box.Valid(); got != tt.want {
+				t.Errorf("Valid() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
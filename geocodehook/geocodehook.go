@@ -0,0 +1,77 @@
+// Package geocodehook implements the read-through geocoding of addresses stored along with their coordinates, as in
+// database columns filled on demand: the stored result is used when present, otherwise the address is geocoded and
+// the result persisted before returning.
+package geocodehook
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/diegohordi/nominatim"
+)
+
+// Coordinates holds the location of a geocoded address.
+type Coordinates struct {
+	Latitude  float64
+	Longitude float64
+}
+
+// ResultStore persists the geocoding results by address, as a table or an ORM model.
+type ResultStore interface {
+
+	// Load retrieves the result stored for the given address, if any.
+	Load(ctx context.Context, address string) (nominatim.Result, bool, error)
+
+	// Save stores the given result for the given address.
+	Save(ctx context.Context, address string, result nominatim.Result) error
+}
+
+// Hook geocodes the addresses missing from a ResultStore through a nominatim.SearchHandler.
+type Hook struct {
+	client nominatim.SearchHandler
+}
+
+// New creates a Hook geocoding through the given client.
+func New(client nominatim.SearchHandler) *Hook {
+	return &Hook{client: client}
+}
+
+// EnsureGeocoded returns the coordinates of the given address from the given store, geocoding the address and
+// persisting its best result on a miss. Addresses not found are not persisted, so nominatim.ErrNoResults is returned
+// until they can be geocoded.
+func (h *Hook) EnsureGeocoded(ctx context.Context, store ResultStore, address string) (Coordinates, error) {
+	result, ok, err := store.Load(ctx, address)
+	if err != nil {
+		return Coordinates{}, fmt.Errorf("geocodehook: loading %q: %w", address, err)
+	}
+	if !ok {
+		query := nominatim.NewSearchQuery()
+		query.FreeFormQuery, query.Limit = address, 1
+		results, err := h.client.Search(ctx, *query)
+		if err != nil {
+			return Coordinates{}, err
+		}
+		if len(results) == 0 {
+			return Coordinates{}, nominatim.ErrNoResults
+		}
+		result = results[0]
+		if err := store.Save(ctx, address, result); err != nil {
+			return Coordinates{}, fmt.Errorf("geocodehook: saving %q: %w", address, err)
+		}
+	}
+	return coordinates(result)
+}
+
+// coordinates parses the coordinates of the given result.
+func coordinates(result nominatim.Result) (Coordinates, error) {
+	latitude, err := strconv.ParseFloat(result.Lat, 64)
+	if err != nil {
+		return Coordinates{}, fmt.Errorf("geocodehook: invalid latitude %q: %w", result.Lat, err)
+	}
+	longitude, err := strconv.ParseFloat(result.Lon, 64)
+	if err != nil {
+		return Coordinates{}, fmt.Errorf("geocodehook: invalid longitude %q: %w", result.Lon, err)
+	}
+	return Coordinates{Latitude: latitude, Longitude: longitude}, nil
+}
@@ -0,0 +1,109 @@
+package geocodehook_test
+
+import (
+	"context"
+	"errors"
+	"github.com/diegohordi/nominatim"
+	"github.com/diegohordi/nominatim/geocodehook"
+	"sync"
+	"testing"
+)
+
+// mapStore is a ResultStore backed by a map.
+type mapStore struct {
+	mu      sync.Mutex
+	results map[string]nominatim.Result
+	err     error
+}
+
+func (s *mapStore) Load(_ context.Context, address string) (nominatim.Result, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	result, ok := s.results[address]
+	return result, ok, s.err
+}
+
+func (s *mapStore) Save(_ context.Context, address string, result nominatim.Result) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.results[address] = result
+	return nil
+}
+
+// searchFunc is a SearchHandler backed by a function.
+type searchFunc func(query nominatim.SearchQuery) ([]nominatim.Result, error)
+
+func (f searchFunc) Search(_ context.Context, query nominatim.SearchQuery, _ ...nominatim.CallOption) ([]nominatim.Result, error) {
+	return f(query)
+}
+
+func TestHook_EnsureGeocoded(t *testing.T) {
+	errStore := errors.New("connection refused")
+	tests := []struct {
+		name         string
+		stored       map[string]nominatim.Result
+		storeErr     error
+		address      string
+		want         geocodehook.Coordinates
+		wantSearches int
+		wantStored   bool
+		wantErr      error
+	}{
+		{
+			name:    "should use the stored result",
+			stored:  map[string]nominatim.Result{"lisboa": {Lat: "38.7077507", Lon: "-9.1365919"}},
+			address: "lisboa",
+			want:    geocodehook.Coordinates{Latitude: 38.7077507, Longitude: -9.1365919},
+		},
+		{
+			name:         "should geocode and persist a missing address",
+			address:      "porto",
+			want:         geocodehook.Coordinates{Latitude: 41.1494512, Longitude: -8.6107884},
+			wantSearches: 1,
+			wantStored:   true,
+		},
+		{
+			name:         "should not persist an address not found",
+			address:      "nowhere",
+			wantSearches: 1,
+			wantErr:      nominatim.ErrNoResults,
+		},
+		{
+			name:     "should fail due to the store",
+			storeErr: errStore,
+			address:  "porto",
+			wantErr:  errStore,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			store := &mapStore{results: map[string]nominatim.Result{}, err: tt.storeErr}
+			for address, result := range tt.stored {
+				store.results[address] = result
+			}
+			searches := 0
+			hook := geocodehook.New(searchFunc(func(query nominatim.SearchQuery) ([]nominatim.Result, error) {
+				searches++
+				if query.FreeFormQuery == "porto" {
+					return []nominatim.Result{{Lat: "41.1494512", Lon: "-8.6107884"}}, nil
+				}
+				return nil, nominatim.ErrNoResults
+			}))
+			got, err := hook.EnsureGeocoded(context.TODO(), store, tt.address)
+			if !errors.Is(err, tt.wantErr) || (tt.wantErr == nil && err != nil) {
+				t.Fatalf("EnsureGeocoded() error = %v, want %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("EnsureGeocoded() got = %+v, want %+v", got, tt.want)
+			}
+			if searches != tt.wantSearches {
+				t.Errorf("EnsureGeocoded() searches = %d, want %d", searches, tt.wantSearches)
+			}
+			if _, stored := store.results[tt.address]; tt.wantStored && !stored {
+				t.Errorf("EnsureGeocoded() didn't persist the result of %q", tt.address)
+			}
+		})
+	}
+}
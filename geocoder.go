@@ -0,0 +1,86 @@
+package nominatim
+
+import (
+	"context"
+	"errors"
+)
+
+// Geocoder is the provider-agnostic part of the Client, searching, reverse geocoding and looking up places, so the
+// applications can swap the Nominatim API for another provider implementing it, or compose several of them through
+// ChainGeocoders. Every Client is a Geocoder.
+type Geocoder interface {
+	SearchHandler
+	ReverseHandler
+	LookupHandler
+}
+
+// geocoderChain is a Geocoder falling back to the next geocoder whenever the previous one finds no results or is
+// unavailable.
+type geocoderChain []Geocoder
+
+// ChainGeocoders composes the given geocoders into one, querying them in order and falling back to the next one
+// whenever the previous one finds no results, is unable to geocode, fails due to a connection error or reports a server
+// error, a rate limit, a refused API key or an endpoint unavailable, as when combining a self-hosted instance with a
+// commercial provider. Errors due to the query, as ErrInvalidQuery, or to the context are returned right away. The
+// error of the last geocoder is returned when all of them fail, while finding no results is reported as the Client
+// does: ErrNoResults when searching and an empty slice when looking up.
+func ChainGeocoders(geocoders ...Geocoder) Geocoder {
+	return geocoderChain(geocoders)
+}
+
+// Search searches through the geocoders in order, until one finds results.
+func (c geocoderChain) Search(ctx context.Context, query SearchQuery, opts ...CallOption) ([]Result, error) {
+	err := error(ErrNoResults)
+	for _, geocoder := range c {
+		var results []Result
+		results, err = geocoder.Search(ctx, query.Clone(), opts...)
+		if err == nil && len(results) == 0 {
+			err = ErrNoResults
+		}
+		if !shouldFallThrough(ctx, err) {
+			return results, err
+		}
+	}
+	return nil, err
+}
+
+// Reverse reverse geocodes through the geocoders in order, until one finds a result.
+func (c geocoderChain) Reverse(ctx context.Context, query ReverseQuery, opts ...CallOption) (Result, error) {
+	err := error(ErrNoResults)
+	for _, geocoder := range c {
+		var result Result
+		result, err = geocoder.Reverse(ctx, query.Clone(), opts...)
+		if !shouldFallThrough(ctx, err) {
+			return result, err
+		}
+	}
+	return Result{}, err
+}
+
+// Lookup looks up through the geocoders in order, until one finds results, returning an empty slice when none does.
+func (c geocoderChain) Lookup(ctx context.Context, query LookupQuery, opts ...CallOption) ([]Result, error) {
+	err := error(ErrNoResults)
+	for _, geocoder := range c {
+		var results []Result
+		results, err = geocoder.Lookup(ctx, query.Clone(), opts...)
+		if err == nil && len(results) == 0 {
+			err = ErrNoResults
+		}
+		if !shouldFallThrough(ctx, err) {
+			return results, err
+		}
+	}
+	if errors.Is(err, ErrNoResults) {
+		return []Result{}, nil
+	}
+	return nil, err
+}
+
+// shouldFallThrough checks if the given error should make a geocoderChain try its next geocoder.
+func shouldFallThrough(ctx context.Context, err error) bool {
+	if err == nil || ctx.Err() != nil {
+		return false
+	}
+	return errors.Is(err, ErrNoResults) || errors.Is(err, ErrUnableToGeocode) || errors.Is(err, ErrRateLimited) ||
+		errors.Is(err, ErrInvalidKey) || errors.Is(err, ErrEndpointUnavailable) || shouldFailover(ctx, err)
+}
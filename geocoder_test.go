@@ -0,0 +1,141 @@
+package nominatim_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/diegohordi/nominatim"
+	"reflect"
+	"testing"
+)
+
+// geocoderFunc is a nominatim.Geocoder answering every request with the same results and error, counting them.
+type geocoderFunc struct {
+	results []nominatim.Result
+	err     error
+	calls   int
+}
+
+func (g *geocoderFunc) Search(context.Context, nominatim.SearchQuery, ...nominatim.CallOption) ([]nominatim.Result, error) {
+	g.calls++
+	return g.results, g.err
+}
+
+func (g *geocoderFunc) Reverse(context.Context, nominatim.ReverseQuery, ...nominatim.CallOption) (nominatim.Result, error) {
+	g.calls++
+	if len(g.results) == 0 {
+		return nominatim.Result{}, g.err
+	}
+	return g.results[0], g.err
+}
+
+func (g *geocoderFunc) Lookup(context.Context, nominatim.LookupQuery, ...nominatim.CallOption) ([]nominatim.Result, error) {
+	g.calls++
+	return g.results, g.err
+}
+
+func TestGeocoder_Client(t *testing.T) {
+	var _ nominatim.Geocoder = nominatim.NewClient("http://localhost", nil)
+}
+
+func TestChainGeocoders(t *testing.T) {
+	found := []nominatim.Result{{PlaceId: 1}}
+	serverErr := fmt.Errorf("%w: 503", nominatim.ErrServerError)
+	invalidErr := fmt.Errorf("%w: missing q", nominatim.ErrInvalidQuery)
+	tests := []struct {
+		name      string
+		geocoders []*geocoderFunc
+		want      []nominatim.Result
+		wantErr   error
+		wantCalls []int
+	}{
+		{
+			name:      "should answer from the first geocoder finding results",
+			geocoders: []*geocoderFunc{{results: found}, {results: []nominatim.Result{{PlaceId: 2}}}},
+			want:      found,
+			wantCalls: []int{1, 0},
+		},
+		{
+			name:      "should fall back on no results",
+			geocoders: []*geocoderFunc{{err: nominatim.ErrNoResults}, {results: found}},
+			want:      found,
+			wantCalls: []int{1, 1},
+		},
+		{
+			name:      "should fall back on server errors and rate limits",
+			geocoders: []*geocoderFunc{{err: serverErr}, {err: nominatim.ErrRateLimited}, {results: found}},
+			want:      found,
+			wantCalls: []int{1, 1, 1},
+		},
+		{
+			name:      "should not fall back on invalid queries",
+			geocoders: []*geocoderFunc{{err: invalidErr}, {results: found}},
+			wantErr:   nominatim.ErrInvalidQuery,
+			wantCalls: []int{1, 0},
+		},
+		{
+			name:      "should return the error of the last geocoder",
+			geocoders: []*geocoderFunc{{err: nominatim.ErrRateLimited}, {err: serverErr}},
+			wantErr:   nominatim.ErrServerError,
+			wantCalls: []int{1, 1},
+		},
+		{
+			name:      "should fail with no geocoders",
+			wantErr:   nominatim.ErrNoResults,
+			wantCalls: []int{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			geocoders := make([]nominatim.Geocoder, 0, len(tt.geocoders))
+			for _, geocoder := range tt.geocoders {
+				geocoders = append(geocoders, geocoder)
+			}
+			chain := nominatim.ChainGeocoders(geocoders...)
+			got, err := chain.Search(context.TODO(), nominatim.SearchQuery{FreeFormQuery: "lisboa"})
+			if !errors.Is(err, tt.wantErr) || (tt.wantErr == nil && err != nil) {
+				t.Fatalf("Search() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Search() = %v, want %v", got, tt.want)
+			}
+			calls := make([]int, 0, len(tt.geocoders))
+			for _, geocoder := range tt.geocoders {
+				calls = append(calls, geocoder.calls)
+			}
+			if !reflect.DeepEqual(calls, tt.wantCalls) {
+				t.Errorf("Search() calls = %v, want %v", calls, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestChainGeocoders_Reverse(t *testing.T) {
+	first, second := &geocoderFunc{err: nominatim.ErrUnableToGeocode}, &geocoderFunc{results: []nominatim.Result{{PlaceId: 2}}}
+	got, err := nominatim.ChainGeocoders(first, second).Reverse(context.TODO(), nominatim.ReverseQuery{Latitude: "38.7", Longitude: "-9.1"})
+	if err != nil || got.PlaceId != 2 {
+		t.Errorf("Reverse() = %v, %v, want place 2", got.PlaceId, err)
+	}
+}
+
+func TestChainGeocoders_Lookup(t *testing.T) {
+	first, second := &geocoderFunc{}, &geocoderFunc{results: []nominatim.Result{{PlaceId: 2}}}
+	got, err := nominatim.ChainGeocoders(first, second).Lookup(context.TODO(), nominatim.LookupQuery{OSMIDs: []string{"R1"}})
+	if err != nil || len(got) != 1 || got[0].PlaceId != 2 {
+		t.Errorf("Lookup() = %v, %v, want place 2", got, err)
+	}
+	chain := nominatim.ChainGeocoders(&geocoderFunc{}, &geocoderFunc{err: nominatim.ErrNoResults})
+	got, err = chain.Lookup(context.TODO(), nominatim.LookupQuery{OSMIDs: []string{"R1"}})
+	if err != nil || got == nil || len(got) != 0 {
+		t.Errorf("Lookup() = %#v, %v, want an empty slice as the Client", got, err)
+	}
+}
+
+func TestChainGeocoders_canceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.TODO())
+	cancel()
+	first, second := &geocoderFunc{err: context.Canceled}, &geocoderFunc{results: []nominatim.Result{{PlaceId: 2}}}
+	if _, err := nominatim.ChainGeocoders(first, second).Search(ctx, nominatim.SearchQuery{}); !errors.Is(err, context.Canceled) || second.calls != 0 {
+		t.Errorf("Search() error = %v, calls %v, want %v and no fallback", err, second.calls, context.Canceled)
+	}
+}
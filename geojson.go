@@ -0,0 +1,119 @@
+package nominatim
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// geoJSONFeatureCollection is a GeoJSON FeatureCollection of results.
+type geoJSONFeatureCollection struct {
+	Type     string           `json:"type"`
+	Features []geoJSONFeature `json:"features"`
+}
+
+// geoJSONFeature is a GeoJSON Feature locating a result.
+type geoJSONFeature struct {
+	Type       string            `json:"type"`
+	BBox       []float64         `json:"bbox,omitempty"`
+	Geometry   json.RawMessage   `json:"geometry"`
+	Properties geoJSONProperties `json:"properties"`
+}
+
+// geoJSONPoint is a GeoJSON Point, as [longitude, latitude].
+type geoJSONPoint struct {
+	Type        string     `json:"type"`
+	Coordinates [2]float64 `json:"coordinates"`
+}
+
+// geoJSONProperties holds the fields of a result kept as the properties of its GeoJSON Feature.
+type geoJSONProperties struct {
+	PlaceId     int               `json:"place_id"`
+	OsmType     string            `json:"osm_type"`
+	OsmId       int               `json:"osm_id"`
+	PlaceRank   int               `json:"place_rank,omitempty"`
+	Category    string            `json:"category"`
+	Type        string            `json:"type"`
+	Importance  float64           `json:"importance"`
+	AddressType string            `json:"addresstype,omitempty"`
+	Name        string            `json:"name"`
+	DisplayName string            `json:"display_name"`
+	Address     map[string]string `json:"address,omitempty"`
+	NameDetails map[string]string `json:"namedetails,omitempty"`
+	ExtraTags   map[string]string `json:"extratags,omitempty"`
+}
+
+// ResultsToGeoJSON marshals the given results as a GeoJSON FeatureCollection, as consumed by Leaflet or Mapbox, with a
+// Feature per result. The geometry of the features is the one requested through PolygonGeoJSON, when present, or the
+// point of the result otherwise, and their properties are the fields of the result.
+func ResultsToGeoJSON(results []Result) ([]byte, error) {
+	collection := geoJSONFeatureCollection{Type: "FeatureCollection", Features: make([]geoJSONFeature, 0, len(results))}
+	for _, result := range results {
+		feature, err := result.geoJSONFeature()
+		if err != nil {
+			return nil, err
+		}
+		collection.Features = append(collection.Features, feature)
+	}
+	return json.Marshal(collection)
+}
+
+// geoJSONFeature builds the GeoJSON Feature of the Result.
+func (r Result) geoJSONFeature() (geoJSONFeature, error) {
+	feature := geoJSONFeature{
+		Type: "Feature",
+		Properties: geoJSONProperties{
+			PlaceId:     r.PlaceId,
+			OsmType:     r.OsmType,
+			OsmId:       r.OsmId,
+			PlaceRank:   r.PlaceRank,
+			Category:    r.Category,
+			Type:        r.Type,
+			Importance:  r.Importance,
+			AddressType: r.AddressType,
+			Name:        r.Name,
+			DisplayName: r.DisplayName,
+			NameDetails: r.NameDetails,
+			ExtraTags:   r.ExtraTags,
+		},
+	}
+	address, err := r.Address.fields()
+	if err != nil {
+		return geoJSONFeature{}, err
+	}
+	feature.Properties.Address = address
+	if box, err := r.BBox(); err == nil {
+		feature.BBox = []float64{box.MinLon, box.MinLat, box.MaxLon, box.MaxLat}
+	}
+	if len(r.GeoJSON) > 0 && string(r.GeoJSON) != "null" {
+		feature.Geometry = r.GeoJSON
+		return feature, nil
+	}
+	point, err := r.Point()
+	if err != nil {
+		return geoJSONFeature{}, fmt.Errorf("nominatim: place %d has no geometry: %w", r.PlaceId, err)
+	}
+	geometry, err := json.Marshal(geoJSONPoint{Type: "Point", Coordinates: [2]float64{point.Lon, point.Lat}})
+	if err != nil {
+		return geoJSONFeature{}, err
+	}
+	feature.Geometry = geometry
+	return feature, nil
+}
+
+// fields returns the fields of the address filled, by their names as sent by Nominatim.
+func (a Address) fields() (map[string]string, error) {
+	data, err := json.Marshal(a)
+	if err != nil {
+		return nil, err
+	}
+	fields := make(map[string]string)
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	for name, value := range fields {
+		if value == "" {
+			delete(fields, name)
+		}
+	}
+	return fields, nil
+}
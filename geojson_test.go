@@ -0,0 +1,72 @@
+package nominatim_test
+
+import (
+	"encoding/json"
+	"github.com/diegohordi/nominatim"
+	"testing"
+)
+
+func TestResultsToGeoJSON(t *testing.T) {
+	belem := nominatim.Result{
+		PlaceId:     3,
+		OsmType:     "way",
+		OsmId:       24961587,
+		Lat:         "38.6916",
+		Lon:         "-9.2160",
+		Category:    "tourism",
+		Type:        "attraction",
+		Importance:  0.6,
+		Name:        "Torre de Belém",
+		DisplayName: "Torre de Belém, Lisboa",
+		Address:     nominatim.Address{City: "Lisboa", CountryCode: "pt"},
+	}
+	outlined := belem
+	outlined.BoundingBox = []string{"38.6914", "38.6918", "-9.2162", "-9.2158"}
+	outlined.GeoJSON = json.RawMessage(`{"type": "Polygon", "coordinates": [[[-9.2162, 38.6914], [-9.2158, 38.6918], [-9.2162, 38.6914]]]}`)
+	unlocated := belem
+	unlocated.Lat = ""
+	properties := `"properties":{"place_id":3,"osm_type":"way","osm_id":24961587,"category":"tourism","type":"attraction",` +
+		`"importance":0.6,"name":"Torre de Belém","display_name":"Torre de Belém, Lisboa","address":{"city":"Lisboa","country_code":"pt"}}`
+	tests := []struct {
+		name    string
+		results []nominatim.Result
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "should marshal an empty collection",
+			want: `{"type":"FeatureCollection","features":[]}`,
+		},
+		{
+			name:    "should locate the results by their points",
+			results: []nominatim.Result{belem},
+			want: `{"type":"FeatureCollection","features":[{"type":"Feature",` +
+				`"geometry":{"type":"Point","coordinates":[-9.216,38.6916]},` + properties + `}]}`,
+		},
+		{
+			name:    "should locate the results by their polygons and bounding boxes",
+			results: []nominatim.Result{outlined},
+			want: `{"type":"FeatureCollection","features":[{"type":"Feature","bbox":[-9.2162,38.6914,-9.2158,38.6918],` +
+				`"geometry":{"type":"Polygon","coordinates":[[[-9.2162,38.6914],[-9.2158,38.6918],[-9.2162,38.6914]]]},` +
+				properties + `}]}`,
+		},
+		{
+			name:    "should fail on the results without location",
+			results: []nominatim.Result{belem, unlocated},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := nominatim.ResultsToGeoJSON(tt.results)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ResultsToGeoJSON() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && string(got) != tt.want {
+				t.Errorf("ResultsToGeoJSON() got = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
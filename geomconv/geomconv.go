@@ -0,0 +1,53 @@
+// Package geomconv converts the points, bounding boxes and geometries of the results from Nominatim API into the types
+// of github.com/twpayne/go-geom, for the downstream spatial processing, as storing the outlines of places in PostGIS.
+package geomconv
+
+import (
+	"fmt"
+
+	"github.com/diegohordi/nominatim"
+	"github.com/diegohordi/nominatim/geo"
+	"github.com/twpayne/go-geom"
+	"github.com/twpayne/go-geom/encoding/geojson"
+)
+
+// Point converts the given point into a geom.Point, in the XY layout, as [longitude, latitude].
+func Point(p geo.Point) *geom.Point {
+	return geom.NewPointFlat(geom.XY, []float64{p.Lon, p.Lat})
+}
+
+// Bounds converts the given bounding box into geom.Bounds, in the XY layout.
+func Bounds(box geo.BBox) *geom.Bounds {
+	return geom.NewBounds(geom.XY).Set(box.MinLon, box.MinLat, box.MaxLon, box.MaxLat)
+}
+
+// ResultPoint converts the coordinates of the given result into a geom.Point.
+func ResultPoint(result nominatim.Result) (*geom.Point, error) {
+	point, err := result.Point()
+	if err != nil {
+		return nil, err
+	}
+	return Point(point), nil
+}
+
+// ResultBounds converts the bounding box of the given result into geom.Bounds.
+func ResultBounds(result nominatim.Result) (*geom.Bounds, error) {
+	box, err := result.BBox()
+	if err != nil {
+		return nil, err
+	}
+	return Bounds(box), nil
+}
+
+// ResultGeometry converts the GeoJSON geometry of the given result, requested through PolygonGeoJSON, into a geom.T,
+// as a *geom.Polygon or a *geom.MultiPolygon. It returns nominatim.ErrNoGeometry if the result has none.
+func ResultGeometry(result nominatim.Result) (geom.T, error) {
+	if len(result.GeoJSON) == 0 || string(result.GeoJSON) == "null" {
+		return nil, nominatim.ErrNoGeometry
+	}
+	var geometry geom.T
+	if err := geojson.Unmarshal(result.GeoJSON, &geometry); err != nil {
+		return nil, fmt.Errorf("geomconv: invalid geometry: %w", err)
+	}
+	return geometry, nil
+}
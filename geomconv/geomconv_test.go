@@ -0,0 +1,68 @@
+package geomconv_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/diegohordi/nominatim"
+	"github.com/diegohordi/nominatim/geomconv"
+	"github.com/twpayne/go-geom"
+)
+
+var belem = nominatim.Result{
+	Lat:         "38.6916",
+	Lon:         "-9.2160",
+	BoundingBox: []string{"38.6914", "38.6918", "-9.2162", "-9.2158"},
+	GeoJSON:     []byte(`{"type":"Polygon","coordinates":[[[-9.2162,38.6914],[-9.2158,38.6914],[-9.2158,38.6918],[-9.2162,38.6914]]]}`),
+}
+
+func TestResultPoint(t *testing.T) {
+	got, err := geomconv.ResultPoint(belem)
+	if err != nil {
+		t.Fatalf("ResultPoint() error = %v", err)
+	}
+	if want := []float64{-9.2160, 38.6916}; !reflect.DeepEqual(got.FlatCoords(), want) {
+		t.Errorf("ResultPoint() got = %v, want %v", got.FlatCoords(), want)
+	}
+}
+
+func TestResultBounds(t *testing.T) {
+	got, err := geomconv.ResultBounds(belem)
+	if err != nil {
+		t.Fatalf("ResultBounds() error = %v", err)
+	}
+	if want := geom.NewBounds(geom.XY).Set(-9.2162, 38.6914, -9.2158, 38.6918); !reflect.DeepEqual(got, want) {
+		t.Errorf("ResultBounds() got = %v, want %v", got, want)
+	}
+}
+
+func TestResultGeometry(t *testing.T) {
+	tests := []struct {
+		name    string
+		geoJSON string
+		want    reflect.Type
+		wantErr error
+	}{
+		{name: "should convert polygons", geoJSON: string(belem.GeoJSON), want: reflect.TypeOf(&geom.Polygon{})},
+		{name: "should convert points", geoJSON: `{"type":"Point","coordinates":[-9.2160,38.6916]}`, want: reflect.TypeOf(&geom.Point{})},
+		{name: "should fail without geometry", wantErr: nominatim.ErrNoGeometry},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			result := nominatim.Result{}
+			if tt.geoJSON != "" {
+				result.GeoJSON = []byte(tt.geoJSON)
+			}
+			got, err := geomconv.ResultGeometry(result)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ResultGeometry() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && reflect.TypeOf(got) != tt.want {
+				t.Errorf("ResultGeometry() got = %T, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,10 @@
+module github.com/diegohordi/nominatim/geomconv
+
+go 1.25
+
+require (
+	github.com/diegohordi/nominatim v0.0.0
+	github.com/twpayne/go-geom v1.5.4
+)
+
+replace github.com/diegohordi/nominatim => ../
@@ -0,0 +1,33 @@
+package nominatim
+
+import (
+	"net/http"
+)
+
+// WithDefaultHeaders makes the client send the given headers on every request, as an X-Api-Key for fronting gateways
+// or an Authorization for protected self-hosted instances, without a custom Transport. They are merged with the ones
+// given before, overriding the User-Agent and Referer, if given.
+func WithDefaultHeaders(header http.Header) Option {
+	return func(d *defaultClient) {
+		if d.defaultHeaders == nil {
+			d.defaultHeaders = http.Header{}
+		}
+		for key, values := range header {
+			d.defaultHeaders[http.CanonicalHeaderKey(key)] = append([]string(nil), values...)
+		}
+	}
+}
+
+// setHeaders sets the client-wide headers on the given request, followed by the ones of the call, if any.
+func (d defaultClient) setHeaders(req *http.Request) {
+	req.Header.Set(headerUserAgent, d.userAgent)
+	if d.referer != "" {
+		req.Header.Set(headerReferer, d.referer)
+	}
+	for key, values := range d.defaultHeaders {
+		req.Header[key] = append([]string(nil), values...)
+	}
+	callOptionsFrom(req.Context()).setHeaders(req)
+	d.setAcceptEncoding(req)
+	setConditionalHeaders(req)
+}
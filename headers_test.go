@@ -0,0 +1,57 @@
+package nominatim_test
+
+import (
+	"context"
+	"github.com/diegohordi/nominatim"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func Test_WithDefaultHeaders(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []nominatim.Option
+		want http.Header
+	}{
+		{
+			name: "should send the given headers",
+			opts: []nominatim.Option{nominatim.WithDefaultHeaders(http.Header{"x-api-key": {"secret"}})},
+			want: http.Header{"X-Api-Key": {"secret"}, "User-Agent": {nominatim.DefaultUserAgent}},
+		},
+		{
+			name: "should merge the headers given before",
+			opts: []nominatim.Option{
+				nominatim.WithDefaultHeaders(http.Header{"X-Api-Key": {"secret"}}),
+				nominatim.WithDefaultHeaders(http.Header{"Authorization": {"Bearer token"}}),
+			},
+			want: http.Header{"X-Api-Key": {"secret"}, "Authorization": {"Bearer token"}, "User-Agent": {nominatim.DefaultUserAgent}},
+		},
+		{
+			name: "should override the User-Agent",
+			opts: []nominatim.Option{
+				nominatim.WithUserAgent("acme-geocoder/1.0"),
+				nominatim.WithDefaultHeaders(http.Header{"User-Agent": {"acme-gateway/2.0"}}),
+			},
+			want: http.Header{"User-Agent": {"acme-gateway/2.0"}},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			var got http.Header
+			transport := TransportFunc(func(req *http.Request) (*http.Response, error) {
+				got = req.Header
+				return statusFixture(http.StatusOK, mustLoadValidStatus(t))()
+			})
+			d := nominatim.NewClient("http://localhost:8080", nil, append(tt.opts, nominatim.WithTransport(transport))...)
+			if _, err := d.CheckStatus(context.TODO()); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("CheckStatus() sent headers = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
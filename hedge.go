@@ -0,0 +1,79 @@
+package nominatim
+
+import (
+	"context"
+	"reflect"
+	"time"
+)
+
+// hedging holds the configuration of hedged requests.
+type hedging struct {
+	baseURL string
+	delay   time.Duration
+}
+
+// WithHedging makes the client fire a duplicate of each request to the given base URL, whenever the response from
+// the primary one takes longer than the given delay or fails, taking the first successful response and cancelling
+// the other request. Responses to the query itself, as invalid query errors, are taken right away. Both requests are
+// bounded by the caller context.
+func WithHedging(baseURL string, delay time.Duration) Option {
+	return func(d *defaultClient) {
+		d.hedging = &hedging{baseURL: baseURL, delay: delay}
+	}
+}
+
+// hedgeOutcome holds the outcome of one of the hedged requests.
+type hedgeOutcome struct {
+	v   interface{}
+	err error
+}
+
+// getHedged performs the GET request for the given query through the primary base URL, along with its mirrors, and
+// through the hedging one, decoding into v the first successful response.
+func (d defaultClient) getHedged(ctx context.Context, query QueryEncoder, v interface{}) error {
+	ctx, cancelFn := context.WithCancel(ctx)
+	defer cancelFn()
+	outcomes := make(chan hedgeOutcome, 2)
+	run := func(get func(ctx context.Context, v interface{}) error) {
+		target := reflect.New(reflect.TypeOf(v).Elem()).Interface()
+		outcomes <- hedgeOutcome{v: target, err: get(ctx, target)}
+	}
+	primary := func(ctx context.Context, v interface{}) error {
+		return d.getWithFailover(ctx, query, v)
+	}
+	secondary := func(ctx context.Context, v interface{}) error {
+		return d.getFrom(ctx, d.hedging.baseURL, query, v)
+	}
+
+	go run(primary)
+	timer := time.NewTimer(d.hedging.delay)
+	defer timer.Stop()
+	hedge := timer.C
+	pending := 1
+	var firstErr error
+	for pending > 0 {
+		select {
+		case <-hedge:
+			hedge = nil
+			pending++
+			go run(secondary)
+		case outcome := <-outcomes:
+			pending--
+			if outcome.err == nil || !shouldFailover(ctx, outcome.err) {
+				if outcome.err == nil {
+					reflect.ValueOf(v).Elem().Set(reflect.ValueOf(outcome.v).Elem())
+				}
+				return outcome.err
+			}
+			if firstErr == nil {
+				firstErr = outcome.err
+			}
+			if hedge != nil {
+				hedge = nil
+				pending++
+				go run(secondary)
+			}
+		}
+	}
+	return firstErr
+}
@@ -0,0 +1,111 @@
+package nominatim_test
+
+import (
+	"context"
+	"errors"
+	"github.com/diegohordi/nominatim"
+	"net/http"
+	"reflect"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+)
+
+func Test_Hedging(t *testing.T) {
+	type response struct {
+		delay   time.Duration
+		fixture func() (*http.Response, error)
+	}
+	tests := []struct {
+		name        string
+		responses   map[string]response
+		wantHosts   []string
+		wantMessage string
+		wantErr     error
+	}{
+		{
+			name: "should not hedge fast responses",
+			responses: map[string]response{
+				"primary": {fixture: statusFixture(http.StatusOK, []byte(`{"message": "primary"}`))},
+			},
+			wantHosts:   []string{"primary"},
+			wantMessage: "primary",
+		},
+		{
+			name: "should take the hedged response when the primary is slow",
+			responses: map[string]response{
+				"primary":   {delay: 10 * time.Second, fixture: statusFixture(http.StatusOK, []byte(`{"message": "primary"}`))},
+				"secondary": {fixture: statusFixture(http.StatusOK, []byte(`{"message": "secondary"}`))},
+			},
+			wantHosts:   []string{"primary", "secondary"},
+			wantMessage: "secondary",
+		},
+		{
+			name: "should hedge right away when the primary fails",
+			responses: map[string]response{
+				"primary":   {fixture: statusFixture(http.StatusBadGateway, nil)},
+				"secondary": {fixture: statusFixture(http.StatusOK, []byte(`{"message": "secondary"}`))},
+			},
+			wantHosts:   []string{"primary", "secondary"},
+			wantMessage: "secondary",
+		},
+		{
+			name: "should not hedge invalid queries",
+			responses: map[string]response{
+				"primary": {fixture: statusFixture(http.StatusBadRequest, nil)},
+			},
+			wantHosts: []string{"primary"},
+			wantErr:   nominatim.ErrInvalidQuery,
+		},
+		{
+			name: "should fail when both fail",
+			responses: map[string]response{
+				"primary":   {fixture: statusFixture(http.StatusBadGateway, nil)},
+				"secondary": {fixture: statusFixture(http.StatusServiceUnavailable, nil)},
+			},
+			wantHosts: []string{"primary", "secondary"},
+			wantErr:   nominatim.ErrServerError,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			mu := sync.Mutex{}
+			hosts := make([]string, 0)
+			transport := TransportFunc(func(req *http.Request) (*http.Response, error) {
+				mu.Lock()
+				hosts = append(hosts, req.URL.Host)
+				mu.Unlock()
+				resp := tt.responses[req.URL.Host]
+				select {
+				case <-time.After(resp.delay):
+					return resp.fixture()
+				case <-req.Context().Done():
+					return nil, req.Context().Err()
+				}
+			})
+			d := nominatim.NewClient("http://primary", nil,
+				nominatim.WithTransport(transport), nominatim.WithHedging("http://secondary", 50*time.Millisecond))
+			start := time.Now()
+			got, err := d.CheckStatus(context.TODO())
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("CheckStatus() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if elapsed := time.Since(start); elapsed > time.Second {
+				t.Errorf("CheckStatus() took %v", elapsed)
+			}
+			if got.Message != tt.wantMessage {
+				t.Errorf("CheckStatus() got = %v, want %v", got.Message, tt.wantMessage)
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			sort.Strings(hosts)
+			if !reflect.DeepEqual(hosts, tt.wantHosts) {
+				t.Errorf("CheckStatus() hosts = %v, want %v", hosts, tt.wantHosts)
+			}
+		})
+	}
+}
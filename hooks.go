@@ -0,0 +1,66 @@
+package nominatim
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// Hooks observes the lifecycle of the requests sent by the client, so applications can audit them, measure them or
+// mutate them, as signing them, without replacing the whole Transport. Every hook is optional, and they are called
+// for every request sent, including the retries, the mirrors and the hedged ones, but not for the responses served
+// from the cache. The hooks may be called concurrently, so they must be safe for concurrent use.
+type Hooks struct {
+
+	// OnRequest is called right before a request is sent, and may mutate it, as setting headers. An error fails the
+	// request without sending it, and is returned by the call, unless retried.
+	OnRequest func(req *http.Request) error
+
+	// OnResponse is called once a response is received, whatever its status code, with the time taken to receive it.
+	// Its body was already read, and must not be read again.
+	OnResponse func(req *http.Request, resp *http.Response, duration time.Duration)
+
+	// OnError is called when a request fails, either because the server couldn't be reached, or its response is an
+	// error or can't be decoded. Responses sent with an error are given to OnResponse as well.
+	OnError func(req *http.Request, err error)
+
+	// OnSlowRequest is called when a request took longer than the threshold of WithSlowRequestThreshold, along with
+	// the time it took, once it is done.
+	OnSlowRequest func(req *http.Request, duration time.Duration)
+}
+
+// WithHooks makes the client call the given hooks along the lifecycle of its requests. It can be given more than once,
+// and the hooks are called in the order they were given, while the first OnRequest failing stops the others.
+func WithHooks(hooks Hooks) Option {
+	return func(d *defaultClient) {
+		d.observers = append(d.observers, hooks)
+	}
+}
+
+func (h Hooks) requestStarted(_ context.Context, _ QueryEncoder, req *http.Request) error {
+	if h.OnRequest == nil {
+		return nil
+	}
+	return h.OnRequest(req)
+}
+
+func (h Hooks) requestFinished(_ context.Context, _ QueryEncoder, req *http.Request, resp *http.Response, duration time.Duration, err error) {
+	if resp != nil && h.OnResponse != nil {
+		h.OnResponse(req, resp, duration)
+	}
+	if err != nil && h.OnError != nil {
+		h.OnError(req, err)
+	}
+}
+
+func (h Hooks) slowRequest(_ context.Context, _ QueryEncoder, req *http.Request, duration time.Duration) {
+	if h.OnSlowRequest != nil {
+		h.OnSlowRequest(req, duration)
+	}
+}
+
+func (h Hooks) retrying(context.Context, QueryEncoder, int, time.Duration, error) {}
+
+func (h Hooks) rateLimited(context.Context, QueryEncoder, time.Duration) {}
+
+func (h Hooks) decodeFailed(context.Context, QueryEncoder, error) {}
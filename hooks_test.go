@@ -0,0 +1,142 @@
+package nominatim_test
+
+import (
+	"context"
+	"errors"
+	"github.com/diegohordi/nominatim"
+	"net/http"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+func Test_WithHooks(t *testing.T) {
+	errRefused := errors.New("refused")
+	tests := []struct {
+		name         string
+		response     func() (*http.Response, error)
+		hooks        func(events *[]string) []nominatim.Hooks
+		wantHeader   string
+		wantEvents   []string
+		wantRequests int
+		wantErr      error
+	}{
+		{
+			name:     "should mutate the requests and observe the responses",
+			response: statusFixture(http.StatusOK, mustLoadValidStatus(t)),
+			hooks: func(events *[]string) []nominatim.Hooks {
+				return []nominatim.Hooks{{
+					OnRequest: func(req *http.Request) error {
+						*events = append(*events, "request "+req.URL.Path)
+						req.Header.Set("X-Signature", "signed")
+						return nil
+					},
+					OnResponse: func(req *http.Request, resp *http.Response, duration time.Duration) {
+						*events = append(*events, "response "+resp.Status)
+					},
+					OnError: func(req *http.Request, err error) {
+						*events = append(*events, "error")
+					},
+				}}
+			},
+			wantHeader:   "signed",
+			wantEvents:   []string{"request /status", "response 200 OK"},
+			wantRequests: 1,
+		},
+		{
+			name:     "should observe the errors sent by the server",
+			response: statusFixture(http.StatusInternalServerError, nil),
+			hooks: func(events *[]string) []nominatim.Hooks {
+				return []nominatim.Hooks{{
+					OnResponse: func(req *http.Request, resp *http.Response, duration time.Duration) {
+						*events = append(*events, "response "+resp.Status)
+					},
+					OnError: func(req *http.Request, err error) {
+						*events = append(*events, "error "+string(nominatim.ClassifyOutcome(err)))
+					},
+				}}
+			},
+			wantEvents:   []string{"response 500 Internal Server Error", "error server-error"},
+			wantRequests: 1,
+			wantErr:      nominatim.ErrServerError,
+		},
+		{
+			name: "should observe the failures to reach the server",
+			response: func() (*http.Response, error) {
+				return nil, errors.New("connection refused")
+			},
+			hooks: func(events *[]string) []nominatim.Hooks {
+				return []nominatim.Hooks{{
+					OnError: func(req *http.Request, err error) {
+						*events = append(*events, "error "+string(nominatim.ClassifyOutcome(err)))
+					},
+				}}
+			},
+			wantEvents:   []string{"error transport-error"},
+			wantRequests: 1,
+		},
+		{
+			name:     "should call the hooks in their order, stopping at the first request refused",
+			response: statusFixture(http.StatusOK, mustLoadValidStatus(t)),
+			hooks: func(events *[]string) []nominatim.Hooks {
+				return []nominatim.Hooks{
+					{
+						OnRequest: func(req *http.Request) error {
+							*events = append(*events, "first")
+							return errRefused
+						},
+						OnError: func(req *http.Request, err error) {
+							*events = append(*events, "error")
+						},
+					},
+					{
+						OnRequest: func(req *http.Request) error {
+							*events = append(*events, "second")
+							return nil
+						},
+					},
+				}
+			},
+			wantEvents: []string{"first", "error"},
+			wantErr:    errRefused,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			mu := sync.Mutex{}
+			var events []string
+			var header string
+			requests := 0
+			transport := TransportFunc(func(req *http.Request) (*http.Response, error) {
+				mu.Lock()
+				defer mu.Unlock()
+				requests++
+				header = req.Header.Get("X-Signature")
+				return tt.response()
+			})
+			opts := []nominatim.Option{nominatim.WithTransport(transport)}
+			for _, hooks := range tt.hooks(&events) {
+				opts = append(opts, nominatim.WithHooks(hooks))
+			}
+			d := nominatim.NewClient("http://localhost:8080", nil, opts...)
+			_, err := d.CheckStatus(context.TODO())
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("CheckStatus() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			if requests != tt.wantRequests {
+				t.Errorf("CheckStatus() requests = %v, want %v", requests, tt.wantRequests)
+			}
+			if header != tt.wantHeader {
+				t.Errorf("CheckStatus() sent X-Signature = %v, want %v", header, tt.wantHeader)
+			}
+			if !reflect.DeepEqual(events, tt.wantEvents) {
+				t.Errorf("CheckStatus() events = %v, want %v", events, tt.wantEvents)
+			}
+		})
+	}
+}
@@ -0,0 +1,73 @@
+package nominatim
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// maxHouseNumberCandidates limits how many individual house numbers are tried when a range or list returns nothing.
+const maxHouseNumberCandidates = 10
+
+var (
+	houseNumberRangePattern     = regexp.MustCompile(`^(\d+)([a-zA-Z]?)\s*(?:-|–|—|to|bis)\s*(\d+)([a-zA-Z]?)$`)
+	houseNumberSeparatorPattern = regexp.MustCompile(`\s*[,;/&+]\s*|\s+`)
+)
+
+// normalizeHouseNumber normalizes house number ranges and lists, as "12 – 14" into "12-14" and "12 / 14; 16" into
+// "12,14,16". Any other house number is only trimmed.
+func normalizeHouseNumber(houseNumber string) string {
+	houseNumber = strings.TrimSpace(houseNumber)
+	if matches := houseNumberRangePattern.FindStringSubmatch(houseNumber); matches != nil {
+		return matches[1] + matches[2] + "-" + matches[3] + matches[4]
+	}
+	if parts := splitHouseNumberList(houseNumber); len(parts) > 1 {
+		return strings.Join(parts, ",")
+	}
+	return houseNumber
+}
+
+// expandHouseNumber expands a house number range or list into the individual house numbers it holds. Ranges whose
+// bounds share the same parity are expanded into one side of the street only, as usual in European addresses. Single
+// house numbers are not expanded.
+func expandHouseNumber(houseNumber string) []string {
+	houseNumber = normalizeHouseNumber(houseNumber)
+	if matches := houseNumberRangePattern.FindStringSubmatch(houseNumber); matches != nil {
+		from, _ := strconv.Atoi(matches[1])
+		to, _ := strconv.Atoi(matches[3])
+		if from > to {
+			from, to = to, from
+		}
+		step := 1
+		if from%2 == to%2 {
+			step = 2
+		}
+		candidates := make([]string, 0)
+		for n := from; n <= to && len(candidates) < maxHouseNumberCandidates; n += step {
+			candidates = append(candidates, strconv.Itoa(n))
+		}
+		return candidates
+	}
+	if parts := splitHouseNumberList(houseNumber); len(parts) > 1 {
+		if len(parts) > maxHouseNumberCandidates {
+			parts = parts[:maxHouseNumberCandidates]
+		}
+		return parts
+	}
+	return nil
+}
+
+// splitHouseNumberList splits a house number list, returning nil if any of its items doesn't start with a digit.
+func splitHouseNumberList(houseNumber string) []string {
+	parts := make([]string, 0)
+	for _, part := range houseNumberSeparatorPattern.Split(houseNumber, -1) {
+		if part == "" {
+			continue
+		}
+		if part[0] < '0' || part[0] > '9' {
+			return nil
+		}
+		parts = append(parts, part)
+	}
+	return parts
+}
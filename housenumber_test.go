@@ -0,0 +1,93 @@
+package nominatim_test
+
+import (
+	"context"
+	"errors"
+	"github.com/diegohordi/nominatim"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+func Test_Search_HouseNumber(t *testing.T) {
+	tests := []struct {
+		name        string
+		houseNumber string
+		found       string
+		wantStreets []string
+		wantErr     error
+	}{
+		{
+			name:        "should send a single house number as is",
+			houseNumber: "12",
+			found:       "12 Main Street",
+			wantStreets: []string{"12 Main Street"},
+		},
+		{
+			name:        "should normalize a range",
+			houseNumber: "12 – 14",
+			found:       "12-14 Main Street",
+			wantStreets: []string{"12-14 Main Street"},
+		},
+		{
+			name:        "should retry each house number from one side of the street",
+			houseNumber: "12-16",
+			found:       "14 Main Street",
+			wantStreets: []string{"12-16 Main Street", "12 Main Street", "14 Main Street"},
+		},
+		{
+			name:        "should retry each house number from a list",
+			houseNumber: "12 / 15",
+			found:       "15 Main Street",
+			wantStreets: []string{"12,15 Main Street", "12 Main Street", "15 Main Street"},
+		},
+		{
+			name:        "should fail when no house number is found",
+			houseNumber: "12-13",
+			wantStreets: []string{"12-13 Main Street", "12 Main Street", "13 Main Street"},
+			wantErr:     nominatim.ErrNoResults,
+		},
+		{
+			name:        "should not retry a single house number",
+			houseNumber: "12",
+			wantStreets: []string{"12 Main Street"},
+			wantErr:     nominatim.ErrNoResults,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			mu := sync.Mutex{}
+			streets := make([]string, 0)
+			d := nominatim.NewClient("http://localhost:8080", &http.Client{
+				Transport: RoundTripFunc(func(req *http.Request) *http.Response {
+					street := req.URL.Query().Get("street")
+					mu.Lock()
+					streets = append(streets, street)
+					mu.Unlock()
+					resp := httptest.NewRecorder()
+					if street == tt.found {
+						resp.Body.Write(mustLoadValidSearchResults(t))
+						return resp.Result()
+					}
+					resp.Body.WriteString("[]")
+					return resp.Result()
+				}),
+			})
+			query := nominatim.NewSearchQuery()
+			query.HouseNumber = tt.houseNumber
+			query.Street = "Main Street"
+			_, err := d.Search(context.TODO(), *query)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Search() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(streets, tt.wantStreets) {
+				t.Errorf("Search() streets = %v, want %v", streets, tt.wantStreets)
+			}
+		})
+	}
+}
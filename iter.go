@@ -0,0 +1,117 @@
+//go:build go1.23
+
+package nominatim
+
+import (
+	"context"
+	"iter"
+)
+
+// SearchIter returns an iterator over the results of the given query, searched through the given client, as an
+// alternative to the slice returned by Search, to compose with the iterator functions of the standard library. The
+// results are searched lazily, a page of the query limit at a time, excluding the places already yielded from the
+// next pages, until the results are exhausted or the iteration stops. A failed search yields its error once, along
+// with a zero Result. Like every iterator-based variant of this package, it needs Go 1.23 or later, while the slice
+// based API remains available for older versions.
+func SearchIter(ctx context.Context, client SearchHandler, query SearchQuery, opts ...CallOption) iter.Seq2[Result, error] {
+	return func(yield func(Result, error) bool) {
+		pager := newSearchPager(client, query, opts)
+		for {
+			page, err := pager.next(ctx)
+			if err != nil {
+				yield(Result{}, err)
+				return
+			}
+			if len(page) == 0 {
+				return
+			}
+			for _, result := range page {
+				if !yield(result, nil) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// LookupIter returns an iterator over the results of the given OSM IDs, looked up through the given client lazily, a
+// chunk of up to 50 at a time, yielded in the order of the given IDs, as in LookupMany. A failed lookup yields its
+// error once, along with a zero Result.
+func LookupIter(ctx context.Context, client LookupHandler, osmIDs []string, opts ...CallOption) iter.Seq2[Result, error] {
+	return func(yield func(Result, error) bool) {
+		for _, chunk := range chunkOSMIDs(osmIDs) {
+			results, err := client.Lookup(ctx, *NewLookupQuery(chunk...), opts...)
+			if err != nil {
+				yield(Result{}, err)
+				return
+			}
+			for _, result := range orderByOSMIDs(chunk, results) {
+				if !yield(result, nil) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// SearchManyIter returns an iterator over the outcomes of the given queries, searched as in SearchMany, yielded
+// along with the index of their query as soon as they are available, rather than in order. Stopping the iteration
+// cancels the searches in flight.
+func SearchManyIter(ctx context.Context, client SearchHandler, queries []SearchQuery, opts ...BatchOption) iter.Seq2[int, SearchResult] {
+	return func(yield func(int, SearchResult) bool) {
+		config := newBatchConfig(opts)
+		outcomes := make([]SearchResult, len(queries))
+		iterateBatch(ctx, len(queries), config.workers, func(ctx context.Context, i int) {
+			results, err := client.Search(ctx, queries[i], config.callOptions...)
+			outcomes[i] = SearchResult{Query: queries[i], Results: results, Err: err}
+		}, func(ctx context.Context, i int) {
+			outcomes[i] = SearchResult{Query: queries[i], Err: ctx.Err()}
+		}, func(i int) bool {
+			return yield(i, outcomes[i])
+		})
+	}
+}
+
+// ReverseManyIter returns an iterator over the outcomes of the given queries, reverse geocoded as in ReverseMany,
+// yielded along with the index of their query as soon as they are available, rather than in order. Stopping the
+// iteration cancels the calls in flight.
+func ReverseManyIter(ctx context.Context, client ReverseHandler, queries []ReverseQuery, opts ...BatchOption) iter.Seq2[int, ReverseResult] {
+	return func(yield func(int, ReverseResult) bool) {
+		config := newBatchConfig(opts)
+		outcomes := make([]ReverseResult, len(queries))
+		iterateBatch(ctx, len(queries), config.workers, func(ctx context.Context, i int) {
+			result, err := client.Reverse(ctx, queries[i], config.callOptions...)
+			outcomes[i] = ReverseResult{Query: queries[i], Result: result, Err: err}
+		}, func(ctx context.Context, i int) {
+			outcomes[i] = ReverseResult{Query: queries[i], Err: ctx.Err()}
+		}, func(i int) bool {
+			return yield(i, outcomes[i])
+		})
+	}
+}
+
+// iterateBatch runs the given calls as in runBatch, on a context cancelled once the iteration stops, yielding the
+// index of every call as soon as it's done.
+func iterateBatch(ctx context.Context, n, workers int, call, skip func(ctx context.Context, i int), yield func(i int) bool) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	done := make(chan int)
+	go func() {
+		defer close(done)
+		runBatch(ctx, n, workers, func(i int) {
+			call(ctx, i)
+			done <- i
+		}, func(i int) {
+			skip(ctx, i)
+			done <- i
+		})
+	}()
+	for i := range done {
+		if !yield(i) {
+			cancel()
+			for range done {
+			}
+			return
+		}
+	}
+}
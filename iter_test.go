@@ -0,0 +1,218 @@
+//go:build go1.23
+
+package nominatim_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/diegohordi/nominatim"
+	"github.com/diegohordi/nominatim/nominatimtest"
+	"net/http"
+	"strconv"
+	"testing"
+)
+
+func Test_SearchIter(t *testing.T) {
+	tests := []struct {
+		name        string
+		statusCode  int
+		body        func() []byte
+		stopAfter   int
+		wantResults int
+		wantErr     error
+	}{
+		{
+			name:        "should yield every result",
+			statusCode:  http.StatusOK,
+			body:        func() []byte { return mustLoadValidSearchResults(t) },
+			wantResults: len(mustLoadValidSearchResultsAsSlice(t)),
+		},
+		{
+			name:        "should stop when the iteration stops",
+			statusCode:  http.StatusOK,
+			body:        func() []byte { return mustLoadValidSearchResults(t) },
+			stopAfter:   1,
+			wantResults: 1,
+		},
+		{
+			name:       "should yield the error of a failed search",
+			statusCode: http.StatusInternalServerError,
+			body:       func() []byte { return nil },
+			wantErr:    nominatim.ErrServerError,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			d := nominatim.NewClient("http://localhost:8080", nil, nominatim.WithTransport(TransportFunc(func(req *http.Request) (*http.Response, error) {
+				return statusFixture(tt.statusCode, tt.body())()
+			})))
+			query := nominatim.NewSearchQuery(nominatim.WithFreeForm("lisboa"))
+			results := 0
+			for result, err := range nominatim.SearchIter(context.TODO(), d, *query) {
+				if err != nil {
+					if !errors.Is(err, tt.wantErr) {
+						t.Errorf("SearchIter() error = %v, wantErr %v", err, tt.wantErr)
+					}
+					break
+				}
+				if result.PlaceId == 0 {
+					t.Errorf("SearchIter() yielded an empty result")
+				}
+				results++
+				if results == tt.stopAfter {
+					break
+				}
+			}
+			if results != tt.wantResults {
+				t.Errorf("SearchIter() results = %v, want %v", results, tt.wantResults)
+			}
+		})
+	}
+}
+
+func ExampleSearchIter() {
+	server := nominatimtest.NewServer()
+	defer server.Close()
+
+	client := nominatim.NewClient(server.URL, nil)
+	for result, err := range nominatim.SearchIter(context.Background(), client, *nominatim.NewSearchQuery(nominatim.WithFreeForm("lisboa"))) {
+		if err != nil {
+			fmt.Println(err)
+			break
+		}
+		fmt.Println(result.Name)
+	}
+	// Output:
+	// Avenida da República
+	// Praça do Comércio
+	// Torre de Belém
+	// Farmácia Estácio
+}
+
+func Test_SearchIter_Pagination(t *testing.T) {
+	places := make([]nominatim.Result, 0, 25)
+	for i := 1; i <= 25; i++ {
+		places = append(places, nominatim.Result{PlaceId: i, Lat: "38.7", Lon: "-9.1", DisplayName: "Farmácia, Lisboa"})
+	}
+	tests := []struct {
+		name         string
+		stopAfter    int
+		wantResults  int
+		wantRequests int
+	}{
+		{name: "should page through every result", wantResults: 25, wantRequests: 3},
+		{name: "should only search the pages needed", stopAfter: 12, wantResults: 12, wantRequests: 2},
+		{name: "should not search beyond the first page when not needed", stopAfter: 10, wantResults: 10, wantRequests: 1},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			server := nominatimtest.NewServer(places...)
+			defer server.Close()
+			d := nominatim.NewClient(server.URL, nil)
+			seen := make(map[int]bool)
+			for result, err := range nominatim.SearchIter(context.TODO(), d, *nominatim.NewSearchQuery(nominatim.WithFreeForm("farmácia"))) {
+				if err != nil {
+					t.Fatalf("SearchIter() error = %v", err)
+				}
+				if seen[result.PlaceId] {
+					t.Errorf("SearchIter() yielded %v twice", result.PlaceId)
+				}
+				seen[result.PlaceId] = true
+				if len(seen) == tt.stopAfter {
+					break
+				}
+			}
+			if len(seen) != tt.wantResults {
+				t.Errorf("SearchIter() results = %v, want %v", len(seen), tt.wantResults)
+			}
+			if requests := server.Requests(); requests != tt.wantRequests {
+				t.Errorf("SearchIter() requests = %v, want %v", requests, tt.wantRequests)
+			}
+		})
+	}
+}
+
+func Test_SearchManyIter(t *testing.T) {
+	server := nominatimtest.NewServer()
+	defer server.Close()
+	client := nominatim.NewClient(server.URL, nil)
+	queries := []nominatim.SearchQuery{
+		*nominatim.NewSearchQuery(nominatim.WithFreeForm("torre de belém")),
+		*nominatim.NewSearchQuery(nominatim.WithFreeForm("hospital")),
+		*nominatim.NewSearchQuery(nominatim.WithFreeForm("praça do comércio")),
+	}
+	seen := make(map[int]bool)
+	for i, outcome := range nominatim.SearchManyIter(context.TODO(), client, queries, nominatim.WithWorkers(2)) {
+		if seen[i] {
+			t.Errorf("SearchManyIter() yielded %v twice", i)
+		}
+		seen[i] = true
+		if outcome.Query.FreeFormQuery != queries[i].FreeFormQuery {
+			t.Errorf("SearchManyIter() query = %v, want %v", outcome.Query.FreeFormQuery, queries[i].FreeFormQuery)
+		}
+		if wantErr := i == 1; (outcome.Err != nil) != wantErr {
+			t.Errorf("SearchManyIter() error = %v, wantErr %v", outcome.Err, wantErr)
+		}
+	}
+	if len(seen) != len(queries) {
+		t.Errorf("SearchManyIter() outcomes = %v, want %v", len(seen), len(queries))
+	}
+	for range nominatim.SearchManyIter(context.TODO(), client, queries, nominatim.WithWorkers(1)) {
+		break
+	}
+}
+
+func Test_ReverseManyIter(t *testing.T) {
+	server := nominatimtest.NewServer()
+	defer server.Close()
+	client := nominatim.NewClient(server.URL, nil)
+	queries := []nominatim.ReverseQuery{
+		*nominatim.NewReverseQuery("41.1487", "-8.6111"),
+		*nominatim.NewReverseQuery("test", "testing"),
+		*nominatim.NewReverseQuery("38.7076", "-9.1365"),
+	}
+	seen := make(map[int]bool)
+	for i, outcome := range nominatim.ReverseManyIter(context.TODO(), client, queries, nominatim.WithWorkers(2)) {
+		seen[i] = true
+		if outcome.Query.Latitude != queries[i].Latitude {
+			t.Errorf("ReverseManyIter() query = %v, want %v", outcome.Query.Latitude, queries[i].Latitude)
+		}
+		if wantErr := i == 1; (outcome.Err != nil) != wantErr {
+			t.Errorf("ReverseManyIter() error = %v, wantErr %v", outcome.Err, wantErr)
+		}
+	}
+	if len(seen) != len(queries) {
+		t.Errorf("ReverseManyIter() outcomes = %v, want %v", len(seen), len(queries))
+	}
+}
+
+func Test_LookupIter(t *testing.T) {
+	server := nominatimtest.NewServer(mustCreateNodes(t, 60)...)
+	defer server.Close()
+	client := nominatim.NewClient(server.URL, nil)
+	osmIDs := make([]string, 0, 60)
+	for i := 60; i >= 1; i-- {
+		osmIDs = append(osmIDs, "N"+strconv.Itoa(i))
+	}
+	want := 60
+	for result, err := range nominatim.LookupIter(context.TODO(), client, osmIDs) {
+		if err != nil {
+			t.Fatalf("LookupIter() error = %v", err)
+		}
+		if result.OsmId != want {
+			t.Errorf("LookupIter() got = %v, want %v", result.OsmId, want)
+		}
+		want--
+		if want == 40 {
+			break
+		}
+	}
+	if requests := server.Requests(); requests != 1 {
+		t.Errorf("LookupIter() requests = %v, want 1", requests)
+	}
+}
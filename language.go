@@ -0,0 +1,286 @@
+package nominatim
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// defaultLanguage is the language of the queries sent by the client with no languages, when it has no default ones.
+const defaultLanguage = "en"
+
+// languageWeightPattern matches the quality weights of the language ranges, from 0 up to 1, with up to 3 decimals.
+var languageWeightPattern = regexp.MustCompile(`^(0(\.[0-9]{0,3})?|1(\.0{0,3})?)$`)
+
+// CanonicalLanguage validates the given BCP 47 language tag, as "en-GB", "zh-Hant-TW" or "pt", checking it is well
+// formed as in RFC 5646, and returns it in its canonical case, as "en-GB" for "EN-gb". The wildcard "*" is accepted,
+// as in the Accept-Language header. It fails with an error matching ErrInvalidQuery if the tag is malformed.
+func CanonicalLanguage(tag string) (string, error) {
+	tag = strings.TrimSpace(tag)
+	if tag == "*" {
+		return tag, nil
+	}
+	subtags := strings.Split(strings.ToLower(tag), "-")
+	if !wellFormedLanguage(subtags) {
+		return "", fmt.Errorf("%w: malformed language tag %q", ErrInvalidQuery, tag)
+	}
+	for i := 1; i < len(subtags) && !isSingleton(subtags[0]) && !isSingleton(subtags[i]); i++ {
+		switch subtag := subtags[i]; {
+		case len(subtag) == 2:
+			subtags[i] = strings.ToUpper(subtag)
+		case len(subtag) == 4 && isAlpha(subtag):
+			subtags[i] = strings.ToUpper(subtag[:1]) + subtag[1:]
+		}
+	}
+	return strings.Join(subtags, "-"), nil
+}
+
+// ParseAcceptLanguage parses the given language ranges, as in the Accept-Language header, as
+// "en-GB;q=0.9,pt;q=0.8", into the AcceptLanguage of a query, validating and canonicalizing their tags, as in
+// CanonicalLanguage, and sorting them by their quality weights, the ones without weight first. It fails with an error
+// matching ErrInvalidQuery if any range is malformed.
+func ParseAcceptLanguage(value string) ([]string, error) {
+	type weighted struct {
+		language string
+		weight   float64
+	}
+	ranges := make([]weighted, 0)
+	for _, entry := range strings.Split(value, ",") {
+		if strings.TrimSpace(entry) == "" {
+			continue
+		}
+		language, err := canonicalLanguageRange(entry)
+		if err != nil {
+			return nil, err
+		}
+		weight := 1.0
+		if i := strings.Index(language, ";q="); i >= 0 {
+			weight, _ = strconv.ParseFloat(language[i+3:], 64)
+		}
+		ranges = append(ranges, weighted{language: language, weight: weight})
+	}
+	sort.SliceStable(ranges, func(i, j int) bool {
+		return ranges[i].weight > ranges[j].weight
+	})
+	languages := make([]string, 0, len(ranges))
+	for _, r := range ranges {
+		languages = append(languages, r.language)
+	}
+	return languages, nil
+}
+
+// canonicalLanguageRange validates the given language range, a tag optionally followed by its quality weight, as
+// "en-GB;q=0.9", returning it canonicalized.
+func canonicalLanguageRange(entry string) (string, error) {
+	parts := strings.SplitN(entry, ";", 2)
+	canonical, err := CanonicalLanguage(parts[0])
+	if err != nil {
+		return "", err
+	}
+	if len(parts) == 1 {
+		return canonical, nil
+	}
+	param := strings.SplitN(parts[1], "=", 2)
+	if len(param) != 2 || strings.TrimSpace(param[0]) != "q" || !languageWeightPattern.MatchString(strings.TrimSpace(param[1])) {
+		return "", fmt.Errorf("%w: malformed language weight %q", ErrInvalidQuery, strings.TrimSpace(entry))
+	}
+	return canonical + ";q=" + strings.TrimSpace(param[1]), nil
+}
+
+// canonicalLanguages canonicalizes the given language ranges, keeping the malformed ones as given, since the client
+// rejects them before sending the queries, through validateLanguages.
+func canonicalLanguages(languages []string) []string {
+	canonical := make([]string, 0, len(languages))
+	for _, language := range languages {
+		c, err := canonicalLanguageRange(language)
+		if err != nil {
+			c = strings.TrimSpace(language)
+		}
+		canonical = append(canonical, c)
+	}
+	return canonical
+}
+
+// validateLanguages checks the given language ranges of a query are well formed, whether the validation of the
+// queries is enabled or not, failing with a ValidationError otherwise.
+func validateLanguages(languages []string) error {
+	v := &validation{}
+	v.languages("AcceptLanguage", languages)
+	return v.err()
+}
+
+// languages checks the given language ranges are well formed.
+func (v *validation) languages(field string, languages []string) {
+	for _, language := range languages {
+		if _, err := canonicalLanguageRange(language); err != nil {
+			v.add(field, "holds a malformed language "+strconv.Quote(language)+", as en-GB or pt;q=0.8")
+			return
+		}
+	}
+}
+
+// wellFormedLanguage checks if the given lower-cased subtags make a well formed language tag, as in RFC 5646:
+// language, extended languages, script, region, variants, extensions and private use, in order.
+func wellFormedLanguage(subtags []string) bool {
+	for _, subtag := range subtags {
+		if len(subtag) == 0 || len(subtag) > 8 || !isAlphanumeric(subtag) {
+			return false
+		}
+	}
+	if subtags[0] == "x" {
+		return privateUse(subtags)
+	}
+	language := subtags[0]
+	if !isAlpha(language) || len(language) < 2 {
+		return false
+	}
+	i := 1
+	if len(language) <= 3 {
+		for extlangs := 0; extlangs < 3 && i < len(subtags) && len(subtags[i]) == 3 && isAlpha(subtags[i]); extlangs++ {
+			i++
+		}
+	}
+	if i < len(subtags) && len(subtags[i]) == 4 && isAlpha(subtags[i]) {
+		i++
+	}
+	if i < len(subtags) && ((len(subtags[i]) == 2 && isAlpha(subtags[i])) || (len(subtags[i]) == 3 && isDigits(subtags[i]))) {
+		i++
+	}
+	for i < len(subtags) && isVariant(subtags[i]) {
+		i++
+	}
+	for i < len(subtags) && isSingleton(subtags[i]) && subtags[i] != "x" {
+		i++
+		start := i
+		for i < len(subtags) && len(subtags[i]) >= 2 {
+			i++
+		}
+		if i == start {
+			return false
+		}
+	}
+	if i < len(subtags) && subtags[i] == "x" {
+		return privateUse(subtags[i:])
+	}
+	return i == len(subtags)
+}
+
+// privateUse checks if the given subtags, starting by "x", make a private use sequence.
+func privateUse(subtags []string) bool {
+	return len(subtags) > 1
+}
+
+// isVariant checks if the given subtag is a variant, as "1996" or "fonipa".
+func isVariant(subtag string) bool {
+	return len(subtag) >= 5 || (len(subtag) == 4 && subtag[0] >= '0' && subtag[0] <= '9')
+}
+
+// isSingleton checks if the given subtag introduces an extension or a private use sequence.
+func isSingleton(subtag string) bool {
+	return len(subtag) == 1
+}
+
+// isAlpha checks if the given subtag is made of ASCII letters only.
+func isAlpha(subtag string) bool {
+	for _, r := range subtag {
+		if r < 'a' || r > 'z' {
+			return false
+		}
+	}
+	return true
+}
+
+// isDigits checks if the given subtag is made of ASCII digits only.
+func isDigits(subtag string) bool {
+	for _, r := range subtag {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// isAlphanumeric checks if the given subtag is made of ASCII letters and digits only.
+func isAlphanumeric(subtag string) bool {
+	for _, r := range subtag {
+		if (r < 'a' || r > 'z') && (r < '0' || r > '9') {
+			return false
+		}
+	}
+	return true
+}
+
+// WithDefaultLanguages makes the client apply the given languages, in order of preference, to every query, so the
+// localization is configured once, instead of "en". The queries having no languages, as the ones created by their
+// constructors, use the given languages only. The queries setting their own languages, even "en", keep them first,
+// followed by the given ones they don't hold yet, as fallbacks, weighted below their own when they are weighted.
+// Malformed languages make every call fail with ErrInvalidQuery.
+func WithDefaultLanguages(languages ...string) Option {
+	return func(d *defaultClient) {
+		d.languages = canonicalLanguages(languages)
+	}
+}
+
+// mergeLanguages merges the given languages of a query with the default languages of the client, if any, or returns
+// the defaultLanguage when neither the query nor the client has any.
+func (d defaultClient) mergeLanguages(languages []string) []string {
+	if len(languages) == 0 && len(d.languages) == 0 {
+		return []string{defaultLanguage}
+	}
+	if len(d.languages) == 0 {
+		return languages
+	}
+	if len(languages) == 0 {
+		return append([]string(nil), d.languages...)
+	}
+	merged := append([]string(nil), languages...)
+	weighted, lowest := false, 1.0
+	for _, language := range canonicalLanguages(languages) {
+		if weight, ok := languageWeight(language); ok {
+			weighted = true
+			lowest = math.Min(lowest, weight)
+		}
+	}
+	fallbacks := make([]string, 0, len(d.languages))
+	for _, language := range d.languages {
+		if !containsLanguage(merged, language) && !containsLanguage(fallbacks, language) {
+			fallbacks = append(fallbacks, languageTag(language))
+		}
+	}
+	for i, fallback := range fallbacks {
+		if weighted {
+			weight := lowest * float64(len(fallbacks)-i) / float64(len(fallbacks)+1)
+			fallback += ";q=" + strconv.FormatFloat(math.Round(weight*1000)/1000, 'f', -1, 64)
+		}
+		merged = append(merged, fallback)
+	}
+	return merged
+}
+
+// containsLanguage checks if the given languages contain the tag of the given one, regardless of their weights.
+func containsLanguage(languages []string, language string) bool {
+	for _, l := range languages {
+		if strings.EqualFold(languageTag(l), languageTag(language)) {
+			return true
+		}
+	}
+	return false
+}
+
+// languageTag returns the tag of the given language range, without its weight.
+func languageTag(language string) string {
+	return strings.TrimSpace(strings.SplitN(language, ";", 2)[0])
+}
+
+// languageWeight returns the quality weight of the given language range, if any.
+func languageWeight(language string) (float64, bool) {
+	parts := strings.SplitN(language, ";q=", 2)
+	if len(parts) != 2 {
+		return 0, false
+	}
+	weight, err := strconv.ParseFloat(strings.TrimSpace(parts[1]), 64)
+	return weight, err == nil
+}
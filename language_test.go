@@ -0,0 +1,211 @@
+package nominatim_test
+
+import (
+	"context"
+	"errors"
+	"github.com/diegohordi/nominatim"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestCanonicalLanguage(t *testing.T) {
+	tests := []struct {
+		tag     string
+		want    string
+		wantErr bool
+	}{
+		{tag: "pt", want: "pt"},
+		{tag: "EN-gb", want: "en-GB"},
+		{tag: " zh-hant-tw ", want: "zh-Hant-TW"},
+		{tag: "sr-latn", want: "sr-Latn"},
+		{tag: "es-419", want: "es-419"},
+		{tag: "de-CH-1996", want: "de-CH-1996"},
+		{tag: "zh-yue-HK", want: "zh-yue-HK"},
+		{tag: "en-US-u-ca-gregory", want: "en-US-u-ca-gregory"},
+		{tag: "en-a-bb-x-priv", want: "en-a-bb-x-priv"},
+		{tag: "x-WHATEVER", want: "x-whatever"},
+		{tag: "*", want: "*"},
+		{tag: "", wantErr: true},
+		{tag: "e", wantErr: true},
+		{tag: "en_GB", wantErr: true},
+		{tag: "en--GB", wantErr: true},
+		{tag: "en-GB-", wantErr: true},
+		{tag: "en-GB-toolongsubtag", wantErr: true},
+		{tag: "en-u", wantErr: true},
+		{tag: "en-x", wantErr: true},
+		{tag: "português", wantErr: true},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.tag, func(t *testing.T) {
+			t.Parallel()
+			got, err := nominatim.CanonicalLanguage(tt.tag)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("CanonicalLanguage() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, nominatim.ErrInvalidQuery) {
+				t.Errorf("CanonicalLanguage() error = %v, want %v", err, nominatim.ErrInvalidQuery)
+			}
+			if got != tt.want {
+				t.Errorf("CanonicalLanguage() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseAcceptLanguage(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    []string
+		wantErr bool
+	}{
+		{name: "should parse a single language", value: "pt", want: []string{"pt"}},
+		{
+			name:  "should sort the languages by their weights",
+			value: "pt;q=0.8, EN-gb ;q=0.9,fr, *;q=0",
+			want:  []string{"fr", "en-GB;q=0.9", "pt;q=0.8", "*;q=0"},
+		},
+		{name: "should skip empty ranges", value: "pt,,en", want: []string{"pt", "en"}},
+		{name: "should be empty when empty", value: "", want: []string{}},
+		{name: "should reject malformed tags", value: "pt,en_GB", wantErr: true},
+		{name: "should reject weights out of range", value: "pt;q=1.5", wantErr: true},
+		{name: "should reject malformed weights", value: "pt;weight=1", wantErr: true},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := nominatim.ParseAcceptLanguage(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseAcceptLanguage() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseAcceptLanguage() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_AcceptLanguage(t *testing.T) {
+	query := nominatim.NewSearchQuery(nominatim.WithFreeForm("lisboa"), nominatim.WithLanguages("PT-pt", "en_GB", "en;q=0.5"))
+	if err := query.Validate(); !errors.Is(err, nominatim.ErrInvalidQuery) {
+		t.Errorf("Validate() error = %v, want %v", err, nominatim.ErrInvalidQuery)
+	}
+	if got, want := query.Encode().Get("accept-language"), "pt-PT,en_GB,en;q=0.5"; got != want {
+		t.Errorf("Encode() accept-language = %v, want %v, keeping the malformed tags as given", got, want)
+	}
+	reverse := nominatim.NewReverseQuery("38.7", "-9.1")
+	reverse.AcceptLanguage = []string{"pt;q=2"}
+	if err := reverse.Validate(); !errors.Is(err, nominatim.ErrInvalidQuery) {
+		t.Errorf("Validate() error = %v, want %v", err, nominatim.ErrInvalidQuery)
+	}
+}
+
+func Test_WithDefaultLanguages(t *testing.T) {
+	tests := []struct {
+		name      string
+		languages []string
+		want      string
+	}{
+		{name: "should keep the languages of the query asking for the default one", languages: []string{"en"}, want: "en,pt-PT,pt"},
+		{name: "should apply to the queries without languages", want: "pt-PT,pt,en"},
+		{name: "should append the missing ones to the languages of the query", languages: []string{"es", "PT"}, want: "es,pt,pt-PT,en"},
+		{name: "should weight the appended ones below the ones of the query", languages: []string{"es;q=0.9", "pt;q=0.6"}, want: "es;q=0.9,pt;q=0.6,pt-PT;q=0.4,en;q=0.2"},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			var got string
+			transport := TransportFunc(func(req *http.Request) (*http.Response, error) {
+				got = req.URL.Query().Get("accept-language")
+				return statusFixture(http.StatusOK, mustLoadValidSearchResults(t))()
+			})
+			client := nominatim.NewClient("http://localhost:8080", nil, nominatim.WithTransport(transport),
+				nominatim.WithDefaultLanguages("pt-pt", "pt", "en"))
+			query := nominatim.NewSearchQuery(nominatim.WithFreeForm("lisboa"))
+			query.AcceptLanguage = tt.languages
+			if _, err := client.Search(context.TODO(), *query); err != nil {
+				t.Fatalf("Search() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Search() accept-language = %v, want %v", got, tt.want)
+			}
+		})
+	}
+	var got string
+	transport := TransportFunc(func(req *http.Request) (*http.Response, error) {
+		got = req.URL.Query().Get("accept-language")
+		return statusFixture(http.StatusOK, mustLoadValidSearchResults(t))()
+	})
+	client := nominatim.NewClient("http://localhost:8080", nil, nominatim.WithTransport(transport), nominatim.WithDefaultLanguages("pt"))
+	if _, err := client.Lookup(context.TODO(), *nominatim.NewLookupQuery("W24961587")); err != nil {
+		t.Fatalf("Lookup() error = %v", err)
+	}
+	if got != "pt" {
+		t.Errorf("Lookup() accept-language = %v, want pt", got)
+	}
+}
+
+func Test_MalformedLanguages(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []nominatim.Option
+		call func(client nominatim.Client) error
+	}{
+		{
+			name: "should reject the searches with malformed languages",
+			call: func(client nominatim.Client) error {
+				_, err := client.Search(context.TODO(), *nominatim.NewSearchQuery(nominatim.WithFreeForm("lisboa"), nominatim.WithLanguages("en_GB")))
+				return err
+			},
+		},
+		{
+			name: "should reject the reverse queries with malformed languages",
+			call: func(client nominatim.Client) error {
+				query := nominatim.NewReverseQuery("38.7", "-9.1")
+				query.AcceptLanguage = []string{"pt;q=2"}
+				_, err := client.Reverse(context.TODO(), *query)
+				return err
+			},
+		},
+		{
+			name: "should reject the lookups with malformed languages",
+			opts: []nominatim.Option{nominatim.WithQueryValidation()},
+			call: func(client nominatim.Client) error {
+				query := nominatim.NewLookupQuery("W24961587")
+				query.AcceptLanguage = []string{"portuguese-language"}
+				_, err := client.Lookup(context.TODO(), *query)
+				return err
+			},
+		},
+		{
+			name: "should reject the calls when the default languages are malformed",
+			opts: []nominatim.Option{nominatim.WithDefaultLanguages("pt_BR")},
+			call: func(client nominatim.Client) error {
+				_, err := client.Search(context.TODO(), *nominatim.NewSearchQuery(nominatim.WithFreeForm("lisboa")))
+				return err
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			sent := false
+			transport := TransportFunc(func(req *http.Request) (*http.Response, error) {
+				sent = true
+				return statusFixture(http.StatusOK, mustLoadValidSearchResults(t))()
+			})
+			client := nominatim.NewClient("http://localhost:8080", nil, append(tt.opts, nominatim.WithTransport(transport))...)
+			if err := tt.call(client); !errors.Is(err, nominatim.ErrInvalidQuery) {
+				t.Errorf("call error = %v, want %v", err, nominatim.ErrInvalidQuery)
+			}
+			if sent {
+				t.Errorf("call sent the request, want it rejected before")
+			}
+		})
+	}
+}
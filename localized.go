@@ -0,0 +1,67 @@
+package nominatim
+
+import (
+	"context"
+	"errors"
+	"strings"
+)
+
+// LocalizedResult holds a result along with its names in several languages, as merged by SearchLocalized.
+type LocalizedResult struct {
+
+	// Result is the result in the first language it was found in, in the order of the languages given.
+	Result
+
+	// DisplayNames holds the display names of the result by language, for the languages it was found in.
+	DisplayNames map[string]string
+
+	// Names holds the names of the result by language, for the languages it was found in.
+	Names map[string]string
+}
+
+// SearchLocalized searches the given query in each of the given languages concurrently, through the given client,
+// so subject to its rate limit, merging the display names and the names of each place by language, for multilingual
+// UIs. The results are in the order of the first language, followed by the ones only found in the others. It fails
+// if any search fails with an error other than ErrNoResults, and with ErrNoResults if none found anything. The
+// languages of the query are replaced, and none means its own ones, searched as they are in a single search, whose
+// names are keyed by them joined by commas, or by an empty string when the query has none.
+func SearchLocalized(ctx context.Context, client SearchHandler, query SearchQuery, langs []string, opts ...BatchOption) ([]LocalizedResult, error) {
+	queries := make([]SearchQuery, 0, len(langs))
+	for _, lang := range langs {
+		localized := query
+		localized.AcceptLanguage = []string{lang}
+		queries = append(queries, localized)
+	}
+	if len(langs) == 0 {
+		queries = append(queries, query)
+		langs = []string{strings.Join(query.AcceptLanguage, ",")}
+	}
+	localized := make([]LocalizedResult, 0)
+	positions := make(map[int]int)
+	for i, outcome := range SearchMany(ctx, client, queries, opts...) {
+		if errors.Is(outcome.Err, ErrNoResults) {
+			continue
+		}
+		if outcome.Err != nil {
+			return nil, outcome.Err
+		}
+		for _, result := range outcome.Results {
+			position, ok := positions[result.PlaceId]
+			if !ok {
+				position = len(localized)
+				positions[result.PlaceId] = position
+				localized = append(localized, LocalizedResult{
+					Result:       result,
+					DisplayNames: make(map[string]string, len(langs)),
+					Names:        make(map[string]string, len(langs)),
+				})
+			}
+			localized[position].DisplayNames[langs[i]] = result.DisplayName
+			localized[position].Names[langs[i]] = result.Name
+		}
+	}
+	if len(localized) == 0 {
+		return nil, ErrNoResults
+	}
+	return localized, nil
+}
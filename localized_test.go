@@ -0,0 +1,118 @@
+package nominatim_test
+
+import (
+	"context"
+	"errors"
+	"github.com/diegohordi/nominatim"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSearchLocalized(t *testing.T) {
+	names := map[string]map[int]string{
+		"en": {1: "Belém Tower", 2: "Jerónimos Monastery"},
+		"pt": {1: "Torre de Belém", 2: "Mosteiro dos Jerónimos", 3: "Padrão dos Descobrimentos"},
+		"fr": {},
+	}
+	client := &searchFunc{search: func(query nominatim.SearchQuery) ([]nominatim.Result, error) {
+		lang := "en"
+		for i, tag := range query.AcceptLanguage {
+			if strings.Contains(tag, ",") || tag == "" {
+				return nil, nominatim.ErrInvalidQuery
+			}
+			if i == 0 {
+				lang = tag
+			}
+		}
+		if lang == "es" {
+			return nil, nominatim.ErrRateLimited
+		}
+		results := make([]nominatim.Result, 0)
+		for id := 1; id <= 3; id++ {
+			if name, ok := names[lang][id]; ok {
+				results = append(results, nominatim.Result{PlaceId: id, Name: name, DisplayName: name + ", Lisboa"})
+			}
+		}
+		if len(results) == 0 {
+			return nil, nominatim.ErrNoResults
+		}
+		return results, nil
+	}}
+	tests := []struct {
+		name           string
+		acceptLanguage []string
+		langs          []string
+		wantIDs        []int
+		wantNames      []map[string]string
+		wantErr        error
+	}{
+		{
+			name:    "should merge the names by language",
+			langs:   []string{"en", "pt", "fr"},
+			wantIDs: []int{1, 2, 3},
+			wantNames: []map[string]string{
+				{"en": "Belém Tower", "pt": "Torre de Belém"},
+				{"en": "Jerónimos Monastery", "pt": "Mosteiro dos Jerónimos"},
+				{"pt": "Padrão dos Descobrimentos"},
+			},
+		},
+		{
+			name:           "should search the language of the query without languages",
+			acceptLanguage: []string{"en"},
+			wantIDs:        []int{1, 2},
+			wantNames:      []map[string]string{{"en": "Belém Tower"}, {"en": "Jerónimos Monastery"}},
+		},
+		{
+			name:           "should search the languages of the query as they are without languages",
+			acceptLanguage: []string{"pt", "en"},
+			wantIDs:        []int{1, 2, 3},
+			wantNames: []map[string]string{
+				{"pt,en": "Torre de Belém"},
+				{"pt,en": "Mosteiro dos Jerónimos"},
+				{"pt,en": "Padrão dos Descobrimentos"},
+			},
+		},
+		{
+			name:      "should search with no languages when neither the query has any",
+			wantIDs:   []int{1, 2},
+			wantNames: []map[string]string{{"": "Belém Tower"}, {"": "Jerónimos Monastery"}},
+		},
+		{
+			name:    "should fail when a search fails",
+			langs:   []string{"en", "es"},
+			wantErr: nominatim.ErrRateLimited,
+		},
+		{
+			name:    "should fail when no language finds anything",
+			langs:   []string{"fr"},
+			wantErr: nominatim.ErrNoResults,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			query := *nominatim.NewSearchQuery(nominatim.WithFreeForm("belém"))
+			query.AcceptLanguage = tt.acceptLanguage
+			got, err := nominatim.SearchLocalized(context.TODO(), client, query, tt.langs)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("SearchLocalized() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			ids := make([]int, 0, len(got))
+			gotNames := make([]map[string]string, 0, len(got))
+			for _, result := range got {
+				ids = append(ids, result.PlaceId)
+				gotNames = append(gotNames, result.Names)
+				for lang, name := range result.Names {
+					if result.DisplayNames[lang] != name+", Lisboa" {
+						t.Errorf("SearchLocalized() display name in %s = %v", lang, result.DisplayNames[lang])
+					}
+				}
+			}
+			if tt.wantErr == nil && (!reflect.DeepEqual(ids, tt.wantIDs) || !reflect.DeepEqual(gotNames, tt.wantNames)) {
+				t.Errorf("SearchLocalized() got = %v %v, want %v %v", ids, gotNames, tt.wantIDs, tt.wantNames)
+			}
+		})
+	}
+}
@@ -0,0 +1,172 @@
+package nominatim
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	// LocationIQBaseURL is the base URL of the LocationIQ API served from the United States.
+	LocationIQBaseURL = "https://us1.locationiq.com/v1"
+
+	// LocationIQEuropeBaseURL is the base URL of the LocationIQ API served from Europe.
+	LocationIQEuropeBaseURL = "https://eu1.locationiq.com/v1"
+)
+
+// keyAPIKey is the parameter authenticating the caller of the commercial Nominatim-compatible APIs, as LocationIQ.
+const keyAPIKey = "key"
+
+// messageRateLimitedDay is the suffix of the message sent by LocationIQ when the daily quota of the plan is exhausted,
+// as in "Rate Limited Day".
+const messageRateLimitedDay = "day"
+
+// ErrMissingAPIKey is returned when creating a client for a commercial provider, as LocationIQ, without an API key.
+var ErrMissingAPIKey = errors.New("nominatim: an API key is required")
+
+// LocationIQPlan holds the rate limits of a LocationIQ plan, enforced by the client before sending the requests, so
+// they aren't refused by the server. The zero limits are not enforced.
+type LocationIQPlan struct {
+
+	// RequestsPerSecond is the number of requests allowed per second.
+	RequestsPerSecond float64
+
+	// RequestsPerMinute is the number of requests allowed per minute.
+	RequestsPerMinute int
+
+	// RequestsPerDay is the number of requests allowed per day.
+	RequestsPerDay int
+}
+
+// LocationIQFreePlan holds the rate limits of the free LocationIQ plan. The limits of the paid plans are listed in the
+// dashboard of the account.
+var LocationIQFreePlan = LocationIQPlan{RequestsPerSecond: 2, RequestsPerMinute: 60, RequestsPerDay: 5000}
+
+// NewLocationIQClient creates a Client for the Nominatim-compatible API of LocationIQ, at the given base URL, as
+// LocationIQEuropeBaseURL, or at LocationIQBaseURL if empty, authenticated by the given API key, which is required,
+// and limited to the rates of the given plan. Its errors are mapped to the ones of the Nominatim API, so the refused
+// keys match ErrInvalidKey and the exhausted daily quotas match ErrQuotaExceeded, on top of ErrRateLimited. The given
+// options are applied after the plan ones, though the responses are always decoded through the LocationIQ errors.
+// LocationIQ documents no status endpoint, so CheckStatus isn't meant to be used with it.
+func NewLocationIQClient(baseURL, apiKey string, plan LocationIQPlan, opts ...Option) (Client, error) {
+	if strings.TrimSpace(apiKey) == "" {
+		return nil, ErrMissingAPIKey
+	}
+	if baseURL == "" {
+		baseURL = LocationIQBaseURL
+	}
+	opts = append([]Option{WithAPIKey(apiKey), WithLimiter(plan.limiter())}, opts...)
+	return NewClientWithOptions(baseURL, append(opts, withLocationIQErrors())...), nil
+}
+
+// WithAPIKey makes the client send the given API key on every request, through the key parameter, as asked by the
+// commercial Nominatim-compatible APIs, as LocationIQ. An empty one means none.
+func WithAPIKey(apiKey string) Option {
+	return func(d *defaultClient) {
+		d.apiKey = apiKey
+	}
+}
+
+// limiter returns the Limiter enforcing every limit of the plan.
+func (p LocationIQPlan) limiter() Limiter {
+	limiters := chainedLimiter{}
+	if p.RequestsPerSecond > 0 {
+		burst := int(p.RequestsPerSecond)
+		limiters = append(limiters, newTokenBucket(p.RequestsPerSecond, burst))
+	}
+	if p.RequestsPerMinute > 0 {
+		limiters = append(limiters, newTokenBucket(float64(p.RequestsPerMinute)/60, p.RequestsPerMinute))
+	}
+	if p.RequestsPerDay > 0 {
+		limiters = append(limiters, newTokenBucket(float64(p.RequestsPerDay)/(24*60*60), p.RequestsPerDay))
+	}
+	return limiters
+}
+
+// chainedLimiter is a Limiter taking a token from every one of its buckets at once, so a request refused by one of
+// them gives back the tokens reserved from the others.
+type chainedLimiter []*tokenBucket
+
+// Wait blocks until every bucket allows a request to be sent, failing right away when the context deadline would be
+// exceeded before that, or when the context is done while waiting, giving back the tokens reserved in both cases.
+func (c chainedLimiter) Wait(ctx context.Context) error {
+	now := time.Now()
+	var delay time.Duration
+	for _, bucket := range c {
+		if reserved := bucket.reserve(now); reserved > delay {
+			delay = reserved
+		}
+	}
+	if delay <= 0 {
+		return nil
+	}
+	if !canWait(ctx, delay) {
+		c.release()
+		return context.DeadlineExceeded
+	}
+	if err := wait(ctx, delay); err != nil {
+		c.release()
+		return err
+	}
+	return nil
+}
+
+// release gives back the tokens reserved from every bucket but not used.
+func (c chainedLimiter) release() {
+	for _, bucket := range c {
+		bucket.release()
+	}
+}
+
+// withLocationIQErrors makes the client map the errors of LocationIQ, wrapping the decoder configured so far.
+func withLocationIQErrors() Option {
+	return func(d *defaultClient) {
+		d.decoder = locationIQDecoder{next: d.decoder}
+	}
+}
+
+// locationIQDecoder decodes the responses of LocationIQ through the next decoder, mapping its errors, sent as
+// {"error": "Invalid key"}, to the ones of the Nominatim API.
+type locationIQDecoder struct {
+	next Decoder
+}
+
+// Decode decodes the given response through the next decoder, mapping the refused keys and the exhausted quotas.
+func (l locationIQDecoder) Decode(resp *http.Response, body []byte, v interface{}) error {
+	err := l.next.Decode(resp, body, v)
+	var respErr Error
+	if !errors.As(err, &respErr) {
+		return err
+	}
+	switch {
+	case respErr.Code == http.StatusUnauthorized || respErr.Code == http.StatusForbidden:
+		return providerError{Err: err, sentinel: ErrInvalidKey}
+	case respErr.Code == http.StatusTooManyRequests &&
+		strings.HasSuffix(strings.ToLower(strings.TrimSpace(respErr.Message)), messageRateLimitedDay):
+		return providerError{Err: respErr, sentinel: ErrQuotaExceeded}
+	}
+	return err
+}
+
+// providerError is an error sent by a commercial provider, matching the given sentinel error through errors.Is, along
+// with the ones matched by the error it wraps.
+type providerError struct {
+	Err      error
+	sentinel error
+}
+
+func (e providerError) Error() string {
+	return e.Err.Error()
+}
+
+// Is reports whether the given target is the sentinel error of the providerError.
+func (e providerError) Is(target error) bool {
+	return target == e.sentinel
+}
+
+// Unwrap returns the error wrapped.
+func (e providerError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,156 @@
+package nominatim_test
+
+import (
+	"context"
+	"errors"
+	"github.com/diegohordi/nominatim"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func Test_NewLocationIQClient(t *testing.T) {
+	tests := []struct {
+		name    string
+		baseURL string
+		apiKey  string
+		wantURL string
+		wantErr error
+	}{
+		{
+			name:    "should send the API key to the US endpoint by default",
+			apiKey:  "pk.123",
+			wantURL: "https://us1.locationiq.com/v1/search?format=jsonv2&q=lisboa&addressdetails=0&extratags=0&namedetails=0&accept-language=en&key=pk.123",
+		},
+		{
+			name:    "should send the API key to the given endpoint",
+			baseURL: nominatim.LocationIQEuropeBaseURL,
+			apiKey:  "pk.123",
+			wantURL: "https://eu1.locationiq.com/v1/search?format=jsonv2&q=lisboa&addressdetails=0&extratags=0&namedetails=0&accept-language=en&key=pk.123",
+		},
+		{
+			name:    "should require an API key",
+			apiKey:  " ",
+			wantErr: nominatim.ErrMissingAPIKey,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			var gotURL string
+			transport := TransportFunc(func(req *http.Request) (*http.Response, error) {
+				gotURL = req.URL.String()
+				return statusFixture(http.StatusOK, mustLoadValidSearchResults(t))()
+			})
+			d, err := nominatim.NewLocationIQClient(tt.baseURL, tt.apiKey, nominatim.LocationIQPlan{}, nominatim.WithTransport(transport))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("NewLocationIQClient() error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if _, err := d.Search(context.TODO(), nominatim.SearchQuery{FreeFormQuery: "lisboa"}); err != nil {
+				t.Fatal(err)
+			}
+			if gotURL != tt.wantURL {
+				t.Errorf("Search() sent %s, want %s", gotURL, tt.wantURL)
+			}
+		})
+	}
+}
+
+func Test_NewLocationIQClient_Errors(t *testing.T) {
+	tests := []struct {
+		name          string
+		statusCode    int
+		body          string
+		wantErrs      []error
+		wantNotErrs   []error
+		wantTransient bool
+	}{
+		{
+			name:        "should map the invalid keys",
+			statusCode:  http.StatusUnauthorized,
+			body:        `{"error":"Invalid key"}`,
+			wantErrs:    []error{nominatim.ErrInvalidKey, nominatim.ErrInvalidQuery},
+			wantNotErrs: []error{nominatim.ErrRateLimited},
+		},
+		{
+			name:       "should map the inactive keys",
+			statusCode: http.StatusForbidden,
+			body:       `{"error":"Key not active - Please write to hello@locationiq.com"}`,
+			wantErrs:   []error{nominatim.ErrInvalidKey},
+		},
+		{
+			name:        "should map the exhausted daily quotas",
+			statusCode:  http.StatusTooManyRequests,
+			body:        `{"error":"Rate Limited Day"}`,
+			wantErrs:    []error{nominatim.ErrQuotaExceeded, nominatim.ErrRateLimited},
+			wantNotErrs: []error{nominatim.ErrInvalidKey},
+		},
+		{
+			name:          "should keep the rate limits per second as transient",
+			statusCode:    http.StatusTooManyRequests,
+			body:          `{"error":"Rate Limited Second"}`,
+			wantErrs:      []error{nominatim.ErrRateLimited},
+			wantNotErrs:   []error{nominatim.ErrQuotaExceeded},
+			wantTransient: true,
+		},
+		{
+			name:        "should keep the results not found",
+			statusCode:  http.StatusNotFound,
+			body:        `{"error":"Unable to geocode"}`,
+			wantErrs:    []error{nominatim.ErrUnableToGeocode},
+			wantNotErrs: []error{nominatim.ErrInvalidKey},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			transport := TransportFunc(func(req *http.Request) (*http.Response, error) {
+				return statusFixture(tt.statusCode, []byte(tt.body))()
+			})
+			d, err := nominatim.NewLocationIQClient("", "pk.123", nominatim.LocationIQPlan{}, nominatim.WithTransport(transport), nominatim.WithStrictDecoding())
+			if err != nil {
+				t.Fatal(err)
+			}
+			_, err = d.Reverse(context.TODO(), nominatim.ReverseQuery{Latitude: "38.7", Longitude: "-9.1"})
+			for _, want := range tt.wantErrs {
+				if !errors.Is(err, want) {
+					t.Errorf("Reverse() error = %v, want %v", err, want)
+				}
+			}
+			for _, notWant := range tt.wantNotErrs {
+				if errors.Is(err, notWant) {
+					t.Errorf("Reverse() error = %v, want not %v", err, notWant)
+				}
+			}
+			if got := nominatim.IsTransient(err); got != tt.wantTransient {
+				t.Errorf("IsTransient() = %v, want %v", got, tt.wantTransient)
+			}
+		})
+	}
+}
+
+func Test_NewLocationIQClient_Plan(t *testing.T) {
+	transport := TransportFunc(func(req *http.Request) (*http.Response, error) {
+		return statusFixture(http.StatusOK, mustLoadValidSearchResults(t))()
+	})
+	plan := nominatim.LocationIQPlan{RequestsPerSecond: 10, RequestsPerMinute: 2}
+	d, err := nominatim.NewLocationIQClient("", "pk.123", plan, nominatim.WithTransport(transport))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 2; i++ {
+		if _, err := d.Search(context.TODO(), nominatim.SearchQuery{FreeFormQuery: "lisboa"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	ctx, cancel := context.WithTimeout(context.TODO(), time.Second)
+	defer cancel()
+	if _, err := d.Search(ctx, nominatim.SearchQuery{FreeFormQuery: "lisboa"}); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Search() error = %v, want %v, the plan allowing 2 requests per minute", err, context.DeadlineExceeded)
+	}
+}
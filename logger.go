@@ -0,0 +1,129 @@
+//go:build go1.21
+
+package nominatim
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// LogLevels holds the levels of the entries logged by the client through WithLeveledLogger.
+type LogLevels struct {
+
+	// Request is the level of the entries logged when a request starts and when it finishes successfully, or with
+	// nothing found.
+	Request slog.Level
+
+	// Failure is the level of the entries logged when a request fails.
+	Failure slog.Level
+
+	// Slow is the level of the entries logged when a request took longer than the threshold of
+	// WithSlowRequestThreshold.
+	Slow slog.Level
+
+	// Retry is the level of the entries logged when a failed request is about to be retried.
+	Retry slog.Level
+
+	// RateLimit is the level of the entries logged when a request waited for the rate limit of the client.
+	RateLimit slog.Level
+
+	// Decode is the level of the entries logged when a response, or one of its results, can't be decoded.
+	Decode slog.Level
+}
+
+// DefaultLogLevels returns the levels used by WithLogger: debug for the requests and the rate limit waits, info for
+// the retries, warn for the failures and the slow requests, and error for the responses that can't be decoded.
+func DefaultLogLevels() LogLevels {
+	return LogLevels{
+		Request:   slog.LevelDebug,
+		Failure:   slog.LevelWarn,
+		Slow:      slog.LevelWarn,
+		Retry:     slog.LevelInfo,
+		RateLimit: slog.LevelDebug,
+		Decode:    slog.LevelError,
+	}
+}
+
+// WithLogger makes the client log the lifecycle of its requests through the given logger, at the DefaultLogLevels:
+// their start and finish, their retries, the waits for the rate limit and the responses that can't be decoded. Like
+// every slog-based feature of this package, it needs Go 1.21 or later. The URLs logged hold the personal data of the
+// requests, as the addresses and the coordinates, unless redacted through WithRedaction.
+func WithLogger(logger *slog.Logger) Option {
+	return WithLeveledLogger(logger, DefaultLogLevels())
+}
+
+// WithLeveledLogger makes the client log the lifecycle of its requests through the given logger, as WithLogger does,
+// at the given levels.
+func WithLeveledLogger(logger *slog.Logger, levels LogLevels) Option {
+	return func(d *defaultClient) {
+		if logger != nil {
+			d.observers = append(d.observers, slogObserver{logger: logger, levels: levels, client: d})
+		}
+	}
+}
+
+// slogObserver logs the lifecycle of the requests through a slog.Logger.
+type slogObserver struct {
+	logger *slog.Logger
+	levels LogLevels
+	client *defaultClient
+}
+
+func (o slogObserver) requestStarted(ctx context.Context, query QueryEncoder, req *http.Request) error {
+	o.logger.LogAttrs(ctx, o.levels.Request, "nominatim: request started",
+		slog.String("endpoint", query.Endpoint()),
+		slog.String("url", o.client.redactURL(req.URL)),
+	)
+	return nil
+}
+
+func (o slogObserver) requestFinished(ctx context.Context, query QueryEncoder, req *http.Request, resp *http.Response, duration time.Duration, err error) {
+	attrs := []slog.Attr{
+		slog.String("endpoint", query.Endpoint()),
+		slog.String("url", o.client.redactURL(req.URL)),
+		slog.Duration("duration", duration),
+	}
+	if resp != nil {
+		attrs = append(attrs, slog.Int("status", resp.StatusCode))
+	}
+	if err == nil || errors.Is(err, ErrNoResults) {
+		o.logger.LogAttrs(ctx, o.levels.Request, "nominatim: request finished", attrs...)
+		return
+	}
+	attrs = append(attrs, slog.String("outcome", string(ClassifyOutcome(err))), slog.Any("error", err))
+	o.logger.LogAttrs(ctx, o.levels.Failure, "nominatim: request failed", attrs...)
+}
+
+func (o slogObserver) slowRequest(ctx context.Context, query QueryEncoder, req *http.Request, duration time.Duration) {
+	o.logger.LogAttrs(ctx, o.levels.Slow, "nominatim: slow request",
+		slog.String("endpoint", query.Endpoint()),
+		slog.String("url", o.client.redactURL(req.URL)),
+		slog.Duration("duration", duration),
+	)
+}
+
+func (o slogObserver) retrying(ctx context.Context, query QueryEncoder, attempt int, delay time.Duration, err error) {
+	o.logger.LogAttrs(ctx, o.levels.Retry, "nominatim: retrying request",
+		slog.String("endpoint", query.Endpoint()),
+		slog.Int("attempt", attempt),
+		slog.Duration("delay", delay),
+		slog.Any("error", err),
+	)
+}
+
+func (o slogObserver) rateLimited(ctx context.Context, query QueryEncoder, waited time.Duration) {
+	o.logger.LogAttrs(ctx, o.levels.RateLimit, "nominatim: waited for the rate limit",
+		slog.String("endpoint", query.Endpoint()),
+		slog.Duration("waited", waited),
+	)
+}
+
+func (o slogObserver) decodeFailed(ctx context.Context, query QueryEncoder, err error) {
+	o.logger.LogAttrs(ctx, o.levels.Decode, "nominatim: unable to decode the response",
+		slog.String("endpoint", query.Endpoint()),
+		slog.Any("error", err),
+	)
+}
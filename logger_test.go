@@ -0,0 +1,195 @@
+//go:build go1.21
+
+package nominatim_test
+
+import (
+	"bytes"
+	"context"
+	"github.com/diegohordi/nominatim"
+	"log/slog"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func Test_WithLogger(t *testing.T) {
+	tests := []struct {
+		name      string
+		transport func() http.RoundTripper
+		opts      func(logger *slog.Logger) []nominatim.Option
+		calls     int
+		want      []string
+	}{
+		{
+			name: "should log the start and the finish of the requests",
+			transport: func() http.RoundTripper {
+				return rateLimitedTransport(t, 0, http.StatusOK, "", mustLoadValidSearchResults(t))
+			},
+			opts: func(logger *slog.Logger) []nominatim.Option {
+				return []nominatim.Option{nominatim.WithLogger(logger)}
+			},
+			calls: 1,
+			want: []string{
+				`level=DEBUG msg="nominatim: request started" endpoint=search url="http://localhost:8080/search?`,
+				`level=DEBUG msg="nominatim: request finished" endpoint=search url="http://localhost:8080/search?`,
+				"status=200",
+			},
+		},
+		{
+			name: "should log the failures and the retries",
+			transport: func() http.RoundTripper {
+				return rateLimitedTransport(t, 1, http.StatusServiceUnavailable, "0", mustLoadValidSearchResults(t))
+			},
+			opts: func(logger *slog.Logger) []nominatim.Option {
+				policy := nominatim.RetryPolicy{MaxAttempts: 2, BaseDelay: time.Millisecond}
+				return []nominatim.Option{nominatim.WithLogger(logger), nominatim.WithRetry(policy)}
+			},
+			calls: 1,
+			want: []string{
+				`level=WARN msg="nominatim: request failed" endpoint=search`,
+				"status=503 outcome=rate-limited",
+				`level=INFO msg="nominatim: retrying request" endpoint=search attempt=1`,
+				`level=DEBUG msg="nominatim: request finished"`,
+			},
+		},
+		{
+			name: "should log the responses that can't be decoded",
+			transport: func() http.RoundTripper {
+				return rateLimitedTransport(t, 0, http.StatusOK, "", []byte(`[{"place_id":"x"}]`))
+			},
+			opts: func(logger *slog.Logger) []nominatim.Option {
+				return []nominatim.Option{nominatim.WithLogger(logger)}
+			},
+			calls: 1,
+			want: []string{
+				`level=ERROR msg="nominatim: unable to decode the response" endpoint=search`,
+				`level=WARN msg="nominatim: request failed" endpoint=search`,
+				"outcome=data-error",
+			},
+		},
+		{
+			name: "should log the results skipped by the lenient decoding",
+			transport: func() http.RoundTripper {
+				return rateLimitedTransport(t, 0, http.StatusOK, "", []byte(`[{"place_id":"x"},{"place_id":1}]`))
+			},
+			opts: func(logger *slog.Logger) []nominatim.Option {
+				return []nominatim.Option{nominatim.WithLogger(logger), nominatim.WithLenientDecoding()}
+			},
+			calls: 1,
+			want: []string{
+				`level=ERROR msg="nominatim: unable to decode the response" endpoint=search error="nominatim: unable to decode result 0`,
+			},
+		},
+		{
+			name: "should log the waits for the rate limit",
+			transport: func() http.RoundTripper {
+				return rateLimitedTransport(t, 0, http.StatusOK, "", mustLoadValidSearchResults(t))
+			},
+			opts: func(logger *slog.Logger) []nominatim.Option {
+				return []nominatim.Option{nominatim.WithLogger(logger), nominatim.WithRateLimit(20, 1)}
+			},
+			calls: 2,
+			want: []string{
+				`level=DEBUG msg="nominatim: waited for the rate limit" endpoint=search waited=`,
+			},
+		},
+		{
+			name: "should log the slow requests",
+			transport: func() http.RoundTripper {
+				return RoundTripFunc(func(req *http.Request) *http.Response {
+					time.Sleep(20 * time.Millisecond)
+					return rateLimitedTransport(t, 0, http.StatusOK, "", mustLoadValidSearchResults(t)).(RoundTripFunc)(req)
+				})
+			},
+			opts: func(logger *slog.Logger) []nominatim.Option {
+				return []nominatim.Option{nominatim.WithLogger(logger), nominatim.WithSlowRequestThreshold(time.Millisecond)}
+			},
+			calls: 1,
+			want: []string{
+				`level=WARN msg="nominatim: slow request" endpoint=search url="http://localhost:8080/search?`,
+			},
+		},
+		{
+			name: "should log the URLs redacted",
+			transport: func() http.RoundTripper {
+				return rateLimitedTransport(t, 0, http.StatusOK, "", mustLoadValidSearchResults(t))
+			},
+			opts: func(logger *slog.Logger) []nominatim.Option {
+				return []nominatim.Option{nominatim.WithLogger(logger), nominatim.WithRedaction(nominatim.RedactHash(""))}
+			},
+			calls: 1,
+			want: []string{
+				`level=DEBUG msg="nominatim: request started" endpoint=search url="http://localhost:8080/search?format=jsonv2&q=sha256%3A`,
+			},
+		},
+		{
+			name: "should log the API key masked",
+			transport: func() http.RoundTripper {
+				return rateLimitedTransport(t, 0, http.StatusOK, "", mustLoadValidSearchResults(t))
+			},
+			opts: func(logger *slog.Logger) []nominatim.Option {
+				return []nominatim.Option{nominatim.WithLogger(logger), nominatim.WithAPIKey("SECRETKEY")}
+			},
+			calls: 1,
+			want: []string{
+				`level=DEBUG msg="nominatim: request started" endpoint=search url="http://localhost:8080/search?format=jsonv2&q=lisboa`,
+				"key=%5Bredacted%5D",
+			},
+		},
+		{
+			name: "should log at the given levels",
+			transport: func() http.RoundTripper {
+				return rateLimitedTransport(t, 0, http.StatusOK, "", mustLoadValidSearchResults(t))
+			},
+			opts: func(logger *slog.Logger) []nominatim.Option {
+				levels := nominatim.DefaultLogLevels()
+				levels.Request = slog.LevelInfo
+				return []nominatim.Option{nominatim.WithLeveledLogger(logger, levels)}
+			},
+			calls: 1,
+			want: []string{
+				`level=INFO msg="nominatim: request started" endpoint=search`,
+				`level=INFO msg="nominatim: request finished" endpoint=search`,
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			output := &bytes.Buffer{}
+			logger := slog.New(slog.NewTextHandler(output, &slog.HandlerOptions{Level: slog.LevelDebug}))
+			client := nominatim.NewClient("http://localhost:8080", &http.Client{Transport: tt.transport()}, tt.opts(logger)...)
+			query := nominatim.NewSearchQuery()
+			query.FreeFormQuery = "lisboa"
+			for i := 0; i < tt.calls; i++ {
+				_, _ = client.Search(context.TODO(), *query)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(output.String(), want) {
+					t.Errorf("WithLogger() output = %s, want %s", output, want)
+				}
+			}
+			if strings.Contains(output.String(), "SECRETKEY") {
+				t.Errorf("WithLogger() output = %s, want the API key masked", output)
+			}
+		})
+	}
+}
+
+func Test_WithLogger_Silent(t *testing.T) {
+	output := &bytes.Buffer{}
+	logger := slog.New(slog.NewTextHandler(output, nil))
+	client := nominatim.NewClient("http://localhost:8080", &http.Client{
+		Transport: rateLimitedTransport(t, 0, http.StatusOK, "", mustLoadValidSearchResults(t)),
+	}, nominatim.WithLogger(logger))
+	query := nominatim.NewSearchQuery()
+	query.FreeFormQuery = "lisboa"
+	if _, err := client.Search(context.TODO(), *query); err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+	if output.Len() > 0 {
+		t.Errorf("WithLogger() output = %s, want nothing logged above the debug level", output)
+	}
+}
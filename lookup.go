@@ -0,0 +1,156 @@
+package nominatim
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// maxLookupIDs is the maximum number of OSM IDs accepted by the lookup endpoint per call.
+const maxLookupIDs = 50
+
+// osmIDPattern matches the OSM IDs accepted by the lookup endpoint, as "N123", "W456" or "R789".
+var osmIDPattern = regexp.MustCompile(`^[NWR][0-9]+$`)
+
+// osmTypes maps the prefixes of the OSM IDs to their types, as sent in the results.
+var osmTypes = map[byte]string{'N': "node", 'W': "way", 'R': "relation"}
+
+// LookupQuery holds the parameters needed to lookup the details of OSM objects.
+type LookupQuery struct {
+
+	// OSMIDs holds the IDs of the objects, prefixed by their type, as "N123" for nodes, "W456" for ways or "R789" for
+	// relations. Up to 50 are accepted, as in LookupMany for more.
+	OSMIDs         []string
+	AddressDetails bool
+	ExtraTags      bool
+	NameDetails    bool
+	AcceptLanguage []string
+
+	// PolygonGeoJSON requests the geometry of the places, as their outlines, in the GeoJSON of the results.
+	PolygonGeoJSON bool
+}
+
+// NewLookupQuery creates a LookupQuery with default values for the given OSM IDs.
+func NewLookupQuery(osmIDs ...string) *LookupQuery {
+	return &LookupQuery{
+		OSMIDs:         osmIDs,
+		AddressDetails: true,
+	}
+}
+
+// Endpoint returns the endpoint the LookupQuery is sent to.
+func (q LookupQuery) Endpoint() string {
+	return EndpointLookup
+}
+
+// Encode encodes the parameters accordingly with the given LookupQuery.
+func (q LookupQuery) Encode() url.Values {
+	queryStr := url.Values{}
+	queryStr.Set(keyFormat, defaultFormat)
+	queryStr.Set(keyOSMIDs, strings.Join(q.OSMIDs, ","))
+	queryStr.Set(keyAddressDetails, "1")
+	if !q.AddressDetails {
+		queryStr.Set(keyAddressDetails, "0")
+	}
+	queryStr.Set(keyExtraTags, "1")
+	if !q.ExtraTags {
+		queryStr.Set(keyExtraTags, "0")
+	}
+	queryStr.Set(keyNameDetails, "1")
+	if !q.NameDetails {
+		queryStr.Set(keyNameDetails, "0")
+	}
+	if q.PolygonGeoJSON {
+		queryStr.Set(keyPolygonGeoJSON, "1")
+	}
+	if languages := canonicalLanguages(q.AcceptLanguage); len(languages) > 0 {
+		queryStr.Set(keyAcceptLanguage, strings.Join(languages, ","))
+	}
+	return queryStr
+}
+
+// Validate reports the problems found in the LookupQuery, as no OSM IDs, too many of them or invalid ones, as a
+// ValidationError, or nil if there is none.
+func (q LookupQuery) Validate() error {
+	v := &validation{}
+	if len(q.OSMIDs) == 0 {
+		v.add("OSMIDs", "is required")
+	}
+	if len(q.OSMIDs) > maxLookupIDs {
+		v.add("OSMIDs", "must hold up to "+strconv.Itoa(maxLookupIDs)+" IDs")
+	}
+	for _, id := range q.OSMIDs {
+		if !osmIDPattern.MatchString(id) {
+			v.add("OSMIDs", "holds an invalid ID "+strconv.Quote(id)+", as N123, W456 or R789")
+			break
+		}
+	}
+	v.languages("AcceptLanguage", q.AcceptLanguage)
+	return v.err()
+}
+
+// OSMID returns the OSM ID of the Result, prefixed by its type, as accepted by LookupQuery, or an empty string if
+// its type is unknown.
+func (r Result) OSMID() string {
+	for prefix, osmType := range osmTypes {
+		if r.OsmType == osmType {
+			return string(prefix) + strconv.Itoa(r.OsmId)
+		}
+	}
+	return ""
+}
+
+// LookupMany looks up the given OSM IDs through the given client, in chunks of up to 50, the maximum accepted by the
+// lookup endpoint, returning the results found in the order of the given IDs. The chunks are looked up one at a
+// time, unless more workers are given through WithWorkers. The IDs not found are skipped, as by the lookup endpoint,
+// and the first chunk failing fails the whole lookup.
+func LookupMany(ctx context.Context, client LookupHandler, osmIDs []string, opts ...BatchOption) ([]Result, error) {
+	config := newBatchConfig(append([]BatchOption{WithWorkers(1)}, opts...))
+	chunks := chunkOSMIDs(osmIDs)
+	found := make([][]Result, len(chunks))
+	errs := make([]error, len(chunks))
+	runBatch(ctx, len(chunks), config.workers, func(i int) {
+		found[i], errs[i] = client.Lookup(ctx, *NewLookupQuery(chunks[i]...), config.callOptions...)
+	}, func(i int) {
+		errs[i] = ctx.Err()
+	})
+	results := make([]Result, 0, len(osmIDs))
+	for i, err := range errs {
+		if err != nil {
+			return nil, fmt.Errorf("nominatim: looking up chunk %d of %d: %w", i+1, len(chunks), err)
+		}
+		results = append(results, orderByOSMIDs(chunks[i], found[i])...)
+	}
+	return results, nil
+}
+
+// orderByOSMIDs orders the given results in the order of the given OSM IDs, skipping the IDs not found.
+func orderByOSMIDs(osmIDs []string, results []Result) []Result {
+	byID := make(map[string]Result, len(results))
+	for _, result := range results {
+		byID[result.OSMID()] = result
+	}
+	ordered := make([]Result, 0, len(results))
+	for _, id := range osmIDs {
+		if result, ok := byID[strings.ToUpper(id)]; ok {
+			ordered = append(ordered, result)
+		}
+	}
+	return ordered
+}
+
+// chunkOSMIDs splits the given OSM IDs into chunks accepted by the lookup endpoint.
+func chunkOSMIDs(osmIDs []string) [][]string {
+	chunks := make([][]string, 0, (len(osmIDs)+maxLookupIDs-1)/maxLookupIDs)
+	for start := 0; start < len(osmIDs); start += maxLookupIDs {
+		end := start + maxLookupIDs
+		if end > len(osmIDs) {
+			end = len(osmIDs)
+		}
+		chunks = append(chunks, osmIDs[start:end])
+	}
+	return chunks
+}
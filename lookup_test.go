@@ -0,0 +1,164 @@
+package nominatim_test
+
+import (
+	"context"
+	"errors"
+	"github.com/diegohordi/nominatim"
+	"github.com/diegohordi/nominatim/nominatimtest"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+// mustCreateNodes creates the given number of places, as nodes numbered from 1.
+func mustCreateNodes(t *testing.T, n int) []nominatim.Result {
+	t.Helper()
+	places := make([]nominatim.Result, 0, n)
+	for i := 1; i <= n; i++ {
+		places = append(places, nominatim.Result{PlaceId: i, OsmType: "node", OsmId: i, Lat: "38.7", Lon: "-9.1", DisplayName: "Lisboa"})
+	}
+	return places
+}
+
+func Test_Lookup(t *testing.T) {
+	server := nominatimtest.NewServer()
+	t.Cleanup(server.Close)
+	tests := []struct {
+		name      string
+		query     *nominatim.LookupQuery
+		opts      []nominatim.Option
+		wantNames []string
+		wantErr   error
+	}{
+		{
+			name:      "should lookup the given OSM IDs",
+			query:     nominatim.NewLookupQuery("W24961587", "N455680276", "R1"),
+			wantNames: []string{"Torre de Belém", "Farmácia Estácio"},
+		},
+		{
+			name:    "should reject invalid IDs when validating the queries",
+			query:   nominatim.NewLookupQuery("24961587"),
+			opts:    []nominatim.Option{nominatim.WithQueryValidation()},
+			wantErr: nominatim.ErrInvalidQuery,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			d := nominatim.NewClient(server.URL, nil, tt.opts...)
+			results, err := d.Lookup(context.TODO(), *tt.query)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Lookup() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			names := make([]string, 0, len(results))
+			for _, result := range results {
+				names = append(names, result.Name)
+			}
+			if err == nil && !reflect.DeepEqual(names, tt.wantNames) {
+				t.Errorf("Lookup() got = %v, want %v", names, tt.wantNames)
+			}
+		})
+	}
+}
+
+func TestLookupQuery_Validate(t *testing.T) {
+	tooMany := make([]string, 51)
+	for i := range tooMany {
+		tooMany[i] = "N" + strconv.Itoa(i+1)
+	}
+	tests := []struct {
+		name       string
+		osmIDs     []string
+		wantFields []string
+	}{
+		{name: "should accept nodes, ways and relations", osmIDs: []string{"N1", "W2", "R3"}},
+		{name: "should reject no IDs", wantFields: []string{"OSMIDs"}},
+		{name: "should reject more than 50 IDs", osmIDs: tooMany, wantFields: []string{"OSMIDs"}},
+		{name: "should reject IDs without type", osmIDs: []string{"N1", "2"}, wantFields: []string{"OSMIDs"}},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assertValidation(t, nominatim.NewLookupQuery(tt.osmIDs...).Validate(), tt.wantFields)
+		})
+	}
+}
+
+func TestResult_OSMID(t *testing.T) {
+	tests := []struct {
+		name   string
+		result nominatim.Result
+		want   string
+	}{
+		{name: "should prefix nodes", result: nominatim.Result{OsmType: "node", OsmId: 1}, want: "N1"},
+		{name: "should prefix ways", result: nominatim.Result{OsmType: "way", OsmId: 2}, want: "W2"},
+		{name: "should prefix relations", result: nominatim.Result{OsmType: "relation", OsmId: 3}, want: "R3"},
+		{name: "should ignore unknown types", result: nominatim.Result{OsmType: "area", OsmId: 4}, want: ""},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := tt.result.OSMID(); got != tt.want {
+				t.Errorf("OSMID() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_LookupMany(t *testing.T) {
+	places := mustCreateNodes(t, 120)
+	osmIDs := make([]string, 0, 121)
+	wantIDs := make([]int, 0, 120)
+	for i := 120; i >= 1; i-- {
+		osmIDs = append(osmIDs, "N"+strconv.Itoa(i))
+		wantIDs = append(wantIDs, i)
+		if i == 60 {
+			osmIDs = append(osmIDs, "W60")
+		}
+	}
+	tests := []struct {
+		name         string
+		opts         []nominatim.BatchOption
+		closed       bool
+		wantRequests int
+		wantErr      bool
+	}{
+		{name: "should lookup the chunks one at a time", wantRequests: 3},
+		{name: "should lookup the chunks concurrently", opts: []nominatim.BatchOption{nominatim.WithWorkers(3)}, wantRequests: 3},
+		{name: "should fail when a chunk fails", closed: true, wantErr: true},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			server := nominatimtest.NewServer(places...)
+			defer server.Close()
+			d := nominatim.NewClient(server.URL, nil)
+			if tt.closed {
+				server.Close()
+			}
+			results, err := nominatim.LookupMany(context.TODO(), d, osmIDs, tt.opts...)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("LookupMany() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err != nil {
+				return
+			}
+			ids := make([]int, 0, len(results))
+			for _, result := range results {
+				ids = append(ids, result.OsmId)
+			}
+			if !reflect.DeepEqual(ids, wantIDs) {
+				t.Errorf("LookupMany() got = %v, want %v", ids, wantIDs)
+			}
+			if requests := server.Requests(); requests != tt.wantRequests {
+				t.Errorf("LookupMany() requests = %v, want %v", requests, tt.wantRequests)
+			}
+		})
+	}
+}
@@ -0,0 +1,116 @@
+package nominatim
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Weights of the criteria scored by MatchScore, summing up to 1.
+const (
+	matchWeightSimilarity   = 0.4
+	matchWeightImportance   = 0.25
+	matchWeightRank         = 0.2
+	matchWeightCompleteness = 0.15
+)
+
+// maxPlaceRank is the place rank of the most precise results, as houses.
+const maxPlaceRank = 30
+
+// diacritics folds the accented latin letters into their plain form, so "Belém" matches "belem".
+var diacritics = strings.NewReplacer(
+	"á", "a", "à", "a", "â", "a", "ã", "a", "ä", "a", "å", "a",
+	"ç", "c",
+	"é", "e", "è", "e", "ê", "e", "ë", "e",
+	"í", "i", "ì", "i", "î", "i", "ï", "i",
+	"ñ", "n",
+	"ó", "o", "ò", "o", "ô", "o", "õ", "o", "ö", "o", "ø", "o",
+	"ú", "u", "ù", "u", "û", "u", "ü", "u",
+	"ý", "y", "ÿ", "y",
+	"ß", "ss",
+)
+
+// BestMatch picks the most plausible result for the given query, the one with the highest MatchScore, for the
+// workflows geocoding one address into one coordinate. Ties are broken by the order of the results, as ranked by the
+// server. It reports false if there are no results.
+func BestMatch(results []Result, query SearchQuery) (Result, bool) {
+	if len(results) == 0 {
+		return Result{}, false
+	}
+	terms := matchTerms(queryText(query))
+	best, bestScore := 0, -1.0
+	for i, result := range results {
+		if score := matchScore(result, terms); score > bestScore {
+			best, bestScore = i, score
+		}
+	}
+	return results[best], true
+}
+
+// MatchScore scores, from 0 to 1, how plausibly the given result matches the given query, by the similarity of its
+// display name to the query, its importance, its place rank, the more precise the better, and the completeness of
+// its address.
+func MatchScore(result Result, query SearchQuery) float64 {
+	return matchScore(result, matchTerms(queryText(query)))
+}
+
+// matchScore scores how plausibly the given result matches the given query terms.
+func matchScore(result Result, terms []string) float64 {
+	rank := float64(result.PlaceRank) / maxPlaceRank
+	if rank > 1 {
+		rank = 1
+	}
+	importance := result.Importance
+	if importance > 1 {
+		importance = 1
+	}
+	return matchWeightSimilarity*similarity(terms, matchTerms(result.DisplayName)) +
+		matchWeightImportance*importance +
+		matchWeightRank*rank +
+		matchWeightCompleteness*result.Address.completeness()
+}
+
+// queryText returns the text of the given query, either free-form or structured.
+func queryText(query SearchQuery) string {
+	if query.FreeFormQuery != "" {
+		return query.FreeFormQuery
+	}
+	s := query.SearchStructuredQuery
+	return strings.Join([]string{s.Amenity, s.HouseNumber, s.Street, s.City, s.County, s.State, s.PostalCode, s.Country}, " ")
+}
+
+// matchTerms splits the given text into its lower-cased terms, without diacritics nor punctuation.
+func matchTerms(text string) []string {
+	return strings.FieldsFunc(diacritics.Replace(strings.ToLower(text)), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// similarity returns the fraction of the given query terms found among the given result terms, either as they are or
+// as their prefix, as in abbreviations.
+func similarity(queryTerms, resultTerms []string) float64 {
+	if len(queryTerms) == 0 {
+		return 0
+	}
+	found := 0
+	for _, queryTerm := range queryTerms {
+		for _, resultTerm := range resultTerms {
+			if resultTerm == queryTerm || (len(queryTerm) > 2 && strings.HasPrefix(resultTerm, queryTerm)) {
+				found++
+				break
+			}
+		}
+	}
+	return float64(found) / float64(len(queryTerms))
+}
+
+// completeness returns the fraction of the main fields of the address that are filled.
+func (a Address) completeness() float64 {
+	fields := []string{a.HouseNumber, a.Postcode, a.City, a.State, a.Country}
+	filled := 0
+	for _, field := range fields {
+		if strings.TrimSpace(field) != "" {
+			filled++
+		}
+	}
+	return float64(filled) / float64(len(fields))
+}
@@ -0,0 +1,100 @@
+package nominatim_test
+
+import (
+	"github.com/diegohordi/nominatim"
+	"testing"
+)
+
+func TestBestMatch(t *testing.T) {
+	belem := nominatim.Result{
+		PlaceId:     3,
+		PlaceRank:   30,
+		Importance:  0.6,
+		DisplayName: "Torre de Belém, Belém, Lisboa, 1400-038, Portugal",
+		Address:     nominatim.Address{City: "Lisboa", Postcode: "1400-038", Country: "Portugal"},
+	}
+	avenue := nominatim.Result{
+		PlaceId:     1,
+		PlaceRank:   26,
+		Importance:  0.7,
+		DisplayName: "Avenida de Belém, Lisboa, Portugal",
+		Address:     nominatim.Address{City: "Lisboa", Country: "Portugal"},
+	}
+	porto := nominatim.Result{
+		PlaceId:     4,
+		PlaceRank:   26,
+		Importance:  0.7,
+		DisplayName: "Torre dos Clérigos, Porto, 4050-291, Portugal",
+		Address:     nominatim.Address{City: "Porto", Postcode: "4050-291", Country: "Portugal"},
+	}
+	twin := avenue
+	twin.PlaceId = 2
+	tests := []struct {
+		name    string
+		results []nominatim.Result
+		query   nominatim.SearchQuery
+		want    int
+		wantOk  bool
+	}{
+		{
+			name:  "should report no match without results",
+			query: nominatim.SearchQuery{FreeFormQuery: "torre de belém"},
+		},
+		{
+			name:    "should pick the most similar result over a more important one",
+			results: []nominatim.Result{avenue, belem},
+			query:   nominatim.SearchQuery{FreeFormQuery: "Torre de Belém"},
+			want:    3,
+			wantOk:  true,
+		},
+		{
+			name:    "should match ignoring diacritics and punctuation",
+			results: []nominatim.Result{avenue, belem},
+			query:   nominatim.SearchQuery{FreeFormQuery: "torre de belem, lisboa"},
+			want:    3,
+			wantOk:  true,
+		},
+		{
+			name:    "should match the structured queries",
+			results: []nominatim.Result{belem, porto},
+			query:   nominatim.SearchQuery{SearchStructuredQuery: nominatim.SearchStructuredQuery{Amenity: "torre", City: "porto"}},
+			want:    4,
+			wantOk:  true,
+		},
+		{
+			name:    "should keep the order of the server on ties",
+			results: []nominatim.Result{twin, avenue, belem},
+			query:   nominatim.SearchQuery{FreeFormQuery: "avenida"},
+			want:    2,
+			wantOk:  true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, ok := nominatim.BestMatch(tt.results, tt.query)
+			if ok != tt.wantOk {
+				t.Fatalf("BestMatch() ok = %v, want %v", ok, tt.wantOk)
+			}
+			if ok && got.PlaceId != tt.want {
+				t.Errorf("BestMatch() got = %v, want %v", got.PlaceId, tt.want)
+			}
+		})
+	}
+}
+
+func TestMatchScore(t *testing.T) {
+	result := nominatim.Result{
+		PlaceRank:   30,
+		Importance:  1,
+		DisplayName: "1, Rua Augusta, Lisboa, 1100-053, Portugal",
+		Address:     nominatim.Address{HouseNumber: "1", Postcode: "1100-053", City: "Lisboa", State: "Lisboa", Country: "Portugal"},
+	}
+	if got := nominatim.MatchScore(result, nominatim.SearchQuery{FreeFormQuery: "1 rua augusta lisboa"}); got < 0.999 || got > 1.001 {
+		t.Errorf("MatchScore() got = %v, want 1", got)
+	}
+	if got := nominatim.MatchScore(nominatim.Result{}, nominatim.SearchQuery{FreeFormQuery: "rua augusta"}); got != 0 {
+		t.Errorf("MatchScore() got = %v, want 0", got)
+	}
+}
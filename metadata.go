@@ -0,0 +1,35 @@
+package nominatim
+
+import (
+	"context"
+)
+
+type metadataKey struct{}
+
+// ResponseMetadata holds information about the response of a call, besides its results.
+type ResponseMetadata struct {
+
+	// DecodeErrors holds the results skipped due to decoding failures, when lenient decoding is enabled.
+	DecodeErrors []DecodeError
+
+	// Cached tells whether the response was served from the cache.
+	Cached bool
+
+	// Stale tells whether the response was served from the cache after expired, while refreshed in the background.
+	Stale bool
+
+	// Revalidated tells whether the cached response was confirmed by the server, through a conditional request.
+	Revalidated bool
+}
+
+// WithResponseMetadata returns a copy of the given context that makes the client fill the given ResponseMetadata
+// with information about the response of the call made with it.
+func WithResponseMetadata(ctx context.Context, metadata *ResponseMetadata) context.Context {
+	return context.WithValue(ctx, metadataKey{}, metadata)
+}
+
+// responseMetadata retrieves the ResponseMetadata to be filled, if any.
+func responseMetadata(ctx context.Context) *ResponseMetadata {
+	metadata, _ := ctx.Value(metadataKey{}).(*ResponseMetadata)
+	return metadata
+}
@@ -0,0 +1,233 @@
+package nominatim
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// defaultMonitorInterval is the interval between the checks of a Monitor, by default.
+const defaultMonitorInterval = 30 * time.Second
+
+// Health is the health of a Nominatim API server, as tracked by a Monitor.
+type Health string
+
+const (
+	// HealthUnknown means the server wasn't checked yet.
+	HealthUnknown Health = "unknown"
+
+	// HealthHealthy means the server reports its database as available.
+	HealthHealthy Health = "healthy"
+
+	// HealthDegraded means the server responds but reports a failure, as when its database is being imported, or
+	// refuses the checks, as when rate limited.
+	HealthDegraded Health = "degraded"
+
+	// HealthDown means the server can't be reached, or doesn't respond in time.
+	HealthDown Health = "down"
+)
+
+// HealthChange holds a transition of the health of the server tracked by a Monitor, either of its overall health or of
+// the availability of its endpoints.
+type HealthChange struct {
+
+	// From is the health before the transition.
+	From Health
+
+	// To is the health after the transition.
+	To Health
+
+	// Status is the status reported by the server, if any.
+	Status Status
+
+	// Err is the error failing the check, if any.
+	Err error
+
+	// Capabilities holds the availability of the endpoints of the server, as detected by the client through its
+	// requests, if it reports them, as the one created by NewClient.
+	Capabilities Capabilities
+
+	// At is the time of the check.
+	At time.Time
+}
+
+// MonitorOption configures a Monitor.
+type MonitorOption func(config *monitorConfig)
+
+// monitorConfig holds the configuration of a Monitor.
+type monitorConfig struct {
+	interval  time.Duration
+	timeout   time.Duration
+	callbacks []func(change HealthChange)
+}
+
+// WithMonitorInterval makes the Monitor check the server at the given interval, 30s by default. Non-positive values
+// mean the default.
+func WithMonitorInterval(interval time.Duration) MonitorOption {
+	return func(config *monitorConfig) {
+		config.interval = interval
+	}
+}
+
+// WithMonitorTimeout bounds each check of the Monitor by the given timeout, the interval by default, so a server not
+// responding in time is considered down.
+func WithMonitorTimeout(timeout time.Duration) MonitorOption {
+	return func(config *monitorConfig) {
+		config.timeout = timeout
+	}
+}
+
+// WithHealthCallback makes the Monitor call the given function on every transition of the health of the server, in
+// the goroutine running the Monitor, so it must not block.
+func WithHealthCallback(callback func(change HealthChange)) MonitorOption {
+	return func(config *monitorConfig) {
+		if callback != nil {
+			config.callbacks = append(config.callbacks, callback)
+		}
+	}
+}
+
+// Monitor periodically checks the status of a Nominatim API server, tracking the transitions of its health and
+// notifying them to its subscribers, so the services embedding the client can gate their geocoding features, as while
+// the server is importing its data. When the client reports its Capabilities, the transitions also hold the
+// availability of every endpoint, as detected by the requests sent through the client, so a deployment with /search
+// disabled is told apart from a healthy one. It is safe for concurrent use.
+type Monitor struct {
+	client      StatusHandler
+	config      monitorConfig
+	mu          sync.Mutex
+	last        HealthChange
+	subscribers map[chan HealthChange]struct{}
+}
+
+// NewMonitor creates a Monitor checking the status of the server through the given client, once started by Run.
+func NewMonitor(client StatusHandler, opts ...MonitorOption) *Monitor {
+	config := monitorConfig{}
+	for _, opt := range opts {
+		opt(&config)
+	}
+	if config.interval <= 0 {
+		config.interval = defaultMonitorInterval
+	}
+	if config.timeout <= 0 {
+		config.timeout = config.interval
+	}
+	return &Monitor{
+		client:      client,
+		config:      config,
+		last:        HealthChange{From: HealthUnknown, To: HealthUnknown},
+		subscribers: make(map[chan HealthChange]struct{}),
+	}
+}
+
+// Run checks the server right away and then at every interval, until the given context is done, returning its
+// error.
+func (m *Monitor) Run(ctx context.Context) error {
+	ticker := time.NewTicker(m.config.interval)
+	defer ticker.Stop()
+	for {
+		m.Check(ctx)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Check checks the server once, notifying the transition of its health or of the availability of its endpoints, if
+// any, and returning its current health. Checks failing due to the given context being done are ignored.
+func (m *Monitor) Check(ctx context.Context) Health {
+	checkCtx, cancel := context.WithTimeout(ctx, m.config.timeout)
+	defer cancel()
+	status, err := m.client.CheckStatus(checkCtx)
+	if ctx.Err() != nil {
+		return m.Health()
+	}
+	change := HealthChange{To: classifyHealth(status, err), Status: status, Err: err, At: time.Now()}
+	if reporter, ok := m.client.(CapabilitiesReporter); ok {
+		change.Capabilities = reporter.Capabilities()
+	}
+	m.mu.Lock()
+	change.From = m.last.To
+	previous := m.last
+	m.last = change
+	if change.From == change.To && sameAvailability(previous.Capabilities, change.Capabilities) {
+		m.mu.Unlock()
+		return change.To
+	}
+	for subscriber := range m.subscribers {
+		notify(subscriber, change)
+	}
+	m.mu.Unlock()
+	for _, callback := range m.config.callbacks {
+		callback(change)
+	}
+	return change.To
+}
+
+// Health returns the health of the server as of the last check.
+func (m *Monitor) Health() Health {
+	return m.Last().To
+}
+
+// Last returns the outcome of the last check, as the transition from the previous health.
+func (m *Monitor) Last() HealthChange {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.last
+}
+
+// Subscribe returns a channel receiving the transitions of the health of the server, along with the function
+// unsubscribing from them, closing the channel. Only the latest transition not received yet is kept, so slow
+// receivers miss the intermediate ones rather than blocking the Monitor.
+func (m *Monitor) Subscribe() (<-chan HealthChange, func()) {
+	subscriber := make(chan HealthChange, 1)
+	m.mu.Lock()
+	m.subscribers[subscriber] = struct{}{}
+	m.mu.Unlock()
+	once := sync.Once{}
+	return subscriber, func() {
+		once.Do(func() {
+			m.mu.Lock()
+			defer m.mu.Unlock()
+			delete(m.subscribers, subscriber)
+			close(subscriber)
+		})
+	}
+}
+
+// notify sends the given transition to the given subscriber, replacing the one not received yet, if any.
+func notify(subscriber chan HealthChange, change HealthChange) {
+	select {
+	case <-subscriber:
+	default:
+	}
+	subscriber <- change
+}
+
+// classifyHealth classifies the health of the server by the outcome of a check.
+func classifyHealth(status Status, err error) Health {
+	var transportErr transportError
+	switch {
+	case err == nil && status.Status == 0:
+		return HealthHealthy
+	case errors.As(err, &transportErr) || errors.Is(err, context.DeadlineExceeded):
+		return HealthDown
+	}
+	return HealthDegraded
+}
+
+// sameAvailability checks if the given Capabilities hold the same unavailable endpoints.
+func sameAvailability(a, b Capabilities) bool {
+	if len(a.Unavailable) != len(b.Unavailable) {
+		return false
+	}
+	for endpoint := range a.Unavailable {
+		if b.Available(endpoint) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,149 @@
+package nominatim_test
+
+import (
+	"context"
+	"errors"
+	"github.com/diegohordi/nominatim"
+	"github.com/diegohordi/nominatim/nominatimtest"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestMonitor_Check(t *testing.T) {
+	tests := []struct {
+		name  string
+		setup func(server *nominatimtest.Server)
+		want  nominatim.Health
+	}{
+		{
+			name:  "should be healthy when the database is available",
+			setup: func(server *nominatimtest.Server) {},
+			want:  nominatim.HealthHealthy,
+		},
+		{
+			name: "should be degraded when the database is unavailable",
+			setup: func(server *nominatimtest.Server) {
+				server.SetFixture(nominatim.EndpointStatus, nominatimtest.Fixture{Body: []byte(`{"status": 700, "message": "No database"}`)})
+			},
+			want: nominatim.HealthDegraded,
+		},
+		{
+			name: "should be degraded when the server fails",
+			setup: func(server *nominatimtest.Server) {
+				server.SetFixture(nominatim.EndpointStatus, nominatimtest.ErrorFixture(http.StatusInternalServerError))
+			},
+			want: nominatim.HealthDegraded,
+		},
+		{
+			name: "should be down when the server doesn't respond in time",
+			setup: func(server *nominatimtest.Server) {
+				server.SetLatency(time.Second)
+			},
+			want: nominatim.HealthDown,
+		},
+		{
+			name: "should be down when the server can't be reached",
+			setup: func(server *nominatimtest.Server) {
+				server.Close()
+			},
+			want: nominatim.HealthDown,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			server := nominatimtest.NewServer()
+			t.Cleanup(server.Close)
+			tt.setup(server)
+			monitor := nominatim.NewMonitor(nominatim.NewClient(server.URL, nil), nominatim.WithMonitorTimeout(20*time.Millisecond))
+			if got := monitor.Check(context.TODO()); got != tt.want {
+				t.Errorf("Check() got = %v, want %v, error %v", got, tt.want, monitor.Last().Err)
+			}
+			if got := monitor.Health(); got != tt.want {
+				t.Errorf("Health() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMonitor_Transitions(t *testing.T) {
+	server := nominatimtest.NewServer()
+	t.Cleanup(server.Close)
+	var callbacks []nominatim.Health
+	monitor := nominatim.NewMonitor(nominatim.NewClient(server.URL, nil), nominatim.WithHealthCallback(func(change nominatim.HealthChange) {
+		callbacks = append(callbacks, change.To)
+	}))
+	changes, unsubscribe := monitor.Subscribe()
+	monitor.Check(context.TODO())
+	if change := <-changes; change.From != nominatim.HealthUnknown || change.To != nominatim.HealthHealthy {
+		t.Errorf("Subscribe() got = %+v, want from unknown to healthy", change)
+	}
+	monitor.Check(context.TODO())
+	server.FailNext(1, nominatimtest.ErrorFixture(http.StatusServiceUnavailable))
+	monitor.Check(context.TODO())
+	monitor.Check(context.TODO())
+	if change := <-changes; change.From != nominatim.HealthDegraded || change.To != nominatim.HealthHealthy {
+		t.Errorf("Subscribe() got = %+v, want the latest transition, from degraded to healthy", change)
+	}
+	unsubscribe()
+	unsubscribe()
+	if _, ok := <-changes; ok {
+		t.Errorf("Subscribe() channel open, want it closed once unsubscribed")
+	}
+	want := []nominatim.Health{nominatim.HealthHealthy, nominatim.HealthDegraded, nominatim.HealthHealthy}
+	if !reflect.DeepEqual(callbacks, want) {
+		t.Errorf("WithHealthCallback() got = %v, want %v", callbacks, want)
+	}
+}
+
+func TestMonitor_Run(t *testing.T) {
+	server := nominatimtest.NewServer()
+	t.Cleanup(server.Close)
+	monitor := nominatim.NewMonitor(nominatim.NewClient(server.URL, nil), nominatim.WithMonitorInterval(5*time.Millisecond))
+	ctx, cancel := context.WithTimeout(context.TODO(), 50*time.Millisecond)
+	defer cancel()
+	if err := monitor.Run(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Run() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+	if monitor.Health() != nominatim.HealthHealthy || server.Requests() < 2 {
+		t.Errorf("Run() health = %v after %d checks, want healthy after a few", monitor.Health(), server.Requests())
+	}
+}
+
+func TestMonitor_Capabilities(t *testing.T) {
+	server := nominatimtest.NewServer()
+	t.Cleanup(server.Close)
+	client := nominatim.NewClient(server.URL, nil)
+	monitor := nominatim.NewMonitor(client)
+	changes, unsubscribe := monitor.Subscribe()
+	defer unsubscribe()
+	monitor.Check(context.TODO())
+	if change := <-changes; change.To != nominatim.HealthHealthy || !change.Capabilities.Available(nominatim.EndpointSearch) {
+		t.Errorf("Subscribe() got = %+v, want healthy with search available", change)
+	}
+	server.SetFixture(nominatim.EndpointSearch, nominatimtest.ErrorFixture(http.StatusNotFound))
+	if _, err := client.Search(context.TODO(), nominatim.SearchQuery{FreeFormQuery: "lisboa"}); !errors.Is(err, nominatim.ErrEndpointUnavailable) {
+		t.Fatalf("Search() error = %v, want %v", err, nominatim.ErrEndpointUnavailable)
+	}
+	monitor.Check(context.TODO())
+	select {
+	case change := <-changes:
+		if change.From != nominatim.HealthHealthy || change.To != nominatim.HealthHealthy || change.Capabilities.Available(nominatim.EndpointSearch) {
+			t.Errorf("Subscribe() got = %+v, want healthy with search unavailable", change)
+		}
+	default:
+		t.Errorf("Subscribe() got no transition, want search unavailable")
+	}
+	monitor.Check(context.TODO())
+	select {
+	case change := <-changes:
+		t.Errorf("Subscribe() got = %+v, want no transition while nothing changes", change)
+	default:
+	}
+	if monitor.Last().Capabilities.Available(nominatim.EndpointSearch) {
+		t.Errorf("Last() got search available, want unavailable")
+	}
+}
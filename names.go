@@ -0,0 +1,82 @@
+package nominatim
+
+import (
+	"strings"
+)
+
+// Name tags sent in namedetails, when requested through NameDetails.
+const (
+	nameTagName          = "name"
+	nameTagOfficial      = "official_name"
+	nameTagShort         = "short_name"
+	nameTagInternational = "int_name"
+	nameTagAlternate     = "alt_name"
+	nameTagOld           = "old_name"
+	nameTagLanguage      = "name:"
+	nameListSeparator    = ";"
+)
+
+// Names holds the names of a place, grouped from the namedetails of a result.
+type Names struct {
+
+	// Name is the default name of the place, usually in the local language.
+	Name string
+
+	// Official is the official name of the place, if different from the default one.
+	Official string
+
+	// Short is the short name of the place, as an abbreviation.
+	Short string
+
+	// International is the name of the place used internationally.
+	International string
+
+	// Alternate holds the other names the place is also known as.
+	Alternate []string
+
+	// Old holds the historic names of the place.
+	Old []string
+
+	// Localized holds the names of the place by language code, as "en" or "pt".
+	Localized map[string]string
+}
+
+// Names groups the namedetails of the result, requested through NameDetails, by their kind.
+func (r Result) Names() Names {
+	names := Names{
+		Name:          r.NameDetails[nameTagName],
+		Official:      r.NameDetails[nameTagOfficial],
+		Short:         r.NameDetails[nameTagShort],
+		International: r.NameDetails[nameTagInternational],
+		Alternate:     splitNameList(r.NameDetails[nameTagAlternate]),
+		Old:           splitNameList(r.NameDetails[nameTagOld]),
+	}
+	for tag, name := range r.NameDetails {
+		if !strings.HasPrefix(tag, nameTagLanguage) || name == "" {
+			continue
+		}
+		if names.Localized == nil {
+			names.Localized = make(map[string]string)
+		}
+		names.Localized[strings.TrimPrefix(tag, nameTagLanguage)] = name
+	}
+	return names
+}
+
+// AlternateNames returns the other names the place is also known as, from the namedetails of the result, requested
+// through NameDetails, including the historic ones.
+func (r Result) AlternateNames() []string {
+	names := r.Names()
+	return append(names.Alternate, names.Old...)
+}
+
+// splitNameList splits the given list of names, separated by semicolons as in OpenStreetMap tags.
+func splitNameList(list string) []string {
+	var names []string
+	for _, name := range strings.Split(list, nameListSeparator) {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
@@ -0,0 +1,57 @@
+package nominatim_test
+
+import (
+	"encoding/json"
+	"github.com/diegohordi/nominatim"
+	"reflect"
+	"testing"
+)
+
+func TestResult_Names(t *testing.T) {
+	tests := []struct {
+		name               string
+		nameDetails        string
+		want               nominatim.Names
+		wantAlternateNames []string
+	}{
+		{
+			name:        "should group the names by their kind",
+			nameDetails: `{"name": "Lisboa", "official_name": "Cidade de Lisboa", "int_name": "Lisbon", "alt_name": "Lisbona; Olisipo", "old_name": "Felicitas Julia", "name:en": "Lisbon", "name:es": "Lisboa"}`,
+			want: nominatim.Names{
+				Name:          "Lisboa",
+				Official:      "Cidade de Lisboa",
+				International: "Lisbon",
+				Alternate:     []string{"Lisbona", "Olisipo"},
+				Old:           []string{"Felicitas Julia"},
+				Localized:     map[string]string{"en": "Lisbon", "es": "Lisboa"},
+			},
+			wantAlternateNames: []string{"Lisbona", "Olisipo", "Felicitas Julia"},
+		},
+		{
+			name:        "should hold only the default name",
+			nameDetails: `{"name": "Sintra"}`,
+			want:        nominatim.Names{Name: "Sintra"},
+		},
+		{
+			name:        "should hold nothing without namedetails",
+			nameDetails: `null`,
+			want:        nominatim.Names{},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			result := nominatim.Result{}
+			if err := json.Unmarshal([]byte(`{"namedetails": `+tt.nameDetails+`}`), &result); err != nil {
+				t.Fatal(err)
+			}
+			if got := result.Names(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Names() got = %+v, want %+v", got, tt.want)
+			}
+			if got := result.AlternateNames(); !reflect.DeepEqual(got, tt.wantAlternateNames) {
+				t.Errorf("AlternateNames() got = %v, want %v", got, tt.wantAlternateNames)
+			}
+		})
+	}
+}
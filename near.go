@@ -0,0 +1,58 @@
+package nominatim
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/diegohordi/nominatim/geo"
+)
+
+// NewNearQuery creates a SearchQuery to find the POIs of the given amenity, as "pharmacy", within the given radius,
+// in meters, from the given point. The amenity is searched through a structured query, bounded by the viewbox of the
+// radius, so the results keep Nominatim ranking, as in NewNearestHospitalQuery.
+func NewNearQuery(amenity string, point geo.Point, radius float64) *SearchQuery {
+	query := NewSearchQuery()
+	query.Amenity = amenity
+	query.Viewbox = viewboxAround(point.Lat, point.Lon, radius)
+	query.Bounded = true
+	query.Limit = maxLimit
+	return query
+}
+
+// NewNearPlaceQuery creates a SearchQuery to find the POIs of the given amenity near the given place, as a city or a
+// street, through the "[amenity] near place" special phrase understood by Nominatim.
+func NewNearPlaceQuery(amenity, place string) *SearchQuery {
+	query := NewSearchQuery()
+	query.FreeFormQuery = "[" + strings.TrimSpace(amenity) + "] near " + strings.TrimSpace(place)
+	return query
+}
+
+// SearchNear finds the POIs of the given amenity, as "pharmacy", within the given radius, in meters, from the given
+// point, through the given client, sorted by their distance to the point. The results outside the radius, but still
+// within its viewbox, are discarded, and ErrNoResults is returned if none is left.
+func SearchNear(ctx context.Context, client SearchHandler, amenity string, point geo.Point, radius float64, opts ...CallOption) ([]Result, error) {
+	results, err := client.Search(ctx, *NewNearQuery(amenity, point, radius), opts...)
+	if err != nil {
+		return nil, err
+	}
+	distances := make(map[int]float64, len(results))
+	near := make([]Result, 0, len(results))
+	for _, result := range results {
+		location, err := result.Point()
+		if err != nil {
+			continue
+		}
+		if distance := geo.DistanceMeters(point, location); distance <= radius {
+			distances[result.PlaceId] = distance
+			near = append(near, result)
+		}
+	}
+	if len(near) == 0 {
+		return nil, ErrNoResults
+	}
+	sort.SliceStable(near, func(i, j int) bool {
+		return distances[near[i].PlaceId] < distances[near[j].PlaceId]
+	})
+	return near, nil
+}
@@ -0,0 +1,62 @@
+package nominatim_test
+
+import (
+	"context"
+	"errors"
+	"github.com/diegohordi/nominatim"
+	"github.com/diegohordi/nominatim/geo"
+	"github.com/diegohordi/nominatim/nominatimtest"
+	"reflect"
+	"testing"
+)
+
+func Test_SearchNear(t *testing.T) {
+	pharmacy := func(id int, lat, lon string) nominatim.Result {
+		return nominatim.Result{PlaceId: id, Lat: lat, Lon: lon, Type: "pharmacy", DisplayName: "Farmácia, Lisboa"}
+	}
+	server := nominatimtest.NewServer(
+		pharmacy(1, "38.7200", "-9.1390"),
+		pharmacy(2, "38.7140", "-9.1390"),
+		pharmacy(3, "38.7215", "-9.1300"),
+		pharmacy(4, "38.7500", "-9.1390"),
+		nominatim.Result{PlaceId: 5, Lat: "38.7131", Lon: "-9.1390", Type: "restaurant", DisplayName: "Restaurante, Lisboa"},
+	)
+	t.Cleanup(server.Close)
+	client := nominatim.NewClient(server.URL, nil)
+	tests := []struct {
+		name    string
+		amenity string
+		radius  float64
+		wantIDs []int
+		wantErr error
+	}{
+		{name: "should sort the results by distance, discarding the ones outside the radius", amenity: "pharmacy", radius: 1000, wantIDs: []int{2, 1}},
+		{name: "should only return the given amenity", amenity: "restaurant", radius: 1000, wantIDs: []int{5}},
+		{name: "should fail when nothing is within the radius", amenity: "pharmacy", radius: 50, wantErr: nominatim.ErrNoResults},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			results, err := nominatim.SearchNear(context.TODO(), client, tt.amenity, geo.Point{Lat: 38.7130, Lon: -9.1390}, tt.radius)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("SearchNear() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			ids := make([]int, 0, len(results))
+			for _, result := range results {
+				ids = append(ids, result.PlaceId)
+			}
+			if len(tt.wantIDs) > 0 && !reflect.DeepEqual(ids, tt.wantIDs) {
+				t.Errorf("SearchNear() got = %v, want %v", ids, tt.wantIDs)
+			}
+		})
+	}
+}
+
+func Test_NewNearPlaceQuery(t *testing.T) {
+	query := nominatim.NewNearPlaceQuery(" pharmacy ", "Lisboa")
+	if want := "[pharmacy] near Lisboa"; query.FreeFormQuery != want {
+		t.Errorf("NewNearPlaceQuery() got = %v, want %v", query.FreeFormQuery, want)
+	}
+}
@@ -3,9 +3,11 @@ package nominatim
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"io"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
 )
 
@@ -13,10 +15,12 @@ const (
 	defaultFormat = "jsonv2"
 )
 
+// Endpoints of Nominatim API.
 const (
-	endpointSearch  = "search"
-	endpointReverse = "reverse"
-	endpointStatus  = "status"
+	EndpointSearch  = "search"
+	EndpointReverse = "reverse"
+	EndpointStatus  = "status"
+	EndpointLookup  = "lookup"
 )
 
 const (
@@ -31,9 +35,11 @@ const (
 	keyAddressDetails = "addressdetails"
 	keyExtraTags      = "extratags"
 	keyNameDetails    = "namedetails"
+	keyPolygonGeoJSON = "polygon_geojson"
 	keyAcceptLanguage = "accept-language"
 	keyExcludePlaces  = "exclude_place_ids"
 	keyFreeFormQuery  = "q"
+	keyAmenity        = "amenity"
 	keyStreet         = "street"
 	keyCity           = "city"
 	keyCounty         = "county"
@@ -43,18 +49,15 @@ const (
 	keyLimit          = "limit"
 	keyLatitude       = "lat"
 	keyLongitude      = "lon"
+	keyOSMIDs         = "osm_ids"
 	keyFormat         = "format"
+	keyLayer          = "layer"
+	keyFeatureType    = "featureType"
+	keyViewbox        = "viewbox"
+	keyBounded        = "bounded"
+	keyCountryCodes   = "countrycodes"
 )
 
-type Error struct {
-	Code    int    `json:"code"`
-	Message string `json:"message"`
-}
-
-func (e Error) Error() string {
-	return fmt.Sprintf("%d: %s", e.Code, e.Message)
-}
-
 // Address holds address information from a result.
 type Address struct {
 	City           string `json:"city"`
@@ -67,27 +70,35 @@ type Address struct {
 	Neighbourhood  string `json:"neighbourhood"`
 	Postcode       string `json:"postcode"`
 	PublicBuilding string `json:"public_building"`
+	Road           string `json:"road"`
 	State          string `json:"state"`
 	Suburb         string `json:"suburb"`
+	Town           string `json:"town"`
+	Village        string `json:"village"`
 }
 
 // Result holds information from a specific location.
 type Result struct {
-	PlaceId     int      `json:"place_id"`
-	Licence     string   `json:"licence"`
-	OsmType     string   `json:"osm_type"`
-	OsmId       int      `json:"osm_id"`
-	Lat         string   `json:"lat"`
-	Lon         string   `json:"lon"`
-	PlaceRank   int      `json:"place_rank"`
-	Category    string   `json:"category"`
-	Type        string   `json:"type"`
-	Importance  float64  `json:"importance"`
-	AddressType string   `json:"addresstype"`
-	DisplayName string   `json:"display_name"`
-	Name        string   `json:"name"`
-	Address     Address  `json:"address"`
-	BoundingBox []string `json:"bounding_box"`
+	PlaceId     int               `json:"place_id"`
+	Licence     string            `json:"licence"`
+	OsmType     string            `json:"osm_type"`
+	OsmId       int               `json:"osm_id"`
+	Lat         string            `json:"lat"`
+	Lon         string            `json:"lon"`
+	PlaceRank   int               `json:"place_rank"`
+	Category    string            `json:"category"`
+	Type        string            `json:"type"`
+	Importance  float64           `json:"importance"`
+	AddressType string            `json:"addresstype"`
+	DisplayName string            `json:"display_name"`
+	Name        string            `json:"name"`
+	Address     Address           `json:"address"`
+	BoundingBox []string          `json:"boundingbox"`
+	NameDetails map[string]string `json:"namedetails"`
+	ExtraTags   map[string]string `json:"extratags"`
+
+	// GeoJSON is the geometry of the place, as a GeoJSON geometry, when requested through PolygonGeoJSON.
+	GeoJSON json.RawMessage `json:"geojson,omitempty"`
 }
 
 // Status holds information from Nomination API server.
@@ -95,138 +106,428 @@ type Status struct {
 	Status          int       `json:"status"`
 	Message         string    `json:"message"`
 	DataUpdated     time.Time `json:"data_updated"`
+	DataUpdatedRaw  string    `json:"-"`
 	SoftwareVersion string    `json:"software_version"`
 	DatabaseVersion string    `json:"database_version"`
 }
 
+// dataUpdatedLayouts holds the timestamp layouts used by the different Nominatim versions to report data_updated.
+var dataUpdatedLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05Z0700",
+	"2006-01-02T15:04:05Z07",
+	"2006-01-02 15:04:05Z07:00",
+	"2006-01-02 15:04:05Z0700",
+	"2006-01-02 15:04:05Z07",
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+}
+
+// plainStatus is a Status without its UnmarshalJSON method.
+type plainStatus Status
+
+// statusFields holds the fields of the status as sent by the server, with data_updated left raw.
+type statusFields struct {
+	*plainStatus
+	DataUpdated json.RawMessage `json:"data_updated"`
+}
+
+// UnmarshalJSON decodes the status, parsing data_updated tolerantly and keeping its raw value in DataUpdatedRaw.
+func (s *Status) UnmarshalJSON(data []byte) error {
+	aux := &statusFields{plainStatus: (*plainStatus)(s)}
+	if err := json.Unmarshal(data, aux); err != nil {
+		return err
+	}
+	s.DataUpdated, s.DataUpdatedRaw = time.Time{}, ""
+	if len(aux.DataUpdated) == 0 || string(aux.DataUpdated) == "null" {
+		return nil
+	}
+	raw := strings.Trim(string(aux.DataUpdated), `"`)
+	dataUpdated, err := parseDataUpdated(raw)
+	if err != nil {
+		return err
+	}
+	s.DataUpdated, s.DataUpdatedRaw = dataUpdated, raw
+	return nil
+}
+
+// parseDataUpdated parses the given timestamp trying every known layout, plus Unix timestamps.
+func parseDataUpdated(value string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if seconds, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return time.Unix(seconds, 0).UTC(), nil
+	}
+	for _, layout := range dataUpdatedLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("nominatim: unknown data_updated format %q", value)
+}
+
 type SearchHandler interface {
 
 	// Search looks up a location from a textual description or address.
-	Search(ctx context.Context, query SearchQuery) ([]Result, error)
+	Search(ctx context.Context, query SearchQuery, opts ...CallOption) ([]Result, error)
 }
 
 type ReverseHandler interface {
 
 	// Reverse generates an address from a latitude and longitude.
-	Reverse(ctx context.Context, query ReverseQuery) (Result, error)
+	Reverse(ctx context.Context, query ReverseQuery, opts ...CallOption) (Result, error)
+}
+
+type LookupHandler interface {
+
+	// Lookup retrieves the details of the given OSM objects.
+	Lookup(ctx context.Context, query LookupQuery, opts ...CallOption) ([]Result, error)
 }
 
 type StatusHandler interface {
 
-	// CheckStatus checks if Nominatim service and database is running.
-	CheckStatus(ctx context.Context) (Status, error)
+	// CheckStatus checks if Nominatim service and database is running. When the server reports a failure, as
+	// StatusNoDatabase, a StatusError is returned along with the status reported.
+	CheckStatus(ctx context.Context, opts ...CallOption) (Status, error)
 }
 
 type Client interface {
 	SearchHandler
 	ReverseHandler
+	LookupHandler
 	StatusHandler
 }
 
 type defaultClient struct {
-	baseURL string
-	client  *http.Client
+	baseURL              string
+	mirrors              []string
+	transport            Transport
+	decoder              Decoder
+	rateLimitRetries     int
+	retryPolicy          *RetryPolicy
+	limiter              Limiter
+	lenientDecoding      bool
+	strictDecoding       bool
+	hedging              *hedging
+	capabilities         *capabilities
+	cache                Cache
+	cachePolicy          CachePolicy
+	cacheCodec           CacheCodec
+	cacheAdmission       *cacheAdmission
+	semaphore            semaphore
+	staleWhileRevalidate *staleWhileRevalidate
+	conditionalKeep      time.Duration
+	queryLengthLimit     *queryLengthLimit
+	validateQueries      bool
+	userAgent            string
+	referer              string
+	defaultHeaders       http.Header
+	timeout              time.Duration
+	callTimeout          time.Duration
+	email                string
+	apiKey               string
+	observers            observers
+	sampling             *Sampling
+	slowRequestThreshold time.Duration
+	redaction            Redaction
+	maxResponseSize      int64
+	compression          bool
+	resultFilter         *ResultFilter
+	boundedFiltering     bool
+	languages            []string
 }
 
-func NewClient(baseURL string, client *http.Client) Client {
-	return &defaultClient{baseURL: baseURL, client: client}
+// NewClient creates a Client for the Nominatim API serving at the given base URL, using the given http.Client and
+// options. The http.DefaultClient is used when no http.Client is given. An empty base URL means the public Nominatim
+// API, as in NewClientWithOptions.
+func NewClient(baseURL string, client *http.Client, opts ...Option) Client {
+	return NewClientWithOptions(baseURL, append([]Option{WithHTTPClient(client)}, opts...)...)
 }
 
-func (d defaultClient) Search(ctx context.Context, query SearchQuery) ([]Result, error) {
-	resultsChan := make(chan []Result, 1)
-	errChan := make(chan error, 1)
-	endpoint := fmt.Sprintf("%s/%s?%s", d.baseURL, endpointSearch, query.buildQueryString())
+// NewClientWithOptions creates a Client for the Nominatim API serving at the given base URL, configured through the
+// given options. The http.DefaultClient is used unless another one is given through WithHTTPClient or WithTransport.
+// An empty base URL means the public Nominatim API, at PublicBaseURL, complying with its usage policy as a client
+// created through NewPublicClient, though identified only by the DefaultUserAgent.
+func NewClientWithOptions(baseURL string, opts ...Option) Client {
+	if baseURL == "" {
+		baseURL, opts = PublicBaseURL, append(publicPolicyOptions("", ""), opts...)
+	}
+	d := &defaultClient{
+		baseURL:      baseURL,
+		transport:    http.DefaultClient,
+		decoder:      DecoderFunc(DecodeResponse),
+		capabilities: newCapabilities(),
+		userAgent:    DefaultUserAgent,
+	}
+	for _, opt := range opts {
+		opt(d)
+	}
+	if d.sampling != nil {
+		d.observers = d.observers.sampled()
+	}
+	return d
+}
 
-	go func() {
-		resp, err := d.client.Get(endpoint)
-		if err != nil {
-			errChan <- err
-			return
+// get performs a GET request for the given query and decodes the response body into v, retrying it accordingly
+// with the client configuration, unless cached.
+func (d defaultClient) get(ctx context.Context, query QueryEncoder, v interface{}) error {
+	query = callOptionsFrom(ctx).query(d.identityQuery(query))
+	entry, ok, err := d.getCached(ctx, d.cacheKey(ctx, query), query, v)
+	if ok {
+		return err
+	}
+	if entry != nil {
+		ctx = withConditionalEntry(ctx, *entry)
+	}
+	return d.fetch(ctx, query, v)
+}
+
+// fetch performs a GET request for the given query and decodes the response body into v, retrying it accordingly
+// with the client configuration.
+func (d defaultClient) fetch(ctx context.Context, query QueryEncoder, v interface{}) error {
+	for attempt := 1; ; attempt++ {
+		err := d.getOnce(ctx, query, v)
+		delay, ok := d.retryDelay(attempt, err)
+		if !ok || !canWait(ctx, delay) {
+			return err
 		}
-		defer func(Body io.ReadCloser) {
-			_ = Body.Close()
-		}(resp.Body)
-		results := make([]Result, 0)
-		if err = json.NewDecoder(resp.Body).Decode(&results); err != nil {
-			errChan <- err
+		d.observers.retrying(ctx, query, attempt, delay, err)
+		if err := wait(ctx, delay); err != nil {
+			return err
 		}
-		resultsChan <- results
-	}()
+	}
+}
 
-	select {
-	case results := <-resultsChan:
-		return results, nil
-	case err := <-errChan:
-		return nil, err
-	case <-ctx.Done():
-		return nil, ctx.Err()
+// getOnce performs a single GET request for the given query and decodes the response body into v, hedging it, if
+// enabled.
+func (d defaultClient) getOnce(ctx context.Context, query QueryEncoder, v interface{}) error {
+	if d.hedging != nil {
+		return d.getHedged(ctx, query, v)
+	}
+	return d.getWithFailover(ctx, query, v)
+}
+
+// getWithFailover performs a GET request for the given query and decodes the response body into v, failing over to
+// the mirrors, if any.
+func (d defaultClient) getWithFailover(ctx context.Context, query QueryEncoder, v interface{}) error {
+	err := d.getFrom(ctx, d.baseURL, query, v)
+	for _, mirror := range d.mirrors {
+		if !shouldFailover(ctx, err) {
+			return err
+		}
+		err = d.getFrom(ctx, mirror, query, v)
 	}
+	return err
 }
 
-func (d defaultClient) Reverse(ctx context.Context, query ReverseQuery) (Result, error) {
-	resultChan := make(chan Result, 1)
+// getFrom performs a single GET request for the given query to the given base URL and decodes the response body
+// into v.
+func (d defaultClient) getFrom(ctx context.Context, baseURL string, query QueryEncoder, v interface{}) error {
+	if d.limiter != nil {
+		start := time.Now()
+		if err := d.limiter.Wait(ctx); err != nil {
+			return err
+		}
+		if waited := time.Since(start); waited >= time.Millisecond {
+			d.observers.rateLimited(ctx, query, waited)
+		}
+	}
+	if d.semaphore != nil {
+		if err := d.semaphore.acquire(ctx); err != nil {
+			return err
+		}
+	}
+	if d.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, d.timeout)
+		defer cancel()
+	}
 	errChan := make(chan error, 1)
-	endpoint := fmt.Sprintf("%s/%s?%s", d.baseURL, endpointReverse, query.buildQueryString())
 
 	go func() {
-		resp, err := d.client.Get(endpoint)
+		if d.semaphore != nil {
+			defer d.semaphore.release()
+		}
+		req, err := NewRequest(ctx, baseURL, query)
 		if err != nil {
+			errChan <- d.redactError(err)
+			return
+		}
+		d.setHeaders(req)
+		start := time.Now()
+		if err := d.observers.requestStarted(ctx, query, req); err != nil {
+			d.finishRequest(ctx, query, req, nil, start, err)
+			errChan <- err
+			return
+		}
+		resp, err := d.transport.Do(req)
+		if err != nil {
+			err = newStageError(StageTransport, baseURL, transportError{err: d.redactError(err)})
+			d.finishRequest(ctx, query, req, nil, start, err)
 			errChan <- err
 			return
 		}
-		defer func(Body io.ReadCloser) {
-			_ = Body.Close()
-		}(resp.Body)
-		result := &struct {
-			Result
-			Error Error `json:"error"`
-		}{}
-		if err = json.NewDecoder(resp.Body).Decode(result); err != nil {
+		body, err := d.readBody(resp)
+		closeBody(resp.Body)
+		switch {
+		case errors.Is(err, ErrResponseTooLarge):
+			err = newStageError(StageDecode, baseURL, err)
+			d.observers.decodeFailed(ctx, query, err)
+		case err != nil:
+			err = newStageError(StageTransport, baseURL, err)
+		}
+		if err != nil {
+			d.finishRequest(ctx, query, req, resp, start, err)
 			errChan <- err
+			return
 		}
-		if result.Error.Code > 0 {
-			errChan <- result.Error
+		resp, body = notModified(ctx, resp, body)
+		err = d.decoder.Decode(resp, body, v)
+		switch {
+		case err == nil:
+		case ClassifyOutcome(err) == OutcomeDataError:
+			err = newStageError(StageDecode, baseURL, err)
+			d.observers.decodeFailed(ctx, query, err)
+		default:
+			err = newStageError(StageResponse, baseURL, err)
 		}
-		resultChan <- result.Result
+		d.finishRequest(ctx, query, req, resp, start, err)
+		d.capabilities.track(query.Endpoint(), err)
+		if err == nil {
+			d.store(ctx, query, resp, body)
+		}
+		errChan <- err
 	}()
 
 	select {
-	case result := <-resultChan:
-		return result, nil
 	case err := <-errChan:
-		return Result{}, err
+		return err
 	case <-ctx.Done():
-		return Result{}, ctx.Err()
+		return ctx.Err()
 	}
 }
 
-func (d defaultClient) CheckStatus(ctx context.Context) (Status, error) {
-	statusChan := make(chan Status, 1)
-	errChan := make(chan error, 1)
-	endpoint := fmt.Sprintf("%s/%s?format=json", d.baseURL, endpointStatus)
+func (d defaultClient) Search(ctx context.Context, query SearchQuery, opts ...CallOption) (_ []Result, err error) {
+	ctx, cancel := d.startCall(ctx, opts)
+	defer cancel()
+	defer func() { err = d.wrapError(EndpointSearch, err) }()
+	if err := d.validateSearch(query); err != nil {
+		return nil, err
+	}
+	query.AcceptLanguage = d.mergeLanguages(query.AcceptLanguage)
+	if err := validateLanguages(query.AcceptLanguage); err != nil {
+		return nil, err
+	}
+	query, err = d.queryLengthLimit.apply(query)
+	if err != nil {
+		return nil, err
+	}
+	results, err := d.search(ctx, query)
+	if !errors.Is(err, ErrNoResults) || query.FreeFormQuery != "" || query.Street == "" {
+		return results, err
+	}
+	for _, houseNumber := range expandHouseNumber(query.HouseNumber) {
+		query.HouseNumber = houseNumber
+		results, retryErr := d.search(ctx, query)
+		if !errors.Is(retryErr, ErrNoResults) {
+			return results, retryErr
+		}
+	}
+	return nil, err
+}
 
-	go func() {
-		resp, err := d.client.Get(endpoint)
-		if err != nil {
-			errChan <- err
-			return
+// search performs a single search, without retrying house number ranges.
+func (d defaultClient) search(ctx context.Context, query SearchQuery) ([]Result, error) {
+	results, err := d.getResults(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	reportRawPage(ctx, results)
+	results = d.filterBounded(query, d.filterResults(ctx, results))
+	if len(results) == 0 {
+		return nil, ErrNoResults
+	}
+	return results, nil
+}
+
+// getResults retrieves a list of results for the given query, decoding them leniently if enabled.
+func (d defaultClient) getResults(ctx context.Context, query QueryEncoder) ([]Result, error) {
+	if !d.lenientDecoding {
+		results := make([]Result, 0)
+		if err := d.get(ctx, query, &results); err != nil {
+			return nil, err
 		}
-		defer func(Body io.ReadCloser) {
-			_ = Body.Close()
-		}(resp.Body)
-		status := &Status{}
-		if err = json.NewDecoder(resp.Body).Decode(status); err != nil {
-			errChan <- err
+		return results, nil
+	}
+	raw := make([]json.RawMessage, 0)
+	if err := d.get(ctx, query, &raw); err != nil {
+		return nil, err
+	}
+	results, decodeErrs := decodeResultsLeniently(raw, d.strictDecoding)
+	for _, decodeErr := range decodeErrs {
+		d.observers.decodeFailed(ctx, query, decodeErr)
+	}
+	if metadata := responseMetadata(ctx); metadata != nil {
+		metadata.DecodeErrors = append(metadata.DecodeErrors, decodeErrs...)
+	}
+	if len(results) == 0 && len(decodeErrs) > 0 {
+		return nil, decodeErrs[0]
+	}
+	return results, nil
+}
+
+func (d defaultClient) Reverse(ctx context.Context, query ReverseQuery, opts ...CallOption) (_ Result, err error) {
+	ctx, cancel := d.startCall(ctx, opts)
+	defer cancel()
+	defer func() { err = d.wrapError(EndpointReverse, err) }()
+	if d.validateQueries {
+		if err := query.Validate(); err != nil {
+			return Result{}, err
 		}
-		statusChan <- *status
-	}()
+	}
+	query.AcceptLanguage = d.mergeLanguages(query.AcceptLanguage)
+	if err := validateLanguages(query.AcceptLanguage); err != nil {
+		return Result{}, err
+	}
+	result := &Result{}
+	if err := d.get(ctx, query, result); err != nil {
+		return Result{}, err
+	}
+	return *result, nil
+}
 
-	select {
-	case result := <-statusChan:
-		return result, nil
-	case err := <-errChan:
+func (d defaultClient) Lookup(ctx context.Context, query LookupQuery, opts ...CallOption) (_ []Result, err error) {
+	ctx, cancel := d.startCall(ctx, opts)
+	defer cancel()
+	defer func() { err = d.wrapError(EndpointLookup, err) }()
+	if d.validateQueries {
+		if err := query.Validate(); err != nil {
+			return nil, err
+		}
+	}
+	query.AcceptLanguage = d.mergeLanguages(query.AcceptLanguage)
+	if err := validateLanguages(query.AcceptLanguage); err != nil {
+		return nil, err
+	}
+	results, err := d.getResults(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	return d.filterResults(ctx, results), nil
+}
+
+func (d defaultClient) CheckStatus(ctx context.Context, opts ...CallOption) (_ Status, err error) {
+	ctx, cancel := d.startCall(ctx, opts)
+	defer cancel()
+	defer func() { err = d.wrapError(EndpointStatus, err) }()
+	status := &Status{}
+	err = d.get(ctx, statusQuery{}, status)
+	var statusErr StatusError
+	if err != nil && !errors.As(err, &statusErr) {
 		return Status{}, err
-	case <-ctx.Done():
-		return Status{}, ctx.Err()
 	}
+	return *status, err
 }
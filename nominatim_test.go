@@ -202,3 +202,65 @@ func Test_Integration_CheckStatus(t *testing.T) {
 		})
 	}
 }
+
+func Test_Status_UnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    time.Time
+		wantRaw string
+		wantErr bool
+	}{
+		{
+			name:    "should parse RFC3339 timestamps",
+			data:    `{"status": 0, "data_updated": "2021-11-25T17:16:32+00:00"}`,
+			want:    time.Date(2021, 11, 25, 17, 16, 32, 0, time.UTC),
+			wantRaw: "2021-11-25T17:16:32+00:00",
+		},
+		{
+			name:    "should parse timestamps with a short offset",
+			data:    `{"status": 0, "data_updated": "2021-11-25 17:16:32+00"}`,
+			want:    time.Date(2021, 11, 25, 17, 16, 32, 0, time.UTC),
+			wantRaw: "2021-11-25 17:16:32+00",
+		},
+		{
+			name:    "should parse timestamps without offset",
+			data:    `{"status": 0, "data_updated": "2021-11-25 17:16:32"}`,
+			want:    time.Date(2021, 11, 25, 17, 16, 32, 0, time.UTC),
+			wantRaw: "2021-11-25 17:16:32",
+		},
+		{
+			name:    "should parse Unix timestamps",
+			data:    `{"status": 0, "data_updated": 1637860592}`,
+			want:    time.Date(2021, 11, 25, 17, 16, 32, 0, time.UTC),
+			wantRaw: "1637860592",
+		},
+		{
+			name: "should accept a missing timestamp",
+			data: `{"status": 0}`,
+		},
+		{
+			name:    "should fail due to unknown format",
+			data:    `{"status": 0, "data_updated": "yesterday"}`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got := nominatim.Status{}
+			err := json.Unmarshal([]byte(tt.data), &got)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("UnmarshalJSON() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !got.DataUpdated.Equal(tt.want) {
+				t.Errorf("UnmarshalJSON() got = %v, want %v", got.DataUpdated, tt.want)
+			}
+			if got.DataUpdatedRaw != tt.wantRaw {
+				t.Errorf("UnmarshalJSON() got raw = %v, want %v", got.DataUpdatedRaw, tt.wantRaw)
+			}
+		})
+	}
+}
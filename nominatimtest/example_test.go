@@ -0,0 +1,32 @@
+package nominatimtest_test
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/diegohordi/nominatim"
+	"github.com/diegohordi/nominatim/nominatimtest"
+)
+
+func ExampleNewServer() {
+	server := nominatimtest.NewServer(nominatim.Result{
+		PlaceId:     1,
+		Lat:         "40.4169",
+		Lon:         "-3.7035",
+		Name:        "Puerta del Sol",
+		DisplayName: "Puerta del Sol, Sol, Madrid, 28013, España",
+	})
+	defer server.Close()
+
+	client := nominatim.NewClient(server.URL, nil)
+	results, err := client.Search(context.Background(), *nominatim.NewSearchQuery(nominatim.WithFreeForm("sol madrid")))
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Println(results[0].Name)
+	fmt.Println("requests:", server.Requests())
+	// Output:
+	// Puerta del Sol
+	// requests: 1
+}
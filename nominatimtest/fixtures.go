@@ -0,0 +1,108 @@
+package nominatimtest
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Fixture is a response served by the Server instead of the one built from its places.
+type Fixture struct {
+
+	// StatusCode is the status code of the response, http.StatusOK if zero.
+	StatusCode int
+
+	// Header holds the headers of the response, as a Retry-After. The Content-Type defaults to JSON.
+	Header http.Header
+
+	// Body is the body of the response.
+	Body []byte
+
+	// Latency is the time the response is delayed by, on top of the latency of the Server.
+	Latency time.Duration
+}
+
+// ErrorFixture returns the Fixture of the error envelope sent by Nominatim with the given status code, as for the
+// requests rejected or the servers overloaded.
+func ErrorFixture(statusCode int) Fixture {
+	body, _ := json.Marshal(map[string]interface{}{
+		"error": map[string]interface{}{"code": statusCode, "message": http.StatusText(statusCode)},
+	})
+	return Fixture{StatusCode: statusCode, Body: body}
+}
+
+// SetFixture makes the Server serve the given Fixture on the given endpoint, as nominatim.EndpointSearch, instead of
+// the response built from its places.
+func (s *Server) SetFixture(endpoint string, fixture Fixture) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.fixtures[strings.Trim(endpoint, "/")] = fixture
+}
+
+// SetLatency delays every response of the Server by the given latency, unless the request is cancelled meanwhile.
+func (s *Server) SetLatency(latency time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.latency = latency
+}
+
+// FailNext makes the Server serve the given Fixture, as an ErrorFixture, to the next n requests, whatever their
+// endpoint, as to test the retries and the failovers.
+func (s *Server) FailNext(n int, fixture Fixture) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i := 0; i < n; i++ {
+		s.failures = append(s.failures, fixture)
+	}
+}
+
+// Reset removes the fixtures, the latency and the failures set on the Server.
+func (s *Server) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.fixtures, s.latency, s.failures = make(map[string]Fixture), 0, nil
+}
+
+// inject serves the failures and the fixtures set on the Server, if any, or the given handler otherwise, delaying them
+// by its latency.
+func (s *Server) inject(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		latency := s.latency
+		fixture, ok := s.fixtures[strings.Trim(r.URL.Path, "/")]
+		if len(s.failures) > 0 {
+			fixture, ok, s.failures = s.failures[0], true, s.failures[1:]
+		}
+		s.mu.Unlock()
+		if ok {
+			latency += fixture.Latency
+		}
+		if latency > 0 {
+			select {
+			case <-time.After(latency):
+			case <-r.Context().Done():
+				return
+			}
+		}
+		if !ok {
+			next.ServeHTTP(w, r)
+			return
+		}
+		fixture.write(w)
+	})
+}
+
+// write writes the Fixture as the response.
+func (f Fixture) write(w http.ResponseWriter) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	for key, values := range f.Header {
+		w.Header()[http.CanonicalHeaderKey(key)] = values
+	}
+	statusCode := f.StatusCode
+	if statusCode == 0 {
+		statusCode = http.StatusOK
+	}
+	w.WriteHeader(statusCode)
+	_, _ = w.Write(f.Body)
+}
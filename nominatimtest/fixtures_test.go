@@ -0,0 +1,104 @@
+package nominatimtest_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/diegohordi/nominatim"
+	"github.com/diegohordi/nominatim/nominatimtest"
+)
+
+func TestServer_Fixtures(t *testing.T) {
+	tests := []struct {
+		name         string
+		setup        func(server *nominatimtest.Server)
+		opts         []nominatim.Option
+		wantResults  int
+		wantErr      error
+		wantOutcome  nominatim.Outcome
+		wantRequests int
+	}{
+		{
+			name:         "should serve the places by default",
+			setup:        func(server *nominatimtest.Server) {},
+			wantResults:  1,
+			wantRequests: 1,
+		},
+		{
+			name: "should serve the fixtures of the endpoint",
+			setup: func(server *nominatimtest.Server) {
+				server.SetFixture(nominatim.EndpointSearch, nominatimtest.Fixture{Body: []byte(`[{"place_id": "invalid"}]`)})
+				server.SetFixture(nominatim.EndpointReverse, nominatimtest.ErrorFixture(http.StatusInternalServerError))
+			},
+			wantOutcome:  nominatim.OutcomeDataError,
+			wantRequests: 1,
+		},
+		{
+			name: "should fail the next requests",
+			setup: func(server *nominatimtest.Server) {
+				server.FailNext(2, nominatimtest.ErrorFixture(http.StatusServiceUnavailable))
+			},
+			opts:         []nominatim.Option{nominatim.WithRetry(nominatim.RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond})},
+			wantResults:  1,
+			wantRequests: 3,
+		},
+		{
+			name: "should delay the responses",
+			setup: func(server *nominatimtest.Server) {
+				server.SetLatency(time.Second)
+			},
+			opts:         []nominatim.Option{nominatim.WithTimeout(10 * time.Millisecond)},
+			wantErr:      context.DeadlineExceeded,
+			wantRequests: 1,
+		},
+		{
+			name: "should delay the responses of the fixtures",
+			setup: func(server *nominatimtest.Server) {
+				server.SetFixture(nominatim.EndpointSearch, nominatimtest.Fixture{Body: []byte(`[]`), Latency: time.Second})
+			},
+			opts:         []nominatim.Option{nominatim.WithTimeout(10 * time.Millisecond)},
+			wantErr:      context.DeadlineExceeded,
+			wantRequests: 1,
+		},
+		{
+			name: "should serve the places once reset",
+			setup: func(server *nominatimtest.Server) {
+				server.SetLatency(time.Second)
+				server.FailNext(1, nominatimtest.ErrorFixture(http.StatusServiceUnavailable))
+				server.SetFixture(nominatim.EndpointSearch, nominatimtest.Fixture{Body: []byte(`[]`)})
+				server.Reset()
+			},
+			wantResults:  1,
+			wantRequests: 1,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			server := nominatimtest.NewServer()
+			t.Cleanup(server.Close)
+			tt.setup(server)
+			client := nominatim.NewClient(server.URL, nil, tt.opts...)
+			got, err := client.Search(context.TODO(), *nominatim.NewSearchQuery(nominatim.WithFreeForm("torre de belém")))
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("Search() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantOutcome != "" && nominatim.ClassifyOutcome(err) != tt.wantOutcome {
+				t.Errorf("Search() outcome = %v, want %v", nominatim.ClassifyOutcome(err), tt.wantOutcome)
+			}
+			if tt.wantErr == nil && tt.wantOutcome == "" && err != nil {
+				t.Fatalf("Search() error = %v", err)
+			}
+			if len(got) != tt.wantResults {
+				t.Errorf("Search() got %d results, want %d", len(got), tt.wantResults)
+			}
+			if server.Requests() != tt.wantRequests {
+				t.Errorf("Search() requests = %v, want %v", server.Requests(), tt.wantRequests)
+			}
+		})
+	}
+}
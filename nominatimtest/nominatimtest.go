@@ -0,0 +1,287 @@
+// Package nominatimtest provides a fake Nominatim API server, serving the search, reverse, lookup and status endpoints
+// from a set of places held in memory, for tests and examples of the code built on top of the nominatim package.
+package nominatimtest
+
+import (
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/diegohordi/nominatim"
+)
+
+const (
+	defaultLimit = 10
+	maxLimit     = 50
+)
+
+// specialPhrasePattern matches the "[amenity] near place" special phrases.
+var specialPhrasePattern = regexp.MustCompile(`^\s*\[([^\]]+)\]\s*(?:near\s+)?(.*)$`)
+
+// licence is the licence sent along with the places.
+const licence = "Data © OpenStreetMap contributors, ODbL 1.0. https://osm.org/copyright"
+
+// DataUpdated is the time the data of the Server was last updated, as reported by its status endpoint.
+var DataUpdated = time.Date(2021, 11, 25, 17, 16, 32, 0, time.UTC)
+
+// Places returns the places served by default by the Server, in Lisbon and Porto.
+func Places() []nominatim.Result {
+	return []nominatim.Result{
+		place(1, "way", 683827991, "38.7429985", "-9.1467899", "highway", "primary", 0.4, "Avenida da República",
+			nominatim.Address{Suburb: "Avenidas Novas", City: "Lisboa", Postcode: "1000-078", Country: "Portugal", CountryCode: "pt"}),
+		place(2, "way", 8127497, "38.7075", "-9.1364", "place", "square", 0.55, "Praça do Comércio",
+			nominatim.Address{Suburb: "Baixa", City: "Lisboa", Postcode: "1100-148", Country: "Portugal", CountryCode: "pt"}),
+		place(3, "way", 24961587, "38.6916", "-9.2160", "tourism", "attraction", 0.6, "Torre de Belém",
+			nominatim.Address{Suburb: "Belém", City: "Lisboa", Postcode: "1400-038", Country: "Portugal", CountryCode: "pt"}),
+		place(4, "way", 4825402, "41.1486", "-8.6110", "highway", "primary", 0.45, "Avenida dos Aliados",
+			nominatim.Address{Suburb: "Santo Ildefonso", City: "Porto", Postcode: "4000-064", Country: "Portugal", CountryCode: "pt"}),
+		place(5, "node", 455680276, "38.7139", "-9.1394", "amenity", "pharmacy", 0.3, "Farmácia Estácio",
+			nominatim.Address{Suburb: "Baixa", City: "Lisboa", Postcode: "1100-200", Country: "Portugal", CountryCode: "pt"}),
+	}
+}
+
+// place creates a place with the given attributes, deriving its display name from its name and address.
+func place(id int, osmType string, osmID int, lat, lon, category, typ string, importance float64, name string, address nominatim.Address) nominatim.Result {
+	parts := []string{name}
+	for _, part := range []string{address.Suburb, address.City, address.Postcode, address.Country} {
+		if part != "" && part != name {
+			parts = append(parts, part)
+		}
+	}
+	return nominatim.Result{
+		PlaceId:     id,
+		Licence:     licence,
+		OsmType:     osmType,
+		OsmId:       osmID,
+		Lat:         lat,
+		Lon:         lon,
+		PlaceRank:   26,
+		Category:    category,
+		Type:        typ,
+		Importance:  importance,
+		DisplayName: strings.Join(parts, ", "),
+		Name:        name,
+		Address:     address,
+	}
+}
+
+// Server is a fake Nominatim API server. Searches match the places whose display name holds every word of the query,
+// or every structured field, and whose type or name matches the amenity, if any, given either as a structured field or
+// as a special phrase, as "[pharmacy] near Lisboa". Bounded searches only match the places within the viewbox, and the
+// searches restricted to country codes only the places in those countries. Reverse geocodes return the nearest place
+// and lookups return the places with the given OSM IDs.
+//
+// The responses can be replaced by Fixtures, as malformed bodies or errors, delayed and failed, so the behaviour of
+// the code built on top of the client can be tested on failures too.
+type Server struct {
+	*httptest.Server
+	places   []nominatim.Result
+	mu       sync.Mutex
+	requests int
+	fixtures map[string]Fixture
+	latency  time.Duration
+	failures []Fixture
+}
+
+// NewServer starts a Server serving the given places, or the default Places if none is given. It must be closed
+// when done.
+func NewServer(places ...nominatim.Result) *Server {
+	if len(places) == 0 {
+		places = Places()
+	}
+	s := &Server{places: places, fixtures: make(map[string]Fixture)}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/search", s.search)
+	mux.HandleFunc("/reverse", s.reverse)
+	mux.HandleFunc("/lookup", s.lookup)
+	mux.HandleFunc("/status", s.status)
+	s.Server = httptest.NewServer(s.count(s.inject(mux)))
+	return s
+}
+
+// Requests returns the number of requests received so far.
+func (s *Server) Requests() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.requests
+}
+
+// count counts the requests sent to the given handler.
+func (s *Server) count(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		s.requests++
+		s.mu.Unlock()
+		next.ServeHTTP(w, r)
+	})
+}
+
+// search serves the search endpoint.
+func (s *Server) search(w http.ResponseWriter, r *http.Request) {
+	params := r.URL.Query()
+	amenity := strings.ToLower(params.Get("amenity"))
+	terms := make([]string, 0)
+	if q := params.Get("q"); q != "" {
+		if matches := specialPhrasePattern.FindStringSubmatch(q); matches != nil {
+			amenity, q = strings.ToLower(matches[1]), matches[2]
+		}
+		terms = append(terms, strings.Fields(strings.ReplaceAll(q, ",", " "))...)
+	}
+	for _, key := range []string{"street", "city", "county", "state", "country", "postalcode"} {
+		if value := params.Get(key); value != "" {
+			terms = append(terms, value)
+		}
+	}
+	viewbox, bounded := parseViewbox(params.Get("viewbox")), params.Get("bounded") == "1"
+	if len(terms) == 0 && amenity == "" {
+		writeError(w, http.StatusBadRequest, "Nothing to search for.")
+		return
+	}
+	limit, err := strconv.Atoi(params.Get("limit"))
+	if err != nil || limit <= 0 {
+		limit = defaultLimit
+	}
+	if limit > maxLimit {
+		limit = maxLimit
+	}
+	countries := make(map[string]bool)
+	for _, code := range strings.Split(params.Get("countrycodes"), ",") {
+		if code = strings.ToLower(strings.TrimSpace(code)); code != "" {
+			countries[code] = true
+		}
+	}
+	excluded := make(map[string]bool)
+	for _, id := range strings.Split(params.Get("exclude_place_ids"), ",") {
+		excluded[strings.TrimSpace(id)] = true
+	}
+	results := make([]nominatim.Result, 0)
+	for _, place := range s.places {
+		if len(results) == limit {
+			break
+		}
+		if excluded[strconv.Itoa(place.PlaceId)] || !matches(place, terms) || (amenity != "" && !isAmenity(place, amenity)) {
+			continue
+		}
+		if len(countries) > 0 && !countries[strings.ToLower(place.Address.CountryCode)] {
+			continue
+		}
+		if bounded && viewbox != nil && !within(place, viewbox) {
+			continue
+		}
+		results = append(results, place)
+	}
+	writeJSON(w, http.StatusOK, results)
+}
+
+// matches checks if the display name of the given place holds every given term, ignoring their case.
+func matches(place nominatim.Result, terms []string) bool {
+	displayName := strings.ToLower(place.DisplayName)
+	for _, term := range terms {
+		if !strings.Contains(displayName, strings.ToLower(term)) {
+			return false
+		}
+	}
+	return true
+}
+
+// parseViewbox parses the given viewbox, as "x1,y1,x2,y2", or returns nil if it's invalid.
+func parseViewbox(viewbox string) []float64 {
+	values := strings.Split(viewbox, ",")
+	if len(values) != 4 {
+		return nil
+	}
+	corners := make([]float64, 0, len(values))
+	for _, value := range values {
+		corner, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil {
+			return nil
+		}
+		corners = append(corners, corner)
+	}
+	return corners
+}
+
+// within checks if the given place is within the given viewbox.
+func within(place nominatim.Result, viewbox []float64) bool {
+	lat, _ := strconv.ParseFloat(place.Lat, 64)
+	lon, _ := strconv.ParseFloat(place.Lon, 64)
+	return lon >= math.Min(viewbox[0], viewbox[2]) && lon <= math.Max(viewbox[0], viewbox[2]) &&
+		lat >= math.Min(viewbox[1], viewbox[3]) && lat <= math.Max(viewbox[1], viewbox[3])
+}
+
+// isAmenity checks if the given place is the given amenity, either by its type or by its name.
+func isAmenity(place nominatim.Result, amenity string) bool {
+	return strings.ToLower(place.Type) == amenity || strings.Contains(strings.ToLower(place.Name), amenity)
+}
+
+// reverse serves the reverse endpoint.
+func (s *Server) reverse(w http.ResponseWriter, r *http.Request) {
+	lat, latErr := strconv.ParseFloat(r.URL.Query().Get("lat"), 64)
+	lon, lonErr := strconv.ParseFloat(r.URL.Query().Get("lon"), 64)
+	if latErr != nil || lonErr != nil || math.Abs(lat) > 90 || math.Abs(lon) > 180 {
+		writeError(w, http.StatusBadRequest, "Floating-point number expected for parameter 'lat' and 'lon'")
+		return
+	}
+	nearest, nearestDistance := -1, math.Inf(1)
+	for i, place := range s.places {
+		placeLat, _ := strconv.ParseFloat(place.Lat, 64)
+		placeLon, _ := strconv.ParseFloat(place.Lon, 64)
+		if distance := math.Hypot(placeLat-lat, placeLon-lon); distance < nearestDistance {
+			nearest, nearestDistance = i, distance
+		}
+	}
+	if nearest < 0 {
+		writeJSON(w, http.StatusOK, map[string]string{"error": "Unable to geocode"})
+		return
+	}
+	writeJSON(w, http.StatusOK, s.places[nearest])
+}
+
+// lookup serves the lookup endpoint, returning the places with the given OSM IDs, in their order.
+func (s *Server) lookup(w http.ResponseWriter, r *http.Request) {
+	ids := strings.Split(r.URL.Query().Get("osm_ids"), ",")
+	if len(ids) > maxLimit {
+		writeError(w, http.StatusBadRequest, "Too many IDs.")
+		return
+	}
+	results := make([]nominatim.Result, 0)
+	for _, id := range ids {
+		for _, place := range s.places {
+			if place.OSMID() == strings.ToUpper(strings.TrimSpace(id)) {
+				results = append(results, place)
+			}
+		}
+	}
+	writeJSON(w, http.StatusOK, results)
+}
+
+// status serves the status endpoint.
+func (s *Server) status(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"status":           0,
+		"message":          "OK",
+		"data_updated":     DataUpdated.Format(time.RFC3339),
+		"software_version": "4.2.3",
+		"database_version": "4.2.3",
+	})
+}
+
+// writeError writes the error envelope sent by Nominatim with the given status code and message.
+func writeError(w http.ResponseWriter, statusCode int, message string) {
+	writeJSON(w, statusCode, map[string]interface{}{
+		"error": map[string]interface{}{"code": statusCode, "message": message},
+	})
+}
+
+// writeJSON writes the given value as JSON with the given status code.
+func writeJSON(w http.ResponseWriter, statusCode int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(statusCode)
+	_ = json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,192 @@
+package nominatimtest
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/diegohordi/nominatim"
+)
+
+// ErrNotRecorded is returned by a Recorder replaying only when the request sent has no recorded response.
+var ErrNotRecorded = errors.New("nominatimtest: no recorded response")
+
+// ignoredParams holds the query parameters left out of the requests recorded, as the email identifying the caller
+// and the API key, as sent through nominatim.WithAPIKey, which must not end up in the cassettes.
+var ignoredParams = []string{"email", "key"}
+
+// ignoredHeaders holds the response headers left out of the responses recorded.
+var ignoredHeaders = []string{"Date", "Set-Cookie"}
+
+// RecorderMode is the way a Recorder deals with the requests sent through it.
+type RecorderMode int
+
+const (
+	// ReplayOrRecord replays the recorded responses, recording the missing ones from the real server.
+	ReplayOrRecord RecorderMode = iota
+
+	// Replay replays the recorded responses only, failing the requests missing with ErrNotRecorded, as in CI.
+	Replay
+
+	// Record records every response from the real server again, replacing the ones recorded before.
+	Record
+)
+
+// interaction holds a request and the response recorded for it.
+type interaction struct {
+	Request string      `json:"request"`
+	Status  int         `json:"status"`
+	Header  http.Header `json:"header,omitempty"`
+	Body    string      `json:"body,omitempty"`
+	RawBody []byte      `json:"raw_body,omitempty"`
+}
+
+// Recorder is an http.RoundTripper recording the responses of a real Nominatim API to a cassette file on the first
+// run and replaying them afterwards, so the tests relying on them are reproducible offline and don't hit the usage
+// policy of the public API on every run. The requests are matched by their method, path and parameters, regardless
+// of their host and of their parameters order, leaving their email and API key out. The responses of the requests not
+// observed accordingly with the nominatim.Sampling of the client are passed through without being recorded.
+type Recorder struct {
+	path         string
+	mode         RecorderMode
+	next         http.RoundTripper
+	mu           sync.Mutex
+	interactions map[string]interaction
+}
+
+// NewRecorder creates a Recorder replaying the responses recorded in the cassette at the given path, if any,
+// accordingly with the given mode, and recording the missing ones through the given http.RoundTripper, or through
+// the http.DefaultTransport if nil.
+func NewRecorder(path string, mode RecorderMode, next http.RoundTripper) (*Recorder, error) {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	r := &Recorder{path: path, mode: mode, next: next, interactions: make(map[string]interaction)}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return r, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	interactions := make([]interaction, 0)
+	if err := json.Unmarshal(data, &interactions); err != nil {
+		return nil, fmt.Errorf("nominatimtest: invalid cassette %s: %w", path, err)
+	}
+	for _, recorded := range interactions {
+		r.interactions[recorded.Request] = recorded
+	}
+	return r, nil
+}
+
+// RoundTrip replays the response recorded for the given request, or records it from the real server, accordingly
+// with the mode of the Recorder.
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	key := requestKey(req)
+	r.mu.Lock()
+	recorded, ok := r.interactions[key]
+	r.mu.Unlock()
+	if ok && r.mode != Record {
+		return recorded.response(req), nil
+	}
+	if r.mode == Replay {
+		return nil, fmt.Errorf("%w for %s", ErrNotRecorded, key)
+	}
+	resp, err := r.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	if !nominatim.IsObserved(req.Context(), resp.StatusCode >= http.StatusBadRequest) {
+		return resp, nil
+	}
+	defer func(Body io.ReadCloser) {
+		_ = Body.Close()
+	}(resp.Body)
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	recorded = interaction{Request: key, Status: resp.StatusCode, Header: resp.Header.Clone()}
+	for _, header := range ignoredHeaders {
+		recorded.Header.Del(header)
+	}
+	if utf8.Valid(body) {
+		recorded.Body = string(body)
+	} else {
+		recorded.RawBody = body
+	}
+	r.mu.Lock()
+	r.interactions[key] = recorded
+	err = r.save()
+	r.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	return resp, nil
+}
+
+// save writes every interaction to the cassette, sorted by their requests, so the cassettes diff well.
+func (r *Recorder) save() error {
+	interactions := make([]interaction, 0, len(r.interactions))
+	for _, recorded := range r.interactions {
+		interactions = append(interactions, recorded)
+	}
+	sort.Slice(interactions, func(i, j int) bool {
+		return interactions[i].Request < interactions[j].Request
+	})
+	data, err := json.MarshalIndent(interactions, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(r.path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(r.path, append(data, '\n'), 0o644)
+}
+
+// requestKey returns the key matching the given request to its recorded response: its method, path and parameters,
+// in their canonical order.
+func requestKey(req *http.Request) string {
+	params := req.URL.Query()
+	for _, param := range ignoredParams {
+		params.Del(param)
+	}
+	key := req.Method + " /" + strings.TrimPrefix(req.URL.Path, "/")
+	if len(params) > 0 {
+		key += "?" + nominatim.EncodeQuery(params)
+	}
+	return key
+}
+
+// response builds the response recorded for the given request.
+func (i interaction) response(req *http.Request) *http.Response {
+	body := i.RawBody
+	if body == nil {
+		body = []byte(i.Body)
+	}
+	header := i.Header.Clone()
+	if header == nil {
+		header = http.Header{}
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", i.Status, http.StatusText(i.Status)),
+		StatusCode:    i.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
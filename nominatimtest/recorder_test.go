@@ -0,0 +1,137 @@
+package nominatimtest_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/diegohordi/nominatim"
+	"github.com/diegohordi/nominatim/nominatimtest"
+)
+
+func search(t *testing.T, baseURL string, recorder *nominatimtest.Recorder, opts ...nominatim.Option) ([]nominatim.Result, error) {
+	t.Helper()
+	client := nominatim.NewClient(baseURL, &http.Client{Transport: recorder}, opts...)
+	return client.Search(context.TODO(), *nominatim.NewSearchQuery(nominatim.WithFreeForm("torre de belém")))
+}
+
+func TestRecorder(t *testing.T) {
+	server := nominatimtest.NewServer()
+	t.Cleanup(server.Close)
+	cassette := filepath.Join(t.TempDir(), "cassettes", "search.json")
+	recorder, err := nominatimtest.NewRecorder(cassette, nominatimtest.ReplayOrRecord, nil)
+	if err != nil {
+		t.Fatalf("NewRecorder() error = %v", err)
+	}
+	recorded, err := search(t, server.URL, recorder)
+	if err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+	if _, err := search(t, server.URL, recorder); err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+	if server.Requests() != 1 {
+		t.Errorf("Search() requests = %v, want 1, the second one replayed", server.Requests())
+	}
+
+	tests := []struct {
+		name         string
+		mode         nominatimtest.RecorderMode
+		opts         []nominatim.Option
+		wantErr      error
+		wantRequests int
+	}{
+		{
+			name:         "should replay the recorded responses regardless of the host and the email",
+			mode:         nominatimtest.Replay,
+			opts:         []nominatim.Option{nominatim.WithEmail("someone@example.com")},
+			wantRequests: 1,
+		},
+		{
+			name:         "should record the responses again",
+			mode:         nominatimtest.Record,
+			wantRequests: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder, err := nominatimtest.NewRecorder(cassette, tt.mode, nil)
+			if err != nil {
+				t.Fatalf("NewRecorder() error = %v", err)
+			}
+			baseURL := "http://nominatim.invalid"
+			if tt.mode == nominatimtest.Record {
+				baseURL = server.URL
+			}
+			got, err := search(t, baseURL, recorder, tt.opts...)
+			if err != nil {
+				t.Fatalf("Search() error = %v", err)
+			}
+			if len(got) != len(recorded) || got[0].DisplayName != recorded[0].DisplayName {
+				t.Errorf("Search() got = %v, want %v", got, recorded)
+			}
+			if server.Requests() != tt.wantRequests {
+				t.Errorf("Search() requests = %v, want %v", server.Requests(), tt.wantRequests)
+			}
+		})
+	}
+}
+
+func TestRecorder_NotRecorded(t *testing.T) {
+	recorder, err := nominatimtest.NewRecorder(filepath.Join(t.TempDir(), "search.json"), nominatimtest.Replay, nil)
+	if err != nil {
+		t.Fatalf("NewRecorder() error = %v", err)
+	}
+	if _, err := search(t, "http://nominatim.invalid", recorder); !errors.Is(err, nominatimtest.ErrNotRecorded) {
+		t.Errorf("Search() error = %v, want %v", err, nominatimtest.ErrNotRecorded)
+	}
+}
+
+func TestRecorder_APIKey(t *testing.T) {
+	server := nominatimtest.NewServer()
+	t.Cleanup(server.Close)
+	cassette := filepath.Join(t.TempDir(), "search.json")
+	recorder, err := nominatimtest.NewRecorder(cassette, nominatimtest.ReplayOrRecord, nil)
+	if err != nil {
+		t.Fatalf("NewRecorder() error = %v", err)
+	}
+	if _, err := search(t, server.URL, recorder, nominatim.WithAPIKey("SECRETKEY")); err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+	if data, _ := os.ReadFile(cassette); len(data) == 0 || strings.Contains(string(data), "SECRETKEY") {
+		t.Errorf("Recorder cassette = %s, want it recorded without the API key", data)
+	}
+	recorder, err = nominatimtest.NewRecorder(cassette, nominatimtest.Replay, nil)
+	if err != nil {
+		t.Fatalf("NewRecorder() error = %v", err)
+	}
+	if _, err := search(t, "http://nominatim.invalid", recorder, nominatim.WithAPIKey("ANOTHERKEY")); err != nil {
+		t.Errorf("Search() error = %v, want the response replayed regardless of the API key", err)
+	}
+}
+
+func TestRecorder_Sampling(t *testing.T) {
+	server := nominatimtest.NewServer()
+	t.Cleanup(server.Close)
+	cassette := filepath.Join(t.TempDir(), "search.json")
+	recorder, err := nominatimtest.NewRecorder(cassette, nominatimtest.ReplayOrRecord, nil)
+	if err != nil {
+		t.Fatalf("NewRecorder() error = %v", err)
+	}
+	if _, err := search(t, server.URL, recorder, nominatim.WithSampling(nominatim.Sampling{Rate: 0})); err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+	if _, err := os.Stat(cassette); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Recorder cassette error = %v, want nothing recorded for the calls not sampled", err)
+	}
+	if _, err := search(t, server.URL, recorder, nominatim.WithSampling(nominatim.Sampling{Rate: 1})); err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+	if _, err := os.Stat(cassette); err != nil {
+		t.Errorf("Recorder cassette error = %v, want the sampled calls recorded", err)
+	}
+}
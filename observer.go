@@ -0,0 +1,74 @@
+package nominatim
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// observer observes the lifecycle of the requests sent by the client, as the logger of WithLogger.
+type observer interface {
+
+	// requestStarted is called right before the request for the given query is sent, and may mutate it. An error
+	// fails the request without sending it.
+	requestStarted(ctx context.Context, query QueryEncoder, req *http.Request) error
+
+	// requestFinished is called once the request for the given query is done, with its response, if any, and the
+	// error failing it.
+	requestFinished(ctx context.Context, query QueryEncoder, req *http.Request, resp *http.Response, duration time.Duration, err error)
+
+	// slowRequest is called once the request for the given query is done, when it took longer than the threshold of
+	// WithSlowRequestThreshold.
+	slowRequest(ctx context.Context, query QueryEncoder, req *http.Request, duration time.Duration)
+
+	// retrying is called right before waiting the given delay to retry the given query, failed with the given error.
+	retrying(ctx context.Context, query QueryEncoder, attempt int, delay time.Duration, err error)
+
+	// rateLimited is called once the given query waited for the rate limit of the client.
+	rateLimited(ctx context.Context, query QueryEncoder, waited time.Duration)
+
+	// decodeFailed is called when the response to the given query, or one of its results, can't be decoded.
+	decodeFailed(ctx context.Context, query QueryEncoder, err error)
+}
+
+// observers notifies every one of its observers, in their order.
+type observers []observer
+
+func (o observers) requestStarted(ctx context.Context, query QueryEncoder, req *http.Request) error {
+	for _, observer := range o {
+		if err := observer.requestStarted(ctx, query, req); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (o observers) requestFinished(ctx context.Context, query QueryEncoder, req *http.Request, resp *http.Response, duration time.Duration, err error) {
+	for _, observer := range o {
+		observer.requestFinished(ctx, query, req, resp, duration, err)
+	}
+}
+
+func (o observers) slowRequest(ctx context.Context, query QueryEncoder, req *http.Request, duration time.Duration) {
+	for _, observer := range o {
+		observer.slowRequest(ctx, query, req, duration)
+	}
+}
+
+func (o observers) retrying(ctx context.Context, query QueryEncoder, attempt int, delay time.Duration, err error) {
+	for _, observer := range o {
+		observer.retrying(ctx, query, attempt, delay, err)
+	}
+}
+
+func (o observers) rateLimited(ctx context.Context, query QueryEncoder, waited time.Duration) {
+	for _, observer := range o {
+		observer.rateLimited(ctx, query, waited)
+	}
+}
+
+func (o observers) decodeFailed(ctx context.Context, query QueryEncoder, err error) {
+	for _, observer := range o {
+		observer.decodeFailed(ctx, query, err)
+	}
+}
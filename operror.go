@@ -0,0 +1,112 @@
+package nominatim
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+)
+
+// Stage is the stage at which a call failed.
+type Stage string
+
+const (
+	// StageQuery means the query was rejected before being sent, as by WithQueryValidation.
+	StageQuery Stage = "query"
+
+	// StageTransport means the server couldn't be reached.
+	StageTransport Stage = "transport"
+
+	// StageResponse means the server responded with an error.
+	StageResponse Stage = "response"
+
+	// StageDecode means the response couldn't be decoded.
+	StageDecode Stage = "decode"
+)
+
+// OpError wraps the errors returned by the client with the operation failed, the host of the instance called and the
+// stage at which it failed, if known, so the errors logged are actionable. The wrapped error remains available
+// through errors.Is and errors.As, as the sentinel errors and the json or net errors.
+type OpError struct {
+
+	// Op is the operation failed, as the endpoint called, EndpointSearch.
+	Op string
+
+	// Host is the host of the instance called, the one of the mirror failing when failing over.
+	Host string
+
+	// Stage is the stage at which the call failed, empty when unknown, as when the context is done.
+	Stage Stage
+
+	// Err is the error failing the call.
+	Err error
+}
+
+func (e OpError) Error() string {
+	var b strings.Builder
+	b.WriteString("nominatim: " + e.Op)
+	if e.Host != "" {
+		b.WriteString(" " + e.Host)
+	}
+	b.WriteString(": ")
+	switch e.Stage {
+	case StageTransport:
+		b.WriteString("unable to reach the server: ")
+	case StageResponse:
+		b.WriteString("server responded with ")
+	case StageDecode:
+		b.WriteString("unable to decode the response: ")
+	}
+	b.WriteString(strings.TrimPrefix(e.Err.Error(), "nominatim: "))
+	return b.String()
+}
+
+// Unwrap returns the error failing the call.
+func (e OpError) Unwrap() error {
+	return e.Err
+}
+
+// stageError marks the errors failing a request with the stage at which it failed and the host it was sent to.
+type stageError struct {
+	stage Stage
+	host  string
+	err   error
+}
+
+func (e stageError) Error() string {
+	return e.err.Error()
+}
+
+// Unwrap returns the error failing the request.
+func (e stageError) Unwrap() error {
+	return e.err
+}
+
+// newStageError marks the given error, failing a request to the given base URL, with the given stage.
+func newStageError(stage Stage, baseURL string, err error) error {
+	return stageError{stage: stage, host: hostOf(baseURL), err: err}
+}
+
+// wrapError wraps the given error, failing the given operation, into an OpError.
+func (d defaultClient) wrapError(op string, err error) error {
+	if err == nil {
+		return nil
+	}
+	opErr := OpError{Op: op, Host: hostOf(d.baseURL), Err: err}
+	var stageErr stageError
+	switch {
+	case errors.As(err, &stageErr):
+		opErr.Host, opErr.Stage = stageErr.host, stageErr.stage
+	case errors.Is(err, ErrInvalidQuery):
+		opErr.Stage = StageQuery
+	}
+	return opErr
+}
+
+// hostOf returns the host of the given base URL.
+func hostOf(baseURL string) string {
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return ""
+	}
+	return u.Host
+}
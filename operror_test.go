@@ -0,0 +1,100 @@
+package nominatim_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/diegohordi/nominatim"
+	"net/http"
+	"net/url"
+	"testing"
+)
+
+func Test_OpError(t *testing.T) {
+	tests := []struct {
+		name      string
+		baseURL   string
+		transport func() (*http.Response, error)
+		opts      []nominatim.Option
+		wantOp    string
+		wantHost  string
+		wantStage nominatim.Stage
+		wantErr   error
+		wantAs    interface{}
+		wantText  string
+	}{
+		{
+			name: "should wrap the failures to reach the server",
+			transport: func() (*http.Response, error) {
+				return nil, &url.Error{Op: "Get", URL: "http://localhost:8080/status", Err: errors.New("connection refused")}
+			},
+			wantHost:  "localhost:8080",
+			wantStage: nominatim.StageTransport,
+			wantAs:    new(*url.Error),
+			wantText:  `nominatim: status localhost:8080: unable to reach the server: Get "http://localhost:8080/status": connection refused`,
+		},
+		{
+			name:      "should wrap the errors sent by the server",
+			transport: statusFixture(http.StatusInternalServerError, []byte(`{"error":{"code":500,"message":"Internal error"}}`)),
+			wantHost:  "localhost:8080",
+			wantStage: nominatim.StageResponse,
+			wantErr:   nominatim.ErrServerError,
+			wantText:  "nominatim: status localhost:8080: server responded with 500: Internal error",
+		},
+		{
+			name:      "should wrap the responses that can't be decoded",
+			transport: statusFixture(http.StatusOK, []byte(`{"status":`)),
+			wantHost:  "localhost:8080",
+			wantStage: nominatim.StageDecode,
+			wantAs:    new(*json.SyntaxError),
+		},
+		{
+			name:      "should tell the mirror failing",
+			transport: statusFixture(http.StatusInternalServerError, nil),
+			opts:      []nominatim.Option{nominatim.WithMirrors("http://mirror.example:8080")},
+			wantHost:  "mirror.example:8080",
+			wantStage: nominatim.StageResponse,
+			wantErr:   nominatim.ErrServerError,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			transport := TransportFunc(func(req *http.Request) (*http.Response, error) {
+				return tt.transport()
+			})
+			d := nominatim.NewClient("http://localhost:8080", nil, append(tt.opts, nominatim.WithTransport(transport))...)
+			_, err := d.CheckStatus(context.TODO())
+			var opErr nominatim.OpError
+			if !errors.As(err, &opErr) {
+				t.Fatalf("CheckStatus() error = %v, want an OpError", err)
+			}
+			if opErr.Op != nominatim.EndpointStatus || opErr.Host != tt.wantHost || opErr.Stage != tt.wantStage {
+				t.Errorf("CheckStatus() error = %+v, want %v %v %v", opErr, nominatim.EndpointStatus, tt.wantHost, tt.wantStage)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("CheckStatus() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantAs != nil && !errors.As(err, tt.wantAs) {
+				t.Errorf("CheckStatus() error = %v, want it as %T", err, tt.wantAs)
+			}
+			if tt.wantText != "" && err.Error() != tt.wantText {
+				t.Errorf("CheckStatus() error = %v, want %v", err, tt.wantText)
+			}
+		})
+	}
+}
+
+func Test_OpError_Query(t *testing.T) {
+	d := nominatim.NewClient("http://localhost:8080", nil, nominatim.WithQueryValidation())
+	_, err := d.Reverse(context.TODO(), *nominatim.NewReverseQuery("91", "0"))
+	var opErr nominatim.OpError
+	if !errors.As(err, &opErr) || opErr.Op != nominatim.EndpointReverse || opErr.Stage != nominatim.StageQuery {
+		t.Fatalf("Reverse() error = %+v, want an OpError of the query stage", err)
+	}
+	var validationErr nominatim.ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Errorf("Reverse() error = %v, want a ValidationError", err)
+	}
+}
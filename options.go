@@ -0,0 +1,29 @@
+package nominatim
+
+import (
+	"net/http"
+	"time"
+)
+
+// Option configures the Client created by NewClient.
+type Option func(*defaultClient)
+
+// WithHTTPClient makes the client send the requests through the given http.Client, or through the
+// http.DefaultClient, if nil.
+func WithHTTPClient(client *http.Client) Option {
+	return func(d *defaultClient) {
+		if client == nil {
+			client = http.DefaultClient
+		}
+		d.transport = client
+	}
+}
+
+// WithTimeout limits each request sent by the client, including reading its response, to the given duration,
+// regardless of the Transport. Each retry, mirror or hedged request gets its own timeout, while the context given to
+// the call still bounds all of them. Non-positive values mean no timeout.
+func WithTimeout(timeout time.Duration) Option {
+	return func(d *defaultClient) {
+		d.timeout = timeout
+	}
+}
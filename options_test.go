@@ -0,0 +1,70 @@
+package nominatim_test
+
+import (
+	"context"
+	"errors"
+	"github.com/diegohordi/nominatim"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func Test_NewClientWithOptions(t *testing.T) {
+	slowTransport := func(delay time.Duration) TransportFunc {
+		return func(req *http.Request) (*http.Response, error) {
+			select {
+			case <-time.After(delay):
+				return statusFixture(http.StatusOK, mustLoadValidStatus(t))()
+			case <-req.Context().Done():
+				return nil, req.Context().Err()
+			}
+		}
+	}
+	tests := []struct {
+		name          string
+		opts          func(userAgent *string) []nominatim.Option
+		wantUserAgent string
+		wantErr       error
+	}{
+		{
+			name: "should send the requests through the given http.Client",
+			opts: func(userAgent *string) []nominatim.Option {
+				client := &http.Client{Transport: RoundTripFunc(func(req *http.Request) *http.Response {
+					*userAgent = req.Header.Get("User-Agent")
+					resp, _ := statusFixture(http.StatusOK, mustLoadValidStatus(t))()
+					return resp
+				})}
+				return []nominatim.Option{nominatim.WithHTTPClient(client), nominatim.WithUserAgent("acme-geocoder/1.0")}
+			},
+			wantUserAgent: "acme-geocoder/1.0",
+		},
+		{
+			name: "should respond within the timeout",
+			opts: func(userAgent *string) []nominatim.Option {
+				return []nominatim.Option{nominatim.WithTransport(slowTransport(time.Millisecond)), nominatim.WithTimeout(time.Second)}
+			},
+		},
+		{
+			name: "should time out",
+			opts: func(userAgent *string) []nominatim.Option {
+				return []nominatim.Option{nominatim.WithTransport(slowTransport(time.Second)), nominatim.WithTimeout(10 * time.Millisecond)}
+			},
+			wantErr: context.DeadlineExceeded,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			var userAgent string
+			d := nominatim.NewClientWithOptions("http://localhost:8080", tt.opts(&userAgent)...)
+			_, err := d.CheckStatus(context.TODO())
+			if !errors.Is(err, tt.wantErr) || (tt.wantErr == nil && err != nil) {
+				t.Fatalf("CheckStatus() error = %v, want %v", err, tt.wantErr)
+			}
+			if userAgent != tt.wantUserAgent {
+				t.Errorf("CheckStatus() sent User-Agent = %q, want %q", userAgent, tt.wantUserAgent)
+			}
+		})
+	}
+}
@@ -0,0 +1,12 @@
+module github.com/diegohordi/nominatim/orbconv
+
+go 1.25
+
+require (
+	github.com/diegohordi/nominatim v0.0.0
+	github.com/paulmach/orb v0.11.1
+)
+
+require go.mongodb.org/mongo-driver v1.11.4 // indirect
+
+replace github.com/diegohordi/nominatim => ../
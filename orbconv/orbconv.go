@@ -0,0 +1,53 @@
+// Package orbconv converts the points, bounding boxes and geometries of the results from Nominatim API into the types
+// of github.com/paulmach/orb, for the downstream spatial processing, as clipping or simplifying the outlines of places.
+package orbconv
+
+import (
+	"fmt"
+
+	"github.com/diegohordi/nominatim"
+	"github.com/diegohordi/nominatim/geo"
+	"github.com/paulmach/orb"
+	"github.com/paulmach/orb/geojson"
+)
+
+// Point converts the given point into an orb.Point, as [longitude, latitude].
+func Point(p geo.Point) orb.Point {
+	return orb.Point{p.Lon, p.Lat}
+}
+
+// Bound converts the given bounding box into an orb.Bound.
+func Bound(box geo.BBox) orb.Bound {
+	return orb.Bound{Min: Point(box.Min()), Max: Point(box.Max())}
+}
+
+// ResultPoint converts the coordinates of the given result into an orb.Point.
+func ResultPoint(result nominatim.Result) (orb.Point, error) {
+	point, err := result.Point()
+	if err != nil {
+		return orb.Point{}, err
+	}
+	return Point(point), nil
+}
+
+// ResultBound converts the bounding box of the given result into an orb.Bound.
+func ResultBound(result nominatim.Result) (orb.Bound, error) {
+	box, err := result.BBox()
+	if err != nil {
+		return orb.Bound{}, err
+	}
+	return Bound(box), nil
+}
+
+// ResultGeometry converts the GeoJSON geometry of the given result, requested through PolygonGeoJSON, into an
+// orb.Geometry, as an orb.Polygon or an orb.MultiPolygon. It returns nominatim.ErrNoGeometry if the result has none.
+func ResultGeometry(result nominatim.Result) (orb.Geometry, error) {
+	if len(result.GeoJSON) == 0 || string(result.GeoJSON) == "null" {
+		return nil, nominatim.ErrNoGeometry
+	}
+	geometry, err := geojson.UnmarshalGeometry(result.GeoJSON)
+	if err != nil {
+		return nil, fmt.Errorf("orbconv: invalid geometry: %w", err)
+	}
+	return geometry.Geometry(), nil
+}
@@ -0,0 +1,70 @@
+package orbconv_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/diegohordi/nominatim"
+	"github.com/diegohordi/nominatim/orbconv"
+	"github.com/paulmach/orb"
+)
+
+var belem = nominatim.Result{
+	Lat:         "38.6916",
+	Lon:         "-9.2160",
+	BoundingBox: []string{"38.6914", "38.6918", "-9.2162", "-9.2158"},
+	GeoJSON:     []byte(`{"type":"Polygon","coordinates":[[[-9.2162,38.6914],[-9.2158,38.6914],[-9.2158,38.6918],[-9.2162,38.6914]]]}`),
+}
+
+func TestResultPoint(t *testing.T) {
+	got, err := orbconv.ResultPoint(belem)
+	if err != nil {
+		t.Fatalf("ResultPoint() error = %v", err)
+	}
+	if want := (orb.Point{-9.2160, 38.6916}); got != want {
+		t.Errorf("ResultPoint() got = %v, want %v", got, want)
+	}
+}
+
+func TestResultBound(t *testing.T) {
+	got, err := orbconv.ResultBound(belem)
+	if err != nil {
+		t.Fatalf("ResultBound() error = %v", err)
+	}
+	if want := (orb.Bound{Min: orb.Point{-9.2162, 38.6914}, Max: orb.Point{-9.2158, 38.6918}}); got != want {
+		t.Errorf("ResultBound() got = %v, want %v", got, want)
+	}
+}
+
+func TestResultGeometry(t *testing.T) {
+	tests := []struct {
+		name     string
+		geoJSON  string
+		wantType string
+		wantErr  error
+	}{
+		{name: "should convert polygons", geoJSON: string(belem.GeoJSON), wantType: "Polygon"},
+		{name: "should convert points", geoJSON: `{"type":"Point","coordinates":[-9.2160,38.6916]}`, wantType: "Point"},
+		{name: "should fail without geometry", wantErr: nominatim.ErrNoGeometry},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			result := nominatim.Result{}
+			if tt.geoJSON != "" {
+				result.GeoJSON = []byte(tt.geoJSON)
+			}
+			got, err := orbconv.ResultGeometry(result)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ResultGeometry() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && got.GeoJSONType() != tt.wantType {
+				t.Errorf("ResultGeometry() got = %v, want %v", got.GeoJSONType(), tt.wantType)
+			}
+		})
+	}
+	if _, err := orbconv.ResultGeometry(nominatim.Result{GeoJSON: []byte(`{"type":"Polygon","coordinates":"invalid"}`)}); err == nil {
+		t.Errorf("ResultGeometry() error = %v, wantErr true", err)
+	}
+}
@@ -0,0 +1,142 @@
+package nominatim
+
+import (
+	"context"
+	"errors"
+	"strconv"
+)
+
+// searchPager pages through the results of a query, excluding the places already returned from the next pages,
+// through exclude_place_ids, as Nominatim has no offset. The places filtered out client-side are excluded as well,
+// and the end of the results is decided on the page sent by the server, before filtering, when the client reports it.
+type searchPager struct {
+	client   SearchHandler
+	query    SearchQuery
+	opts     []CallOption
+	seen     map[int]bool
+	excluded map[int]bool
+	pages    int
+	done     bool
+}
+
+// newSearchPager creates a searchPager for the given query, paging by its limit, or by the default one.
+func newSearchPager(client SearchHandler, query SearchQuery, opts []CallOption) *searchPager {
+	if query.Limit <= 0 || query.Limit > maxLimit {
+		query.Limit = defaultLimit
+	}
+	query.ExcludedPlaces = append([]string(nil), query.ExcludedPlaces...)
+	return &searchPager{client: client, query: query, opts: opts, seen: make(map[int]bool), excluded: make(map[int]bool)}
+}
+
+// next returns the results of the next page, not returned by the previous ones, or nil when there is no page left,
+// skipping the pages whose results were all filtered out client-side. Only the first page fails with ErrNoResults,
+// as the next ones finding nothing just mean the results are exhausted, unless every result was filtered out.
+func (p *searchPager) next(ctx context.Context) ([]Result, error) {
+	for !p.done {
+		page, err := p.fetch(ctx)
+		if err != nil || len(page) > 0 {
+			return page, err
+		}
+		if p.done && len(p.seen) == 0 {
+			return nil, ErrNoResults
+		}
+	}
+	return nil, nil
+}
+
+// fetch searches the next page, returning its results not returned by the previous ones, possibly none when they
+// were all filtered out client-side.
+func (p *searchPager) fetch(ctx context.Context) ([]Result, error) {
+	raw := &rawPage{}
+	results, err := p.client.Search(withRawPage(ctx, raw), p.query, p.opts...)
+	p.pages++
+	if errors.Is(err, ErrNoResults) && len(raw.ids) > 0 {
+		err = nil
+	}
+	if err != nil {
+		p.done = true
+		if p.pages > 1 && errors.Is(err, ErrNoResults) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	ids := raw.ids
+	if !raw.reported {
+		ids = make([]int, 0, len(results))
+		for _, result := range results {
+			ids = append(ids, result.PlaceId)
+		}
+	}
+	excluded := 0
+	for _, id := range ids {
+		if p.excluded[id] {
+			continue
+		}
+		p.excluded[id] = true
+		p.query.ExcludedPlaces = append(p.query.ExcludedPlaces, strconv.Itoa(id))
+		excluded++
+	}
+	page := make([]Result, 0, len(results))
+	for _, result := range results {
+		if p.seen[result.PlaceId] {
+			continue
+		}
+		p.seen[result.PlaceId] = true
+		page = append(page, result)
+	}
+	if len(ids) < p.query.Limit || excluded == 0 {
+		p.done = true
+	}
+	return page, nil
+}
+
+// rawPageKey is the context key of the rawPage filled by the client.
+type rawPageKey struct{}
+
+// rawPage holds the place IDs of the results sent by the server for a search, before being filtered client-side.
+type rawPage struct {
+	ids      []int
+	reported bool
+}
+
+// withRawPage makes the client report the results sent by the server for the search into the given rawPage.
+func withRawPage(ctx context.Context, page *rawPage) context.Context {
+	return context.WithValue(ctx, rawPageKey{}, page)
+}
+
+// reportRawPage reports the given results sent by the server into the rawPage of the context, if any.
+func reportRawPage(ctx context.Context, results []Result) {
+	page, _ := ctx.Value(rawPageKey{}).(*rawPage)
+	if page == nil {
+		return
+	}
+	page.reported = true
+	page.ids = page.ids[:0]
+	for _, result := range results {
+		page.ids = append(page.ids, result.PlaceId)
+	}
+}
+
+// SearchAll searches the given query through the given client, paging through its results, a page of the maximum
+// limit of 50 at a time, excluding the places already found, through exclude_place_ids, until the given maximum
+// number of results is found or the results are exhausted. The results are merged in their order, without
+// duplicates. A non-positive maximum means every result. ErrNoResults is returned if nothing matched the query.
+func SearchAll(ctx context.Context, client SearchHandler, query SearchQuery, max int, opts ...CallOption) ([]Result, error) {
+	query.Limit = maxLimit
+	pager := newSearchPager(client, query, opts)
+	results := make([]Result, 0)
+	for max <= 0 || len(results) < max {
+		page, err := pager.next(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if len(page) == 0 {
+			break
+		}
+		results = append(results, page...)
+	}
+	if max > 0 && len(results) > max {
+		results = results[:max]
+	}
+	return results, nil
+}
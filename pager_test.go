@@ -0,0 +1,97 @@
+package nominatim_test
+
+import (
+	"context"
+	"errors"
+	"github.com/diegohordi/nominatim"
+	"github.com/diegohordi/nominatim/nominatimtest"
+	"testing"
+)
+
+func Test_SearchAll(t *testing.T) {
+	places := make([]nominatim.Result, 0, 120)
+	for i := 1; i <= 120; i++ {
+		places = append(places, nominatim.Result{PlaceId: i, Lat: "38.7", Lon: "-9.1", DisplayName: "Farmácia, Lisboa"})
+	}
+	tests := []struct {
+		name         string
+		q            string
+		max          int
+		wantResults  int
+		wantRequests int
+		wantErr      error
+	}{
+		{name: "should merge the pages up to the maximum", q: "farmácia", max: 75, wantResults: 75, wantRequests: 2},
+		{name: "should stop when the results are exhausted", q: "farmácia", max: 500, wantResults: 120, wantRequests: 3},
+		{name: "should return every result without maximum", q: "farmácia", wantResults: 120, wantRequests: 3},
+		{name: "should search a single page when enough", q: "farmácia", max: 50, wantResults: 50, wantRequests: 1},
+		{name: "should fail when nothing matches", q: "hospital", max: 100, wantRequests: 1, wantErr: nominatim.ErrNoResults},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			server := nominatimtest.NewServer(places...)
+			defer server.Close()
+			d := nominatim.NewClient(server.URL, nil)
+			results, err := nominatim.SearchAll(context.TODO(), d, *nominatim.NewSearchQuery(nominatim.WithFreeForm(tt.q)), tt.max)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("SearchAll() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			seen := make(map[int]bool)
+			for _, result := range results {
+				if seen[result.PlaceId] {
+					t.Errorf("SearchAll() returned %v twice", result.PlaceId)
+				}
+				seen[result.PlaceId] = true
+			}
+			if len(results) != tt.wantResults {
+				t.Errorf("SearchAll() results = %v, want %v", len(results), tt.wantResults)
+			}
+			if requests := server.Requests(); requests != tt.wantRequests {
+				t.Errorf("SearchAll() requests = %v, want %v", requests, tt.wantRequests)
+			}
+		})
+	}
+}
+
+func Test_SearchAll_filtered(t *testing.T) {
+	places := make([]nominatim.Result, 0, 120)
+	for i := 1; i <= 120; i++ {
+		places = append(places, nominatim.Result{PlaceId: i, Lat: "38.7", Lon: "-9.1", DisplayName: "Farmácia, Lisboa", Type: "pharmacy"})
+	}
+	for i := 0; i < 50; i++ {
+		places[i].Type = "hospital"
+	}
+	tests := []struct {
+		name         string
+		types        []string
+		wantResults  int
+		wantRequests int
+		wantErr      error
+	}{
+		{name: "should keep paging past the pages filtered out", types: []string{"pharmacy"}, wantResults: 70, wantRequests: 3},
+		{name: "should keep paging past the pages partially filtered", types: []string{"hospital"}, wantResults: 50, wantRequests: 3},
+		{name: "should fail when every result is filtered out", types: []string{"clinic"}, wantRequests: 3, wantErr: nominatim.ErrNoResults},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			server := nominatimtest.NewServer(places...)
+			defer server.Close()
+			d := nominatim.NewClient(server.URL, nil, nominatim.WithDefaultResultFilter(nominatim.ResultFilter{Types: tt.types}))
+			results, err := nominatim.SearchAll(context.TODO(), d, *nominatim.NewSearchQuery(nominatim.WithFreeForm("farmácia")), 0)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("SearchAll() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(results) != tt.wantResults {
+				t.Errorf("SearchAll() results = %v, want %v", len(results), tt.wantResults)
+			}
+			if requests := server.Requests(); requests != tt.wantRequests {
+				t.Errorf("SearchAll() requests = %v, want %v", requests, tt.wantRequests)
+			}
+		})
+	}
+}
@@ -0,0 +1,88 @@
+package nominatim
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/diegohordi/nominatim/geo"
+)
+
+// NewReverseQueryFromPoint creates a ReverseQuery with default values for the given point, formatted as in
+// NewReverseQueryFromFloats.
+func NewReverseQueryFromPoint(point geo.Point) *ReverseQuery {
+	return NewReverseQueryFromFloats(point.Lat, point.Lon)
+}
+
+// Point parses the coordinates of the ReverseQuery.
+func (q ReverseQuery) Point() (geo.Point, error) {
+	return parsePoint(q.Latitude, q.Longitude)
+}
+
+// Point parses the coordinates of the Result.
+func (r Result) Point() (geo.Point, error) {
+	return parsePoint(r.Lat, r.Lon)
+}
+
+// BBox parses the bounding box of the Result, sent by Nominatim as [min latitude, max latitude, min longitude,
+// max longitude].
+func (r Result) BBox() (geo.BBox, error) {
+	if len(r.BoundingBox) != 4 {
+		return geo.BBox{}, fmt.Errorf("nominatim: invalid bounding box %q", r.BoundingBox)
+	}
+	values := make([]float64, 0, len(r.BoundingBox))
+	for _, value := range r.BoundingBox {
+		coordinate, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil {
+			return geo.BBox{}, fmt.Errorf("nominatim: invalid bounding box %q: %w", r.BoundingBox, err)
+		}
+		values = append(values, coordinate)
+	}
+	return geo.BBox{MinLat: values[0], MaxLat: values[1], MinLon: values[2], MaxLon: values[3]}, nil
+}
+
+// FormatViewbox formats the given bounding box as a viewbox, as "min longitude,min latitude,max longitude,max
+// latitude", with its coordinates formatted as in FormatCoordinate.
+func FormatViewbox(box geo.BBox) string {
+	return strings.Join([]string{
+		FormatCoordinate(box.MinLon),
+		FormatCoordinate(box.MinLat),
+		FormatCoordinate(box.MaxLon),
+		FormatCoordinate(box.MaxLat),
+	}, ",")
+}
+
+// ParseViewbox parses the given viewbox, as "x1,y1,x2,y2", its corners given in any order.
+func ParseViewbox(viewbox string) (geo.BBox, error) {
+	values := strings.Split(viewbox, ",")
+	if len(values) != 4 {
+		return geo.BBox{}, fmt.Errorf("nominatim: invalid viewbox %q", viewbox)
+	}
+	a, err := parsePoint(values[1], values[0])
+	if err != nil {
+		return geo.BBox{}, fmt.Errorf("nominatim: invalid viewbox %q: %w", viewbox, err)
+	}
+	b, err := parsePoint(values[3], values[2])
+	if err != nil {
+		return geo.BBox{}, fmt.Errorf("nominatim: invalid viewbox %q: %w", viewbox, err)
+	}
+	return geo.NewBBox(a, b), nil
+}
+
+// WithViewboxBBox makes the query prefer, or only return, if bounded, the results within the given bounding box.
+func WithViewboxBBox(box geo.BBox, bounded bool) SearchOption {
+	return WithViewbox(FormatViewbox(box), bounded)
+}
+
+// parsePoint parses the given latitude and longitude.
+func parsePoint(latitude, longitude string) (geo.Point, error) {
+	lat, err := strconv.ParseFloat(strings.TrimSpace(latitude), 64)
+	if err != nil {
+		return geo.Point{}, fmt.Errorf("nominatim: invalid latitude %q: %w", latitude, err)
+	}
+	lon, err := strconv.ParseFloat(strings.TrimSpace(longitude), 64)
+	if err != nil {
+		return geo.Point{}, fmt.Errorf("nominatim: invalid longitude %q: %w", longitude, err)
+	}
+	return geo.Point{Lat: lat, Lon: lon}, nil
+}
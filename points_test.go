@@ -0,0 +1,139 @@
+package nominatim_test
+
+import (
+	"github.com/diegohordi/nominatim"
+	"github.com/diegohordi/nominatim/geo"
+	"testing"
+)
+
+func TestResult_Point(t *testing.T) {
+	tests := []struct {
+		name    string
+		result  nominatim.Result
+		want    geo.Point
+		wantErr bool
+	}{
+		{name: "should parse the coordinates", result: nominatim.Result{Lat: "38.6945252", Lon: "-9.3221278"}, want: geo.Point{Lat: 38.6945252, Lon: -9.3221278}},
+		{name: "should fail due to invalid latitude", result: nominatim.Result{Lat: "test", Lon: "-9.3221278"}, wantErr: true},
+		{name: "should fail due to missing longitude", result: nominatim.Result{Lat: "38.6945252"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := tt.result.Point()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Point() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("Point() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResult_BBox(t *testing.T) {
+	tests := []struct {
+		name    string
+		result  func() nominatim.Result
+		want    geo.BBox
+		wantErr bool
+	}{
+		{
+			name: "should parse the bounding box sent by the server",
+			result: func() nominatim.Result {
+				return mustLoadValidSearchResultsAsSlice(t)[0]
+			},
+			want: geo.BBox{MinLat: 38.6939653, MaxLat: 38.6950274, MinLon: -9.3257181, MaxLon: -9.3189774},
+		},
+		{
+			name: "should fail due to missing bounding box",
+			result: func() nominatim.Result {
+				return nominatim.Result{}
+			},
+			wantErr: true,
+		},
+		{
+			name: "should fail due to invalid coordinate",
+			result: func() nominatim.Result {
+				return nominatim.Result{BoundingBox: []string{"38.69", "38.70", "west", "-9.31"}}
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := tt.result().BBox()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("BBox() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("BBox() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_WithViewboxBBox(t *testing.T) {
+	box := geo.NewBBox(geo.Point{Lat: 38.8, Lon: -9.09}, geo.Point{Lat: 38.69, Lon: -9.23})
+	query := nominatim.NewSearchQuery(nominatim.WithViewboxBBox(box, true))
+	if want := "-9.23,38.69,-9.09,38.8"; query.Viewbox != want || !query.Bounded {
+		t.Errorf("WithViewboxBBox() got = %v, %v, want %v, true", query.Viewbox, query.Bounded, want)
+	}
+}
+
+func Test_NewReverseQueryFromPoint(t *testing.T) {
+	point := geo.Point{Lat: 38.6945252, Lon: -9.3221278}
+	got, err := nominatim.NewReverseQueryFromPoint(point).Point()
+	if err != nil || got != point {
+		t.Errorf("Point() got = %v, %v, want %v", got, err, point)
+	}
+}
+
+func Test_ParseViewbox(t *testing.T) {
+	tests := []struct {
+		name    string
+		viewbox string
+		want    geo.BBox
+		wantErr bool
+	}{
+		{
+			name:    "should parse the viewbox",
+			viewbox: "-9.23,38.69,-9.09,38.8",
+			want:    geo.BBox{MinLat: 38.69, MinLon: -9.23, MaxLat: 38.8, MaxLon: -9.09},
+		},
+		{
+			name:    "should parse the corners in any order",
+			viewbox: "-9.09, 38.8, -9.23, 38.69",
+			want:    geo.BBox{MinLat: 38.69, MinLon: -9.23, MaxLat: 38.8, MaxLon: -9.09},
+		},
+		{
+			name:    "should fail due to missing coordinates",
+			viewbox: "-9.23,38.69,-9.09",
+			wantErr: true,
+		},
+		{
+			name:    "should fail due to invalid coordinate",
+			viewbox: "-9.23,38.69,east,38.8",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := nominatim.ParseViewbox(tt.viewbox)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseViewbox() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("ParseViewbox() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
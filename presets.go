@@ -0,0 +1,74 @@
+package nominatim
+
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
+// metersPerDegree is the approximate length of a latitude degree.
+const metersPerDegree = 111320.0
+
+// NewDepotQuery creates a SearchQuery to lookup a logistics depot by its address. Only addresses are considered and
+// the single best ranked result is returned, with its address details, as needed to route deliveries to it.
+func NewDepotQuery(address string) *SearchQuery {
+	query := NewSearchQuery()
+	query.FreeFormQuery = address
+	query.Layers = []string{LayerAddress}
+	query.Limit = 1
+	return query
+}
+
+// NewStoreLocatorQuery creates a SearchQuery to locate the stores of the given brand in the given area, as a city or
+// a postal code. Only points of interest are considered and the maximum number of results allowed is returned.
+func NewStoreLocatorQuery(brand, area string) *SearchQuery {
+	query := NewSearchQuery()
+	query.FreeFormQuery = strings.TrimSpace(strings.Join([]string{brand, area}, " "))
+	query.Layers = []string{LayerPOI}
+	query.Limit = 50
+	return query
+}
+
+// NewNearestHospitalQuery creates a SearchQuery to find the hospitals within the given radius, in meters, from the
+// given coordinates. Only points of interest inside the bounding box of the radius are considered, and the results
+// keep Nominatim ranking, so the caller may sort them by distance.
+func NewNearestHospitalQuery(latitude, longitude, radius float64) *SearchQuery {
+	query := NewSearchQuery()
+	query.FreeFormQuery = "hospital"
+	query.Layers = []string{LayerPOI}
+	query.Viewbox = viewboxAround(latitude, longitude, radius)
+	query.Bounded = true
+	query.Limit = 10
+	return query
+}
+
+// viewboxAround builds a viewbox holding the circle with the given radius, in meters, around the given coordinates.
+func viewboxAround(latitude, longitude, radius float64) string {
+	deltaLat := radius / metersPerDegree
+	deltaLon := 180.0
+	if cos := math.Cos(latitude * math.Pi / 180); cos > 0 {
+		deltaLon = math.Min(radius/(metersPerDegree*cos), 180)
+	}
+	corners := []float64{
+		math.Max(longitude-deltaLon, -180),
+		math.Max(latitude-deltaLat, -90),
+		math.Min(longitude+deltaLon, 180),
+		math.Min(latitude+deltaLat, 90),
+	}
+	values := make([]string, len(corners))
+	for i, corner := range corners {
+		values[i] = strconv.FormatFloat(corner, 'f', CoordinatePrecision, 64)
+	}
+	return strings.Join(values, ",")
+}
+
+// NewAutocompleteQuery creates a SearchQuery to autocomplete the given text, biased towards the given user location.
+// Only a few results are returned, with no address details, to keep the responses small.
+func NewAutocompleteQuery(text string, bias LocationBias) *SearchQuery {
+	query := NewSearchQuery()
+	query.FreeFormQuery = text
+	query.Bias = &bias
+	query.Limit = 5
+	query.AddressDetails = false
+	return query
+}
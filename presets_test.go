@@ -0,0 +1,81 @@
+package nominatim_test
+
+import (
+	"context"
+	"github.com/diegohordi/nominatim"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func Test_Presets(t *testing.T) {
+	tests := []struct {
+		name  string
+		query *nominatim.SearchQuery
+		want  url.Values
+	}{
+		{
+			name:  "should lookup a logistics depot",
+			query: nominatim.NewDepotQuery("Rua do Centro Empresarial 1, 2710-444 Sintra"),
+			want: url.Values{
+				"q":              {"Rua do Centro Empresarial 1, 2710-444 Sintra"},
+				"layer":          {"address"},
+				"limit":          {"1"},
+				"addressdetails": {"1"},
+			},
+		},
+		{
+			name:  "should locate stores",
+			query: nominatim.NewStoreLocatorQuery("Pingo Doce", "Lisboa"),
+			want: url.Values{
+				"q":     {"Pingo Doce Lisboa"},
+				"layer": {"poi"},
+				"limit": {"50"},
+			},
+		},
+		{
+			name:  "should find the nearest hospitals",
+			query: nominatim.NewNearestHospitalQuery(0, 0, 1113.2),
+			want: url.Values{
+				"q":       {"hospital"},
+				"layer":   {"poi"},
+				"viewbox": {"-0.0100000,-0.0100000,0.0100000,0.0100000"},
+				"bounded": {"1"},
+				"limit":   {"10"},
+			},
+		},
+		{
+			name:  "should autocomplete with no address details",
+			query: nominatim.NewAutocompleteQuery("farmácia", nominatim.LocationBias{}),
+			want: url.Values{
+				"q":              {"farmácia"},
+				"limit":          {"5"},
+				"addressdetails": {"0"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			var got url.Values
+			d := nominatim.NewClient("http://localhost:8080", &http.Client{
+				Transport: RoundTripFunc(func(req *http.Request) *http.Response {
+					got = req.URL.Query()
+					resp := httptest.NewRecorder()
+					resp.Body.Write(mustLoadValidSearchResults(t))
+					return resp.Result()
+				}),
+			})
+			if _, err := d.Search(context.TODO(), *tt.query); err != nil {
+				t.Fatal(err)
+			}
+			for key, want := range tt.want {
+				if got.Get(key) != want[0] {
+					t.Errorf("Search() %s = %v, want %v", key, got.Get(key), want[0])
+				}
+			}
+		})
+	}
+}
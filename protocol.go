@@ -0,0 +1,135 @@
+package nominatim
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// QueryEncoder encodes a query into the endpoint and parameters of a Nominatim API request.
+type QueryEncoder interface {
+
+	// Endpoint returns the endpoint the query is sent to, as "search".
+	Endpoint() string
+
+	// Encode encodes the query parameters.
+	Encode() url.Values
+}
+
+// Transport sends the requests to Nominatim API. It is satisfied by *http.Client.
+type Transport interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// Decoder decodes the response body into v, returning the error sent by the server instead, if any.
+type Decoder interface {
+	Decode(resp *http.Response, body []byte, v interface{}) error
+}
+
+// DecoderFunc is an adapter to allow the use of ordinary functions as Decoder.
+type DecoderFunc func(resp *http.Response, body []byte, v interface{}) error
+
+// Decode calls f(resp, body, v).
+func (f DecoderFunc) Decode(resp *http.Response, body []byte, v interface{}) error {
+	return f(resp, body, v)
+}
+
+// CachePolicy decides whether and for how long a response may be cached.
+type CachePolicy interface {
+
+	// CacheKey returns the key under which the response to the given request may be cached, or an empty string if
+	// it must not be cached.
+	CacheKey(req *http.Request) string
+
+	// TTL returns for how long the given response may be cached, or zero if it must not be cached.
+	TTL(resp *http.Response) time.Duration
+}
+
+// DefaultCachePolicy caches the successful responses to GET requests, keyed by their URL, for a fixed TTL.
+type DefaultCachePolicy struct {
+	MaxAge time.Duration
+}
+
+// CacheKey returns the URL of GET requests.
+func (p DefaultCachePolicy) CacheKey(req *http.Request) string {
+	if req.Method != http.MethodGet {
+		return ""
+	}
+	return req.URL.String()
+}
+
+// TTL returns MaxAge for successful responses.
+func (p DefaultCachePolicy) TTL(resp *http.Response) time.Duration {
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return 0
+	}
+	return p.MaxAge
+}
+
+// WithTransport makes the client send the requests through the given Transport, instead of the http.Client given
+// to NewClient.
+func WithTransport(transport Transport) Option {
+	return func(d *defaultClient) {
+		d.transport = transport
+	}
+}
+
+// WithDecoder makes the client decode the responses through the given Decoder, instead of DecodeResponse.
+func WithDecoder(decoder Decoder) Option {
+	return func(d *defaultClient) {
+		d.decoder = decoder
+	}
+}
+
+// NewRequest creates the request for the given query, to the Nominatim API serving at the given base URL, with its
+// parameters encoded in their canonical order, through EncodeQuery.
+func NewRequest(ctx context.Context, baseURL string, query QueryEncoder) (*http.Request, error) {
+	endpoint := fmt.Sprintf("%s/%s?%s", strings.TrimSuffix(baseURL, "/"), query.Endpoint(), EncodeQuery(query.Encode()))
+	return http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+}
+
+// DecodeResponse decodes the given response body into v, returning the error sent by the server instead, if any,
+// either through the status code or through the error envelope. The responses of the status endpoint are decoded
+// either from their JSON or text format, returning a StatusError when the server reports a failure.
+func DecodeResponse(resp *http.Response, body []byte, v interface{}) error {
+	return decodeResponse(resp, body, v, false)
+}
+
+// DecodeResponseStrict decodes the given response body into v as DecodeResponse does, failing when the body holds
+// fields v doesn't know about.
+func DecodeResponseStrict(resp *http.Response, body []byte, v interface{}) error {
+	return decodeResponse(resp, body, v, true)
+}
+
+// decodeResponse decodes the given response body into v, strictly if asked to.
+func decodeResponse(resp *http.Response, body []byte, v interface{}, strict bool) error {
+	envelope := &errorEnvelope{}
+	hasEnvelope := json.Unmarshal(body, envelope) == nil && envelope.Error != nil
+	if status, ok := v.(*Status); ok && !hasEnvelope {
+		return decodeStatus(resp, body, status, strict)
+	}
+	if resp.StatusCode >= http.StatusBadRequest {
+		return newResponseError(resp, body)
+	}
+	if hasEnvelope {
+		return *envelope.Error
+	}
+	return unmarshal(body, v, strict)
+}
+
+// statusQuery is the query sent to the status endpoint.
+type statusQuery struct{}
+
+// Endpoint returns the status endpoint.
+func (q statusQuery) Endpoint() string {
+	return EndpointStatus
+}
+
+// Encode encodes the status query parameters.
+func (q statusQuery) Encode() url.Values {
+	return url.Values{keyFormat: {"json"}}
+}
@@ -0,0 +1,134 @@
+package nominatim_test
+
+import (
+	"context"
+	"errors"
+	"github.com/diegohordi/nominatim"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// TransportFunc is an adapter to allow the use of ordinary functions as nominatim.Transport.
+type TransportFunc func(req *http.Request) (*http.Response, error)
+
+func (f TransportFunc) Do(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func Test_NewRequest(t *testing.T) {
+	tests := []struct {
+		name    string
+		baseURL string
+		query   nominatim.QueryEncoder
+		want    string
+	}{
+		{
+			name:    "should build a search request",
+			baseURL: "http://localhost:8080",
+			query: func() nominatim.QueryEncoder {
+				query := nominatim.NewSearchQuery()
+				query.FreeFormQuery = "test"
+				return *query
+			}(),
+			want: "http://localhost:8080/search?format=jsonv2&q=test&limit=10&addressdetails=1&extratags=0&namedetails=0",
+		},
+		{
+			name:    "should build a reverse request from a base URL with a trailing slash",
+			baseURL: "http://localhost:8080/",
+			query:   *nominatim.NewReverseQuery("38.6945252", "-9.3221278"),
+			want:    "http://localhost:8080/reverse?format=jsonv2&lat=38.6945252&lon=-9.3221278&addressdetails=1&extratags=0&namedetails=0",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := nominatim.NewRequest(context.TODO(), tt.baseURL, tt.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.URL.String() != tt.want {
+				t.Errorf("NewRequest() got = %v, want %v", got.URL.String(), tt.want)
+			}
+		})
+	}
+}
+
+func Test_DecodeResponse(t *testing.T) {
+	tests := []struct {
+		name       string
+		statusCode int
+		body       string
+		wantErr    error
+	}{
+		{
+			name:       "should decode a valid body",
+			statusCode: http.StatusOK,
+			body:       `{"status": 0, "message": "OK"}`,
+		},
+		{
+			name:       "should return the error from the envelope",
+			statusCode: http.StatusOK,
+			body:       `{"error": "Unable to geocode"}`,
+			wantErr:    nominatim.ErrUnableToGeocode,
+		},
+		{
+			name:       "should return the error from the status code",
+			statusCode: http.StatusBadRequest,
+			body:       `{"error": {"code": 400, "message": "Need coordinates or OSM object to lookup."}}`,
+			wantErr:    nominatim.ErrInvalidQuery,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			status := nominatim.Status{}
+			err := nominatim.DecodeResponse(&http.Response{StatusCode: tt.statusCode}, []byte(tt.body), &status)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("DecodeResponse() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_DefaultCachePolicy(t *testing.T) {
+	policy := nominatim.DefaultCachePolicy{MaxAge: time.Hour}
+	get := httptest.NewRequest(http.MethodGet, "http://localhost:8080/search?q=test", nil)
+	post := httptest.NewRequest(http.MethodPost, "http://localhost:8080/search?q=test", nil)
+	if got := policy.CacheKey(get); got != "http://localhost:8080/search?q=test" {
+		t.Errorf("CacheKey() got = %v, want the request URL", got)
+	}
+	if got := policy.CacheKey(post); got != "" {
+		t.Errorf("CacheKey() got = %v, want no key", got)
+	}
+	if got := policy.TTL(&http.Response{StatusCode: http.StatusOK}); got != time.Hour {
+		t.Errorf("TTL() got = %v, want %v", got, time.Hour)
+	}
+	if got := policy.TTL(&http.Response{StatusCode: http.StatusInternalServerError}); got != 0 {
+		t.Errorf("TTL() got = %v, want 0", got)
+	}
+}
+
+func Test_WithTransportAndDecoder(t *testing.T) {
+	transport := TransportFunc(func(req *http.Request) (*http.Response, error) {
+		resp := httptest.NewRecorder()
+		resp.Body.WriteString("OK")
+		return resp.Result(), nil
+	})
+	decoder := nominatim.DecoderFunc(func(resp *http.Response, body []byte, v interface{}) error {
+		status := v.(*nominatim.Status)
+		status.Message = string(body)
+		return nil
+	})
+	d := nominatim.NewClient("http://localhost:8080", nil, nominatim.WithTransport(transport), nominatim.WithDecoder(decoder))
+	got, err := d.CheckStatus(context.TODO())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Message != "OK" {
+		t.Errorf("CheckStatus() got = %v, want OK", got.Message)
+	}
+}
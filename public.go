@@ -0,0 +1,61 @@
+package nominatim
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+)
+
+// PublicBaseURL is the base URL of the public Nominatim API, run by the OpenStreetMap Foundation.
+const PublicBaseURL = "https://nominatim.openstreetmap.org"
+
+// keyEmail is the parameter identifying the caller of the public Nominatim API by email.
+const keyEmail = "email"
+
+// ErrMissingUserAgent is returned when creating a client for the public Nominatim API without an User-Agent
+// identifying the application, as required by its usage policy.
+var ErrMissingUserAgent = errors.New("nominatim: an User-Agent identifying the application is required")
+
+// NewPublicClient creates a Client for the public Nominatim API, at PublicBaseURL, complying with its usage policy:
+// the requests are limited to 1 per second, sent one at a time, identifying the application by the given User-Agent,
+// which is required, and by the given email, if any. The given options are applied after the policy ones, so a cache
+// or retries can be added.
+func NewPublicClient(userAgent, email string, opts ...Option) (Client, error) {
+	if strings.TrimSpace(userAgent) == "" || userAgent == DefaultUserAgent {
+		return nil, ErrMissingUserAgent
+	}
+	return NewClientWithOptions(PublicBaseURL, append(publicPolicyOptions(userAgent, email), opts...)...), nil
+}
+
+// publicPolicyOptions returns the options complying with the usage policy of the public Nominatim API.
+func publicPolicyOptions(userAgent, email string) []Option {
+	return []Option{
+		WithRateLimit(DefaultRateLimit, 1),
+		WithMaxConcurrency(1),
+		WithUserAgent(userAgent),
+		WithEmail(email),
+	}
+}
+
+// WithEmail makes the client send the given email on every request, identifying the caller, as asked by the public
+// Nominatim API for large numbers of requests. An empty one means none.
+func WithEmail(email string) Option {
+	return func(d *defaultClient) {
+		d.email = email
+	}
+}
+
+// identityQuery returns the given query along with the email and API key parameters, if any.
+func (d defaultClient) identityQuery(query QueryEncoder) QueryEncoder {
+	params := url.Values{}
+	if d.email != "" {
+		params.Set(keyEmail, d.email)
+	}
+	if d.apiKey != "" {
+		params.Set(keyAPIKey, d.apiKey)
+	}
+	if len(params) == 0 {
+		return query
+	}
+	return paramsQuery{QueryEncoder: query, params: params}
+}
@@ -0,0 +1,87 @@
+package nominatim_test
+
+import (
+	"context"
+	"errors"
+	"github.com/diegohordi/nominatim"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func Test_NewPublicClient(t *testing.T) {
+	tests := []struct {
+		name      string
+		userAgent string
+		email     string
+		wantURL   string
+		wantErr   error
+	}{
+		{
+			name:      "should send the User-Agent and the email to the public API",
+			userAgent: "acme-geocoder/1.0",
+			email:     "ops@acme.example",
+			wantURL:   "https://nominatim.openstreetmap.org/status?format=json&email=ops%40acme.example",
+		},
+		{
+			name:      "should send no email unless given",
+			userAgent: "acme-geocoder/1.0",
+			wantURL:   "https://nominatim.openstreetmap.org/status?format=json",
+		},
+		{
+			name:    "should require an User-Agent",
+			wantErr: nominatim.ErrMissingUserAgent,
+		},
+		{
+			name:      "should require an User-Agent other than the default one",
+			userAgent: nominatim.DefaultUserAgent,
+			wantErr:   nominatim.ErrMissingUserAgent,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			var gotURL, gotUserAgent string
+			transport := TransportFunc(func(req *http.Request) (*http.Response, error) {
+				gotURL, gotUserAgent = req.URL.String(), req.Header.Get("User-Agent")
+				return statusFixture(http.StatusOK, mustLoadValidStatus(t))()
+			})
+			d, err := nominatim.NewPublicClient(tt.userAgent, tt.email, nominatim.WithTransport(transport))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("NewPublicClient() error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if _, err := d.CheckStatus(context.TODO()); err != nil {
+				t.Fatal(err)
+			}
+			if gotURL != tt.wantURL || gotUserAgent != tt.userAgent {
+				t.Errorf("CheckStatus() sent %s as %q, want %s as %q", gotURL, gotUserAgent, tt.wantURL, tt.userAgent)
+			}
+		})
+	}
+}
+
+func Test_NewClient_EmptyBaseURL(t *testing.T) {
+	var gotURL, gotUserAgent string
+	transport := TransportFunc(func(req *http.Request) (*http.Response, error) {
+		gotURL, gotUserAgent = req.URL.String(), req.Header.Get("User-Agent")
+		return statusFixture(http.StatusOK, mustLoadValidStatus(t))()
+	})
+	d := nominatim.NewClient("", nil, nominatim.WithTransport(transport), nominatim.WithUserAgent("acme-geocoder/1.0"))
+	if _, err := d.CheckStatus(context.TODO()); err != nil {
+		t.Fatal(err)
+	}
+	if want := nominatim.PublicBaseURL + "/status?format=json"; gotURL != want || gotUserAgent != "acme-geocoder/1.0" {
+		t.Errorf("CheckStatus() sent %s as %q, want %s as the given User-Agent", gotURL, gotUserAgent, want)
+	}
+	start := time.Now()
+	if _, err := d.CheckStatus(context.TODO()); err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed < 500*time.Millisecond {
+		t.Errorf("CheckStatus() took %s, want it rate limited to 1 request per second", elapsed)
+	}
+}
@@ -0,0 +1,143 @@
+package nominatim
+
+import (
+	"encoding/csv"
+	"errors"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Precision is how precisely a result locates the geocoded address.
+type Precision string
+
+const (
+	PrecisionHouse         Precision = "house"
+	PrecisionStreet        Precision = "street"
+	PrecisionNeighbourhood Precision = "neighbourhood"
+	PrecisionPostcode      Precision = "postcode"
+	PrecisionCity          Precision = "city"
+	PrecisionRegion        Precision = "region"
+	PrecisionCountry       Precision = "country"
+	PrecisionUnknown       Precision = "unknown"
+)
+
+// typePostcode is the type of the results locating a postal code.
+const typePostcode = "postcode"
+
+// Precision returns how precisely the result locates the geocoded address, accordingly with its place rank.
+func (r Result) Precision() Precision {
+	switch {
+	case r.Type == typePostcode:
+		return PrecisionPostcode
+	case r.PlaceRank >= 28:
+		return PrecisionHouse
+	case r.PlaceRank >= 26:
+		return PrecisionStreet
+	case r.PlaceRank >= 17:
+		return PrecisionNeighbourhood
+	case r.PlaceRank >= 13:
+		return PrecisionCity
+	case r.PlaceRank >= 5:
+		return PrecisionRegion
+	case r.PlaceRank == 4:
+		return PrecisionCountry
+	}
+	return PrecisionUnknown
+}
+
+// QualityReport aggregates the quality of a batch of geocodes, taking into account the best result of each one. It
+// can be exported as JSON, through encoding/json, or as CSV, through WriteCSV.
+type QualityReport struct {
+
+	// Geocodes is the number of geocodes added to the report.
+	Geocodes int `json:"geocodes"`
+
+	// Failures is the number of geocodes failed for other reasons than finding no results.
+	Failures int `json:"failures"`
+
+	// ZeroResults is the number of geocodes that found no results.
+	ZeroResults int `json:"zero_results"`
+
+	// ZeroResultRate is the rate of geocodes that found no results.
+	ZeroResultRate float64 `json:"zero_result_rate"`
+
+	// Precision holds the number of geocodes by the precision of their best result.
+	Precision map[Precision]int `json:"precision"`
+
+	// Countries holds the number of geocodes by the country code of their best result.
+	Countries map[string]int `json:"countries"`
+
+	// AverageConfidence is the average importance of the best results.
+	AverageConfidence float64 `json:"average_confidence"`
+
+	confidenceSum float64
+}
+
+// NewQualityReport creates an empty QualityReport.
+func NewQualityReport() *QualityReport {
+	return &QualityReport{Precision: make(map[Precision]int), Countries: make(map[string]int)}
+}
+
+// Add adds to the report the outcome of a geocode, as returned by Search.
+func (r *QualityReport) Add(results []Result, err error) {
+	r.Geocodes++
+	switch {
+	case errors.Is(err, ErrNoResults) || (err == nil && len(results) == 0):
+		r.ZeroResults++
+	case err != nil:
+		r.Failures++
+	default:
+		best := results[0]
+		r.Precision[best.Precision()]++
+		r.Countries[strings.ToUpper(best.Address.CountryCode)]++
+		r.confidenceSum += best.Importance
+	}
+	r.ZeroResultRate = float64(r.ZeroResults) / float64(r.Geocodes)
+	if found := r.Geocodes - r.ZeroResults - r.Failures; found > 0 {
+		r.AverageConfidence = r.confidenceSum / float64(found)
+	}
+}
+
+// AddResult adds to the report the outcome of a geocode, as returned by Reverse.
+func (r *QualityReport) AddResult(result Result, err error) {
+	if err != nil {
+		r.Add(nil, err)
+		return
+	}
+	r.Add([]Result{result}, nil)
+}
+
+// WriteCSV writes the report as CSV, with a metric and its value per row.
+func (r *QualityReport) WriteCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	rows := [][]string{
+		{"metric", "value"},
+		{"geocodes", strconv.Itoa(r.Geocodes)},
+		{"failures", strconv.Itoa(r.Failures)},
+		{"zero_results", strconv.Itoa(r.ZeroResults)},
+		{"zero_result_rate", strconv.FormatFloat(r.ZeroResultRate, 'f', -1, 64)},
+		{"average_confidence", strconv.FormatFloat(r.AverageConfidence, 'f', -1, 64)},
+	}
+	precisions := make([]string, 0, len(r.Precision))
+	for precision := range r.Precision {
+		precisions = append(precisions, string(precision))
+	}
+	sort.Strings(precisions)
+	for _, precision := range precisions {
+		rows = append(rows, []string{"precision:" + precision, strconv.Itoa(r.Precision[Precision(precision)])})
+	}
+	countries := make([]string, 0, len(r.Countries))
+	for country := range r.Countries {
+		countries = append(countries, country)
+	}
+	sort.Strings(countries)
+	for _, country := range countries {
+		rows = append(rows, []string{"country:" + country, strconv.Itoa(r.Countries[country])})
+	}
+	if err := writer.WriteAll(rows); err != nil {
+		return err
+	}
+	return writer.Error()
+}
@@ -0,0 +1,88 @@
+package nominatim_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"github.com/diegohordi/nominatim"
+	"reflect"
+	"testing"
+)
+
+func TestResult_Precision(t *testing.T) {
+	tests := []struct {
+		name   string
+		result nominatim.Result
+		want   nominatim.Precision
+	}{
+		{name: "should be house", result: nominatim.Result{PlaceRank: 30}, want: nominatim.PrecisionHouse},
+		{name: "should be street", result: nominatim.Result{PlaceRank: 26}, want: nominatim.PrecisionStreet},
+		{name: "should be neighbourhood", result: nominatim.Result{PlaceRank: 20}, want: nominatim.PrecisionNeighbourhood},
+		{name: "should be postcode", result: nominatim.Result{PlaceRank: 21, Type: "postcode"}, want: nominatim.PrecisionPostcode},
+		{name: "should be city", result: nominatim.Result{PlaceRank: 16}, want: nominatim.PrecisionCity},
+		{name: "should be region", result: nominatim.Result{PlaceRank: 8}, want: nominatim.PrecisionRegion},
+		{name: "should be country", result: nominatim.Result{PlaceRank: 4}, want: nominatim.PrecisionCountry},
+		{name: "should be unknown", result: nominatim.Result{}, want: nominatim.PrecisionUnknown},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := tt.result.Precision(); got != tt.want {
+				t.Errorf("Precision() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestQualityReport(t *testing.T) {
+	report := nominatim.NewQualityReport()
+	report.Add([]nominatim.Result{
+		{PlaceRank: 30, Importance: 0.5, Address: nominatim.Address{CountryCode: "pt"}},
+		{PlaceRank: 26, Importance: 0.2},
+	}, nil)
+	report.Add([]nominatim.Result{{PlaceRank: 26, Importance: 0.25, Address: nominatim.Address{CountryCode: "es"}}}, nil)
+	report.AddResult(nominatim.Result{PlaceRank: 30, Importance: 0.75, Address: nominatim.Address{CountryCode: "pt"}}, nil)
+	report.Add(nil, nominatim.ErrNoResults)
+	report.AddResult(nominatim.Result{}, nominatim.Error{Message: "Unable to geocode"})
+	report.Add(nil, nil)
+	report.Add(nil, errors.New("connection refused"))
+
+	want := &nominatim.QualityReport{
+		Geocodes:          7,
+		Failures:          1,
+		ZeroResults:       3,
+		ZeroResultRate:    3.0 / 7.0,
+		Precision:         map[nominatim.Precision]int{nominatim.PrecisionHouse: 2, nominatim.PrecisionStreet: 1},
+		Countries:         map[string]int{"PT": 2, "ES": 1},
+		AverageConfidence: 0.5,
+	}
+	got, wantJSON := mustMarshal(t, report), mustMarshal(t, want)
+	if !bytes.Equal(got, wantJSON) {
+		t.Errorf("QualityReport got = %s, want %s", got, wantJSON)
+	}
+
+	buf := &bytes.Buffer{}
+	if err := report.WriteCSV(buf); err != nil {
+		t.Fatal(err)
+	}
+	wantCSV := "metric,value\ngeocodes,7\nfailures,1\nzero_results,3\nzero_result_rate,0.42857142857142855\n" +
+		"average_confidence,0.5\nprecision:house,2\nprecision:street,1\ncountry:ES,1\ncountry:PT,2\n"
+	if buf.String() != wantCSV {
+		t.Errorf("WriteCSV() got = %q, want %q", buf.String(), wantCSV)
+	}
+
+	decoded := &nominatim.QualityReport{}
+	if err := json.Unmarshal(got, decoded); err != nil || !reflect.DeepEqual(decoded.Countries, want.Countries) {
+		t.Errorf("QualityReport JSON round trip got = %+v, %v", decoded, err)
+	}
+}
+
+func mustMarshal(t *testing.T, v interface{}) []byte {
+	t.Helper()
+	content, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return content
+}
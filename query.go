@@ -0,0 +1,91 @@
+package nominatim
+
+import (
+	"net/http"
+	"net/url"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// canonicalParams holds the canonical order of the query parameters: the format first, followed by what is being
+// looked up, how it is bounded and what details are returned. Parameters not listed here follow them, in
+// alphabetical order, so adding new parameters never reorders the existing ones.
+var canonicalParams = []string{
+	keyFormat,
+	keyFreeFormQuery,
+	keyAmenity,
+	keyStreet,
+	keyCity,
+	keyCounty,
+	keyState,
+	keyCountry,
+	keyPostalCode,
+	keyLatitude,
+	keyLongitude,
+	keyOSMIDs,
+	keyLayer,
+	keyFeatureType,
+	keyCountryCodes,
+	keyViewbox,
+	keyBounded,
+	keyExcludePlaces,
+	keyLimit,
+	keyAddressDetails,
+	keyExtraTags,
+	keyNameDetails,
+	keyPolygonGeoJSON,
+	keyAcceptLanguage,
+}
+
+// EncodeQuery encodes the given parameters into a query string, in their canonical order, as documented in
+// canonicalParams, which is stable across versions of this package.
+func EncodeQuery(values url.Values) string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	rank := func(key string) int {
+		for i, canonical := range canonicalParams {
+			if key == canonical {
+				return i
+			}
+		}
+		return len(canonicalParams)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if ri, rj := rank(keys[i]), rank(keys[j]); ri != rj {
+			return ri < rj
+		}
+		return keys[i] < keys[j]
+	})
+	var b strings.Builder
+	for _, key := range keys {
+		for _, value := range values[key] {
+			if b.Len() > 0 {
+				b.WriteByte('&')
+			}
+			b.WriteString(url.QueryEscape(key))
+			b.WriteByte('=')
+			b.WriteString(url.QueryEscape(value))
+		}
+	}
+	return b.String()
+}
+
+// MatchesQuery checks if the given request was built for the given query, regardless of the order of its parameters
+// and of its base URL. Only the parameters encoded by the query are compared, so the ones added by the client, as the
+// email, the API key, its default languages or the parameters of the call options, are ignored. It is meant for tests
+// asserting the requests sent by the client, which keep passing when new parameters are added to the queries.
+func MatchesQuery(r *http.Request, q QueryEncoder) bool {
+	if !strings.HasSuffix(strings.TrimSuffix(r.URL.Path, "/"), "/"+q.Endpoint()) {
+		return false
+	}
+	params := r.URL.Query()
+	for key, values := range q.Encode() {
+		if !reflect.DeepEqual(params[key], values) {
+			return false
+		}
+	}
+	return true
+}
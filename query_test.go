@@ -0,0 +1,100 @@
+package nominatim_test
+
+import (
+	"github.com/diegohordi/nominatim"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func Test_EncodeQuery(t *testing.T) {
+	tests := []struct {
+		name   string
+		values url.Values
+		want   string
+	}{
+		{
+			name: "should encode the parameters in their canonical order",
+			values: url.Values{
+				"accept-language": {"en,pt"},
+				"limit":           {"10"},
+				"q":               {"avenida da república"},
+				"format":          {"jsonv2"},
+			},
+			want: "format=jsonv2&q=avenida+da+rep%C3%BAblica&limit=10&accept-language=en%2Cpt",
+		},
+		{
+			name: "should encode unknown parameters last, in alphabetical order",
+			values: url.Values{
+				"zoom":   {"18"},
+				"email":  {"me@example.com"},
+				"format": {"jsonv2"},
+			},
+			want: "format=jsonv2&email=me%40example.com&zoom=18",
+		},
+		{
+			name: "should encode the country codes along with what is being looked up",
+			values: url.Values{
+				"limit":        {"10"},
+				"countrycodes": {"pt,es"},
+				"viewbox":      {"-9.2,38.7,-9.1,38.8"},
+				"q":            {"farmácia"},
+			},
+			want: "q=farm%C3%A1cia&countrycodes=pt%2Ces&viewbox=-9.2%2C38.7%2C-9.1%2C38.8&limit=10",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := nominatim.EncodeQuery(tt.values); got != tt.want {
+				t.Errorf("EncodeQuery() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_MatchesQuery(t *testing.T) {
+	query := nominatim.NewSearchQuery()
+	query.FreeFormQuery = "lisboa"
+	tests := []struct {
+		name string
+		url  string
+		want bool
+	}{
+		{
+			name: "should match regardless of the parameters order",
+			url:  "http://localhost:8080/search?q=lisboa&accept-language=en&limit=10&addressdetails=1&extratags=0&namedetails=0&format=jsonv2",
+			want: true,
+		},
+		{
+			name: "should match regardless of the parameters added by the client",
+			url:  "http://localhost:8080/search?q=lisboa&accept-language=pt&limit=10&addressdetails=1&extratags=0&namedetails=0&format=jsonv2&email=ops%40acme.example&key=pk.123",
+			want: true,
+		},
+		{
+			name: "should not match a request missing a parameter of the query",
+			url:  "http://localhost:8080/search?q=lisboa&limit=10&extratags=0&namedetails=0&format=jsonv2",
+			want: false,
+		},
+		{
+			name: "should not match a different query",
+			url:  "http://localhost:8080/search?q=porto&accept-language=en&limit=10&addressdetails=1&extratags=0&namedetails=0&format=jsonv2",
+			want: false,
+		},
+		{
+			name: "should not match a different endpoint",
+			url:  "http://localhost:8080/reverse?q=lisboa&accept-language=en&limit=10&addressdetails=1&extratags=0&namedetails=0&format=jsonv2",
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := nominatim.MatchesQuery(httptest.NewRequest("GET", tt.url, nil), *query); got != tt.want {
+				t.Errorf("MatchesQuery() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,82 @@
+package nominatim
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// QueryLengthPolicy tells what to do with free-form queries longer than the maximum length.
+type QueryLengthPolicy int
+
+const (
+	// QueryLengthReject rejects the long queries with ErrInvalidQuery, without sending them.
+	QueryLengthReject QueryLengthPolicy = iota
+
+	// QueryLengthTruncate truncates the long queries, preserving the tokens holding digits, as house numbers and
+	// postal codes, as long as possible.
+	QueryLengthTruncate
+)
+
+// queryLengthLimit holds the maximum length of the free-form queries and what to do with the longer ones.
+type queryLengthLimit struct {
+	max    int
+	policy QueryLengthPolicy
+}
+
+// WithMaxQueryLength limits the free-form queries to the given number of characters, either rejecting or truncating
+// the longer ones, accordingly with the given policy, as Nominatim rejects very long queries with opaque errors.
+// Non-positive values mean no limit.
+func WithMaxQueryLength(max int, policy QueryLengthPolicy) Option {
+	return func(d *defaultClient) {
+		d.queryLengthLimit = nil
+		if max > 0 {
+			d.queryLengthLimit = &queryLengthLimit{max: max, policy: policy}
+		}
+	}
+}
+
+// apply applies the limit to the free-form query of the given SearchQuery.
+func (l *queryLengthLimit) apply(query SearchQuery) (SearchQuery, error) {
+	if l == nil || utf8.RuneCountInString(query.FreeFormQuery) <= l.max {
+		return query, nil
+	}
+	if l.policy == QueryLengthReject {
+		return query, fmt.Errorf("%w: free-form query longer than %d characters", ErrInvalidQuery, l.max)
+	}
+	query.FreeFormQuery = truncateQuery(query.FreeFormQuery, l.max)
+	return query, nil
+}
+
+// truncateQuery truncates the given free-form query to the given number of characters, dropping whole tokens from
+// the end, starting by the ones without digits, so house numbers and postal codes are preserved as long as possible.
+// A query made of a single token is cut as is.
+func truncateQuery(q string, max int) string {
+	tokens := strings.Fields(q)
+	length := utf8.RuneCountInString(strings.Join(tokens, " "))
+	for _, essential := range []bool{false, true} {
+		for i := len(tokens) - 1; i >= 0 && length > max; i-- {
+			if tokens[i] == "" || hasDigit(tokens[i]) != essential {
+				continue
+			}
+			length -= utf8.RuneCountInString(tokens[i]) + 1
+			tokens[i] = ""
+		}
+	}
+	kept := make([]string, 0, len(tokens))
+	for _, token := range tokens {
+		if token != "" {
+			kept = append(kept, token)
+		}
+	}
+	if len(kept) == 0 {
+		return string([]rune(q)[:max])
+	}
+	return strings.Trim(strings.Join(kept, " "), " ,;")
+}
+
+// hasDigit checks if the given token holds any digit.
+func hasDigit(token string) bool {
+	return strings.IndexFunc(token, unicode.IsDigit) >= 0
+}
@@ -0,0 +1,89 @@
+package nominatim_test
+
+import (
+	"context"
+	"errors"
+	"github.com/diegohordi/nominatim"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func Test_WithMaxQueryLength(t *testing.T) {
+	tests := []struct {
+		name    string
+		max     int
+		policy  nominatim.QueryLengthPolicy
+		q       string
+		want    string
+		wantErr error
+	}{
+		{
+			name:   "should send short queries as is",
+			max:    50,
+			policy: nominatim.QueryLengthReject,
+			q:      "avenida da república, lisboa",
+			want:   "avenida da república, lisboa",
+		},
+		{
+			name:    "should reject long queries",
+			max:     10,
+			policy:  nominatim.QueryLengthReject,
+			q:       "avenida da república, lisboa",
+			wantErr: nominatim.ErrInvalidQuery,
+		},
+		{
+			name:   "should truncate long queries preserving house numbers and postal codes",
+			max:    35,
+			policy: nominatim.QueryLengthTruncate,
+			q:      "avenida da república 12, 2780-142 oeiras, lisboa, portugal",
+			want:   "avenida da república 12, 2780-142",
+		},
+		{
+			name:   "should drop any other token before house numbers and postal codes",
+			max:    15,
+			policy: nominatim.QueryLengthTruncate,
+			q:      "avenida 12, 2780-142 oeiras",
+			want:   "12, 2780-142",
+		},
+		{
+			name:   "should drop house numbers and postal codes when still too long",
+			max:    5,
+			policy: nominatim.QueryLengthTruncate,
+			q:      "avenida 12, 2780-142",
+			want:   "12",
+		},
+		{
+			name:   "should cut a single long token",
+			max:    5,
+			policy: nominatim.QueryLengthTruncate,
+			q:      "república",
+			want:   "repúb",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			var got string
+			transport := TransportFunc(func(req *http.Request) (*http.Response, error) {
+				got = req.URL.Query().Get("q")
+				return statusFixture(http.StatusOK, mustLoadValidSearchResults(t))()
+			})
+			d := nominatim.NewClient("http://localhost:8080", nil, nominatim.WithTransport(transport),
+				nominatim.WithMaxQueryLength(tt.max, tt.policy))
+			query := nominatim.NewSearchQuery()
+			query.FreeFormQuery = tt.q
+			_, err := d.Search(context.TODO(), *query)
+			if !errors.Is(err, tt.wantErr) || (tt.wantErr == nil && err != nil) {
+				t.Fatalf("Search() error = %v, want %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Search() sent q = %q, want %q", got, tt.want)
+			}
+			if len([]rune(got)) > tt.max || strings.HasSuffix(got, ",") {
+				t.Errorf("Search() sent q = %q, longer than %d characters", got, tt.max)
+			}
+		})
+	}
+}
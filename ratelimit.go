@@ -0,0 +1,98 @@
+package nominatim
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// DefaultRateLimit is the maximum number of requests per second allowed by the nominatim.org usage policy.
+const DefaultRateLimit = 1.0
+
+// WithRateLimit limits the requests sent by the client to the given number of requests per second, allowing bursts
+// up to the given size. DefaultRateLimit and a burst of 1 are used for non-positive values. The rate limit is shared
+// by all the endpoints, and waiting for it respects the context cancellation.
+func WithRateLimit(requestsPerSecond float64, burst int) Option {
+	return func(d *defaultClient) {
+		d.limiter = newTokenBucket(requestsPerSecond, burst)
+	}
+}
+
+// Limiter limits the rate of the requests sent by the client, as the token bucket of WithRateLimit, or a distributed
+// one, shared by a fleet of clients, as the one of the rediscache module.
+type Limiter interface {
+
+	// Wait blocks until a request may be sent, failing when the context is done before that.
+	Wait(ctx context.Context) error
+}
+
+// WithLimiter limits the requests sent by the client through the given Limiter, shared by all the endpoints, instead
+// of the token bucket of WithRateLimit, so the rate limit can be enforced across processes.
+func WithLimiter(limiter Limiter) Option {
+	return func(d *defaultClient) {
+		d.limiter = limiter
+	}
+}
+
+// tokenBucket is a token bucket rate limiter.
+type tokenBucket struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+// newTokenBucket creates a full tokenBucket with the given rate and burst.
+func newTokenBucket(rate float64, burst int) *tokenBucket {
+	if rate <= 0 {
+		rate = DefaultRateLimit
+	}
+	if burst <= 0 {
+		burst = 1
+	}
+	return &tokenBucket{rate: rate, burst: float64(burst), tokens: float64(burst)}
+}
+
+// Wait blocks until a token is available or the context is done. It fails right away when the context deadline
+// would be exceeded before that.
+func (b *tokenBucket) Wait(ctx context.Context) error {
+	delay := b.reserve(time.Now())
+	if delay <= 0 {
+		return nil
+	}
+	if !canWait(ctx, delay) {
+		b.release()
+		return context.DeadlineExceeded
+	}
+	if err := wait(ctx, delay); err != nil {
+		b.release()
+		return err
+	}
+	return nil
+}
+
+// reserve takes a token from the bucket, returning how long to wait before it is actually available.
+func (b *tokenBucket) reserve(now time.Time) time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if !b.last.IsZero() {
+		b.tokens += now.Sub(b.last).Seconds() * b.rate
+		if b.tokens > b.burst {
+			b.tokens = b.burst
+		}
+	}
+	b.last = now
+	b.tokens--
+	if b.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-b.tokens / b.rate * float64(time.Second))
+}
+
+// release gives back a token reserved but not used.
+func (b *tokenBucket) release() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.tokens++
+}
@@ -0,0 +1,143 @@
+package nominatim_test
+
+import (
+	"context"
+	"errors"
+	"github.com/diegohordi/nominatim"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func Test_RateLimit(t *testing.T) {
+	type args struct {
+		ctx func() (context.Context, context.CancelFunc)
+	}
+	tests := []struct {
+		name        string
+		opt         nominatim.Option
+		requests    int
+		args        args
+		minDuration time.Duration
+		maxDuration time.Duration
+		wantErr     error
+	}{
+		{
+			name:        "should spread the requests accordingly with the rate limit",
+			opt:         nominatim.WithRateLimit(20, 1),
+			requests:    3,
+			args:        args{ctx: func() (context.Context, context.CancelFunc) { return context.TODO(), nil }},
+			minDuration: 90 * time.Millisecond,
+			maxDuration: time.Second,
+		},
+		{
+			name:        "should allow bursts",
+			opt:         nominatim.WithRateLimit(1, 3),
+			requests:    3,
+			args:        args{ctx: func() (context.Context, context.CancelFunc) { return context.TODO(), nil }},
+			maxDuration: 500 * time.Millisecond,
+		},
+		{
+			name:     "should fail when the wait exceeds the context deadline",
+			opt:      nominatim.WithRateLimit(0, 0),
+			requests: 2,
+			args: args{ctx: func() (context.Context, context.CancelFunc) {
+				return context.WithTimeout(context.TODO(), 10*time.Millisecond)
+			}},
+			maxDuration: 500 * time.Millisecond,
+			wantErr:     context.DeadlineExceeded,
+		},
+		{
+			name:     "should fail when the context is cancelled while waiting",
+			opt:      nominatim.WithRateLimit(0, 0),
+			requests: 2,
+			args: args{ctx: func() (context.Context, context.CancelFunc) {
+				ctx, cancelFn := context.WithCancel(context.TODO())
+				time.AfterFunc(10*time.Millisecond, cancelFn)
+				return ctx, cancelFn
+			}},
+			maxDuration: 500 * time.Millisecond,
+			wantErr:     context.Canceled,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			d := nominatim.NewClient("http://localhost:8080", &http.Client{
+				Transport: RoundTripFunc(func(req *http.Request) *http.Response {
+					resp := httptest.NewRecorder()
+					resp.Body.Write(mustLoadValidStatus(t))
+					return resp.Result()
+				}),
+			}, tt.opt)
+			ctx, cancelFn := tt.args.ctx()
+			if cancelFn != nil {
+				defer cancelFn()
+			}
+			start := time.Now()
+			var err error
+			for i := 0; i < tt.requests && err == nil; i++ {
+				_, err = d.CheckStatus(ctx)
+			}
+			elapsed := time.Since(start)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("CheckStatus() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if elapsed < tt.minDuration || elapsed > tt.maxDuration {
+				t.Errorf("CheckStatus() took %v, want between %v and %v", elapsed, tt.minDuration, tt.maxDuration)
+			}
+		})
+	}
+}
+
+// limiterFunc adapts a function into a nominatim.Limiter.
+type limiterFunc func(ctx context.Context) error
+
+func (f limiterFunc) Wait(ctx context.Context) error {
+	return f(ctx)
+}
+
+func Test_WithLimiter(t *testing.T) {
+	errLimited := errors.New("limited")
+	tests := []struct {
+		name         string
+		limiter      limiterFunc
+		wantRequests int
+		wantErr      error
+	}{
+		{
+			name:         "should send the requests allowed by the limiter",
+			limiter:      func(ctx context.Context) error { return nil },
+			wantRequests: 1,
+		},
+		{
+			name:    "should not send the requests refused by the limiter",
+			limiter: func(ctx context.Context) error { return errLimited },
+			wantErr: errLimited,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			requests := 0
+			d := nominatim.NewClient("http://localhost:8080", &http.Client{
+				Transport: RoundTripFunc(func(req *http.Request) *http.Response {
+					requests++
+					resp := httptest.NewRecorder()
+					resp.Body.Write(mustLoadValidStatus(t))
+					return resp.Result()
+				}),
+			}, nominatim.WithLimiter(tt.limiter))
+			if _, err := d.CheckStatus(context.TODO()); !errors.Is(err, tt.wantErr) {
+				t.Errorf("CheckStatus() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if requests != tt.wantRequests {
+				t.Errorf("CheckStatus() requests = %v, want %v", requests, tt.wantRequests)
+			}
+		})
+	}
+}
@@ -0,0 +1,137 @@
+package nominatim
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"math"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// sensitiveParams holds the parameters of the requests holding personal data.
+var sensitiveParams = map[string]bool{
+	keyFreeFormQuery: true,
+	keyAmenity:       true,
+	keyStreet:        true,
+	keyCity:          true,
+	keyCounty:        true,
+	keyState:         true,
+	keyCountry:       true,
+	keyPostalCode:    true,
+	keyLatitude:      true,
+	keyLongitude:     true,
+	keyViewbox:       true,
+	keyOSMIDs:        true,
+	keyEmail:         true,
+}
+
+// secretParams holds the parameters of the requests holding secrets, always masked by redactedSecret, whatever the
+// Redaction of the client.
+var secretParams = map[string]bool{keyAPIKey: true}
+
+// redactedSecret masks the values of the secretParams.
+const redactedSecret = "[redacted]"
+
+// coordinateParams holds the parameters of the requests holding coordinates.
+var coordinateParams = map[string]bool{keyLatitude: true, keyLongitude: true, keyViewbox: true}
+
+// Redaction redacts the value of the given parameter of a request, holding personal data: the free-form and the
+// structured addresses, the coordinates, the viewbox, the OSM IDs looked up and the email.
+type Redaction func(param, value string) string
+
+// RedactHash redacts the values into a short hash of them, salted by the given salt, so the requests for the same
+// values can still be correlated without revealing them.
+func RedactHash(salt string) Redaction {
+	return func(_, value string) string {
+		sum := sha256.Sum256([]byte(salt + value))
+		return "sha256:" + hex.EncodeToString(sum[:6])
+	}
+}
+
+// RedactTruncate redacts the coordinates by rounding them to the given number of decimals, as 2 for about a kilometer,
+// and the other values by keeping just their first given number of characters, followed by an ellipsis.
+func RedactTruncate(chars, decimals int) Redaction {
+	return func(param, value string) string {
+		if coordinateParams[param] {
+			if rounded, ok := roundCoordinates(value, decimals); ok {
+				return rounded
+			}
+		}
+		runes := []rune(value)
+		if len(runes) <= chars {
+			return value
+		}
+		if chars < 0 {
+			chars = 0
+		}
+		return string(runes[:chars]) + "…"
+	}
+}
+
+// WithRedaction makes the client redact the personal data held by the requests, through the given Redaction, from
+// everything it logs, as through WithLogger, or embeds in the text of its errors, as the URLs of the requests failing
+// to reach the server. The requests sent and the ones given to the Hooks are not redacted.
+func WithRedaction(redaction Redaction) Option {
+	return func(d *defaultClient) {
+		d.redaction = redaction
+	}
+}
+
+// roundCoordinates rounds the given comma-separated coordinates to the given number of decimals, if they are numbers.
+func roundCoordinates(value string, decimals int) (string, bool) {
+	if decimals < 0 {
+		decimals = 0
+	}
+	scale := math.Pow(10, float64(decimals))
+	coordinates := strings.Split(value, ",")
+	for i, coordinate := range coordinates {
+		number, err := strconv.ParseFloat(strings.TrimSpace(coordinate), 64)
+		if err != nil {
+			return "", false
+		}
+		coordinates[i] = strconv.FormatFloat(math.Round(number*scale)/scale, 'f', decimals, 64)
+	}
+	return strings.Join(coordinates, ","), true
+}
+
+// redactURL returns the given request URL with its secrets masked and its personal data redacted, if enabled.
+func (d defaultClient) redactURL(u *url.URL) string {
+	values := u.Query()
+	redacted := false
+	for param, params := range values {
+		switch {
+		case secretParams[param]:
+			for i := range params {
+				params[i] = redactedSecret
+			}
+		case d.redaction != nil && sensitiveParams[param]:
+			for i, value := range params {
+				params[i] = d.redaction(param, value)
+			}
+		default:
+			continue
+		}
+		redacted = true
+	}
+	if !redacted {
+		return u.String()
+	}
+	masked := *u
+	masked.RawQuery = EncodeQuery(values)
+	return masked.String()
+}
+
+// redactError returns the given error with the secrets of the URL it embeds masked and its personal data redacted,
+// if enabled.
+func (d defaultClient) redactError(err error) error {
+	urlErr, ok := err.(*url.Error)
+	if !ok {
+		return err
+	}
+	u, parseErr := url.Parse(urlErr.URL)
+	if parseErr != nil {
+		return &url.Error{Op: urlErr.Op, URL: "[redacted]", Err: urlErr.Err}
+	}
+	return &url.Error{Op: urlErr.Op, URL: d.redactURL(u), Err: urlErr.Err}
+}
@@ -0,0 +1,122 @@
+package nominatim_test
+
+import (
+	"context"
+	"errors"
+	"github.com/diegohordi/nominatim"
+	"strings"
+	"testing"
+)
+
+func Test_RedactTruncate(t *testing.T) {
+	tests := []struct {
+		name     string
+		chars    int
+		decimals int
+		param    string
+		value    string
+		want     string
+	}{
+		{
+			name:  "should truncate the addresses",
+			chars: 3,
+			param: "q",
+			value: "Praça do Comércio, Lisboa",
+			want:  "Pra…",
+		},
+		{
+			name:  "should keep the short values",
+			chars: 8,
+			param: "postalcode",
+			value: "1100",
+			want:  "1100",
+		},
+		{
+			name:     "should round the coordinates",
+			decimals: 2,
+			param:    "lat",
+			value:    "38.70756",
+			want:     "38.71",
+		},
+		{
+			name:  "should round the viewbox",
+			param: "viewbox",
+			value: "-9.23,38.69,-9.09,38.80",
+			want:  "-9,39,-9,39",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := nominatim.RedactTruncate(tt.chars, tt.decimals)(tt.param, tt.value); got != tt.want {
+				t.Errorf("RedactTruncate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_RedactHash(t *testing.T) {
+	redact := nominatim.RedactHash("salt")
+	got := redact("q", "Praça do Comércio, Lisboa")
+	if !strings.HasPrefix(got, "sha256:") || strings.Contains(got, "Lisboa") {
+		t.Errorf("RedactHash() = %v, want a hash", got)
+	}
+	if again := redact("q", "Praça do Comércio, Lisboa"); again != got {
+		t.Errorf("RedactHash() = %v, want %v for the same value", again, got)
+	}
+	if salted := nominatim.RedactHash("pepper")("q", "Praça do Comércio, Lisboa"); salted == got {
+		t.Errorf("RedactHash() = %v, want another hash for another salt", salted)
+	}
+}
+
+func Test_WithRedaction(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    []nominatim.Option
+		want    string
+		notWant string
+	}{
+		{
+			name:    "should redact the URL embedded in the transport errors",
+			opts:    []nominatim.Option{nominatim.WithRedaction(nominatim.RedactTruncate(3, 2))},
+			want:    "q=Pra%E2%80%A6",
+			notWant: "Lisboa",
+		},
+		{
+			name: "should not redact anything by default",
+			want: "Lisboa",
+		},
+		{
+			name:    "should always mask the API key",
+			opts:    []nominatim.Option{nominatim.WithAPIKey("SECRETKEY")},
+			want:    "key=%5Bredacted%5D",
+			notWant: "SECRETKEY",
+		},
+		{
+			name:    "should mask the API key along with the redacted data",
+			opts:    []nominatim.Option{nominatim.WithAPIKey("SECRETKEY"), nominatim.WithRedaction(nominatim.RedactTruncate(3, 2))},
+			want:    "key=%5Bredacted%5D",
+			notWant: "SECRETKEY",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			d := nominatim.NewClient("http://127.0.0.1:1", nil, tt.opts...)
+			query := nominatim.NewSearchQuery()
+			query.FreeFormQuery = "Praça do Comércio, Lisboa"
+			_, err := d.Search(context.TODO(), *query)
+			if err == nil || errors.Is(err, nominatim.ErrNoResults) {
+				t.Fatalf("Search() error = %v, want a transport error", err)
+			}
+			if !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Search() error = %v, want %v", err, tt.want)
+			}
+			if tt.notWant != "" && strings.Contains(err.Error(), tt.notWant) {
+				t.Errorf("Search() error = %v, want %v redacted", err, tt.notWant)
+			}
+		})
+	}
+}
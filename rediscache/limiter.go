@@ -0,0 +1,89 @@
+package rediscache
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/diegohordi/nominatim"
+	"github.com/redis/go-redis/v9"
+)
+
+// defaultLimiterKey is the key holding the state of the Limiter when none is given.
+const defaultLimiterKey = "nominatim:ratelimit"
+
+// reserveScript reserves the next slot of the rate limit, following the generic cell rate algorithm: the key holds
+// the theoretical arrival time of the next request, in milliseconds, and the script returns how long to wait before
+// sending the reserved request, in milliseconds as well. The time is the one of Redis, so the clocks of the clients
+// don't matter.
+var reserveScript = redis.NewScript(`
+local interval = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+local time = redis.call("TIME")
+local now = tonumber(time[1]) * 1000 + math.floor(tonumber(time[2]) / 1000)
+local tat = tonumber(redis.call("GET", KEYS[1]) or now)
+if tat < now then
+	tat = now
+end
+tat = tat + interval
+redis.call("SET", KEYS[1], tat, "PX", tat - now + 1)
+local delay = tat - burst * interval - now
+if delay < 0 then
+	return 0
+end
+return delay
+`)
+
+// Limiter is a nominatim.Limiter backed by Redis, so the rate limit is enforced across every client sharing it, as
+// the pods of a fleet sharing the same upstream Nominatim instance.
+type Limiter struct {
+	client   redis.UniversalClient
+	key      string
+	interval time.Duration
+	burst    int
+}
+
+var _ nominatim.Limiter = (*Limiter)(nil)
+
+// NewLimiter creates a Limiter allowing the given number of requests per second, with bursts up to the given size,
+// across every client sharing the given key, or "nominatim:ratelimit" if empty. nominatim.DefaultRateLimit and a
+// burst of 1 are used for non-positive values, while the interval between the requests is rounded up to
+// milliseconds, so up to 1000 requests per second.
+func NewLimiter(client redis.UniversalClient, key string, requestsPerSecond float64, burst int) *Limiter {
+	if key == "" {
+		key = defaultLimiterKey
+	}
+	if requestsPerSecond <= 0 {
+		requestsPerSecond = nominatim.DefaultRateLimit
+	}
+	if burst <= 0 {
+		burst = 1
+	}
+	interval := time.Duration(math.Ceil(1000/requestsPerSecond)) * time.Millisecond
+	return &Limiter{client: client, key: key, interval: interval, burst: burst}
+}
+
+// Wait reserves the next slot of the rate limit and blocks until it comes, or the context is done. It fails right
+// away when the context deadline would be exceeded before that, or Redis can't be reached. The slots reserved are not
+// given back, so the requests not sent due to the context still count against the rate limit.
+func (l *Limiter) Wait(ctx context.Context) error {
+	delay, err := reserveScript.Run(ctx, l.client, []string{l.key}, l.interval.Milliseconds(), l.burst).Int64()
+	if err != nil {
+		return err
+	}
+	if delay <= 0 {
+		return nil
+	}
+	duration := time.Duration(delay) * time.Millisecond
+	if deadline, ok := ctx.Deadline(); ok && time.Now().Add(duration).After(deadline) {
+		return context.DeadlineExceeded
+	}
+	timer := time.NewTimer(duration)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,54 @@
+package rediscache_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/diegohordi/nominatim/rediscache"
+)
+
+func Test_Limiter(t *testing.T) {
+	_, client := mustStartRedis(t)
+	first := rediscache.NewLimiter(client, "", 20, 1)
+	second := rediscache.NewLimiter(client, "", 20, 1)
+	ctx := context.TODO()
+	start := time.Now()
+	for _, limiter := range []*rediscache.Limiter{first, second, first} {
+		if err := limiter.Wait(ctx); err != nil {
+			t.Fatalf("Wait() error = %v", err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 90*time.Millisecond || elapsed > time.Second {
+		t.Errorf("Wait() took %v, want the requests of both limiters spread by the rate limit", elapsed)
+	}
+}
+
+func Test_Limiter_Burst(t *testing.T) {
+	_, client := mustStartRedis(t)
+	limiter := rediscache.NewLimiter(client, "test:ratelimit", 1, 3)
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		if err := limiter.Wait(context.TODO()); err != nil {
+			t.Fatalf("Wait() error = %v", err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("Wait() took %v, want the burst allowed right away", elapsed)
+	}
+	ctx, cancel := context.WithTimeout(context.TODO(), 10*time.Millisecond)
+	defer cancel()
+	if err := limiter.Wait(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Wait() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+}
+
+func Test_Limiter_Failure(t *testing.T) {
+	server, client := mustStartRedis(t)
+	limiter := rediscache.NewLimiter(client, "", 0, 0)
+	server.Close()
+	if err := limiter.Wait(context.TODO()); err == nil {
+		t.Errorf("Wait() error = %v, want an error", err)
+	}
+}
@@ -0,0 +1,57 @@
+// Package rediscache implements nominatim.Cache and nominatim.Limiter on top of Redis, so fleets of services can share
+// the responses from Nominatim API and stay within its rate limit together.
+package rediscache
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/diegohordi/nominatim"
+	"github.com/redis/go-redis/v9"
+)
+
+// defaultPrefix is the prefix of the keys used when none is given.
+const defaultPrefix = "nominatim:"
+
+// Cache is a nominatim.Cache backed by Redis.
+type Cache struct {
+	client redis.UniversalClient
+	prefix string
+}
+
+var _ nominatim.Cache = (*Cache)(nil)
+
+// New creates a Cache storing the responses through the given Redis client, under keys prefixed by the given
+// prefix, or by "nominatim:" if empty.
+func New(client redis.UniversalClient, prefix string) *Cache {
+	if prefix == "" {
+		prefix = defaultPrefix
+	}
+	return &Cache{client: client, prefix: prefix}
+}
+
+// Get retrieves the value cached under the given key, if present. Redis takes care of the expiration.
+func (c *Cache) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	value, err := c.client.Get(ctx, c.prefix+key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return value, true, nil
+}
+
+// Set caches the given value under the given key for the given TTL.
+func (c *Cache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	if ttl <= 0 {
+		return nil
+	}
+	return c.client.Set(ctx, c.prefix+key, value, ttl).Err()
+}
+
+// Delete removes the value cached under the given key, if any.
+func (c *Cache) Delete(ctx context.Context, key string) error {
+	return c.client.Del(ctx, c.prefix+key).Err()
+}
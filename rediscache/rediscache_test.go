@@ -0,0 +1,62 @@
+package rediscache_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/diegohordi/nominatim/rediscache"
+	"github.com/redis/go-redis/v9"
+)
+
+func mustStartRedis(t *testing.T) (*miniredis.Miniredis, *redis.Client) {
+	t.Helper()
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() {
+		_ = client.Close()
+	})
+	return server, client
+}
+
+func Test_Cache(t *testing.T) {
+	server, client := mustStartRedis(t)
+	cache := rediscache.New(client, "")
+	ctx := context.TODO()
+
+	if _, ok, err := cache.Get(ctx, "missing"); ok || err != nil {
+		t.Errorf("Get() got = %v, %v, want a miss", ok, err)
+	}
+	if err := cache.Set(ctx, "key", []byte("value"), time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	if !server.Exists("nominatim:key") {
+		t.Errorf("Set() didn't store the prefixed key")
+	}
+	if value, ok, err := cache.Get(ctx, "key"); !ok || err != nil || string(value) != "value" {
+		t.Errorf("Get() got = %s, %v, %v, want value", value, ok, err)
+	}
+	server.FastForward(2 * time.Minute)
+	if _, ok, _ := cache.Get(ctx, "key"); ok {
+		t.Errorf("Get() got a hit, want it expired")
+	}
+	if err := cache.Set(ctx, "key", []byte("value"), time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	if err := cache.Delete(ctx, "key"); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok, _ := cache.Get(ctx, "key"); ok {
+		t.Errorf("Get() got a hit, want it deleted")
+	}
+}
+
+func Test_Cache_Failure(t *testing.T) {
+	server, client := mustStartRedis(t)
+	cache := rediscache.New(client, "test:")
+	server.Close()
+	if _, ok, err := cache.Get(context.TODO(), "key"); ok || err == nil {
+		t.Errorf("Get() got = %v, %v, want an error", ok, err)
+	}
+}
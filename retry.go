@@ -0,0 +1,186 @@
+package nominatim
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+const headerRetryAfter = "Retry-After"
+
+const (
+	defaultRetryBaseDelay = 500 * time.Millisecond
+	defaultRetryMaxDelay  = 30 * time.Second
+)
+
+// RetryPolicy configures how failed requests are retried, with exponential backoff.
+type RetryPolicy struct {
+
+	// MaxAttempts is the maximum number of attempts, including the first one. Requests are not retried if it is
+	// lower than 2.
+	MaxAttempts int
+
+	// BaseDelay is the delay before the first retry, doubled at each following retry. Defaults to 500ms.
+	BaseDelay time.Duration
+
+	// MaxDelay caps the delay between retries. Defaults to 30s.
+	MaxDelay time.Duration
+
+	// Jitter is the fraction, between 0 and 1, of each delay that is randomly subtracted from it, avoiding many
+	// clients retrying at the same time.
+	Jitter float64
+
+	// RetryOn decides whether a failed request should be retried. Defaults to IsTransient.
+	RetryOn func(err error) bool
+}
+
+// DefaultRetryPolicy creates a RetryPolicy with sensible values.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts: 3,
+		BaseDelay:   defaultRetryBaseDelay,
+		MaxDelay:    defaultRetryMaxDelay,
+		Jitter:      0.2,
+		RetryOn:     IsTransient,
+	}
+}
+
+// WithRetry enables retrying failed requests accordingly with the given policy. The requests are not retried when
+// the delay would exceed the context deadline. When the server sends a Retry-After header, the client waits at least
+// as long as asked.
+func WithRetry(policy RetryPolicy) Option {
+	return func(d *defaultClient) {
+		d.retryPolicy = &policy
+	}
+}
+
+// IsTransient checks if the given error is worth retrying: transient network failures, server errors and rate
+// limiting, unless due to an exhausted quota.
+func IsTransient(err error) bool {
+	switch {
+	case err == nil, errors.Is(err, ErrQuotaExceeded):
+		return false
+	case errors.Is(err, ErrRateLimited), errors.Is(err, ErrServerError):
+		return true
+	case errors.Is(err, io.ErrUnexpectedEOF), errors.Is(err, syscall.ECONNRESET), errors.Is(err, syscall.ECONNREFUSED):
+		return true
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+// backoff calculates the delay before the given retry attempt, starting from 1.
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	baseDelay, maxDelay := p.BaseDelay, p.MaxDelay
+	if baseDelay <= 0 {
+		baseDelay = defaultRetryBaseDelay
+	}
+	if maxDelay <= 0 {
+		maxDelay = defaultRetryMaxDelay
+	}
+	delay := time.Duration(math.Min(float64(baseDelay)*math.Pow(2, float64(attempt-1)), float64(maxDelay)))
+	if jitter := math.Max(0, math.Min(p.Jitter, 1)); jitter > 0 {
+		delay -= time.Duration(rand.Float64() * jitter * float64(delay))
+	}
+	return delay
+}
+
+// shouldRetry checks if the given error should be retried accordingly with the policy.
+func (p RetryPolicy) shouldRetry(attempt int, err error) bool {
+	if attempt >= p.MaxAttempts {
+		return false
+	}
+	if p.RetryOn != nil {
+		return p.RetryOn(err)
+	}
+	return IsTransient(err)
+}
+
+// retryDelay calculates how long to wait before retrying the given failed attempt, starting from 1, if it should be
+// retried at all.
+func (d defaultClient) retryDelay(attempt int, err error) (time.Duration, bool) {
+	if err == nil {
+		return 0, false
+	}
+	var rateLimitErr RateLimitError
+	isRateLimited := errors.As(err, &rateLimitErr)
+	if isRateLimited && attempt <= d.rateLimitRetries {
+		if rateLimitErr.RetryAt.IsZero() {
+			policy := RetryPolicy{}
+			if d.retryPolicy != nil {
+				policy = *d.retryPolicy
+			}
+			return policy.backoff(attempt), true
+		}
+		return rateLimitErr.RetryAfter, true
+	}
+	if d.retryPolicy == nil || !d.retryPolicy.shouldRetry(attempt, err) {
+		return 0, false
+	}
+	delay := d.retryPolicy.backoff(attempt)
+	if isRateLimited && rateLimitErr.RetryAfter > delay {
+		delay = rateLimitErr.RetryAfter
+	}
+	return delay, true
+}
+
+// WithRateLimitRetries enables retrying up to the given number of times a request refused due to rate limiting,
+// waiting as long as asked by the server through the Retry-After header, or backing off as the RetryPolicy given
+// through WithRetry, or the defaults of RetryPolicy, when there is none. The request is not retried when the wait
+// would exceed the context deadline.
+func WithRateLimitRetries(retries int) Option {
+	return func(d *defaultClient) {
+		d.rateLimitRetries = retries
+	}
+}
+
+// parseRetryAfter parses the given Retry-After header value, either in seconds or as an HTTP date, relative to now.
+func parseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+	date, err := http.ParseTime(value)
+	if err != nil {
+		return 0, false
+	}
+	if wait := date.Sub(now); wait > 0 {
+		return wait, true
+	}
+	return 0, true
+}
+
+// canWait checks if waiting for the given duration still fits in the context deadline, if any.
+func canWait(ctx context.Context, duration time.Duration) bool {
+	deadline, ok := ctx.Deadline()
+	return !ok || time.Now().Add(duration).Before(deadline)
+}
+
+// wait blocks for the given duration or until the context is done.
+func wait(ctx context.Context, duration time.Duration) error {
+	if duration <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(duration)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
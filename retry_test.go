@@ -0,0 +1,312 @@
+package nominatim_test
+
+import (
+	"context"
+	"errors"
+	"github.com/diegohordi/nominatim"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// rateLimitedTransport responds with the given status and Retry-After header for the first given number of requests,
+// and with the given body afterwards.
+func rateLimitedTransport(t *testing.T, limitedRequests int32, statusCode int, retryAfter string, body []byte) http.RoundTripper {
+	t.Helper()
+	var requests int32
+	return RoundTripFunc(func(req *http.Request) *http.Response {
+		resp := httptest.NewRecorder()
+		if atomic.AddInt32(&requests, 1) <= limitedRequests {
+			resp.Header().Set("Retry-After", retryAfter)
+			resp.WriteHeader(statusCode)
+			return resp.Result()
+		}
+		resp.Body.Write(body)
+		return resp.Result()
+	})
+}
+
+func Test_RateLimitRetries(t *testing.T) {
+	type fields struct {
+		transport func() http.RoundTripper
+		opts      []nominatim.Option
+	}
+	type args struct {
+		ctx func() (context.Context, context.CancelFunc)
+	}
+	tests := []struct {
+		name           string
+		fields         fields
+		args           args
+		wantRetryAfter time.Duration
+		wantErr        error
+	}{
+		{
+			name: "should surface the wait duration when retries are disabled",
+			fields: fields{
+				transport: func() http.RoundTripper {
+					return rateLimitedTransport(t, 1, http.StatusTooManyRequests, "2", mustLoadValidStatus(t))
+				},
+			},
+			args: args{
+				ctx: func() (context.Context, context.CancelFunc) {
+					return context.TODO(), nil
+				},
+			},
+			wantRetryAfter: 2 * time.Second,
+			wantErr:        nominatim.ErrRateLimited,
+		},
+		{
+			name: "should surface the wait duration of a service unavailable response",
+			fields: fields{
+				transport: func() http.RoundTripper {
+					return rateLimitedTransport(t, 1, http.StatusServiceUnavailable, "3", mustLoadValidStatus(t))
+				},
+			},
+			args: args{
+				ctx: func() (context.Context, context.CancelFunc) {
+					return context.TODO(), nil
+				},
+			},
+			wantRetryAfter: 3 * time.Second,
+			wantErr:        nominatim.ErrServerError,
+		},
+		{
+			name: "should retry after the given wait duration",
+			fields: fields{
+				transport: func() http.RoundTripper {
+					return rateLimitedTransport(t, 2, http.StatusTooManyRequests, "0", mustLoadValidStatus(t))
+				},
+				opts: []nominatim.Option{nominatim.WithRateLimitRetries(2)},
+			},
+			args: args{
+				ctx: func() (context.Context, context.CancelFunc) {
+					return context.TODO(), nil
+				},
+			},
+		},
+		{
+			name: "should back off when no wait duration is given",
+			fields: fields{
+				transport: func() http.RoundTripper {
+					return rateLimitedTransport(t, 1, http.StatusTooManyRequests, "", mustLoadValidStatus(t))
+				},
+				opts: []nominatim.Option{nominatim.WithRateLimitRetries(1)},
+			},
+			args: args{
+				ctx: func() (context.Context, context.CancelFunc) {
+					return context.WithTimeout(context.TODO(), 100*time.Millisecond)
+				},
+			},
+			wantErr: nominatim.ErrRateLimited,
+		},
+		{
+			name: "should retry after the backoff of the retry policy when no wait duration is given",
+			fields: fields{
+				transport: func() http.RoundTripper {
+					return rateLimitedTransport(t, 1, http.StatusTooManyRequests, "", mustLoadValidStatus(t))
+				},
+				opts: []nominatim.Option{
+					nominatim.WithRateLimitRetries(1),
+					nominatim.WithRetry(nominatim.RetryPolicy{BaseDelay: 10 * time.Millisecond}),
+				},
+			},
+			args: args{
+				ctx: func() (context.Context, context.CancelFunc) {
+					return context.WithTimeout(context.TODO(), time.Second)
+				},
+			},
+		},
+		{
+			name: "should fail when retries are exhausted",
+			fields: fields{
+				transport: func() http.RoundTripper {
+					return rateLimitedTransport(t, 3, http.StatusTooManyRequests, "0", mustLoadValidStatus(t))
+				},
+				opts: []nominatim.Option{nominatim.WithRateLimitRetries(2)},
+			},
+			args: args{
+				ctx: func() (context.Context, context.CancelFunc) {
+					return context.TODO(), nil
+				},
+			},
+			wantErr: nominatim.ErrRateLimited,
+		},
+		{
+			name: "should not retry beyond the context deadline",
+			fields: fields{
+				transport: func() http.RoundTripper {
+					return rateLimitedTransport(t, 1, http.StatusTooManyRequests, "60", mustLoadValidStatus(t))
+				},
+				opts: []nominatim.Option{nominatim.WithRateLimitRetries(1)},
+			},
+			args: args{
+				ctx: func() (context.Context, context.CancelFunc) {
+					return context.WithTimeout(context.TODO(), time.Second)
+				},
+			},
+			wantRetryAfter: time.Minute,
+			wantErr:        nominatim.ErrRateLimited,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			d := nominatim.NewClient("http://localhost:8080", &http.Client{Transport: tt.fields.transport()}, tt.fields.opts...)
+			ctx, cancelFn := tt.args.ctx()
+			if cancelFn != nil {
+				defer cancelFn()
+			}
+			_, err := d.CheckStatus(ctx)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("CheckStatus() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			var rateLimitErr nominatim.RateLimitError
+			if errors.As(err, &rateLimitErr) && rateLimitErr.RetryAfter != tt.wantRetryAfter {
+				t.Errorf("CheckStatus() retry after = %v, want %v", rateLimitErr.RetryAfter, tt.wantRetryAfter)
+			}
+			if delay, ok := nominatim.RetryDelay(err); ok && (delay > tt.wantRetryAfter || delay < tt.wantRetryAfter-time.Second) {
+				t.Errorf("RetryDelay() got = %v, want about %v", delay, tt.wantRetryAfter)
+			}
+		})
+	}
+}
+
+// failingTransport responds with the given responses in order, repeating the last one, counting the requests.
+type failingTransport struct {
+	requests  int32
+	responses []func() (*http.Response, error)
+}
+
+func (f *failingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	i := int(atomic.AddInt32(&f.requests, 1)) - 1
+	if i >= len(f.responses) {
+		i = len(f.responses) - 1
+	}
+	return f.responses[i]()
+}
+
+func Test_Retry(t *testing.T) {
+	statusResponse := func(statusCode int, body []byte) func() (*http.Response, error) {
+		return func() (*http.Response, error) {
+			resp := httptest.NewRecorder()
+			resp.WriteHeader(statusCode)
+			resp.Body.Write(body)
+			return resp.Result(), nil
+		}
+	}
+	connectionReset := func() (*http.Response, error) {
+		return nil, syscall.ECONNRESET
+	}
+	policy := func(maxAttempts int, retryOn func(err error) bool) nominatim.RetryPolicy {
+		return nominatim.RetryPolicy{
+			MaxAttempts: maxAttempts,
+			BaseDelay:   time.Millisecond,
+			MaxDelay:    5 * time.Millisecond,
+			Jitter:      0.5,
+			RetryOn:     retryOn,
+		}
+	}
+	tests := []struct {
+		name         string
+		responses    []func() (*http.Response, error)
+		opts         []nominatim.Option
+		wantRequests int32
+		wantErr      error
+	}{
+		{
+			name:         "should not retry by default",
+			responses:    []func() (*http.Response, error){statusResponse(http.StatusBadGateway, nil)},
+			wantRequests: 1,
+			wantErr:      nominatim.ErrServerError,
+		},
+		{
+			name: "should retry server errors",
+			responses: []func() (*http.Response, error){
+				statusResponse(http.StatusBadGateway, nil),
+				statusResponse(http.StatusOK, mustLoadValidStatus(t)),
+			},
+			opts:         []nominatim.Option{nominatim.WithRetry(policy(3, nil))},
+			wantRequests: 2,
+		},
+		{
+			name: "should retry transient network errors",
+			responses: []func() (*http.Response, error){
+				connectionReset,
+				statusResponse(http.StatusOK, mustLoadValidStatus(t)),
+			},
+			opts:         []nominatim.Option{nominatim.WithRetry(policy(3, nil))},
+			wantRequests: 2,
+		},
+		{
+			name:         "should fail when attempts are exhausted",
+			responses:    []func() (*http.Response, error){statusResponse(http.StatusTooManyRequests, nil)},
+			opts:         []nominatim.Option{nominatim.WithRetry(policy(3, nil))},
+			wantRequests: 3,
+			wantErr:      nominatim.ErrRateLimited,
+		},
+		{
+			name:         "should not retry invalid queries",
+			responses:    []func() (*http.Response, error){statusResponse(http.StatusBadRequest, nil)},
+			opts:         []nominatim.Option{nominatim.WithRetry(policy(3, nil))},
+			wantRequests: 1,
+			wantErr:      nominatim.ErrInvalidQuery,
+		},
+		{
+			name: "should retry accordingly with the given predicate",
+			responses: []func() (*http.Response, error){
+				statusResponse(http.StatusBadRequest, nil),
+				statusResponse(http.StatusOK, mustLoadValidStatus(t)),
+			},
+			opts: []nominatim.Option{nominatim.WithRetry(policy(2, func(err error) bool {
+				return errors.Is(err, nominatim.ErrInvalidQuery)
+			}))},
+			wantRequests: 2,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			transport := &failingTransport{responses: tt.responses}
+			d := nominatim.NewClient("http://localhost:8080", &http.Client{Transport: transport}, tt.opts...)
+			_, err := d.CheckStatus(context.TODO())
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("CheckStatus() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if requests := atomic.LoadInt32(&transport.requests); requests != tt.wantRequests {
+				t.Errorf("CheckStatus() requests = %v, want %v", requests, tt.wantRequests)
+			}
+		})
+	}
+}
+
+func Test_IsTransient(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "should not retry successful requests", err: nil, want: false},
+		{name: "should retry server errors", err: nominatim.Error{Code: http.StatusInternalServerError}, want: true},
+		{name: "should retry rate limiting", err: nominatim.RateLimitError{}, want: true},
+		{name: "should retry connection resets", err: syscall.ECONNRESET, want: true},
+		{name: "should not retry invalid queries", err: nominatim.ErrInvalidQuery, want: false},
+		{name: "should not retry cancelled requests", err: context.Canceled, want: false},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := nominatim.IsTransient(tt.err); got != tt.want {
+				t.Errorf("IsTransient() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
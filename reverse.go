@@ -2,9 +2,14 @@ package nominatim
 
 import (
 	"net/url"
+	"strconv"
 	"strings"
 )
 
+// CoordinatePrecision is the number of decimal places the coordinates given as numbers are sent with, about 1cm at
+// the equator, more than the precision of the OSM data.
+const CoordinatePrecision = 7
+
 // ReverseQuery holds the parameters needed to perform the search.
 type ReverseQuery struct {
 	Latitude       string
@@ -13,6 +18,9 @@ type ReverseQuery struct {
 	ExtraTags      bool
 	NameDetails    bool
 	AcceptLanguage []string
+
+	// PolygonGeoJSON requests the geometry of the places, as their outlines, in the GeoJSON of the results.
+	PolygonGeoJSON bool
 }
 
 // NewReverseQuery creates a ReverseQuery with default values and the given options.
@@ -20,13 +28,34 @@ func NewReverseQuery(latitude, longitude string) *ReverseQuery {
 	return &ReverseQuery{
 		Latitude:       latitude,
 		Longitude:      longitude,
-		AcceptLanguage: []string{"en"},
 		AddressDetails: true,
 	}
 }
 
-// buildQueryString builds a query string accordingly with the given ReverseQuery.
-func (q ReverseQuery) buildQueryString() string {
+// NewReverseQueryFromFloats creates a ReverseQuery with default values for the given latitude and longitude, formatted
+// with CoordinatePrecision decimal places, without trailing zeros.
+func NewReverseQueryFromFloats(latitude, longitude float64) *ReverseQuery {
+	return NewReverseQuery(FormatCoordinate(latitude), FormatCoordinate(longitude))
+}
+
+// FormatCoordinate formats the given coordinate with CoordinatePrecision decimal places, without trailing zeros, as
+// in "38.6945252" or "-9".
+func FormatCoordinate(coordinate float64) string {
+	formatted := strconv.FormatFloat(coordinate, 'f', CoordinatePrecision, 64)
+	formatted = strings.TrimRight(strings.TrimRight(formatted, "0"), ".")
+	if formatted == "-0" {
+		return "0"
+	}
+	return formatted
+}
+
+// Endpoint returns the endpoint the ReverseQuery is sent to.
+func (q ReverseQuery) Endpoint() string {
+	return EndpointReverse
+}
+
+// Encode encodes the parameters accordingly with the given ReverseQuery.
+func (q ReverseQuery) Encode() url.Values {
 	queryStr := url.Values{}
 	queryStr.Set(keyFormat, defaultFormat)
 	queryStr.Set(keyLatitude, q.Latitude)
@@ -43,8 +72,11 @@ func (q ReverseQuery) buildQueryString() string {
 	if !q.NameDetails {
 		queryStr.Set(keyNameDetails, "0")
 	}
-	if len(q.AcceptLanguage) > 0 {
-		queryStr.Set(keyAcceptLanguage, strings.Join(q.AcceptLanguage, ","))
+	if q.PolygonGeoJSON {
+		queryStr.Set(keyPolygonGeoJSON, "1")
+	}
+	if languages := canonicalLanguages(q.AcceptLanguage); len(languages) > 0 {
+		queryStr.Set(keyAcceptLanguage, strings.Join(languages, ","))
 	}
-	return queryStr.Encode()
+	return queryStr
 }
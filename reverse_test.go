@@ -335,3 +335,31 @@ func Test_Integration_Reverse(t *testing.T) {
 		})
 	}
 }
+
+func Test_NewReverseQueryFromFloats(t *testing.T) {
+	tests := []struct {
+		name          string
+		latitude      float64
+		longitude     float64
+		wantLatitude  string
+		wantLongitude string
+	}{
+		{name: "should keep 7 decimal places", latitude: 38.6945252, longitude: -9.3221278, wantLatitude: "38.6945252", wantLongitude: "-9.3221278"},
+		{name: "should round beyond 7 decimal places", latitude: 38.694525249, longitude: -9.322127851, wantLatitude: "38.6945252", wantLongitude: "-9.3221279"},
+		{name: "should trim trailing zeros", latitude: 38.5, longitude: -9, wantLatitude: "38.5", wantLongitude: "-9"},
+		{name: "should not use scientific notation", latitude: 0.0000001, longitude: -0.00000001, wantLatitude: "0.0000001", wantLongitude: "0"},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			query := nominatim.NewReverseQueryFromFloats(tt.latitude, tt.longitude)
+			if query.Latitude != tt.wantLatitude || query.Longitude != tt.wantLongitude {
+				t.Errorf("NewReverseQueryFromFloats() got = %v, %v, want %v, %v", query.Latitude, query.Longitude, tt.wantLatitude, tt.wantLongitude)
+			}
+			if !query.AddressDetails || query.AcceptLanguage != nil {
+				t.Errorf("NewReverseQueryFromFloats() got = %v, want the default values", query)
+			}
+		})
+	}
+}
@@ -0,0 +1,115 @@
+package nominatim
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"net/http"
+	"time"
+)
+
+// Sampling decides which calls are observed by the hooks of WithHooks, the logger of WithLogger and the transports
+// checking IsObserved, as nominatimtest.Recorder, so high-QPS traffic, as autocomplete, doesn't overwhelm the tracing
+// or the logging backends, while the failures may still always be captured. Every request of a call, including its
+// retries, follows the decision made once for the call.
+type Sampling struct {
+
+	// Rate is the fraction, between 0 and 1, of the calls observed, chosen randomly. None is observed when it is zero.
+	Rate float64
+
+	// AlwaysOnError makes the failed requests observed, whatever the rate, though finding nothing is not a failure.
+	// The events known before the outcome of the request, as its start, are observed only when sampled.
+	AlwaysOnError bool
+}
+
+// WithSampling makes the client observe only the calls sampled accordingly with the given Sampling, instead of every
+// one of them. OnRequest is still called for every request, as it may mutate them, as signing them.
+func WithSampling(sampling Sampling) Option {
+	return func(d *defaultClient) {
+		d.sampling = &sampling
+	}
+}
+
+// sampledKey is the context key of the sampling decision made for a call.
+type sampledKey struct{}
+
+// sample decides whether the call of the given context is sampled, accordingly with the Sampling of the client, if any.
+func (d defaultClient) sample(ctx context.Context) context.Context {
+	if d.sampling == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, sampledKey{}, samplingDecision{
+		sampled:       rand.Float64() < d.sampling.Rate,
+		alwaysOnError: d.sampling.AlwaysOnError,
+	})
+}
+
+// samplingDecision holds the sampling decision made for a call.
+type samplingDecision struct {
+	sampled       bool
+	alwaysOnError bool
+}
+
+// IsObserved checks if the request of the given context, failed or not, must be observed accordingly with the
+// Sampling given through WithSampling to the client sending it, for the transports observing the requests, as
+// nominatimtest.Recorder. Every request is observed when the client has no sampling.
+func IsObserved(ctx context.Context, failed bool) bool {
+	decision, ok := ctx.Value(sampledKey{}).(samplingDecision)
+	return !ok || decision.sampled || (failed && decision.alwaysOnError)
+}
+
+// isObservedError checks if the request of the given context, failed with the given error, if any, must be observed.
+func isObservedError(ctx context.Context, err error) bool {
+	return IsObserved(ctx, err != nil && !errors.Is(err, ErrNoResults) && !errors.Is(err, ErrUnableToGeocode))
+}
+
+// sampledObserver notifies its observer of the events of the calls observed accordingly with their sampling.
+type sampledObserver struct {
+	observer
+}
+
+// sampled wraps the given observers, so they are notified of the observed calls only.
+func (o observers) sampled() observers {
+	sampled := make(observers, 0, len(o))
+	for _, observer := range o {
+		sampled = append(sampled, sampledObserver{observer: observer})
+	}
+	return sampled
+}
+
+func (o sampledObserver) requestStarted(ctx context.Context, query QueryEncoder, req *http.Request) error {
+	if _, mutates := o.observer.(Hooks); !mutates && !IsObserved(ctx, false) {
+		return nil
+	}
+	return o.observer.requestStarted(ctx, query, req)
+}
+
+func (o sampledObserver) requestFinished(ctx context.Context, query QueryEncoder, req *http.Request, resp *http.Response, duration time.Duration, err error) {
+	if isObservedError(ctx, err) {
+		o.observer.requestFinished(ctx, query, req, resp, duration, err)
+	}
+}
+
+func (o sampledObserver) slowRequest(ctx context.Context, query QueryEncoder, req *http.Request, duration time.Duration) {
+	if IsObserved(ctx, false) {
+		o.observer.slowRequest(ctx, query, req, duration)
+	}
+}
+
+func (o sampledObserver) retrying(ctx context.Context, query QueryEncoder, attempt int, delay time.Duration, err error) {
+	if isObservedError(ctx, err) {
+		o.observer.retrying(ctx, query, attempt, delay, err)
+	}
+}
+
+func (o sampledObserver) rateLimited(ctx context.Context, query QueryEncoder, waited time.Duration) {
+	if IsObserved(ctx, false) {
+		o.observer.rateLimited(ctx, query, waited)
+	}
+}
+
+func (o sampledObserver) decodeFailed(ctx context.Context, query QueryEncoder, err error) {
+	if IsObserved(ctx, true) {
+		o.observer.decodeFailed(ctx, query, err)
+	}
+}
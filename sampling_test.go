@@ -0,0 +1,105 @@
+package nominatim_test
+
+import (
+	"context"
+	"errors"
+	"github.com/diegohordi/nominatim"
+	"net/http"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+func Test_WithSampling(t *testing.T) {
+	tests := []struct {
+		name       string
+		sampling   nominatim.Sampling
+		response   func() (*http.Response, error)
+		wantHeader string
+		wantEvents []string
+		wantErr    error
+	}{
+		{
+			name:       "should observe every call sampled",
+			sampling:   nominatim.Sampling{Rate: 1},
+			response:   statusFixture(http.StatusOK, mustLoadValidStatus(t)),
+			wantHeader: "signed",
+			wantEvents: []string{"request", "response 200 OK"},
+		},
+		{
+			name:       "should still mutate the requests not sampled",
+			sampling:   nominatim.Sampling{Rate: 0, AlwaysOnError: true},
+			response:   statusFixture(http.StatusOK, mustLoadValidStatus(t)),
+			wantHeader: "signed",
+			wantEvents: []string{"request"},
+		},
+		{
+			name:       "should always observe the failures when asked to",
+			sampling:   nominatim.Sampling{Rate: 0, AlwaysOnError: true},
+			response:   statusFixture(http.StatusInternalServerError, nil),
+			wantHeader: "signed",
+			wantEvents: []string{"request", "response 500 Internal Server Error", "error"},
+			wantErr:    nominatim.ErrServerError,
+		},
+		{
+			name:       "should not observe the failures not sampled",
+			sampling:   nominatim.Sampling{Rate: 0},
+			response:   statusFixture(http.StatusInternalServerError, nil),
+			wantHeader: "signed",
+			wantEvents: []string{"request"},
+			wantErr:    nominatim.ErrServerError,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			mu := sync.Mutex{}
+			var events []string
+			var header string
+			transport := TransportFunc(func(req *http.Request) (*http.Response, error) {
+				header = req.Header.Get("X-Signature")
+				return tt.response()
+			})
+			hooks := nominatim.Hooks{
+				OnRequest: func(req *http.Request) error {
+					mu.Lock()
+					defer mu.Unlock()
+					events = append(events, "request")
+					req.Header.Set("X-Signature", "signed")
+					return nil
+				},
+				OnResponse: func(req *http.Request, resp *http.Response, duration time.Duration) {
+					mu.Lock()
+					defer mu.Unlock()
+					events = append(events, "response "+resp.Status)
+				},
+				OnError: func(req *http.Request, err error) {
+					mu.Lock()
+					defer mu.Unlock()
+					events = append(events, "error")
+				},
+			}
+			d := nominatim.NewClient("http://localhost:8080", nil, nominatim.WithSampling(tt.sampling),
+				nominatim.WithTransport(transport), nominatim.WithHooks(hooks))
+			if _, err := d.CheckStatus(context.TODO()); !errors.Is(err, tt.wantErr) {
+				t.Errorf("CheckStatus() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			if header != tt.wantHeader {
+				t.Errorf("CheckStatus() sent X-Signature = %v, want %v", header, tt.wantHeader)
+			}
+			if !reflect.DeepEqual(events, tt.wantEvents) {
+				t.Errorf("CheckStatus() events = %v, want %v", events, tt.wantEvents)
+			}
+		})
+	}
+}
+
+func Test_IsObserved(t *testing.T) {
+	if !nominatim.IsObserved(context.TODO(), false) {
+		t.Errorf("IsObserved() = false, want every request observed without sampling")
+	}
+}
@@ -6,14 +6,38 @@ import (
 	"strings"
 )
 
+const (
+	LayerAddress = "address"
+	LayerPOI     = "poi"
+	LayerRailway = "railway"
+	LayerNatural = "natural"
+	LayerManMade = "manmade"
+)
+
+const (
+	defaultLimit = 10
+	maxLimit     = 50
+)
+
+const (
+	FeatureTypeCountry    = "country"
+	FeatureTypeState      = "state"
+	FeatureTypeCity       = "city"
+	FeatureTypeSettlement = "settlement"
+)
+
 // SearchStructuredQuery holds parameters used to perform a structured query.
 type SearchStructuredQuery struct {
-	Street     string
-	City       string
-	County     string
-	State      string
-	Country    string
-	PostalCode string
+	HouseNumber string
+	Street      string
+	City        string
+	County      string
+	State       string
+	Country     string
+	PostalCode  string
+
+	// Amenity is the name or the type of a POI, as "pub" or "restaurant".
+	Amenity string
 }
 
 // SearchQuery holds the parameters needed to perform the search.
@@ -26,26 +50,57 @@ type SearchQuery struct {
 	AcceptLanguage []string
 	ExcludedPlaces []string
 	Limit          int
+	Layers         []string
+	FeatureType    string
+	Viewbox        string
+	Bounded        bool
+	Bias           *LocationBias
+
+	// CountryCodes restricts the results to the given countries, by their ISO 3166-1 alpha-2 codes, as "pt".
+	CountryCodes []string
+
+	// PolygonGeoJSON requests the geometry of the places, as their outlines, in the GeoJSON of the results.
+	PolygonGeoJSON bool
 }
 
 // NewSearchQuery creates a SearchQuery with default values and the given options.
-func NewSearchQuery() *SearchQuery {
-	return &SearchQuery{
-		Limit:          10,
-		AcceptLanguage: []string{"en"},
+func NewSearchQuery(opts ...SearchOption) *SearchQuery {
+	q := &SearchQuery{
+		Limit:          defaultLimit,
 		AddressDetails: true,
 	}
+	for _, opt := range opts {
+		opt(q)
+	}
+	return q
 }
 
-// buildQueryString builds a query string accordingly with the given SearchQuery.
-func (q SearchQuery) buildQueryString() string {
+// street builds the street parameter, prefixed by the normalized house number, if any.
+func (q SearchStructuredQuery) street() string {
+	houseNumber := normalizeHouseNumber(q.HouseNumber)
+	if houseNumber == "" {
+		return q.Street
+	}
+	return houseNumber + " " + q.Street
+}
+
+// Endpoint returns the endpoint the SearchQuery is sent to.
+func (q SearchQuery) Endpoint() string {
+	return EndpointSearch
+}
+
+// Encode encodes the parameters accordingly with the given SearchQuery.
+func (q SearchQuery) Encode() url.Values {
 	queryStr := url.Values{}
 	queryStr.Set(keyFormat, defaultFormat)
 	if q.FreeFormQuery != "" {
 		queryStr.Set(keyFreeFormQuery, q.FreeFormQuery)
 	}
+	if q.FreeFormQuery == "" && q.Amenity != "" {
+		queryStr.Set(keyAmenity, q.Amenity)
+	}
 	if q.FreeFormQuery == "" && q.Street != "" {
-		queryStr.Set(keyStreet, q.Street)
+		queryStr.Set(keyStreet, q.street())
 	}
 	if q.FreeFormQuery == "" && q.City != "" {
 		queryStr.Set(keyCity, q.City)
@@ -74,21 +129,42 @@ func (q SearchQuery) buildQueryString() string {
 	if !q.NameDetails {
 		queryStr.Set(keyNameDetails, "0")
 	}
-	if len(q.AcceptLanguage) > 0 {
-		queryStr.Set(keyAcceptLanguage, strings.Join(q.AcceptLanguage, ","))
+	if q.PolygonGeoJSON {
+		queryStr.Set(keyPolygonGeoJSON, "1")
+	}
+	if languages := canonicalLanguages(q.AcceptLanguage); len(languages) > 0 {
+		queryStr.Set(keyAcceptLanguage, strings.Join(languages, ","))
 	}
 	if len(q.ExcludedPlaces) > 0 {
 		queryStr.Set(keyExcludePlaces, strings.Join(q.ExcludedPlaces, ","))
 	}
+	if len(q.Layers) > 0 {
+		queryStr.Set(keyLayer, strings.Join(q.Layers, ","))
+	}
+	if q.FeatureType != "" {
+		queryStr.Set(keyFeatureType, q.FeatureType)
+	}
+	if q.Viewbox != "" {
+		queryStr.Set(keyViewbox, q.Viewbox)
+	}
+	if q.Viewbox == "" && q.Bias != nil {
+		queryStr.Set(keyViewbox, q.Bias.viewbox())
+	}
+	if q.Bounded {
+		queryStr.Set(keyBounded, "1")
+	}
+	if len(q.CountryCodes) > 0 {
+		queryStr.Set(keyCountryCodes, strings.ToLower(strings.Join(q.CountryCodes, ",")))
+	}
 	if q.Limit != 0 {
 		limit := q.Limit
 		if limit < 0 {
-			limit = 10
+			limit = defaultLimit
 		}
-		if limit > 50 {
-			limit = 50
+		if limit > maxLimit {
+			limit = maxLimit
 		}
 		queryStr.Set(keyLimit, strconv.Itoa(limit))
 	}
-	return queryStr.Encode()
+	return queryStr
 }
@@ -479,3 +479,56 @@ func Test_Integration_Search(t *testing.T) {
 		})
 	}
 }
+
+func TestSearchQuery_Encode(t *testing.T) {
+	tests := []struct {
+		name  string
+		query func() nominatim.SearchQuery
+		want  string
+	}{
+		{
+			name: "should encode the amenity of structured queries",
+			query: func() nominatim.SearchQuery {
+				return *nominatim.NewSearchQuery(nominatim.WithStructured(nominatim.SearchStructuredQuery{
+					Amenity: "pub",
+					City:    "Lisboa",
+				}))
+			},
+			want: "format=jsonv2&amenity=pub&city=Lisboa&limit=10&addressdetails=1&extratags=0&namedetails=0",
+		},
+		{
+			name: "should not encode the amenity of free-form queries",
+			query: func() nominatim.SearchQuery {
+				query := nominatim.NewSearchQuery(nominatim.WithFreeForm("pub lisboa"))
+				query.Amenity = "pub"
+				return *query
+			},
+			want: "format=jsonv2&q=pub+lisboa&limit=10&addressdetails=1&extratags=0&namedetails=0",
+		},
+		{
+			name: "should encode the country codes in lower case",
+			query: func() nominatim.SearchQuery {
+				return *nominatim.NewSearchQuery(nominatim.WithFreeForm("lisboa"), nominatim.WithCountryCodes("PT", "es"))
+			},
+			want: "format=jsonv2&q=lisboa&countrycodes=pt%2Ces&limit=10&addressdetails=1&extratags=0&namedetails=0",
+		},
+		{
+			name: "should request the polygons as GeoJSON",
+			query: func() nominatim.SearchQuery {
+				query := nominatim.NewSearchQuery(nominatim.WithFreeForm("lisboa"))
+				query.PolygonGeoJSON = true
+				return *query
+			},
+			want: "format=jsonv2&q=lisboa&limit=10&addressdetails=1&extratags=0&namedetails=0&polygon_geojson=1",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := nominatim.EncodeQuery(tt.query().Encode()); got != tt.want {
+				t.Errorf("Encode() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,92 @@
+package nominatim
+
+// SearchOption configures the SearchQuery created by NewSearchQuery.
+type SearchOption func(*SearchQuery)
+
+// WithFreeForm makes the query search for the given free-form text.
+func WithFreeForm(q string) SearchOption {
+	return func(query *SearchQuery) {
+		query.FreeFormQuery = q
+	}
+}
+
+// WithStructured makes the query search for the given structured address. It can't be combined with a free-form
+// text, which Search rejects.
+func WithStructured(structured SearchStructuredQuery) SearchOption {
+	return func(query *SearchQuery) {
+		query.SearchStructuredQuery = structured
+	}
+}
+
+// WithLimit limits the number of results to the given one, capped to the maximum of 50 accepted by the server.
+// Non-positive values mean the default of 10.
+func WithLimit(limit int) SearchOption {
+	return func(query *SearchQuery) {
+		switch {
+		case limit <= 0:
+			query.Limit = defaultLimit
+		case limit > maxLimit:
+			query.Limit = maxLimit
+		default:
+			query.Limit = limit
+		}
+	}
+}
+
+// WithLanguages makes the results use the given languages, in order of preference. None means the default languages
+// of the client, or "en".
+func WithLanguages(languages ...string) SearchOption {
+	return func(query *SearchQuery) {
+		query.AcceptLanguage = append([]string(nil), languages...)
+	}
+}
+
+// WithDetails sets which details are returned along with the results: the address breakdown, the extra tags and the
+// name details.
+func WithDetails(addressDetails, extraTags, nameDetails bool) SearchOption {
+	return func(query *SearchQuery) {
+		query.AddressDetails, query.ExtraTags, query.NameDetails = addressDetails, extraTags, nameDetails
+	}
+}
+
+// WithExcludedPlaces excludes the places with the given IDs from the results.
+func WithExcludedPlaces(placeIDs ...string) SearchOption {
+	return func(query *SearchQuery) {
+		query.ExcludedPlaces = append(query.ExcludedPlaces, placeIDs...)
+	}
+}
+
+// WithLayers restricts the results to the given layers, as LayerAddress or LayerPOI.
+func WithLayers(layers ...string) SearchOption {
+	return func(query *SearchQuery) {
+		query.Layers = append(query.Layers, layers...)
+	}
+}
+
+// WithCountryCodes restricts the results to the given countries, by their ISO 3166-1 alpha-2 codes, as "pt".
+func WithCountryCodes(codes ...string) SearchOption {
+	return func(query *SearchQuery) {
+		query.CountryCodes = append(query.CountryCodes, codes...)
+	}
+}
+
+// WithFeatureType restricts the results to the given feature type, as FeatureTypeCity.
+func WithFeatureType(featureType string) SearchOption {
+	return func(query *SearchQuery) {
+		query.FeatureType = featureType
+	}
+}
+
+// WithViewbox prefers the results within the given viewbox, as "x1,y1,x2,y2", or restricts them to it, if bounded.
+func WithViewbox(viewbox string, bounded bool) SearchOption {
+	return func(query *SearchQuery) {
+		query.Viewbox, query.Bounded = viewbox, bounded
+	}
+}
+
+// WithBias biases the results towards the given location.
+func WithBias(bias LocationBias) SearchOption {
+	return func(query *SearchQuery) {
+		query.Bias = &bias
+	}
+}
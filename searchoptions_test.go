@@ -0,0 +1,89 @@
+package nominatim_test
+
+import (
+	"github.com/diegohordi/nominatim"
+	"reflect"
+	"testing"
+)
+
+func TestNewSearchQuery_Options(t *testing.T) {
+	defaults := func() nominatim.SearchQuery {
+		return *nominatim.NewSearchQuery()
+	}
+	tests := []struct {
+		name string
+		opts []nominatim.SearchOption
+		want func() nominatim.SearchQuery
+	}{
+		{
+			name: "should keep the defaults",
+			want: func() nominatim.SearchQuery {
+				return nominatim.SearchQuery{Limit: 10, AddressDetails: true}
+			},
+		},
+		{
+			name: "should set the free-form query, limit and languages",
+			opts: []nominatim.SearchOption{nominatim.WithFreeForm("lisboa"), nominatim.WithLimit(5), nominatim.WithLanguages("en", "pt")},
+			want: func() nominatim.SearchQuery {
+				q := defaults()
+				q.FreeFormQuery, q.Limit, q.AcceptLanguage = "lisboa", 5, []string{"en", "pt"}
+				return q
+			},
+		},
+		{
+			name: "should set the structured query and details",
+			opts: []nominatim.SearchOption{
+				nominatim.WithStructured(nominatim.SearchStructuredQuery{Street: "avenida da república", City: "lisboa"}),
+				nominatim.WithDetails(false, true, true),
+			},
+			want: func() nominatim.SearchQuery {
+				q := defaults()
+				q.Street, q.City = "avenida da república", "lisboa"
+				q.AddressDetails, q.ExtraTags, q.NameDetails = false, true, true
+				return q
+			},
+		},
+		{
+			name: "should cap the limit",
+			opts: []nominatim.SearchOption{nominatim.WithLimit(100)},
+			want: func() nominatim.SearchQuery {
+				q := defaults()
+				q.Limit = 50
+				return q
+			},
+		},
+		{
+			name: "should default a non-positive limit",
+			opts: []nominatim.SearchOption{nominatim.WithLimit(-1)},
+			want: defaults,
+		},
+		{
+			name: "should set the filters and bias",
+			opts: []nominatim.SearchOption{
+				nominatim.WithLayers(nominatim.LayerPOI),
+				nominatim.WithFeatureType(nominatim.FeatureTypeCity),
+				nominatim.WithCountryCodes("pt"),
+				nominatim.WithExcludedPlaces("1", "2"),
+				nominatim.WithViewbox("-9.2,38.8,-9.1,38.7", true),
+				nominatim.WithBias(nominatim.LocationBias{Latitude: 38.7223, Longitude: -9.1393}),
+			},
+			want: func() nominatim.SearchQuery {
+				q := defaults()
+				q.Layers, q.FeatureType, q.ExcludedPlaces = []string{nominatim.LayerPOI}, nominatim.FeatureTypeCity, []string{"1", "2"}
+				q.Viewbox, q.Bounded = "-9.2,38.8,-9.1,38.7", true
+				q.CountryCodes = []string{"pt"}
+				q.Bias = &nominatim.LocationBias{Latitude: 38.7223, Longitude: -9.1393}
+				return q
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got, want := *nominatim.NewSearchQuery(tt.opts...), tt.want(); !reflect.DeepEqual(got, want) {
+				t.Errorf("NewSearchQuery() got = %+v, want %+v", got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,167 @@
+package nominatim
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// Checks performed by SelfCheck.
+const (
+	SelfCheckConnectivity = "connectivity"
+	SelfCheckCredentials  = "credentials"
+	SelfCheckStatus       = "status"
+	SelfCheckSearch       = "search"
+	SelfCheckReverse      = "reverse"
+	SelfCheckClock        = "clock"
+)
+
+const (
+	// maxClockSkew is the maximum difference between the local clock and the server one accepted by SelfCheck.
+	maxClockSkew = time.Minute
+
+	// selfCheckSearchQuery and selfCheckLatitude, selfCheckLongitude are the canary queries sent by SelfCheck.
+	selfCheckSearchQuery = "london"
+	selfCheckLatitude    = "51.5073219"
+	selfCheckLongitude   = "-0.1276474"
+)
+
+// errSelfCheckSkipped is reported by the checks skipped due to a previous failure.
+var errSelfCheckSkipped = errors.New("nominatim: skipped due to a previous failure")
+
+// SelfChecker is implemented by the clients able to verify their own setup.
+type SelfChecker interface {
+
+	// SelfCheck verifies the connectivity to the server, the acceptance of the credentials, the server health, the
+	// search and reverse endpoints, through canary queries, and the clock sanity.
+	SelfCheck(ctx context.Context) SelfCheckReport
+}
+
+// SelfCheckResult holds the outcome of a single check.
+type SelfCheckResult struct {
+	Name     string
+	Err      error
+	Skipped  bool
+	Duration time.Duration
+}
+
+// Passed tells whether the check was performed and succeeded.
+func (r SelfCheckResult) Passed() bool {
+	return r.Err == nil && !r.Skipped
+}
+
+// SelfCheckReport holds the outcome of the checks performed by SelfCheck, in order.
+type SelfCheckReport struct {
+	Checks []SelfCheckResult
+}
+
+// Healthy tells whether every check passed.
+func (r SelfCheckReport) Healthy() bool {
+	return r.Err() == nil
+}
+
+// Err returns the error of the first failed check, if any.
+func (r SelfCheckReport) Err() error {
+	for _, check := range r.Checks {
+		if !check.Passed() {
+			return fmt.Errorf("%s: %w", check.Name, check.Err)
+		}
+	}
+	return nil
+}
+
+// SelfCheck verifies the client setup, as at service startup or from a readiness probe. The canary queries bypass the
+// cache, and finding nothing for them still passes, as self-hosted instances may hold only a region of the world.
+func (d defaultClient) SelfCheck(ctx context.Context) SelfCheckReport {
+	report := SelfCheckReport{}
+	run := func(name string, check func() error) bool {
+		if len(report.Checks) > 0 && !report.Checks[len(report.Checks)-1].Passed() {
+			report.Checks = append(report.Checks, SelfCheckResult{Name: name, Err: errSelfCheckSkipped, Skipped: true})
+			return false
+		}
+		start := time.Now()
+		err := check()
+		report.Checks = append(report.Checks, SelfCheckResult{Name: name, Err: err, Duration: time.Since(start)})
+		return err == nil
+	}
+
+	var resp *http.Response
+	var body []byte
+	run(SelfCheckConnectivity, func() (err error) {
+		resp, body, err = d.selfCheckStatus(ctx)
+		return err
+	})
+	run(SelfCheckCredentials, func() error {
+		if resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden {
+			return newResponseError(resp, body)
+		}
+		return nil
+	})
+	run(SelfCheckStatus, func() error {
+		status := &Status{}
+		if err := d.decoder.Decode(resp, body, status); err != nil {
+			return err
+		}
+		if status.Status != 0 {
+			return StatusError{Code: status.Status, Message: status.Message}
+		}
+		return nil
+	})
+	ctx = withCallOptions(ctx, []CallOption{withoutCache()})
+	run(SelfCheckSearch, func() error {
+		query := NewSearchQuery()
+		query.FreeFormQuery, query.Limit = selfCheckSearchQuery, 1
+		_, err := d.search(ctx, *query)
+		if errors.Is(err, ErrNoResults) {
+			return nil
+		}
+		return err
+	})
+	run(SelfCheckReverse, func() error {
+		_, err := d.Reverse(ctx, *NewReverseQuery(selfCheckLatitude, selfCheckLongitude))
+		if errors.Is(err, ErrUnableToGeocode) {
+			return nil
+		}
+		return err
+	})
+	run(SelfCheckClock, func() error {
+		date, err := http.ParseTime(resp.Header.Get(headerDate))
+		if err != nil {
+			return nil
+		}
+		skew := time.Since(date)
+		if skew < -maxClockSkew || skew > maxClockSkew {
+			return fmt.Errorf("nominatim: clock skewed by %s from the server", skew.Round(time.Second))
+		}
+		return nil
+	})
+	return report
+}
+
+// selfCheckStatus sends a status request straight to the server, bypassing the cache, the retries and the mirrors,
+// though with the email and API key of the client and within its rate limit, returning the raw response.
+func (d defaultClient) selfCheckStatus(ctx context.Context) (*http.Response, []byte, error) {
+	if d.limiter != nil {
+		if err := d.limiter.Wait(ctx); err != nil {
+			return nil, nil, err
+		}
+	}
+	query := callOptionsFrom(ctx).query(d.identityQuery(statusQuery{}))
+	req, err := NewRequest(ctx, d.baseURL, query)
+	if err != nil {
+		return nil, nil, err
+	}
+	d.setHeaders(req)
+	resp, err := d.transport.Do(req)
+	if err != nil {
+		return nil, nil, d.redactError(err)
+	}
+	defer closeBody(resp.Body)
+	body, err := d.readBody(resp)
+	if err != nil {
+		return nil, nil, err
+	}
+	return resp, body, nil
+}
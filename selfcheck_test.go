@@ -0,0 +1,170 @@
+package nominatim_test
+
+import (
+	"context"
+	"errors"
+	"github.com/diegohordi/nominatim"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func Test_SelfCheck(t *testing.T) {
+	type responses struct {
+		status  func() (*http.Response, error)
+		date    time.Time
+		search  func() (*http.Response, error)
+		reverse func() (*http.Response, error)
+	}
+	healthy := func() responses {
+		return responses{
+			status:  statusFixture(http.StatusOK, mustLoadValidStatus(t)),
+			date:    time.Now(),
+			search:  statusFixture(http.StatusOK, []byte("[]")),
+			reverse: statusFixture(http.StatusOK, mustLoadValidReverseResult(t)),
+		}
+	}
+	tests := []struct {
+		name       string
+		responses  func() responses
+		wantFailed string
+		wantErr    error
+	}{
+		{
+			name:      "should pass every check",
+			responses: healthy,
+		},
+		{
+			name: "should fail the connectivity check",
+			responses: func() responses {
+				r := healthy()
+				r.status = func() (*http.Response, error) { return nil, errors.New("connection refused") }
+				return r
+			},
+			wantFailed: nominatim.SelfCheckConnectivity,
+		},
+		{
+			name: "should fail the credentials check",
+			responses: func() responses {
+				r := healthy()
+				r.status = statusFixture(http.StatusForbidden, []byte("invalid key"))
+				return r
+			},
+			wantFailed: nominatim.SelfCheckCredentials,
+			wantErr:    nominatim.ErrInvalidQuery,
+		},
+		{
+			name: "should fail the status check",
+			responses: func() responses {
+				r := healthy()
+				r.status = statusFixture(http.StatusOK, []byte(`{"status": 700, "message": "Database connection failed"}`))
+				return r
+			},
+			wantFailed: nominatim.SelfCheckStatus,
+		},
+		{
+			name: "should fail the search check",
+			responses: func() responses {
+				r := healthy()
+				r.search = statusFixture(http.StatusNotFound, nil)
+				return r
+			},
+			wantFailed: nominatim.SelfCheckSearch,
+			wantErr:    nominatim.ErrEndpointUnavailable,
+		},
+		{
+			name: "should pass the reverse check when unable to geocode",
+			responses: func() responses {
+				r := healthy()
+				r.reverse = statusFixture(http.StatusOK, mustLoadUnableToGeocodeReverseResult(t))
+				return r
+			},
+		},
+		{
+			name: "should fail the clock check",
+			responses: func() responses {
+				r := healthy()
+				r.date = time.Now().Add(-time.Hour)
+				return r
+			},
+			wantFailed: nominatim.SelfCheckClock,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			r := tt.responses()
+			transport := TransportFunc(func(req *http.Request) (*http.Response, error) {
+				switch req.URL.Path {
+				case "/search":
+					return r.search()
+				case "/reverse":
+					return r.reverse()
+				}
+				resp, err := r.status()
+				if err == nil {
+					resp.Header.Set("Date", r.date.UTC().Format(http.TimeFormat))
+				}
+				return resp, err
+			})
+			d := nominatim.NewClient("http://localhost:8080", nil, nominatim.WithTransport(transport),
+				nominatim.WithCache(nominatim.NewLRUCache(10), time.Hour))
+			report := d.(nominatim.SelfChecker).SelfCheck(context.TODO())
+			if len(report.Checks) != 6 {
+				t.Fatalf("SelfCheck() got %d checks, want 6", len(report.Checks))
+			}
+			if report.Healthy() != (tt.wantFailed == "") {
+				t.Errorf("SelfCheck() healthy = %v, want failed %q: %v", report.Healthy(), tt.wantFailed, report.Err())
+			}
+			failed := ""
+			for _, check := range report.Checks {
+				if !check.Passed() && !check.Skipped {
+					failed = check.Name
+					break
+				}
+			}
+			if failed != tt.wantFailed {
+				t.Errorf("SelfCheck() failed check = %q, want %q: %v", failed, tt.wantFailed, report.Err())
+			}
+			if tt.wantErr != nil && !errors.Is(report.Err(), tt.wantErr) {
+				t.Errorf("SelfCheck() error = %v, want %v", report.Err(), tt.wantErr)
+			}
+		})
+	}
+}
+
+// waitCounter is a nominatim.Limiter counting the waits.
+type waitCounter struct {
+	waits int32
+}
+
+func (c *waitCounter) Wait(context.Context) error {
+	atomic.AddInt32(&c.waits, 1)
+	return nil
+}
+
+func Test_SelfCheck_APIKey(t *testing.T) {
+	transport := TransportFunc(func(req *http.Request) (*http.Response, error) {
+		switch {
+		case req.URL.Query().Get("key") != "pk.123":
+			return statusFixture(http.StatusUnauthorized, []byte(`{"error":"Invalid key"}`))()
+		case req.URL.Path == "/search":
+			return statusFixture(http.StatusOK, []byte("[]"))()
+		case req.URL.Path == "/reverse":
+			return statusFixture(http.StatusOK, mustLoadValidReverseResult(t))()
+		}
+		return statusFixture(http.StatusOK, mustLoadValidStatus(t))()
+	})
+	limiter := &waitCounter{}
+	d := nominatim.NewClient("http://localhost:8080", nil, nominatim.WithTransport(transport),
+		nominatim.WithAPIKey("pk.123"), nominatim.WithLimiter(limiter))
+	report := d.(nominatim.SelfChecker).SelfCheck(context.TODO())
+	if !report.Healthy() {
+		t.Errorf("SelfCheck() healthy = false, want the API key sent: %v", report.Err())
+	}
+	if waits := atomic.LoadInt32(&limiter.waits); waits != 3 {
+		t.Errorf("SelfCheck() waited %d times for the rate limit, want 3", waits)
+	}
+}
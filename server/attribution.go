@@ -0,0 +1,78 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+const (
+	// DefaultAttribution is the attribution asked by the ODbL for the OpenStreetMap data served by Nominatim, as sent
+	// by Nominatim itself in the licence of the results.
+	DefaultAttribution = "Data © OpenStreetMap contributors, ODbL 1.0. https://osm.org/copyright"
+
+	// DefaultLicenseURL is the URL of the ODbL, the licence of the OpenStreetMap data.
+	DefaultLicenseURL = "https://opendatacommons.org/licenses/odbl/1-0/"
+)
+
+const (
+	headerAttribution = "X-Attribution"
+	headerLink        = "Link"
+	fieldAttribution  = "attribution"
+)
+
+// Attribution holds the attribution injected by the Handler into the responses it proxies, so the consumers of the
+// gateway remain compliant with the licence of the data even when they never see the original payload.
+type Attribution struct {
+
+	// Text is the attribution, as DefaultAttribution, sent through the X-Attribution header and the attribution field
+	// of every result.
+	Text string
+
+	// LicenseURL is the URL of the licence of the data, as DefaultLicenseURL, sent through the Link header with the
+	// license relation, if any.
+	LicenseURL string
+}
+
+// WithAttribution makes the Handler inject the given attribution into the successful responses it proxies, through
+// the X-Attribution and Link headers and an attribution field added to every result.
+func WithAttribution(attribution Attribution) Option {
+	return func(h *Handler) {
+		h.attribution = &attribution
+	}
+}
+
+// setHeaders sets the headers holding the attribution on the given header.
+func (a Attribution) setHeaders(header http.Header) {
+	if a.Text != "" {
+		header.Set(headerAttribution, a.Text)
+	}
+	if a.LicenseURL != "" {
+		header.Add(headerLink, "<"+a.LicenseURL+`>; rel="license"`)
+	}
+}
+
+// inject adds the attribution field to the given response, either a result or a list of results, returning it
+// encoded.
+func (a Attribution) inject(v interface{}) (interface{}, error) {
+	data, err := json.Marshal(v)
+	if err != nil || a.Text == "" {
+		return v, err
+	}
+	text, err := json.Marshal(a.Text)
+	if err != nil {
+		return nil, err
+	}
+	results := make([]map[string]json.RawMessage, 0)
+	if err := json.Unmarshal(data, &results); err == nil {
+		for _, result := range results {
+			result[fieldAttribution] = text
+		}
+		return results, nil
+	}
+	result := make(map[string]json.RawMessage)
+	if err := json.Unmarshal(data, &result); err != nil {
+		return v, nil
+	}
+	result[fieldAttribution] = text
+	return result, nil
+}
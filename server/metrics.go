@@ -0,0 +1,89 @@
+package server
+
+import (
+	"net/http"
+	"sync"
+	"time"
+)
+
+// EndpointMetrics holds the counters of the requests served by an endpoint of the Handler.
+type EndpointMetrics struct {
+
+	// Requests is the number of requests served.
+	Requests uint64 `json:"requests"`
+
+	// ClientErrors is the number of requests rejected, as invalid or rate limited, or canceled by their consumers,
+	// with 4xx status codes.
+	ClientErrors uint64 `json:"client_errors"`
+
+	// UpstreamErrors is the number of requests failed due to the upstream instance, with 5xx status codes.
+	UpstreamErrors uint64 `json:"upstream_errors"`
+
+	// CacheHits is the number of requests served from the cache of the client.
+	CacheHits uint64 `json:"cache_hits"`
+
+	// TotalDuration is the time spent serving the requests, as the sum of their durations.
+	TotalDuration time.Duration `json:"total_duration"`
+}
+
+// AverageDuration returns the average time spent serving a request.
+func (m EndpointMetrics) AverageDuration() time.Duration {
+	if m.Requests == 0 {
+		return 0
+	}
+	return m.TotalDuration / time.Duration(m.Requests)
+}
+
+// metrics holds the counters of the requests served by the Handler, by endpoint.
+type metrics struct {
+	mu        sync.Mutex
+	endpoints map[string]EndpointMetrics
+}
+
+// newMetrics creates empty metrics.
+func newMetrics() *metrics {
+	return &metrics{endpoints: make(map[string]EndpointMetrics)}
+}
+
+// record records a request served by the given endpoint.
+func (m *metrics) record(endpoint string, statusCode int, cached bool, duration time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	counters := m.endpoints[endpoint]
+	counters.Requests++
+	switch {
+	case statusCode >= http.StatusInternalServerError:
+		counters.UpstreamErrors++
+	case statusCode >= http.StatusBadRequest:
+		counters.ClientErrors++
+	}
+	if cached {
+		counters.CacheHits++
+	}
+	counters.TotalDuration += duration
+	m.endpoints[endpoint] = counters
+}
+
+// snapshot returns a copy of the counters.
+func (m *metrics) snapshot() map[string]EndpointMetrics {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	endpoints := make(map[string]EndpointMetrics, len(m.endpoints))
+	for endpoint, counters := range m.endpoints {
+		endpoints[endpoint] = counters
+	}
+	return endpoints
+}
+
+// Metrics returns the counters of the requests served so far, by endpoint, as nominatim.EndpointSearch.
+func (h *Handler) Metrics() map[string]EndpointMetrics {
+	return h.metrics.snapshot()
+}
+
+// MetricsHandler returns a http.Handler serving the counters of the requests served so far, by endpoint, as JSON, to
+// be mounted apart from the Handler, as under an internal port.
+func (h *Handler) MetricsHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		writeJSON(w, http.StatusOK, h.Metrics())
+	})
+}
@@ -0,0 +1,196 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/diegohordi/nominatim"
+)
+
+// Parameters of the requests, as sent to Nominatim.
+const (
+	paramFormat         = "format"
+	paramQuery          = "q"
+	paramAmenity        = "amenity"
+	paramStreet         = "street"
+	paramCity           = "city"
+	paramCounty         = "county"
+	paramState          = "state"
+	paramCountry        = "country"
+	paramPostalCode     = "postalcode"
+	paramLimit          = "limit"
+	paramAddressDetails = "addressdetails"
+	paramExtraTags      = "extratags"
+	paramNameDetails    = "namedetails"
+	paramAcceptLanguage = "accept-language"
+	paramExcludePlaces  = "exclude_place_ids"
+	paramLayer          = "layer"
+	paramFeatureType    = "featureType"
+	paramViewbox        = "viewbox"
+	paramBounded        = "bounded"
+	paramCountryCodes   = "countrycodes"
+	paramPolygonGeoJSON = "polygon_geojson"
+	paramLatitude       = "lat"
+	paramLongitude      = "lon"
+	paramOSMIDs         = "osm_ids"
+)
+
+// supportedFormats holds the formats accepted by the Handler, all of them answered in jsonv2.
+var supportedFormats = map[string]bool{"": true, "json": true, "jsonv2": true}
+
+// paramError is returned when a parameter of the request is invalid. It matches nominatim.ErrInvalidQuery through
+// errors.Is.
+type paramError struct {
+	param   string
+	message string
+}
+
+func (e paramError) Error() string {
+	return fmt.Sprintf("%s: parameter %q %s", nominatim.ErrInvalidQuery, e.param, e.message)
+}
+
+// Is reports whether the given target is nominatim.ErrInvalidQuery.
+func (e paramError) Is(target error) bool {
+	return target == nominatim.ErrInvalidQuery
+}
+
+// params parses the parameters of the requests.
+type params struct {
+	values url.Values
+	err    error
+}
+
+// newParams parses the parameters of the given request, rejecting the formats other than JSON.
+func newParams(r *http.Request) *params {
+	p := &params{values: r.URL.Query()}
+	if format := p.values.Get(paramFormat); !supportedFormats[format] {
+		p.err = paramError{param: paramFormat, message: "must be json or jsonv2"}
+	}
+	return p
+}
+
+// string returns the given parameter, trimmed.
+func (p *params) string(name string) string {
+	return strings.TrimSpace(p.values.Get(name))
+}
+
+// list returns the items of the given comma-separated parameter.
+func (p *params) list(name string) []string {
+	items := make([]string, 0)
+	for _, item := range strings.Split(p.values.Get(name), ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	if len(items) == 0 {
+		return nil
+	}
+	return items
+}
+
+// bool returns the given boolean parameter, or the given default when it is not set.
+func (p *params) bool(name string, def bool) bool {
+	value := p.string(name)
+	switch value {
+	case "":
+		return def
+	case "1", "true":
+		return true
+	case "0", "false":
+		return false
+	}
+	if p.err == nil {
+		p.err = paramError{param: name, message: "must be 0 or 1"}
+	}
+	return def
+}
+
+// int returns the given integer parameter, or the given default when it is not set.
+func (p *params) int(name string, def int) int {
+	value := p.string(name)
+	if value == "" {
+		return def
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		if p.err == nil {
+			p.err = paramError{param: name, message: "must be a number"}
+		}
+		return def
+	}
+	return n
+}
+
+// languages returns the languages given either through the accept-language parameter or the Accept-Language header.
+func (p *params) languages(r *http.Request) []string {
+	if languages := p.list(paramAcceptLanguage); len(languages) > 0 {
+		return languages
+	}
+	languages := make([]string, 0)
+	for _, language := range strings.Split(r.Header.Get(headerAcceptLanguage), ",") {
+		if language = strings.TrimSpace(strings.SplitN(language, ";", 2)[0]); language != "" && language != "*" {
+			languages = append(languages, language)
+		}
+	}
+	return languages
+}
+
+// parseSearchQuery parses the search query of the given request.
+func parseSearchQuery(r *http.Request) (nominatim.SearchQuery, error) {
+	p := newParams(r)
+	query := nominatim.NewSearchQuery()
+	query.FreeFormQuery = p.string(paramQuery)
+	query.SearchStructuredQuery = nominatim.SearchStructuredQuery{
+		Amenity:    p.string(paramAmenity),
+		Street:     p.string(paramStreet),
+		City:       p.string(paramCity),
+		County:     p.string(paramCounty),
+		State:      p.string(paramState),
+		Country:    p.string(paramCountry),
+		PostalCode: p.string(paramPostalCode),
+	}
+	nominatim.WithLimit(p.int(paramLimit, query.Limit))(query)
+	query.AddressDetails = p.bool(paramAddressDetails, query.AddressDetails)
+	query.ExtraTags = p.bool(paramExtraTags, query.ExtraTags)
+	query.NameDetails = p.bool(paramNameDetails, query.NameDetails)
+	if languages := p.languages(r); len(languages) > 0 {
+		query.AcceptLanguage = languages
+	}
+	query.ExcludedPlaces = p.list(paramExcludePlaces)
+	query.Layers = p.list(paramLayer)
+	query.FeatureType = p.string(paramFeatureType)
+	query.Viewbox = p.string(paramViewbox)
+	query.Bounded = p.bool(paramBounded, false)
+	query.CountryCodes = p.list(paramCountryCodes)
+	query.PolygonGeoJSON = p.bool(paramPolygonGeoJSON, false)
+	return *query, p.err
+}
+
+// parseReverseQuery parses the reverse query of the given request.
+func parseReverseQuery(r *http.Request) (nominatim.ReverseQuery, error) {
+	p := newParams(r)
+	query := nominatim.NewReverseQuery(p.string(paramLatitude), p.string(paramLongitude))
+	query.AddressDetails = p.bool(paramAddressDetails, query.AddressDetails)
+	query.ExtraTags = p.bool(paramExtraTags, query.ExtraTags)
+	query.NameDetails = p.bool(paramNameDetails, query.NameDetails)
+	if languages := p.languages(r); len(languages) > 0 {
+		query.AcceptLanguage = languages
+	}
+	return *query, p.err
+}
+
+// parseLookupQuery parses the lookup query of the given request.
+func parseLookupQuery(r *http.Request) (nominatim.LookupQuery, error) {
+	p := newParams(r)
+	query := nominatim.NewLookupQuery(p.list(paramOSMIDs)...)
+	query.AddressDetails = p.bool(paramAddressDetails, query.AddressDetails)
+	query.ExtraTags = p.bool(paramExtraTags, query.ExtraTags)
+	query.NameDetails = p.bool(paramNameDetails, query.NameDetails)
+	if languages := p.languages(r); len(languages) > 0 {
+		query.AcceptLanguage = languages
+	}
+	return *query, p.err
+}
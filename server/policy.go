@@ -0,0 +1,83 @@
+package server
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// paramPolicy restricts the parameters the consumers of the Handler may set.
+type paramPolicy struct {
+	allowed  map[string]bool
+	denied   map[string]bool
+	maxLimit int
+}
+
+// WithAllowedParams restricts the parameters the consumers of the Handler may set to the given ones, as "q" and
+// "limit", rejecting the requests setting any other with 400. The format is always allowed.
+func WithAllowedParams(params ...string) Option {
+	return func(h *Handler) {
+		if h.policy.allowed == nil {
+			h.policy.allowed = make(map[string]bool)
+		}
+		for _, param := range params {
+			h.policy.allowed[param] = true
+		}
+	}
+}
+
+// WithDeniedParams forbids the consumers of the Handler to set the given parameters, as "polygon_geojson", rejecting
+// the requests setting them with 400, so a shared upstream instance is protected from expensive queries.
+func WithDeniedParams(params ...string) Option {
+	return func(h *Handler) {
+		if h.policy.denied == nil {
+			h.policy.denied = make(map[string]bool)
+		}
+		for _, param := range params {
+			h.policy.denied[param] = true
+		}
+	}
+}
+
+// WithMaxLimit rejects the searches asking for more than the given number of results with 400, the searches not
+// asking for a limit being limited to it. Non-positive values mean the limit of the upstream instance.
+func WithMaxLimit(limit int) Option {
+	return func(h *Handler) {
+		h.policy.maxLimit = limit
+	}
+}
+
+// check checks the given parameters of a request against the policy, returning a paramError for the first one
+// rejected, in alphabetical order.
+func (p paramPolicy) check(values url.Values) error {
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		switch {
+		case p.denied[name], p.allowed != nil && !p.allowed[name] && name != paramFormat:
+			return paramError{param: name, message: "is not allowed"}
+		}
+	}
+	if p.maxLimit <= 0 {
+		return nil
+	}
+	if value := strings.TrimSpace(values.Get(paramLimit)); value != "" {
+		if limit, err := strconv.Atoi(value); err == nil && limit > p.maxLimit {
+			return paramError{param: paramLimit, message: fmt.Sprintf("must be at most %d", p.maxLimit)}
+		}
+	}
+	return nil
+}
+
+// limit caps the given limit of a search to the maximum limit of the policy, if any.
+func (p paramPolicy) limit(limit int) int {
+	if p.maxLimit > 0 && (limit <= 0 || limit > p.maxLimit) {
+		return p.maxLimit
+	}
+	return limit
+}
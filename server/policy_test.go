@@ -0,0 +1,121 @@
+package server_test
+
+import (
+	"github.com/diegohordi/nominatim"
+	"github.com/diegohordi/nominatim/nominatimtest"
+	"github.com/diegohordi/nominatim/server"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestHandler_ParamPolicy(t *testing.T) {
+	upstream := nominatimtest.NewServer()
+	t.Cleanup(upstream.Close)
+	tests := []struct {
+		name           string
+		opts           []server.Option
+		target         string
+		wantStatusCode int
+		wantBody       string
+		wantRequests   int
+	}{
+		{
+			name:           "should reject the denied parameters",
+			opts:           []server.Option{server.WithDeniedParams("polygon_geojson")},
+			target:         "/search?q=lisboa&polygon_geojson=1",
+			wantStatusCode: http.StatusBadRequest,
+			wantBody:       `parameter \"polygon_geojson\" is not allowed`,
+		},
+		{
+			name:           "should reject the parameters not allowed",
+			opts:           []server.Option{server.WithAllowedParams("q", "limit")},
+			target:         "/search?q=lisboa&format=json&viewbox=-9.2,38.6,-9.1,38.8",
+			wantStatusCode: http.StatusBadRequest,
+			wantBody:       `parameter \"viewbox\" is not allowed`,
+		},
+		{
+			name:           "should proxy the allowed parameters",
+			opts:           []server.Option{server.WithAllowedParams("q", "limit"), server.WithDeniedParams("polygon_geojson")},
+			target:         "/search?q=torre+de+bel%C3%A9m&format=jsonv2&limit=5",
+			wantStatusCode: http.StatusOK,
+			wantBody:       `"name":"Torre de Belém"`,
+			wantRequests:   1,
+		},
+		{
+			name:           "should reject the limits over the maximum",
+			opts:           []server.Option{server.WithMaxLimit(10)},
+			target:         "/search?q=lisboa&limit=11",
+			wantStatusCode: http.StatusBadRequest,
+			wantBody:       `parameter \"limit\" must be at most 10`,
+		},
+		{
+			name:           "should proxy the limits up to the maximum",
+			opts:           []server.Option{server.WithMaxLimit(10)},
+			target:         "/search?q=torre+de+bel%C3%A9m&limit=10",
+			wantStatusCode: http.StatusOK,
+			wantBody:       `"name":"Torre de Belém"`,
+			wantRequests:   1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			before := upstream.Requests()
+			handler := server.New(nominatim.NewClient(upstream.URL, nil), tt.opts...)
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.target, nil))
+			if rec.Code != tt.wantStatusCode {
+				t.Errorf("ServeHTTP() status = %v, want %v", rec.Code, tt.wantStatusCode)
+			}
+			if !strings.Contains(rec.Body.String(), tt.wantBody) {
+				t.Errorf("ServeHTTP() body = %s, want %s", rec.Body, tt.wantBody)
+			}
+			if got := upstream.Requests() - before; got != tt.wantRequests {
+				t.Errorf("ServeHTTP() upstream requests = %v, want %v", got, tt.wantRequests)
+			}
+		})
+	}
+}
+
+func TestHandler_ParamPolicy_polygonGeoJSON(t *testing.T) {
+	tests := []struct {
+		name           string
+		opts           []server.Option
+		wantStatusCode int
+		wantPolygon    string
+	}{
+		{name: "should proxy the polygons when not denied", wantStatusCode: http.StatusOK, wantPolygon: "1"},
+		{name: "should reject the polygons when denied", opts: []server.Option{server.WithDeniedParams("polygon_geojson")}, wantStatusCode: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotPolygon string
+			upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				gotPolygon = r.URL.Query().Get("polygon_geojson")
+				_, _ = w.Write([]byte("[]"))
+			}))
+			t.Cleanup(upstream.Close)
+			handler := server.New(nominatim.NewClient(upstream.URL, nil), tt.opts...)
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/search?q=lisboa&polygon_geojson=1", nil))
+			if rec.Code != tt.wantStatusCode || gotPolygon != tt.wantPolygon {
+				t.Errorf("ServeHTTP() status = %v, upstream polygon_geojson = %q, want %v, %q", rec.Code, gotPolygon, tt.wantStatusCode, tt.wantPolygon)
+			}
+		})
+	}
+}
+
+func TestHandler_ParamPolicy_defaultLimit(t *testing.T) {
+	var gotLimit string
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotLimit = r.URL.Query().Get("limit")
+		_, _ = w.Write([]byte("[]"))
+	}))
+	t.Cleanup(upstream.Close)
+	handler := server.New(nominatim.NewClient(upstream.URL, nil), server.WithMaxLimit(3))
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/search?q=lisboa", nil))
+	if gotLimit != "3" {
+		t.Errorf("ServeHTTP() upstream limit = %v, want 3", gotLimit)
+	}
+}
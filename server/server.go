@@ -0,0 +1,227 @@
+// Package server implements an embeddable geocoding gateway: an http.Handler serving the search, reverse and lookup
+// endpoints of the Nominatim API by proxying them to an upstream instance through a nominatim.Client, so the caching,
+// the rate limiting and the retries of the client are shared by every consumer of the gateway, along with metrics of
+// the requests served.
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/diegohordi/nominatim"
+)
+
+// Paths served by the Handler.
+const (
+	PathSearch  = "/" + nominatim.EndpointSearch
+	PathReverse = "/" + nominatim.EndpointReverse
+	PathLookup  = "/" + nominatim.EndpointLookup
+)
+
+const (
+	headerAcceptLanguage = "Accept-Language"
+	headerRetryAfter     = "Retry-After"
+	headerContentType    = "Content-Type"
+	contentTypeJSON      = "application/json; charset=utf-8"
+)
+
+// statusClientClosedRequest is the status code of the requests canceled by their consumers before being served, as
+// nginx does, so they are not counted as failures of the upstream instance.
+const statusClientClosedRequest = 499
+
+// messageUnableToGeocode is the error sent by Nominatim when a location can't be reverse geocoded.
+const messageUnableToGeocode = "Unable to geocode"
+
+// Option configures the Handler.
+type Option func(h *Handler)
+
+// WithCallOptions makes the Handler make every upstream call with the given options, as extra headers.
+func WithCallOptions(opts ...nominatim.CallOption) Option {
+	return func(h *Handler) {
+		h.callOptions = append(h.callOptions, opts...)
+	}
+}
+
+// Handler serves the search, reverse and lookup endpoints of the Nominatim API, as /search, /reverse and /lookup,
+// relative to where it is mounted, proxying them to an upstream instance through a nominatim.Client. The requests are
+// parsed into queries and validated, so the invalid ones never reach the upstream instance, and the results are sent
+// in the jsonv2 format. It is safe for concurrent use.
+type Handler struct {
+	client      nominatim.Client
+	callOptions []nominatim.CallOption
+	attribution *Attribution
+	monitor     *nominatim.Monitor
+	policy      paramPolicy
+	metrics     *metrics
+	mux         *http.ServeMux
+}
+
+// New creates a Handler proxying the requests through the given client. The caching, the rate limiting and the
+// retries are the ones of the client, configured as in nominatim.WithCache, nominatim.WithRateLimit and
+// nominatim.WithRetry, so the gateway stays within the usage policy of the upstream instance whatever the load of its
+// consumers.
+func New(client nominatim.Client, opts ...Option) *Handler {
+	h := &Handler{client: client, metrics: newMetrics(), mux: http.NewServeMux()}
+	for _, opt := range opts {
+		opt(h)
+	}
+	h.mux.HandleFunc(PathSearch, h.instrument(nominatim.EndpointSearch, h.search))
+	h.mux.HandleFunc(PathReverse, h.instrument(nominatim.EndpointReverse, h.reverse))
+	h.mux.HandleFunc(PathLookup, h.instrument(nominatim.EndpointLookup, h.lookup))
+	if h.monitor != nil {
+		h.mux.HandleFunc(PathWatchStatus, h.watchStatus)
+	}
+	return h
+}
+
+// ServeHTTP serves the given request, accepting only GET and HEAD requests to the endpoints.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.mux.ServeHTTP(w, r)
+}
+
+// endpointHandler serves a request to an endpoint, returning the response to send or the error to send instead.
+type endpointHandler func(ctx context.Context, r *http.Request) (interface{}, error)
+
+// instrument adapts the given endpointHandler into a http.HandlerFunc, recording the metrics of the requests served.
+func (h *Handler) instrument(endpoint string, handle endpointHandler) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			writeError(w, http.StatusMethodNotAllowed, http.StatusText(http.StatusMethodNotAllowed))
+			h.metrics.record(endpoint, http.StatusMethodNotAllowed, false, time.Since(start))
+			return
+		}
+		metadata := &nominatim.ResponseMetadata{}
+		var v interface{}
+		err := h.policy.check(r.URL.Query())
+		if err == nil {
+			v, err = handle(nominatim.WithResponseMetadata(r.Context(), metadata), r)
+		}
+		statusCode := http.StatusOK
+		switch {
+		case errors.Is(err, nominatim.ErrUnableToGeocode) && endpoint == nominatim.EndpointReverse:
+			writeJSON(w, http.StatusOK, map[string]string{"error": messageUnableToGeocode})
+		case err != nil:
+			statusCode = statusCodeOf(err)
+			if delay, ok := nominatim.RetryDelay(err); ok && statusCode == http.StatusTooManyRequests {
+				w.Header().Set(headerRetryAfter, strconv.Itoa(int(delay.Round(time.Second)/time.Second)))
+			}
+			writeError(w, statusCode, messageOf(err))
+		case h.attribution != nil:
+			if v, err = h.attribution.inject(v); err != nil {
+				statusCode = http.StatusInternalServerError
+				writeError(w, statusCode, err.Error())
+				break
+			}
+			h.attribution.setHeaders(w.Header())
+			writeJSON(w, http.StatusOK, v)
+		default:
+			writeJSON(w, http.StatusOK, v)
+		}
+		h.metrics.record(endpoint, statusCode, metadata.Cached, time.Since(start))
+	}
+}
+
+// search serves the search endpoint, sending an empty list when nothing is found, as Nominatim does.
+func (h *Handler) search(ctx context.Context, r *http.Request) (interface{}, error) {
+	query, err := parseSearchQuery(r)
+	if err != nil {
+		return nil, err
+	}
+	query.Limit = h.policy.limit(query.Limit)
+	if err := query.Validate(); err != nil {
+		return nil, err
+	}
+	results, err := h.client.Search(ctx, query, h.callOptions...)
+	if errors.Is(err, nominatim.ErrNoResults) {
+		return []nominatim.Result{}, nil
+	}
+	return results, err
+}
+
+// reverse serves the reverse endpoint.
+func (h *Handler) reverse(ctx context.Context, r *http.Request) (interface{}, error) {
+	query, err := parseReverseQuery(r)
+	if err != nil {
+		return nil, err
+	}
+	if err := query.Validate(); err != nil {
+		return nil, err
+	}
+	return h.client.Reverse(ctx, query, h.callOptions...)
+}
+
+// lookup serves the lookup endpoint.
+func (h *Handler) lookup(ctx context.Context, r *http.Request) (interface{}, error) {
+	query, err := parseLookupQuery(r)
+	if err != nil {
+		return nil, err
+	}
+	if err := query.Validate(); err != nil {
+		return nil, err
+	}
+	results, err := h.client.Lookup(ctx, query, h.callOptions...)
+	if errors.Is(err, nominatim.ErrNoResults) || (results == nil && err == nil) {
+		return []nominatim.Result{}, nil
+	}
+	return results, err
+}
+
+// statusCodeOf returns the status code sent for the given error.
+func statusCodeOf(err error) int {
+	switch {
+	case errors.Is(err, nominatim.ErrInvalidQuery):
+		return http.StatusBadRequest
+	case errors.Is(err, nominatim.ErrRateLimited):
+		return http.StatusTooManyRequests
+	case errors.Is(err, nominatim.ErrEndpointUnavailable):
+		return http.StatusNotFound
+	case errors.Is(err, context.DeadlineExceeded):
+		return http.StatusGatewayTimeout
+	case errors.Is(err, context.Canceled):
+		return statusClientClosedRequest
+	}
+	return http.StatusBadGateway
+}
+
+// transportMessage is the message sent when the upstream instance couldn't be reached, as the transport errors carry its
+// URL and the query string.
+const transportMessage = "unable to reach the upstream instance"
+
+// messageOf returns the message sent for the given error, without the operation and the host of the upstream instance
+// added by the client.
+func messageOf(err error) string {
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		return transportMessage
+	}
+	var opErr nominatim.OpError
+	if errors.As(err, &opErr) {
+		if opErr.Stage == nominatim.StageTransport {
+			return transportMessage
+		}
+		err = opErr.Err
+	}
+	return err.Error()
+}
+
+// writeError writes the error envelope sent by Nominatim with the given status code and message.
+func writeError(w http.ResponseWriter, statusCode int, message string) {
+	writeJSON(w, statusCode, map[string]interface{}{
+		"error": map[string]interface{}{"code": statusCode, "message": strings.TrimSpace(message)},
+	})
+}
+
+// writeJSON writes the given value as JSON with the given status code.
+func writeJSON(w http.ResponseWriter, statusCode int, v interface{}) {
+	w.Header().Set(headerContentType, contentTypeJSON)
+	w.WriteHeader(statusCode)
+	_ = json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,278 @@
+package server_test
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/diegohordi/nominatim"
+	"github.com/diegohordi/nominatim/nominatimtest"
+	"github.com/diegohordi/nominatim/server"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHandler(t *testing.T) {
+	upstream := nominatimtest.NewServer()
+	t.Cleanup(upstream.Close)
+	rateLimited := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "30")
+		w.WriteHeader(http.StatusTooManyRequests)
+		_, _ = w.Write([]byte(`{"error":{"code":429,"message":"Too Many Requests"}}`))
+	}))
+	t.Cleanup(rateLimited.Close)
+	unableToGeocode := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"error":"Unable to geocode"}`))
+	}))
+	t.Cleanup(unableToGeocode.Close)
+	tests := []struct {
+		name           string
+		upstream       string
+		method         string
+		target         string
+		header         http.Header
+		wantStatusCode int
+		wantBody       string
+		wantHeader     http.Header
+	}{
+		{
+			name:           "should proxy a search",
+			upstream:       upstream.URL,
+			target:         "/search?q=torre+de+bel%C3%A9m&format=jsonv2",
+			wantStatusCode: http.StatusOK,
+			wantBody:       `"name":"Torre de Belém"`,
+		},
+		{
+			name:           "should proxy a structured search restricted to country codes",
+			upstream:       upstream.URL,
+			target:         "/search?street=avenida+dos+aliados&city=porto&countrycodes=pt&limit=100",
+			wantStatusCode: http.StatusOK,
+			wantBody:       `"name":"Avenida dos Aliados"`,
+		},
+		{
+			name:           "should send an empty list when nothing is found",
+			upstream:       upstream.URL,
+			target:         "/search?q=hospital",
+			wantStatusCode: http.StatusOK,
+			wantBody:       "[]\n",
+		},
+		{
+			name:           "should reject an empty search",
+			upstream:       upstream.URL,
+			target:         "/search?limit=5",
+			wantStatusCode: http.StatusBadRequest,
+			wantBody:       `"code":400`,
+		},
+		{
+			name:           "should reject an invalid parameter",
+			upstream:       upstream.URL,
+			target:         "/search?q=lisboa&limit=ten",
+			wantStatusCode: http.StatusBadRequest,
+			wantBody:       `parameter \"limit\" must be a number`,
+		},
+		{
+			name:           "should reject an unsupported format",
+			upstream:       upstream.URL,
+			target:         "/search?q=lisboa&format=xml",
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:           "should proxy a reverse geocode",
+			upstream:       upstream.URL,
+			target:         "/reverse?lat=41.1486&lon=-8.6110",
+			header:         http.Header{"Accept-Language": {"pt-PT,pt;q=0.9"}},
+			wantStatusCode: http.StatusOK,
+			wantBody:       `"name":"Avenida dos Aliados"`,
+		},
+		{
+			name:           "should reject invalid coordinates",
+			upstream:       upstream.URL,
+			target:         "/reverse?lat=98&lon=-8.6110",
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:           "should send the location couldn't be geocoded as Nominatim does",
+			upstream:       unableToGeocode.URL,
+			target:         "/reverse?lat=0&lon=0",
+			wantStatusCode: http.StatusOK,
+			wantBody:       `{"error":"Unable to geocode"}`,
+		},
+		{
+			name:           "should proxy a lookup",
+			upstream:       upstream.URL,
+			target:         "/lookup?osm_ids=N455680276,W24961587",
+			wantStatusCode: http.StatusOK,
+			wantBody:       `"name":"Farmácia Estácio"`,
+		},
+		{
+			name:           "should reject invalid OSM IDs",
+			upstream:       upstream.URL,
+			target:         "/lookup?osm_ids=X1",
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:           "should send the rate limiting of the upstream instance",
+			upstream:       rateLimited.URL,
+			target:         "/search?q=lisboa",
+			wantStatusCode: http.StatusTooManyRequests,
+			wantHeader:     http.Header{"Retry-After": {"30"}},
+		},
+		{
+			name:           "should fail due to an unreachable upstream instance",
+			upstream:       "http://127.0.0.1:1",
+			target:         "/search?q=lisboa",
+			wantStatusCode: http.StatusBadGateway,
+			wantBody:       `{"error":{"code":502,"message":"unable to reach the upstream instance"}}`,
+		},
+		{
+			name:           "should reject other methods",
+			upstream:       upstream.URL,
+			method:         http.MethodPost,
+			target:         "/search?q=lisboa",
+			wantStatusCode: http.StatusMethodNotAllowed,
+			wantHeader:     http.Header{"Allow": {"GET, HEAD"}},
+		},
+		{
+			name:           "should not serve other endpoints",
+			upstream:       upstream.URL,
+			target:         "/details?place_id=1",
+			wantStatusCode: http.StatusNotFound,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			handler := server.New(nominatim.NewClient(tt.upstream, nil))
+			method := tt.method
+			if method == "" {
+				method = http.MethodGet
+			}
+			req := httptest.NewRequest(method, tt.target, nil)
+			for key, values := range tt.header {
+				req.Header[key] = values
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+			if rec.Code != tt.wantStatusCode {
+				t.Fatalf("ServeHTTP() status code = %v, want %v, body = %s", rec.Code, tt.wantStatusCode, rec.Body)
+			}
+			if !strings.Contains(rec.Body.String(), tt.wantBody) {
+				t.Errorf("ServeHTTP() body = %s, want %s", rec.Body, tt.wantBody)
+			}
+			for key := range tt.wantHeader {
+				if got := rec.Header().Get(key); got != tt.wantHeader.Get(key) {
+					t.Errorf("ServeHTTP() header %s = %v, want %v", key, got, tt.wantHeader.Get(key))
+				}
+			}
+		})
+	}
+}
+
+func TestHandler_Metrics(t *testing.T) {
+	upstream := nominatimtest.NewServer()
+	t.Cleanup(upstream.Close)
+	client := nominatim.NewClient(upstream.URL, nil, nominatim.WithCache(nominatim.NewLRUCache(10), time.Minute))
+	handler := server.New(client, server.WithCallOptions(nominatim.WithHeader("X-Gateway", "test")))
+	for _, target := range []string{"/search?q=lisboa", "/search?q=lisboa", "/search?limit=5", "/reverse?lat=41.1486&lon=-8.6110"} {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, target, nil))
+	}
+	got := handler.Metrics()
+	search := got[nominatim.EndpointSearch]
+	if search.Requests != 3 || search.ClientErrors != 1 || search.UpstreamErrors != 0 || search.CacheHits != 1 {
+		t.Errorf("Metrics() search = %+v, want 3 requests, 1 client error and 1 cache hit", search)
+	}
+	if got[nominatim.EndpointReverse].Requests != 1 {
+		t.Errorf("Metrics() reverse = %+v, want 1 request", got[nominatim.EndpointReverse])
+	}
+	if upstream.Requests() != 2 {
+		t.Errorf("Metrics() upstream requests = %v, want 2", upstream.Requests())
+	}
+	rec := httptest.NewRecorder()
+	handler.MetricsHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	served := make(map[string]server.EndpointMetrics)
+	if err := json.Unmarshal(rec.Body.Bytes(), &served); err != nil {
+		t.Fatalf("MetricsHandler() body = %s, error = %v", rec.Body, err)
+	}
+	if served[nominatim.EndpointSearch].Requests != 3 {
+		t.Errorf("MetricsHandler() search = %+v, want 3 requests", served[nominatim.EndpointSearch])
+	}
+}
+
+func TestHandler_Attribution(t *testing.T) {
+	upstream := nominatimtest.NewServer()
+	t.Cleanup(upstream.Close)
+	attribution := server.Attribution{Text: server.DefaultAttribution, LicenseURL: server.DefaultLicenseURL}
+	handler := server.New(nominatim.NewClient(upstream.URL, nil), server.WithAttribution(attribution))
+	tests := []struct {
+		name       string
+		target     string
+		wantStatus int
+		wantCount  int
+	}{
+		{
+			name:       "should add the attribution to every result searched",
+			target:     "/search?q=torre+de+bel%C3%A9m",
+			wantStatus: http.StatusOK,
+			wantCount:  1,
+		},
+		{
+			name:       "should add the attribution to the result reverse geocoded",
+			target:     "/reverse?lat=41.1486&lon=-8.6110",
+			wantStatus: http.StatusOK,
+			wantCount:  1,
+		},
+		{
+			name:       "should not add the attribution to the errors",
+			target:     "/search",
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.target, nil))
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("ServeHTTP() status = %v, want %v: %s", rec.Code, tt.wantStatus, rec.Body)
+			}
+			if got := strings.Count(rec.Body.String(), `"attribution":"Data © OpenStreetMap contributors`); got != tt.wantCount {
+				t.Errorf("ServeHTTP() body = %s, want %d attributions", rec.Body, tt.wantCount)
+			}
+			wantHeader, wantLink := "", ""
+			if tt.wantCount > 0 {
+				wantHeader, wantLink = server.DefaultAttribution, "<"+server.DefaultLicenseURL+`>; rel="license"`
+			}
+			if got := rec.Header().Get("X-Attribution"); got != wantHeader {
+				t.Errorf("ServeHTTP() X-Attribution = %q, want %q", got, wantHeader)
+			}
+			if got := rec.Header().Get("Link"); got != wantLink {
+				t.Errorf("ServeHTTP() Link = %q, want %q", got, wantLink)
+			}
+		})
+	}
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/search?q=torre+de+bel%C3%A9m", nil))
+	results := make([]nominatim.Result, 0)
+	if err := json.Unmarshal(rec.Body.Bytes(), &results); err != nil || len(results) != 1 || results[0].Name != "Torre de Belém" {
+		t.Errorf("ServeHTTP() results = %+v, error = %v, want the results kept", results, err)
+	}
+}
+
+func TestHandler_canceled(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	t.Cleanup(upstream.Close)
+	handler := server.New(nominatim.NewClient(upstream.URL, nil))
+	ctx, cancel := context.WithCancel(context.TODO())
+	cancel()
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/search?q=lisboa", nil).WithContext(ctx))
+	if rec.Code != 499 {
+		t.Errorf("ServeHTTP() status code = %v, want 499", rec.Code)
+	}
+	if got := handler.Metrics()[nominatim.EndpointSearch]; got.UpstreamErrors != 0 || got.ClientErrors != 1 {
+		t.Errorf("Metrics() search = %+v, want the request counted as a client error", got)
+	}
+}
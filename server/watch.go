@@ -0,0 +1,110 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/diegohordi/nominatim"
+)
+
+// PathWatchStatus is the path streaming the health of the upstream instance, served once a Monitor is given through
+// WithMonitor.
+const PathWatchStatus = "/watch/status"
+
+const (
+	headerCacheControl   = "Cache-Control"
+	contentTypeEventData = "text/event-stream"
+	eventHealth          = "health"
+)
+
+// HealthEvent is the event streamed by PathWatchStatus on every transition of the health of the upstream instance.
+type HealthEvent struct {
+	From    nominatim.Health `json:"from"`
+	To      nominatim.Health `json:"to"`
+	Status  int              `json:"status,omitempty"`
+	Message string           `json:"message,omitempty"`
+	Error   string           `json:"error,omitempty"`
+	At      time.Time        `json:"at,omitempty"`
+
+	// Unavailable holds the endpoints detected as unavailable, as "search", sorted.
+	Unavailable []string `json:"unavailable,omitempty"`
+}
+
+// newHealthEvent creates the HealthEvent streamed for the given transition.
+func newHealthEvent(change nominatim.HealthChange) HealthEvent {
+	event := HealthEvent{
+		From:    change.From,
+		To:      change.To,
+		Status:  change.Status.Status,
+		Message: change.Status.Message,
+		At:      change.At,
+	}
+	if change.Err != nil {
+		event.Error = messageOf(change.Err)
+	}
+	for endpoint := range change.Capabilities.Unavailable {
+		event.Unavailable = append(event.Unavailable, endpoint)
+	}
+	sort.Strings(event.Unavailable)
+	return event
+}
+
+// WithMonitor makes the Handler serve PathWatchStatus, streaming the transitions of the health of the upstream
+// instance tracked by the given Monitor as server-sent events, so dashboards and dependent services are pushed the
+// health of the geocoder instead of polling it. The Monitor must be run apart, as through Monitor.Run.
+func WithMonitor(monitor *nominatim.Monitor) Option {
+	return func(h *Handler) {
+		h.monitor = monitor
+	}
+}
+
+// watchStatus streams the current health of the upstream instance, and then every transition of it, as server-sent
+// events named health, until the client goes away.
+func (h *Handler) watchStatus(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", http.MethodGet)
+		writeError(w, http.StatusMethodNotAllowed, http.StatusText(http.StatusMethodNotAllowed))
+		return
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeError(w, http.StatusInternalServerError, "streaming unsupported")
+		return
+	}
+	changes, unsubscribe := h.monitor.Subscribe()
+	defer unsubscribe()
+	w.Header().Set(headerContentType, contentTypeEventData)
+	w.Header().Set(headerCacheControl, "no-cache")
+	w.WriteHeader(http.StatusOK)
+	if err := writeEvent(w, newHealthEvent(h.monitor.Last())); err != nil {
+		return
+	}
+	flusher.Flush()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case change, ok := <-changes:
+			if !ok {
+				return
+			}
+			if err := writeEvent(w, newHealthEvent(change)); err != nil {
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
+
+// writeEvent writes the given HealthEvent as a server-sent event.
+func writeEvent(w http.ResponseWriter, event HealthEvent) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", eventHealth, data)
+	return err
+}
@@ -0,0 +1,88 @@
+package server_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/diegohordi/nominatim"
+	"github.com/diegohordi/nominatim/server"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// statusFunc is a nominatim.StatusHandler failing while down is set.
+type statusFunc struct {
+	down int32
+}
+
+func (s *statusFunc) CheckStatus(context.Context, ...nominatim.CallOption) (nominatim.Status, error) {
+	if atomic.LoadInt32(&s.down) == 1 {
+		return nominatim.Status{Status: nominatim.StatusNoDatabase, Message: "no database"}, errors.New("no database")
+	}
+	return nominatim.Status{Message: "OK"}, nil
+}
+
+// readEvent reads the next health event from the given stream.
+func readEvent(t *testing.T, stream *bufio.Reader) server.HealthEvent {
+	t.Helper()
+	event := server.HealthEvent{}
+	for {
+		line, err := stream.ReadString('\n')
+		if err != nil {
+			t.Fatalf("ReadString() error = %v", err)
+		}
+		if data := strings.TrimPrefix(line, "data: "); data != line {
+			if err := json.Unmarshal([]byte(data), &event); err != nil {
+				t.Fatalf("Unmarshal() error = %v", err)
+			}
+		}
+		if line == "\n" {
+			return event
+		}
+	}
+}
+
+func TestHandler_WatchStatus(t *testing.T) {
+	status := &statusFunc{}
+	monitor := nominatim.NewMonitor(status)
+	monitor.Check(context.TODO())
+	handler := server.New(nominatim.NewClient("http://nominatim.invalid", nil), server.WithMonitor(monitor))
+	gateway := httptest.NewServer(handler)
+	t.Cleanup(gateway.Close)
+
+	ctx, cancel := context.WithTimeout(context.TODO(), 5*time.Second)
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, gateway.URL+server.PathWatchStatus, nil)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("Do() error = %v", err)
+	}
+	defer resp.Body.Close()
+	if got := resp.Header.Get("Content-Type"); resp.StatusCode != http.StatusOK || got != "text/event-stream" {
+		t.Fatalf("ServeHTTP() status = %v, content type = %v, want an event stream", resp.StatusCode, got)
+	}
+	stream := bufio.NewReader(resp.Body)
+	if event := readEvent(t, stream); event.To != nominatim.HealthHealthy {
+		t.Errorf("ServeHTTP() first event = %+v, want the current health", event)
+	}
+	atomic.StoreInt32(&status.down, 1)
+	monitor.Check(context.TODO())
+	event := readEvent(t, stream)
+	if event.From != nominatim.HealthHealthy || event.To != nominatim.HealthDegraded || event.Status != nominatim.StatusNoDatabase || event.Error == "" {
+		t.Errorf("ServeHTTP() event = %+v, want the transition to degraded", event)
+	}
+}
+
+func TestHandler_WatchStatus_withoutMonitor(t *testing.T) {
+	handler := server.New(nominatim.NewClient("http://nominatim.invalid", nil))
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, server.PathWatchStatus, nil))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("ServeHTTP() status = %v, want %v", rec.Code, http.StatusNotFound)
+	}
+}
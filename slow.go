@@ -0,0 +1,26 @@
+package nominatim
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// WithSlowRequestThreshold makes the client report the requests taking longer than the given threshold, from being
+// sent until their response is read, so degraded instances are spotted early. They are logged by the logger of
+// WithLogger, at the Slow level, and given to the OnSlowRequest hook of WithHooks. Non-positive thresholds disable it.
+func WithSlowRequestThreshold(threshold time.Duration) Option {
+	return func(d *defaultClient) {
+		d.slowRequestThreshold = threshold
+	}
+}
+
+// finishRequest notifies the observers that the request for the given query, started at the given time, is done,
+// reporting it as slow when it took longer than the threshold.
+func (d defaultClient) finishRequest(ctx context.Context, query QueryEncoder, req *http.Request, resp *http.Response, start time.Time, err error) {
+	duration := time.Since(start)
+	if d.slowRequestThreshold > 0 && duration > d.slowRequestThreshold {
+		d.observers.slowRequest(ctx, query, req, duration)
+	}
+	d.observers.requestFinished(ctx, query, req, resp, duration, err)
+}
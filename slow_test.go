@@ -0,0 +1,60 @@
+package nominatim_test
+
+import (
+	"context"
+	"github.com/diegohordi/nominatim"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+)
+
+func Test_WithSlowRequestThreshold(t *testing.T) {
+	tests := []struct {
+		name      string
+		threshold time.Duration
+		wantSlow  bool
+	}{
+		{
+			name:      "should report the requests slower than the threshold",
+			threshold: 10 * time.Millisecond,
+			wantSlow:  true,
+		},
+		{
+			name:      "should not report the requests faster than the threshold",
+			threshold: time.Minute,
+		},
+		{
+			name: "should not report any request without threshold",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			transport := TransportFunc(func(req *http.Request) (*http.Response, error) {
+				time.Sleep(30 * time.Millisecond)
+				return statusFixture(http.StatusOK, mustLoadValidStatus(t))()
+			})
+			mu := sync.Mutex{}
+			var slow []string
+			hooks := nominatim.Hooks{OnSlowRequest: func(req *http.Request, duration time.Duration) {
+				mu.Lock()
+				defer mu.Unlock()
+				if duration >= 30*time.Millisecond {
+					slow = append(slow, req.URL.Path)
+				}
+			}}
+			d := nominatim.NewClient("http://localhost:8080", nil, nominatim.WithTransport(transport),
+				nominatim.WithHooks(hooks), nominatim.WithSlowRequestThreshold(tt.threshold))
+			if _, err := d.CheckStatus(context.TODO()); err != nil {
+				t.Fatal(err)
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			if got := len(slow) == 1 && slow[0] == "/status"; got != tt.wantSlow {
+				t.Errorf("CheckStatus() slow requests = %v, wantSlow %v", slow, tt.wantSlow)
+			}
+		})
+	}
+}
@@ -0,0 +1,104 @@
+package nominatim
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// statusTextOK is the body sent by the status endpoint in text format when the server is healthy.
+const statusTextOK = "OK"
+
+// statusTextErrorPrefix prefixes the bodies sent by the status endpoint in text format when the server fails.
+const statusTextErrorPrefix = "ERROR:"
+
+// statusErrors maps the status codes reported by the status endpoint to the sentinel errors matched by their
+// StatusError.
+var statusErrors = map[int]error{
+	StatusNoDatabase:       ErrNoDatabase,
+	StatusModuleFailed:     ErrModuleFailed,
+	StatusModuleCallFailed: ErrModuleCallFailed,
+	StatusQueryFailed:      ErrQueryFailed,
+	StatusNoValue:          ErrNoValue,
+}
+
+// statusMessages maps the messages reported by the status endpoint, lower-cased, to their status codes.
+var statusMessages = map[string]int{
+	"no database":                StatusNoDatabase,
+	"database connection failed": StatusNoDatabase,
+	"module failed":              StatusModuleFailed,
+	"module call failed":         StatusModuleCallFailed,
+	"query failed":               StatusQueryFailed,
+	"no value":                   StatusNoValue,
+}
+
+// StatusError holds the failure reported by the status endpoint, either through the status of its JSON body or
+// through an HTTP 500 with a plain text body, as sent by the servers not honoring the JSON format. It matches
+// ErrServerError through errors.Is, along with the sentinel error of its code, as ErrNoDatabase.
+type StatusError struct {
+
+	// Code is the status code reported, as StatusNoDatabase, or zero if it couldn't be told from the message.
+	Code int
+
+	// Message is the message reported.
+	Message string
+}
+
+func (e StatusError) Error() string {
+	if e.Code == 0 {
+		return "nominatim: server reported " + e.Message
+	}
+	return fmt.Sprintf("nominatim: status %d: %s", e.Code, e.Message)
+}
+
+// Is reports whether the StatusError matches the given sentinel error.
+func (e StatusError) Is(target error) bool {
+	return target == ErrServerError || (target != nil && target == statusErrors[e.Code])
+}
+
+// decodeStatus decodes the given response of the status endpoint into the given status, either from its JSON or
+// text format, returning a StatusError when the server reports a failure, along with the status reported.
+func decodeStatus(resp *http.Response, body []byte, status *Status, strict bool) error {
+	failed := resp.StatusCode >= http.StatusBadRequest
+	if failed {
+		err := newResponseError(resp, body)
+		if !errors.Is(err, ErrServerError) || errors.Is(err, ErrRateLimited) {
+			return err
+		}
+	}
+	text := strings.TrimSpace(string(body))
+	if message, ok := trimStatusErrorPrefix(text); ok {
+		*status = Status{Status: statusMessages[strings.ToLower(message)], Message: message}
+		return StatusError{Code: status.Status, Message: status.Message}
+	}
+	if !failed && strings.EqualFold(text, statusTextOK) {
+		*status = Status{Message: text}
+		return nil
+	}
+	if !failed {
+		if err := unmarshal(body, status, strict); err != nil {
+			return err
+		}
+		if status.Status == 0 {
+			return nil
+		}
+		return StatusError{Code: status.Status, Message: status.Message}
+	}
+	reported := &Status{}
+	if err := json.Unmarshal(body, reported); err != nil || reported.Status == 0 {
+		return newResponseError(resp, body)
+	}
+	*status = *reported
+	return StatusError{Code: status.Status, Message: status.Message}
+}
+
+// trimStatusErrorPrefix trims the prefix of the failures reported by the status endpoint in text format from the
+// given text, if any, reporting whether it was found.
+func trimStatusErrorPrefix(text string) (string, bool) {
+	if len(text) < len(statusTextErrorPrefix) || !strings.EqualFold(text[:len(statusTextErrorPrefix)], statusTextErrorPrefix) {
+		return text, false
+	}
+	return strings.TrimSpace(text[len(statusTextErrorPrefix):]), true
+}
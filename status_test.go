@@ -0,0 +1,112 @@
+package nominatim_test
+
+import (
+	"context"
+	"errors"
+	"github.com/diegohordi/nominatim"
+	"github.com/diegohordi/nominatim/nominatimtest"
+	"net/http"
+	"testing"
+)
+
+func Test_CheckStatus_Failures(t *testing.T) {
+	tests := []struct {
+		name          string
+		fixture       nominatimtest.Fixture
+		want          nominatim.Status
+		wantStatusErr *nominatim.StatusError
+		wantErr       error
+	}{
+		{
+			name:    "should decode the text format",
+			fixture: nominatimtest.Fixture{Body: []byte("OK")},
+			want:    nominatim.Status{Message: "OK"},
+		},
+		{
+			name:          "should report the status of the JSON format",
+			fixture:       nominatimtest.Fixture{Body: []byte(`{"status": 700, "message": "No database"}`)},
+			want:          nominatim.Status{Status: nominatim.StatusNoDatabase, Message: "No database"},
+			wantStatusErr: &nominatim.StatusError{Code: nominatim.StatusNoDatabase, Message: "No database"},
+			wantErr:       nominatim.ErrServerError,
+		},
+		{
+			name:          "should report the status of the JSON format sent along with an HTTP 500",
+			fixture:       nominatimtest.Fixture{StatusCode: http.StatusInternalServerError, Body: []byte(`{"status": 701, "message": "Module failed"}`)},
+			want:          nominatim.Status{Status: nominatim.StatusModuleFailed, Message: "Module failed"},
+			wantStatusErr: &nominatim.StatusError{Code: nominatim.StatusModuleFailed, Message: "Module failed"},
+			wantErr:       nominatim.ErrModuleFailed,
+		},
+		{
+			name:          "should report the status of the text format",
+			fixture:       nominatimtest.Fixture{StatusCode: http.StatusInternalServerError, Body: []byte("ERROR: Database connection failed")},
+			want:          nominatim.Status{Status: nominatim.StatusNoDatabase, Message: "Database connection failed"},
+			wantStatusErr: &nominatim.StatusError{Code: nominatim.StatusNoDatabase, Message: "Database connection failed"},
+			wantErr:       nominatim.ErrNoDatabase,
+		},
+		{
+			name:          "should report the unknown messages of the text format",
+			fixture:       nominatimtest.Fixture{StatusCode: http.StatusInternalServerError, Body: []byte("ERROR: Disk full")},
+			want:          nominatim.Status{Message: "Disk full"},
+			wantStatusErr: &nominatim.StatusError{Message: "Disk full"},
+			wantErr:       nominatim.ErrServerError,
+		},
+		{
+			name:    "should return the errors not reported by the status endpoint",
+			fixture: nominatimtest.Fixture{StatusCode: http.StatusBadGateway, Body: []byte("<html></html>")},
+			wantErr: nominatim.ErrServerError,
+		},
+		{
+			name:    "should return the rate limits",
+			fixture: nominatimtest.ErrorFixture(http.StatusTooManyRequests),
+			wantErr: nominatim.ErrRateLimited,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			server := nominatimtest.NewServer()
+			t.Cleanup(server.Close)
+			server.SetFixture(nominatim.EndpointStatus, tt.fixture)
+			got, err := nominatim.NewClient(server.URL, nil).CheckStatus(context.TODO())
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("CheckStatus() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			var statusErr nominatim.StatusError
+			if ok := errors.As(err, &statusErr); ok != (tt.wantStatusErr != nil) || (ok && statusErr != *tt.wantStatusErr) {
+				t.Errorf("CheckStatus() error = %#v, want %#v", err, tt.wantStatusErr)
+			}
+			if got != tt.want {
+				t.Errorf("CheckStatus() got = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStatusError_Is(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    nominatim.StatusError
+		target error
+		want   bool
+	}{
+		{name: "should match the server errors", err: nominatim.StatusError{Message: "Disk full"}, target: nominatim.ErrServerError, want: true},
+		{name: "should match no database", err: nominatim.StatusError{Code: nominatim.StatusNoDatabase}, target: nominatim.ErrNoDatabase, want: true},
+		{name: "should match module failed", err: nominatim.StatusError{Code: nominatim.StatusModuleFailed}, target: nominatim.ErrModuleFailed, want: true},
+		{name: "should match module call failed", err: nominatim.StatusError{Code: nominatim.StatusModuleCallFailed}, target: nominatim.ErrModuleCallFailed, want: true},
+		{name: "should match query failed", err: nominatim.StatusError{Code: nominatim.StatusQueryFailed}, target: nominatim.ErrQueryFailed, want: true},
+		{name: "should match no value", err: nominatim.StatusError{Code: nominatim.StatusNoValue}, target: nominatim.ErrNoValue, want: true},
+		{name: "should not match another code", err: nominatim.StatusError{Code: nominatim.StatusNoValue}, target: nominatim.ErrNoDatabase},
+		{name: "should not match the unknown codes", err: nominatim.StatusError{Message: "Disk full"}, target: nominatim.ErrNoDatabase},
+		{name: "should not match the rate limits", err: nominatim.StatusError{Code: nominatim.StatusNoDatabase}, target: nominatim.ErrRateLimited},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := errors.Is(tt.err, tt.target); got != tt.want {
+				t.Errorf("errors.Is(%v, %v) got = %v, want %v", tt.err, tt.target, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,99 @@
+package nominatim
+
+import (
+	"context"
+	"errors"
+)
+
+// Outcome is the category of the outcome of a call, telling apart the failures to reach the server, the ones sent by
+// the server and the ones due to the data, so batch jobs can decide what to do with each one.
+type Outcome string
+
+const (
+	OutcomeSuccess        Outcome = "success"
+	OutcomeNoResult       Outcome = "no-result"
+	OutcomeClientError    Outcome = "client-error"
+	OutcomeServerError    Outcome = "server-error"
+	OutcomeRateLimited    Outcome = "rate-limited"
+	OutcomeCancelled      Outcome = "cancelled"
+	OutcomeTransportError Outcome = "transport-error"
+	OutcomeDataError      Outcome = "data-error"
+)
+
+// maxSummarySamples is the number of sample errors kept by a BatchSummary per outcome.
+const maxSummarySamples = 5
+
+// ClassifyOutcome categorizes the outcome of a call by the error it returned, if any:
+//
+// - OutcomeSuccess: no error;
+// - OutcomeCancelled: the context was cancelled or its deadline exceeded;
+// - OutcomeRateLimited: the server refused the request due to its usage policy;
+// - OutcomeNoResult: nothing matched the query, or the location couldn't be geocoded;
+// - OutcomeServerError: the server failed to process the request;
+// - OutcomeTransportError: the server couldn't be reached;
+// - OutcomeClientError: the query was rejected, or the endpoint is unavailable;
+// - OutcomeDataError: the response couldn't be decoded, or any other failure.
+func ClassifyOutcome(err error) Outcome {
+	var transportErr transportError
+	switch {
+	case err == nil:
+		return OutcomeSuccess
+	case errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded):
+		return OutcomeCancelled
+	case errors.Is(err, ErrRateLimited):
+		return OutcomeRateLimited
+	case errors.Is(err, ErrNoResults):
+		return OutcomeNoResult
+	case errors.Is(err, ErrServerError):
+		return OutcomeServerError
+	case errors.As(err, &transportErr):
+		return OutcomeTransportError
+	case errors.Is(err, ErrInvalidQuery) || errors.Is(err, ErrEndpointUnavailable):
+		return OutcomeClientError
+	}
+	return OutcomeDataError
+}
+
+// BatchSummary summarizes the outcomes of a batch of calls by category, with their counts and a few sample errors,
+// enabling automated decisions, as retrying only the rate-limited ones later.
+type BatchSummary struct {
+	Total   int                  `json:"total"`
+	Counts  map[Outcome]int      `json:"counts"`
+	Samples map[Outcome][]string `json:"samples,omitempty"`
+}
+
+// NewBatchSummary creates an empty BatchSummary.
+func NewBatchSummary() *BatchSummary {
+	return &BatchSummary{Counts: make(map[Outcome]int), Samples: make(map[Outcome][]string)}
+}
+
+// Add adds to the summary the outcome of a call, by the error it returned, if any, returning its category.
+func (s *BatchSummary) Add(err error) Outcome {
+	outcome := ClassifyOutcome(err)
+	s.Total++
+	s.Counts[outcome]++
+	if err != nil && len(s.Samples[outcome]) < maxSummarySamples {
+		s.Samples[outcome] = append(s.Samples[outcome], err.Error())
+	}
+	return outcome
+}
+
+// Merge adds to the summary the outcomes of the given one.
+func (s *BatchSummary) Merge(other *BatchSummary) {
+	s.Total += other.Total
+	for outcome, count := range other.Counts {
+		s.Counts[outcome] += count
+	}
+	for outcome, samples := range other.Samples {
+		for _, sample := range samples {
+			if len(s.Samples[outcome]) < maxSummarySamples {
+				s.Samples[outcome] = append(s.Samples[outcome], sample)
+			}
+		}
+	}
+}
+
+// Failed returns the number of calls not succeeded, including the ones that found no results.
+func (s *BatchSummary) Failed() int {
+	return s.Total - s.Counts[OutcomeSuccess]
+}
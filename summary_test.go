@@ -0,0 +1,88 @@
+package nominatim_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/diegohordi/nominatim"
+	"net/http"
+	"testing"
+)
+
+func TestClassifyOutcome(t *testing.T) {
+	tests := []struct {
+		name     string
+		response func() (*http.Response, error)
+		want     nominatim.Outcome
+	}{
+		{name: "should be success", response: statusFixture(http.StatusOK, mustLoadValidStatus(t)), want: nominatim.OutcomeSuccess},
+		{name: "should be rate limited", response: statusFixture(http.StatusTooManyRequests, nil), want: nominatim.OutcomeRateLimited},
+		{name: "should be no result", response: statusFixture(http.StatusOK, mustLoadUnableToGeocodeReverseResult(t)), want: nominatim.OutcomeNoResult},
+		{name: "should be server error", response: statusFixture(http.StatusBadGateway, nil), want: nominatim.OutcomeServerError},
+		{name: "should be client error", response: statusFixture(http.StatusBadRequest, nil), want: nominatim.OutcomeClientError},
+		{name: "should be client error due to unavailable endpoint", response: statusFixture(http.StatusNotFound, nil), want: nominatim.OutcomeClientError},
+		{name: "should be data error", response: statusFixture(http.StatusOK, []byte("<html>")), want: nominatim.OutcomeDataError},
+		{
+			name:     "should be transport error",
+			response: func() (*http.Response, error) { return nil, errors.New("connection refused") },
+			want:     nominatim.OutcomeTransportError,
+		},
+		{
+			name:     "should be cancelled",
+			response: func() (*http.Response, error) { return nil, context.DeadlineExceeded },
+			want:     nominatim.OutcomeCancelled,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			transport := TransportFunc(func(req *http.Request) (*http.Response, error) {
+				return tt.response()
+			})
+			d := nominatim.NewClient("http://localhost:8080", nil, nominatim.WithTransport(transport))
+			_, err := d.CheckStatus(context.TODO())
+			if got := nominatim.ClassifyOutcome(err); got != tt.want {
+				t.Errorf("ClassifyOutcome(%v) got = %v, want %v", err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBatchSummary(t *testing.T) {
+	summary := nominatim.NewBatchSummary()
+	summary.Add(nil)
+	summary.Add(nominatim.ErrNoResults)
+	for i := 0; i < 7; i++ {
+		summary.Add(nominatim.RateLimitError{Err: nominatim.Error{Code: http.StatusTooManyRequests, Message: fmt.Sprint(i)}})
+	}
+	other := nominatim.NewBatchSummary()
+	other.Add(nil)
+	other.Add(context.Canceled)
+	summary.Merge(other)
+
+	if summary.Total != 11 || summary.Failed() != 9 {
+		t.Errorf("BatchSummary got total = %d, failed = %d, want 11 and 9", summary.Total, summary.Failed())
+	}
+	wantCounts := map[nominatim.Outcome]int{
+		nominatim.OutcomeSuccess:     2,
+		nominatim.OutcomeNoResult:    1,
+		nominatim.OutcomeRateLimited: 7,
+		nominatim.OutcomeCancelled:   1,
+	}
+	for outcome, want := range wantCounts {
+		if got := summary.Counts[outcome]; got != want {
+			t.Errorf("BatchSummary got %d %s, want %d", got, outcome, want)
+		}
+	}
+	if got := len(summary.Samples[nominatim.OutcomeRateLimited]); got != 5 {
+		t.Errorf("BatchSummary got %d rate limited samples, want 5", got)
+	}
+	if _, ok := summary.Samples[nominatim.OutcomeSuccess]; ok {
+		t.Errorf("BatchSummary got success samples, want none")
+	}
+	if _, err := json.Marshal(summary); err != nil {
+		t.Errorf("BatchSummary JSON error = %v", err)
+	}
+}
@@ -0,0 +1,48 @@
+package nominatim
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"time"
+)
+
+// defaultRevalidateTimeout is how long a background revalidation may take.
+const defaultRevalidateTimeout = 30 * time.Second
+
+// staleWhileRevalidate holds the stale-while-revalidate configuration, as well as the keys being revalidated.
+type staleWhileRevalidate struct {
+	maxStale     time.Duration
+	revalidating sync.Map
+}
+
+// WithStaleWhileRevalidate makes the client keep the cached responses for the given period after they expire,
+// serving them immediately while they are refreshed in the background, so known queries never block on the server.
+// It requires a cache, given through WithCache.
+func WithStaleWhileRevalidate(maxStale time.Duration) Option {
+	return func(d *defaultClient) {
+		if maxStale <= 0 {
+			d.staleWhileRevalidate = nil
+			return
+		}
+		d.staleWhileRevalidate = &staleWhileRevalidate{maxStale: maxStale}
+	}
+}
+
+// revalidate refreshes in the background the cached response to the given query, unless already being refreshed.
+// The response is decoded into a new value of the same type of v, and cached on success.
+func (d defaultClient) revalidate(key string, query QueryEncoder, v interface{}, entry cacheEntry) {
+	if _, loaded := d.staleWhileRevalidate.revalidating.LoadOrStore(key, struct{}{}); loaded {
+		return
+	}
+	target := reflect.New(reflect.TypeOf(v).Elem()).Interface()
+	go func() {
+		defer d.staleWhileRevalidate.revalidating.Delete(key)
+		ctx, cancel := context.WithTimeout(context.Background(), defaultRevalidateTimeout)
+		defer cancel()
+		if d.conditionalKeep > 0 && entry.conditional() {
+			ctx = withConditionalEntry(ctx, entry)
+		}
+		_ = d.fetch(ctx, query, target)
+	}()
+}
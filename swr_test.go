@@ -0,0 +1,74 @@
+package nominatim_test
+
+import (
+	"context"
+	"github.com/diegohordi/nominatim"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func Test_WithStaleWhileRevalidate(t *testing.T) {
+	var requests int32
+	transport := TransportFunc(func(req *http.Request) (*http.Response, error) {
+		atomic.AddInt32(&requests, 1)
+		return statusFixture(http.StatusOK, mustLoadValidSearchResults(t))()
+	})
+	ttl := 200 * time.Millisecond
+	d := nominatim.NewClient("http://localhost:8080", nil, nominatim.WithTransport(transport),
+		nominatim.WithCache(nominatim.NewLRUCache(10), ttl), nominatim.WithStaleWhileRevalidate(time.Hour))
+	search := func() *nominatim.ResponseMetadata {
+		metadata := &nominatim.ResponseMetadata{}
+		query := nominatim.NewSearchQuery()
+		query.FreeFormQuery = "lisboa"
+		if _, err := d.Search(nominatim.WithResponseMetadata(context.TODO(), metadata), *query); err != nil {
+			t.Fatalf("Search() error = %v", err)
+		}
+		return metadata
+	}
+	waitRequests := func(want int32) {
+		deadline := time.Now().Add(time.Second)
+		for atomic.LoadInt32(&requests) < want && time.Now().Before(deadline) {
+			time.Sleep(time.Millisecond)
+		}
+		time.Sleep(10 * time.Millisecond)
+		if got := atomic.LoadInt32(&requests); got != want {
+			t.Fatalf("requests = %v, want %v", got, want)
+		}
+	}
+	if metadata := search(); metadata.Cached {
+		t.Errorf("Search() cached = true, want the first query sent")
+	}
+	waitRequests(1)
+	time.Sleep(ttl + 50*time.Millisecond)
+	if metadata := search(); !metadata.Cached || !metadata.Stale {
+		t.Errorf("Search() got = %+v, want the expired response served as stale", metadata)
+	}
+	waitRequests(2)
+	if metadata := search(); !metadata.Cached || metadata.Stale {
+		t.Errorf("Search() got = %+v, want the revalidated response served as fresh", metadata)
+	}
+	waitRequests(2)
+}
+
+func Test_WithStaleWhileRevalidate_Disabled(t *testing.T) {
+	var requests int32
+	transport := TransportFunc(func(req *http.Request) (*http.Response, error) {
+		atomic.AddInt32(&requests, 1)
+		return statusFixture(http.StatusOK, mustLoadValidSearchResults(t))()
+	})
+	d := nominatim.NewClient("http://localhost:8080", nil, nominatim.WithTransport(transport),
+		nominatim.WithCache(nominatim.NewLRUCache(10), time.Millisecond), nominatim.WithStaleWhileRevalidate(0))
+	query := nominatim.NewSearchQuery()
+	query.FreeFormQuery = "lisboa"
+	for i := 0; i < 2; i++ {
+		if _, err := d.Search(context.TODO(), *query); err != nil {
+			t.Fatalf("Search() error = %v", err)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	if got := atomic.LoadInt32(&requests); got != 2 {
+		t.Errorf("requests = %v, want the expired response sent again", got)
+	}
+}
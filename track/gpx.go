@@ -0,0 +1,65 @@
+package track
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/diegohordi/nominatim/geo"
+)
+
+// gpx holds the parts of a GPX document holding track points.
+type gpx struct {
+	Tracks []struct {
+		Segments []struct {
+			Points []gpxPoint `xml:"trkpt"`
+		} `xml:"trkseg"`
+	} `xml:"trk"`
+	Routes []struct {
+		Points []gpxPoint `xml:"rtept"`
+	} `xml:"rte"`
+}
+
+// gpxPoint holds a track or route point of a GPX document.
+type gpxPoint struct {
+	Lat  float64 `xml:"lat,attr"`
+	Lon  float64 `xml:"lon,attr"`
+	Time string  `xml:"time"`
+}
+
+// ReadGPX reads the points of the tracks of the given GPX document, in order, joining their segments, or the points
+// of its routes, when it has no tracks.
+func ReadGPX(r io.Reader) ([]Point, error) {
+	doc := &gpx{}
+	if err := xml.NewDecoder(r).Decode(doc); err != nil {
+		return nil, fmt.Errorf("track: reading GPX: %w", err)
+	}
+	raw := make([]gpxPoint, 0)
+	for _, track := range doc.Tracks {
+		for _, segment := range track.Segments {
+			raw = append(raw, segment.Points...)
+		}
+	}
+	if len(raw) == 0 {
+		for _, route := range doc.Routes {
+			raw = append(raw, route.Points...)
+		}
+	}
+	points := make([]Point, 0, len(raw))
+	for _, p := range raw {
+		point := Point{Point: geo.Point{Lat: p.Lat, Lon: p.Lon}}
+		if !point.Valid() {
+			return nil, fmt.Errorf("track: invalid GPX point %v,%v", p.Lat, p.Lon)
+		}
+		if p.Time != "" {
+			t, err := time.Parse(time.RFC3339, p.Time)
+			if err != nil {
+				return nil, fmt.Errorf("track: invalid GPX time %q: %w", p.Time, err)
+			}
+			point.Time = t
+		}
+		points = append(points, point)
+	}
+	return points, nil
+}
@@ -0,0 +1,74 @@
+package track_test
+
+import (
+	"github.com/diegohordi/nominatim/geo"
+	"github.com/diegohordi/nominatim/track"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestReadGPX(t *testing.T) {
+	tests := []struct {
+		name    string
+		gpx     string
+		want    []track.Point
+		wantErr bool
+	}{
+		{
+			name: "should read the points of every track segment",
+			gpx: `<?xml version="1.0"?>
+<gpx version="1.1" xmlns="http://www.topografix.com/GPX/1/1">
+  <trk><trkseg>
+    <trkpt lat="38.6916" lon="-9.2160"><time>2021-11-25T17:16:32Z</time></trkpt>
+    <trkpt lat="38.6918" lon="-9.2140"></trkpt>
+  </trkseg><trkseg>
+    <trkpt lat="38.7075" lon="-9.1364"></trkpt>
+  </trkseg></trk>
+</gpx>`,
+			want: []track.Point{
+				{Point: geo.Point{Lat: 38.6916, Lon: -9.2160}, Time: time.Date(2021, 11, 25, 17, 16, 32, 0, time.UTC)},
+				{Point: geo.Point{Lat: 38.6918, Lon: -9.2140}},
+				{Point: geo.Point{Lat: 38.7075, Lon: -9.1364}},
+			},
+		},
+		{
+			name: "should read the route points without tracks",
+			gpx:  `<gpx><rte><rtept lat="41.1486" lon="-8.6110"/></rte></gpx>`,
+			want: []track.Point{{Point: geo.Point{Lat: 41.1486, Lon: -8.6110}}},
+		},
+		{
+			name:    "should fail due to an invalid point",
+			gpx:     `<gpx><trk><trkseg><trkpt lat="98.6916" lon="-9.2160"/></trkseg></trk></gpx>`,
+			wantErr: true,
+		},
+		{
+			name:    "should fail due to an invalid time",
+			gpx:     `<gpx><trk><trkseg><trkpt lat="38.6916" lon="-9.2160"><time>yesterday</time></trkpt></trkseg></trk></gpx>`,
+			wantErr: true,
+		},
+		{
+			name:    "should fail due to an invalid document",
+			gpx:     `<gpx><trk>`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := track.ReadGPX(strings.NewReader(tt.gpx))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ReadGPX() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("ReadGPX() = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if !got[i].Time.Equal(tt.want[i].Time) || got[i].Point != tt.want[i].Point {
+					t.Errorf("ReadGPX() point %d = %v, want %v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,182 @@
+// Package track implements the reverse geocoding of tracks, as the ones recorded by GPS devices, into address
+// timelines: the track points are sampled at a distance interval, the samples reverse geocoded, and the consecutive
+// samples resolving to the same road merged into segments, as for summarizing trips.
+package track
+
+import (
+	"context"
+	"time"
+
+	"github.com/diegohordi/nominatim"
+	"github.com/diegohordi/nominatim/geo"
+)
+
+// defaultInterval is the distance between the samples, in meters, by default.
+const defaultInterval = 500
+
+// Point is a point of a track, along with the time it was recorded, if known.
+type Point struct {
+	geo.Point
+	Time time.Time
+}
+
+// Segment is a part of a track resolving to the same address, from the first sample resolving to it up to the last
+// one.
+type Segment struct {
+
+	// Start and End are the first and the last samples of the Segment, which are the same when it has one sample.
+	Start Point
+	End   Point
+
+	// StartDistance and EndDistance are the distances along the track from its first point up to the Start and the
+	// End of the Segment, in meters.
+	StartDistance float64
+	EndDistance   float64
+
+	// Samples is the number of samples of the Segment.
+	Samples int
+
+	// Result is the address the samples resolved to, unless Err is set.
+	Result nominatim.Result
+	Err    error
+}
+
+// Option configures Timeline.
+type Option func(config *config)
+
+// config holds the configuration of Timeline.
+type config struct {
+	interval     float64
+	languages    []string
+	key          func(result nominatim.Result) string
+	batchOptions []nominatim.BatchOption
+}
+
+// WithInterval makes Timeline sample the track every given number of meters, 500 by default. Non-positive values
+// mean the default.
+func WithInterval(meters float64) Option {
+	return func(config *config) {
+		config.interval = meters
+	}
+}
+
+// WithLanguages makes Timeline reverse geocode the samples in the given languages, in order of preference.
+func WithLanguages(languages ...string) Option {
+	return func(config *config) {
+		config.languages = languages
+	}
+}
+
+// WithSegmentKey makes Timeline merge the consecutive samples whose results have the same given key, as their city,
+// or their display name for finer segments, instead of their road and their locality.
+func WithSegmentKey(key func(result nominatim.Result) string) Option {
+	return func(config *config) {
+		config.key = key
+	}
+}
+
+// WithBatchOptions makes Timeline reverse geocode the samples with the given options, as the number of workers.
+func WithBatchOptions(opts ...nominatim.BatchOption) Option {
+	return func(config *config) {
+		config.batchOptions = append(config.batchOptions, opts...)
+	}
+}
+
+// Sample returns the points of the given track at least the given number of meters apart along it, always including
+// its first and last points.
+func Sample(points []Point, interval float64) []Point {
+	samples, _ := sample(points, interval)
+	return samples
+}
+
+// sample samples the given track as in Sample, returning also the distances of the samples along it.
+func sample(points []Point, interval float64) ([]Point, []float64) {
+	if len(points) == 0 {
+		return nil, nil
+	}
+	samples, distances := []Point{points[0]}, []float64{0}
+	travelled, sampledAt := 0.0, 0.0
+	for i := 1; i < len(points); i++ {
+		travelled += geo.DistanceMeters(points[i-1].Point, points[i].Point)
+		if travelled-sampledAt >= interval || i == len(points)-1 {
+			samples, distances = append(samples, points[i]), append(distances, travelled)
+			sampledAt = travelled
+		}
+	}
+	return samples, distances
+}
+
+// Timeline samples the given track, reverse geocodes the samples through the given client concurrently, and merges
+// the consecutive samples resolving to the same address, by their road and their locality, as the display names of
+// the reverse geocodes are usually down to the house, unless another key is given through WithSegmentKey, returning
+// the segments in the order of the track. The consecutive samples failing with the same
+// error are merged into a segment holding it, so a failure doesn't stop the others.
+func Timeline(ctx context.Context, client nominatim.ReverseHandler, points []Point, opts ...Option) []Segment {
+	config := config{interval: defaultInterval, key: roadAndLocality}
+	for _, opt := range opts {
+		opt(&config)
+	}
+	if config.interval <= 0 {
+		config.interval = defaultInterval
+	}
+	samples, distances := sample(points, config.interval)
+	queries := make([]nominatim.ReverseQuery, 0, len(samples))
+	for _, s := range samples {
+		query := nominatim.NewReverseQueryFromPoint(s.Point)
+		if len(config.languages) > 0 {
+			query.AcceptLanguage = config.languages
+		}
+		queries = append(queries, *query)
+	}
+	outcomes := nominatim.ReverseMany(ctx, client, queries, config.batchOptions...)
+	segments := make([]Segment, 0)
+	for i, outcome := range outcomes {
+		if n := len(segments); n > 0 && sameSegment(segments[n-1], outcome, config.key) {
+			segments[n-1].End, segments[n-1].EndDistance = samples[i], distances[i]
+			segments[n-1].Samples++
+			continue
+		}
+		segments = append(segments, Segment{
+			Start:         samples[i],
+			End:           samples[i],
+			StartDistance: distances[i],
+			EndDistance:   distances[i],
+			Samples:       1,
+			Result:        outcome.Result,
+			Err:           outcome.Err,
+		})
+	}
+	return segments
+}
+
+// sameSegment checks if the given outcome belongs to the given segment, either resolving to the same key or failing
+// with the same error.
+func sameSegment(segment Segment, outcome nominatim.ReverseResult, key func(result nominatim.Result) string) bool {
+	if segment.Err != nil || outcome.Err != nil {
+		return segment.Err != nil && outcome.Err != nil && segment.Err.Error() == outcome.Err.Error()
+	}
+	return key(segment.Result) == key(outcome.Result)
+}
+
+// roadAndLocality is the default key of the segments: the road of the result, or its neighbourhood or its suburb when
+// it has none, along with its city, its town or its village, or its display name when its address holds none of them,
+// as when the address details are not requested.
+func roadAndLocality(result nominatim.Result) string {
+	address := result.Address
+	road := firstOf(address.Road, address.Neighbourhood, address.Suburb)
+	locality := firstOf(address.City, address.Town, address.Village)
+	if road == "" && locality == "" {
+		return result.DisplayName
+	}
+	return road + "\n" + locality
+}
+
+// firstOf returns the first of the given values not empty, if any.
+func firstOf(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,195 @@
+package track_test
+
+import (
+	"context"
+	"errors"
+	"github.com/diegohordi/nominatim"
+	"github.com/diegohordi/nominatim/geo"
+	"github.com/diegohordi/nominatim/nominatimtest"
+	"github.com/diegohordi/nominatim/track"
+	"math"
+	"testing"
+)
+
+// belemToComercio is a track along the river, from Torre de Belém to Praça do Comércio.
+var belemToComercio = []track.Point{
+	{Point: geo.Point{Lat: 38.6916, Lon: -9.2160}},
+	{Point: geo.Point{Lat: 38.6918, Lon: -9.2140}},
+	{Point: geo.Point{Lat: 38.6920, Lon: -9.2120}},
+	{Point: geo.Point{Lat: 38.7070, Lon: -9.1400}},
+	{Point: geo.Point{Lat: 38.7073, Lon: -9.1380}},
+	{Point: geo.Point{Lat: 38.7075, Lon: -9.1364}},
+}
+
+func TestSample(t *testing.T) {
+	tests := []struct {
+		name     string
+		points   []track.Point
+		interval float64
+		want     []int
+	}{
+		{
+			name:     "should keep the points apart by the interval, plus the last one",
+			points:   belemToComercio,
+			interval: 1000,
+			want:     []int{0, 3, 5},
+		},
+		{
+			name:     "should keep every point given a small interval",
+			points:   belemToComercio,
+			interval: 1,
+			want:     []int{0, 1, 2, 3, 4, 5},
+		},
+		{
+			name:     "should keep a single point",
+			points:   belemToComercio[:1],
+			interval: 1000,
+			want:     []int{0},
+		},
+		{
+			name:     "should accept no points",
+			interval: 1000,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got := track.Sample(tt.points, tt.interval)
+			if len(got) != len(tt.want) {
+				t.Fatalf("Sample() = %v, want %v", got, tt.want)
+			}
+			for i, index := range tt.want {
+				if got[i] != tt.points[index] {
+					t.Errorf("Sample() point %d = %v, want %v", i, got[i], tt.points[index])
+				}
+			}
+		})
+	}
+}
+
+// reverseFunc is a ReverseHandler backed by a function.
+type reverseFunc func(query nominatim.ReverseQuery) (nominatim.Result, error)
+
+func (f reverseFunc) Reverse(_ context.Context, query nominatim.ReverseQuery, _ ...nominatim.CallOption) (nominatim.Result, error) {
+	return f(query)
+}
+
+func TestTimeline(t *testing.T) {
+	server := nominatimtest.NewServer()
+	t.Cleanup(server.Close)
+	client := nominatim.NewClient(server.URL, nil)
+	errServer := errors.New("server error")
+	houses := reverseFunc(func(query nominatim.ReverseQuery) (nominatim.Result, error) {
+		return nominatim.Result{
+			Name:        "Avenida da Índia",
+			DisplayName: query.Longitude + ", Avenida da Índia, Belém, Lisboa",
+			Address:     nominatim.Address{HouseNumber: query.Longitude, Road: "Avenida da Índia", City: "Lisboa"},
+		}, nil
+	})
+	tests := []struct {
+		name         string
+		client       nominatim.ReverseHandler
+		points       []track.Point
+		opts         []track.Option
+		wantNames    []string
+		wantSamples  []int
+		wantErrs     []error
+		wantDistance float64
+	}{
+		{
+			name:         "should merge the consecutive samples resolving to the same address",
+			client:       client,
+			points:       belemToComercio,
+			opts:         []track.Option{track.WithInterval(100), track.WithBatchOptions(nominatim.WithWorkers(2))},
+			wantNames:    []string{"Torre de Belém", "Praça do Comércio"},
+			wantSamples:  []int{3, 3},
+			wantErrs:     []error{nil, nil},
+			wantDistance: 7134,
+		},
+		{
+			name:   "should merge the samples by the given key",
+			client: client,
+			points: belemToComercio,
+			opts: []track.Option{track.WithInterval(100), track.WithSegmentKey(func(result nominatim.Result) string {
+				return result.Address.City
+			})},
+			wantNames:    []string{"Torre de Belém"},
+			wantSamples:  []int{6},
+			wantErrs:     []error{nil},
+			wantDistance: 7134,
+		},
+		{
+			name:         "should merge the houses of the same road",
+			client:       houses,
+			points:       belemToComercio,
+			opts:         []track.Option{track.WithInterval(100)},
+			wantNames:    []string{"Avenida da Índia"},
+			wantSamples:  []int{6},
+			wantErrs:     []error{nil},
+			wantDistance: 7134,
+		},
+		{
+			name:   "should keep the houses apart by their display names",
+			client: houses,
+			points: belemToComercio,
+			opts: []track.Option{track.WithInterval(100), track.WithSegmentKey(func(result nominatim.Result) string {
+				return result.DisplayName
+			})},
+			wantNames:    []string{"Avenida da Índia", "Avenida da Índia", "Avenida da Índia", "Avenida da Índia", "Avenida da Índia", "Avenida da Índia"},
+			wantSamples:  []int{1, 1, 1, 1, 1, 1},
+			wantErrs:     []error{nil, nil, nil, nil, nil, nil},
+			wantDistance: 7134,
+		},
+		{
+			name: "should merge the consecutive samples failing with the same error",
+			client: reverseFunc(func(query nominatim.ReverseQuery) (nominatim.Result, error) {
+				if query.Longitude == "-9.216" {
+					return nominatim.Result{Name: "Torre de Belém", DisplayName: "Torre de Belém"}, nil
+				}
+				return nominatim.Result{}, errServer
+			}),
+			points:       belemToComercio,
+			opts:         []track.Option{track.WithInterval(100)},
+			wantNames:    []string{"Torre de Belém", ""},
+			wantSamples:  []int{1, 5},
+			wantErrs:     []error{nil, errServer},
+			wantDistance: 7134,
+		},
+		{
+			name:   "should accept no points",
+			client: client,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			segments := track.Timeline(context.TODO(), tt.client, tt.points, tt.opts...)
+			if len(segments) != len(tt.wantNames) {
+				t.Fatalf("Timeline() segments = %v, want %v", len(segments), len(tt.wantNames))
+			}
+			for i, segment := range segments {
+				if segment.Result.Name != tt.wantNames[i] {
+					t.Errorf("Timeline() name = %v, want %v", segment.Result.Name, tt.wantNames[i])
+				}
+				if segment.Samples != tt.wantSamples[i] {
+					t.Errorf("Timeline() samples = %v, want %v", segment.Samples, tt.wantSamples[i])
+				}
+				if !errors.Is(segment.Err, tt.wantErrs[i]) {
+					t.Errorf("Timeline() error = %v, want %v", segment.Err, tt.wantErrs[i])
+				}
+			}
+			if len(segments) == 0 {
+				return
+			}
+			last := segments[len(segments)-1]
+			if last.End != tt.points[len(tt.points)-1] {
+				t.Errorf("Timeline() end = %v, want %v", last.End, tt.points[len(tt.points)-1])
+			}
+			if math.Abs(last.EndDistance-tt.wantDistance) > 10 {
+				t.Errorf("Timeline() distance = %v, want %v", last.EndDistance, tt.wantDistance)
+			}
+		})
+	}
+}
@@ -0,0 +1,58 @@
+package nominatim
+
+import (
+	"runtime/debug"
+)
+
+const (
+	headerUserAgent = "User-Agent"
+	headerReferer   = "Referer"
+	modulePath      = "github.com/diegohordi/nominatim"
+	userAgentPrefix = "diegohordi-nominatim/"
+	develVersion    = "devel"
+)
+
+// DefaultUserAgent is the User-Agent sent by the client unless another one is given through WithUserAgent, holding
+// the version of this package, as "diegohordi-nominatim/v1.2.3". As the OSM usage policy requires an User-Agent
+// identifying the application, one should be given when using the public instance.
+var DefaultUserAgent = userAgentPrefix + moduleVersion()
+
+// WithUserAgent makes the client identify itself with the given User-Agent, as required by the nominatim.org usage
+// policy. An empty one means the DefaultUserAgent.
+func WithUserAgent(userAgent string) Option {
+	return func(d *defaultClient) {
+		d.userAgent = userAgent
+		if userAgent == "" {
+			d.userAgent = DefaultUserAgent
+		}
+	}
+}
+
+// WithReferer makes the client send the given Referer, which the nominatim.org usage policy accepts as an alternative
+// identification, as for browser-originated traffic proxied through a backend. An empty one means none.
+func WithReferer(referer string) Option {
+	return func(d *defaultClient) {
+		d.referer = referer
+	}
+}
+
+// moduleVersion returns the version of this package, as built into the binary, if known.
+func moduleVersion() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return develVersion
+	}
+	modules := append([]*debug.Module{&info.Main}, info.Deps...)
+	for _, module := range modules {
+		if module.Path != modulePath {
+			continue
+		}
+		if module.Replace != nil {
+			module = module.Replace
+		}
+		if module.Version != "" && module.Version != "(devel)" {
+			return module.Version
+		}
+	}
+	return develVersion
+}
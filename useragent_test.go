@@ -0,0 +1,71 @@
+package nominatim_test
+
+import (
+	"context"
+	"github.com/diegohordi/nominatim"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func Test_WithUserAgent(t *testing.T) {
+	if !strings.HasPrefix(nominatim.DefaultUserAgent, "diegohordi-nominatim/") {
+		t.Errorf("DefaultUserAgent got = %s, want it identifying the package", nominatim.DefaultUserAgent)
+	}
+	tests := []struct {
+		name string
+		opts []nominatim.Option
+		want string
+	}{
+		{name: "should send the default User-Agent", want: nominatim.DefaultUserAgent},
+		{name: "should send the given User-Agent", opts: []nominatim.Option{nominatim.WithUserAgent("acme-geocoder/1.0")}, want: "acme-geocoder/1.0"},
+		{name: "should send the default User-Agent if empty", opts: []nominatim.Option{nominatim.WithUserAgent("")}, want: nominatim.DefaultUserAgent},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			var got string
+			transport := TransportFunc(func(req *http.Request) (*http.Response, error) {
+				got = req.Header.Get("User-Agent")
+				return statusFixture(http.StatusOK, mustLoadValidStatus(t))()
+			})
+			d := nominatim.NewClient("http://localhost:8080", nil, append(tt.opts, nominatim.WithTransport(transport))...)
+			if _, err := d.CheckStatus(context.TODO()); err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("CheckStatus() sent User-Agent = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_WithReferer(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []nominatim.Option
+		want string
+	}{
+		{name: "should send no Referer by default"},
+		{name: "should send the given Referer", opts: []nominatim.Option{nominatim.WithReferer("https://acme.example/map")}, want: "https://acme.example/map"},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			var got string
+			transport := TransportFunc(func(req *http.Request) (*http.Response, error) {
+				got = req.Header.Get("Referer")
+				return statusFixture(http.StatusOK, mustLoadValidStatus(t))()
+			})
+			d := nominatim.NewClient("http://localhost:8080", nil, append(tt.opts, nominatim.WithTransport(transport))...)
+			if _, err := d.CheckStatus(context.TODO()); err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("CheckStatus() sent Referer = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,191 @@
+package nominatim
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// countryCodePattern matches the ISO 3166-1 alpha-2 country codes.
+var countryCodePattern = regexp.MustCompile(`^[A-Za-z]{2}$`)
+
+// FieldError describes a problem found in a field of a query.
+type FieldError struct {
+	Field   string
+	Message string
+}
+
+func (e FieldError) Error() string {
+	return e.Field + ": " + e.Message
+}
+
+// ValidationError holds the problems found in a query by its Validate method. It matches ErrInvalidQuery through
+// errors.Is.
+type ValidationError struct {
+	Problems []FieldError
+}
+
+func (e ValidationError) Error() string {
+	problems := make([]string, 0, len(e.Problems))
+	for _, problem := range e.Problems {
+		problems = append(problems, problem.Error())
+	}
+	return ErrInvalidQuery.Error() + ": " + strings.Join(problems, "; ")
+}
+
+// Is reports whether the ValidationError matches the given sentinel error.
+func (e ValidationError) Is(target error) bool {
+	return target == ErrInvalidQuery
+}
+
+// validation collects the problems found in a query.
+type validation struct {
+	problems []FieldError
+}
+
+// add adds a problem with the given field.
+func (v *validation) add(field, message string) {
+	v.problems = append(v.problems, FieldError{Field: field, Message: message})
+}
+
+// err returns the ValidationError holding the problems found, if any.
+func (v *validation) err() error {
+	if len(v.problems) == 0 {
+		return nil
+	}
+	return ValidationError{Problems: v.problems}
+}
+
+// coordinate checks the given coordinate is a number within the given bounds.
+func (v *validation) coordinate(field, value string, bound float64) {
+	if strings.TrimSpace(value) == "" {
+		v.add(field, "is required")
+		return
+	}
+	coordinate, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+	if err != nil {
+		v.add(field, "is not a number")
+		return
+	}
+	if coordinate < -bound || coordinate > bound {
+		v.add(field, "must be between "+strconv.FormatFloat(-bound, 'f', -1, 64)+" and "+
+			strconv.FormatFloat(bound, 'f', -1, 64))
+	}
+}
+
+// WithQueryValidation makes the client validate the queries, through their Validate method, before sending them,
+// failing with a ValidationError instead of letting the server, or the encoding, deal with the invalid values.
+func WithQueryValidation() Option {
+	return func(d *defaultClient) {
+		d.validateQueries = true
+	}
+}
+
+// structuredFields returns the names of the structured fields set.
+func (q SearchStructuredQuery) structuredFields() []string {
+	fields := make([]string, 0)
+	for _, field := range []struct {
+		name  string
+		value string
+	}{
+		{"HouseNumber", q.HouseNumber},
+		{"Street", q.Street},
+		{"City", q.City},
+		{"County", q.County},
+		{"State", q.State},
+		{"Country", q.Country},
+		{"PostalCode", q.PostalCode},
+		{"Amenity", q.Amenity},
+	} {
+		if field.value != "" {
+			fields = append(fields, field.name)
+		}
+	}
+	return fields
+}
+
+// validateForm checks the SearchQuery is either free-form or structured, as Nominatim ignores the structured fields
+// of free-form queries.
+func (q SearchQuery) validateForm(v *validation) {
+	if fields := q.structuredFields(); q.FreeFormQuery != "" && len(fields) > 0 {
+		v.add("FreeFormQuery", "can't be combined with the structured fields "+strings.Join(fields, ", ")+
+			", which would be ignored")
+	}
+}
+
+// Validate reports the problems found in the SearchQuery, as an empty query, a limit out of range, conflicting
+// free-form and structured fields, an invalid viewbox or invalid country codes, as a ValidationError, or nil if there
+// is none.
+func (q SearchQuery) Validate() error {
+	v := &validation{}
+	if strings.TrimSpace(q.FreeFormQuery) == "" && len(q.structuredFields()) == 0 {
+		v.add("FreeFormQuery", "either a free-form or a structured query is required")
+	}
+	q.validateForm(v)
+	if q.HouseNumber != "" && q.Street == "" {
+		v.add("HouseNumber", "requires Street")
+	}
+	if q.Limit < 0 || q.Limit > maxLimit {
+		v.add("Limit", "must be 0, for the default, or between 1 and "+strconv.Itoa(maxLimit))
+	}
+	if q.Viewbox != "" {
+		q.validateViewbox(v)
+	}
+	for _, code := range q.CountryCodes {
+		if !countryCodePattern.MatchString(code) {
+			v.add("CountryCodes", "holds an invalid code "+strconv.Quote(code)+", as pt")
+			break
+		}
+	}
+	if q.Bounded && q.Viewbox == "" && q.Bias == nil {
+		v.add("Bounded", "requires Viewbox or Bias")
+	}
+	if q.Bias != nil &&
+		(q.Bias.Latitude < -90 || q.Bias.Latitude > 90 || q.Bias.Longitude < -180 || q.Bias.Longitude > 180) {
+		v.add("Bias", "is not a valid location")
+	}
+	v.languages("AcceptLanguage", q.AcceptLanguage)
+	return v.err()
+}
+
+// validateViewbox checks the viewbox is made of four coordinates, as "x1,y1,x2,y2".
+func (q SearchQuery) validateViewbox(v *validation) {
+	coordinates := strings.Split(q.Viewbox, ",")
+	if len(coordinates) != 4 {
+		v.add("Viewbox", "must be made of four coordinates, as x1,y1,x2,y2")
+		return
+	}
+	for i, coordinate := range coordinates {
+		bound := 180.0
+		if i%2 == 1 {
+			bound = 90
+		}
+		nested := &validation{}
+		nested.coordinate("Viewbox", coordinate, bound)
+		if len(nested.problems) > 0 {
+			v.add("Viewbox", "holds an invalid coordinate "+strconv.Quote(coordinate))
+			return
+		}
+	}
+}
+
+// validateSearch validates the given SearchQuery, if enabled, rejecting the ones combining free-form and structured
+// fields anyway.
+func (d defaultClient) validateSearch(query SearchQuery) error {
+	if d.validateQueries {
+		return query.Validate()
+	}
+	v := &validation{}
+	query.validateForm(v)
+	return v.err()
+}
+
+// Validate reports the problems found in the ReverseQuery, as missing or invalid coordinates, as a ValidationError,
+// or nil if there is none.
+func (q ReverseQuery) Validate() error {
+	v := &validation{}
+	v.coordinate("Latitude", q.Latitude, 90)
+	v.coordinate("Longitude", q.Longitude, 180)
+	v.languages("AcceptLanguage", q.AcceptLanguage)
+	return v.err()
+}
@@ -0,0 +1,196 @@
+package nominatim_test
+
+import (
+	"context"
+	"errors"
+	"github.com/diegohordi/nominatim"
+	"net/http"
+	"reflect"
+	"sync/atomic"
+	"testing"
+)
+
+func TestSearchQuery_Validate(t *testing.T) {
+	tests := []struct {
+		name       string
+		query      func() nominatim.SearchQuery
+		wantFields []string
+	}{
+		{
+			name: "should accept a free-form query",
+			query: func() nominatim.SearchQuery {
+				return *nominatim.NewSearchQuery(nominatim.WithFreeForm("lisboa"))
+			},
+		},
+		{
+			name: "should accept a structured query",
+			query: func() nominatim.SearchQuery {
+				return *nominatim.NewSearchQuery(nominatim.WithStructured(nominatim.SearchStructuredQuery{
+					HouseNumber: "1", Street: "avenida da república", City: "lisboa",
+				}))
+			},
+		},
+		{
+			name: "should reject an empty query",
+			query: func() nominatim.SearchQuery {
+				return *nominatim.NewSearchQuery()
+			},
+			wantFields: []string{"FreeFormQuery"},
+		},
+		{
+			name: "should reject free-form and structured fields together",
+			query: func() nominatim.SearchQuery {
+				query := nominatim.NewSearchQuery(nominatim.WithFreeForm("lisboa"))
+				query.City = "porto"
+				return *query
+			},
+			wantFields: []string{"FreeFormQuery"},
+		},
+		{
+			name: "should report every problem found",
+			query: func() nominatim.SearchQuery {
+				query := nominatim.NewSearchQuery()
+				query.HouseNumber = "1"
+				query.Limit = 100
+				query.Viewbox = "-9.2,38.7,-9.1"
+				return *query
+			},
+			wantFields: []string{"HouseNumber", "Limit", "Viewbox"},
+		},
+		{
+			name: "should reject a viewbox out of bounds",
+			query: func() nominatim.SearchQuery {
+				query := nominatim.NewSearchQuery(nominatim.WithFreeForm("lisboa"))
+				query.Viewbox = "-9.2,98.7,-9.1,38.8"
+				return *query
+			},
+			wantFields: []string{"Viewbox"},
+		},
+		{
+			name: "should reject a bounded query without viewbox",
+			query: func() nominatim.SearchQuery {
+				query := nominatim.NewSearchQuery(nominatim.WithFreeForm("lisboa"))
+				query.Bounded = true
+				return *query
+			},
+			wantFields: []string{"Bounded"},
+		},
+		{
+			name: "should accept the default limit",
+			query: func() nominatim.SearchQuery {
+				query := nominatim.NewSearchQuery(nominatim.WithFreeForm("lisboa"))
+				query.Limit = 0
+				return *query
+			},
+		},
+		{
+			name: "should reject a negative limit",
+			query: func() nominatim.SearchQuery {
+				query := nominatim.NewSearchQuery(nominatim.WithFreeForm("lisboa"))
+				query.Limit = -1
+				return *query
+			},
+			wantFields: []string{"Limit"},
+		},
+		{
+			name: "should reject an invalid country code",
+			query: func() nominatim.SearchQuery {
+				return *nominatim.NewSearchQuery(nominatim.WithFreeForm("lisboa"), nominatim.WithCountryCodes("pt", "prt"))
+			},
+			wantFields: []string{"CountryCodes"},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assertValidation(t, tt.query().Validate(), tt.wantFields)
+		})
+	}
+}
+
+func TestReverseQuery_Validate(t *testing.T) {
+	tests := []struct {
+		name       string
+		latitude   string
+		longitude  string
+		wantFields []string
+	}{
+		{name: "should accept valid coordinates", latitude: "38.6945252", longitude: "-9.3221278"},
+		{name: "should reject missing coordinates", wantFields: []string{"Latitude", "Longitude"}},
+		{name: "should reject invalid coordinates", latitude: "test", longitude: "testing", wantFields: []string{"Latitude", "Longitude"}},
+		{name: "should reject coordinates out of bounds", latitude: "91", longitude: "-180", wantFields: []string{"Latitude"}},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assertValidation(t, nominatim.NewReverseQuery(tt.latitude, tt.longitude).Validate(), tt.wantFields)
+		})
+	}
+}
+
+// assertValidation asserts the given error is a ValidationError reporting problems with the given fields, or nil if
+// no field is given.
+func assertValidation(t *testing.T, err error, wantFields []string) {
+	t.Helper()
+	if len(wantFields) == 0 {
+		if err != nil {
+			t.Errorf("Validate() error = %v, want nil", err)
+		}
+		return
+	}
+	if !errors.Is(err, nominatim.ErrInvalidQuery) {
+		t.Errorf("Validate() error = %v, want %v", err, nominatim.ErrInvalidQuery)
+	}
+	var validationErr nominatim.ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("Validate() error = %v, want a ValidationError", err)
+	}
+	fields := make([]string, 0, len(validationErr.Problems))
+	for _, problem := range validationErr.Problems {
+		fields = append(fields, problem.Field)
+	}
+	if !reflect.DeepEqual(fields, wantFields) {
+		t.Errorf("Validate() fields = %v, want %v", fields, wantFields)
+	}
+}
+
+func Test_WithQueryValidation(t *testing.T) {
+	var requests int32
+	transport := TransportFunc(func(req *http.Request) (*http.Response, error) {
+		atomic.AddInt32(&requests, 1)
+		return statusFixture(http.StatusBadRequest, nil)()
+	})
+	d := nominatim.NewClient("http://localhost:8080", nil, nominatim.WithTransport(transport), nominatim.WithQueryValidation())
+	if _, err := d.Search(context.TODO(), *nominatim.NewSearchQuery()); !errors.As(err, &nominatim.ValidationError{}) {
+		t.Errorf("Search() error = %v, want a ValidationError", err)
+	}
+	if _, err := d.Reverse(context.TODO(), *nominatim.NewReverseQuery("test", "testing")); !errors.As(err, &nominatim.ValidationError{}) {
+		t.Errorf("Reverse() error = %v, want a ValidationError", err)
+	}
+	if got := atomic.LoadInt32(&requests); got != 0 {
+		t.Errorf("requests = %v, want 0", got)
+	}
+}
+
+func Test_SearchConflictingQuery(t *testing.T) {
+	var requests int32
+	transport := TransportFunc(func(req *http.Request) (*http.Response, error) {
+		atomic.AddInt32(&requests, 1)
+		return statusFixture(http.StatusOK, nil)()
+	})
+	d := nominatim.NewClient("http://localhost:8080", nil, nominatim.WithTransport(transport))
+	query := nominatim.NewSearchQuery(nominatim.WithFreeForm("lisboa"), nominatim.WithStructured(nominatim.SearchStructuredQuery{
+		City:    "porto",
+		Country: "portugal",
+	}))
+	_, err := d.Search(context.TODO(), *query)
+	assertValidation(t, err, []string{"FreeFormQuery"})
+	if want := "nominatim: search localhost:8080: invalid query: FreeFormQuery: can't be combined with the structured fields City, Country, which would be ignored"; err == nil || err.Error() != want {
+		t.Errorf("Search() error = %v, want %v", err, want)
+	}
+	if got := atomic.LoadInt32(&requests); got != 0 {
+		t.Errorf("requests = %v, want 0", got)
+	}
+}
@@ -0,0 +1,259 @@
+package nominatim
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// WKB codes of the geometry types.
+const (
+	wkbPoint           uint32 = 1
+	wkbLineString      uint32 = 2
+	wkbPolygon         uint32 = 3
+	wkbMultiPoint      uint32 = 4
+	wkbMultiLineString uint32 = 5
+	wkbMultiPolygon    uint32 = 6
+)
+
+// wkbTypes maps the GeoJSON geometry types to their WKB codes.
+var wkbTypes = map[string]uint32{
+	"Point":           wkbPoint,
+	"LineString":      wkbLineString,
+	"Polygon":         wkbPolygon,
+	"MultiPoint":      wkbMultiPoint,
+	"MultiLineString": wkbMultiLineString,
+	"MultiPolygon":    wkbMultiPolygon,
+}
+
+// wkbLittleEndian is the byte order marker of the WKB geometries, little endian.
+const wkbLittleEndian = 1
+
+// position is a position of a geometry, as [longitude, latitude].
+type position [2]float64
+
+// shape is a geometry of a result, parsed from its GeoJSON.
+type shape struct {
+	kind string
+
+	// polygons holds the positions of the geometry, nested as the ones of a MultiPolygon: a single polygon holding a
+	// single ring for points, line strings and multi points, and a single polygon for polygons and multi line
+	// strings.
+	polygons [][][]position
+}
+
+// WKT returns the geometry of the Result as Well-Known Text, for inserting it into spatial databases, as through
+// ST_GeomFromText(wkt, 4326) in PostGIS. The geometry is the one requested through PolygonGeoJSON, when present, or
+// the point of the result otherwise.
+func (r Result) WKT() (string, error) {
+	s, err := r.shape()
+	if err != nil {
+		return "", err
+	}
+	return s.wkt(), nil
+}
+
+// WKB returns the geometry of the Result as Well-Known Binary, in little endian, for inserting it into spatial
+// databases, as through ST_GeomFromWKB(wkb, 4326) in PostGIS. The geometry is the one requested through
+// PolygonGeoJSON, when present, or the point of the result otherwise.
+func (r Result) WKB() ([]byte, error) {
+	s, err := r.shape()
+	if err != nil {
+		return nil, err
+	}
+	return s.wkb(), nil
+}
+
+// shape parses the geometry of the Result.
+func (r Result) shape() (shape, error) {
+	if len(r.GeoJSON) == 0 || string(r.GeoJSON) == "null" {
+		point, err := r.Point()
+		if err != nil {
+			return shape{}, fmt.Errorf("%w: place %d: %v", ErrNoGeometry, r.PlaceId, err)
+		}
+		return shape{kind: "Point", polygons: [][][]position{{{{point.Lon, point.Lat}}}}}, nil
+	}
+	s, err := parseShape(r.GeoJSON)
+	if err != nil {
+		return shape{}, fmt.Errorf("nominatim: invalid geometry of place %d: %w", r.PlaceId, err)
+	}
+	return s, nil
+}
+
+// parseShape parses the given GeoJSON geometry.
+func parseShape(data json.RawMessage) (shape, error) {
+	geometry := struct {
+		Type        string          `json:"type"`
+		Coordinates json.RawMessage `json:"coordinates"`
+	}{}
+	if err := json.Unmarshal(data, &geometry); err != nil {
+		return shape{}, err
+	}
+	var polygons [][][][]float64
+	var err error
+	switch geometry.Type {
+	case "Point":
+		var point []float64
+		err = json.Unmarshal(geometry.Coordinates, &point)
+		polygons = [][][][]float64{{{point}}}
+	case "LineString", "MultiPoint":
+		var ring [][]float64
+		err = json.Unmarshal(geometry.Coordinates, &ring)
+		polygons = [][][][]float64{{ring}}
+	case "Polygon", "MultiLineString":
+		var polygon [][][]float64
+		err = json.Unmarshal(geometry.Coordinates, &polygon)
+		polygons = [][][][]float64{polygon}
+	case "MultiPolygon":
+		err = json.Unmarshal(geometry.Coordinates, &polygons)
+	default:
+		return shape{}, fmt.Errorf("unsupported geometry type %q", geometry.Type)
+	}
+	if err != nil {
+		return shape{}, err
+	}
+	s := shape{kind: geometry.Type, polygons: make([][][]position, 0, len(polygons))}
+	for _, polygon := range polygons {
+		rings := make([][]position, 0, len(polygon))
+		for _, ring := range polygon {
+			positions := make([]position, 0, len(ring))
+			for _, coordinates := range ring {
+				if len(coordinates) < 2 {
+					return shape{}, fmt.Errorf("invalid position %v", coordinates)
+				}
+				positions = append(positions, position{coordinates[0], coordinates[1]})
+			}
+			rings = append(rings, positions)
+		}
+		s.polygons = append(s.polygons, rings)
+	}
+	return s, nil
+}
+
+// wkt formats the shape as Well-Known Text.
+func (s shape) wkt() string {
+	name := strings.ToUpper(s.kind)
+	if s.empty() {
+		return name + " EMPTY"
+	}
+	switch s.kind {
+	case "Point":
+		return name + "(" + s.polygons[0][0][0].wkt() + ")"
+	case "LineString", "MultiPoint":
+		return name + wktRing(s.polygons[0][0])
+	case "Polygon", "MultiLineString":
+		return name + wktPolygon(s.polygons[0])
+	}
+	polygons := make([]string, 0, len(s.polygons))
+	for _, polygon := range s.polygons {
+		polygons = append(polygons, wktPolygon(polygon))
+	}
+	return name + "(" + strings.Join(polygons, ",") + ")"
+}
+
+// empty checks if the shape has no positions.
+func (s shape) empty() bool {
+	for _, polygon := range s.polygons {
+		for _, ring := range polygon {
+			if len(ring) > 0 {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// wktPolygon formats the given rings as Well-Known Text, as "((x y,x y),(x y,x y))".
+func wktPolygon(rings [][]position) string {
+	formatted := make([]string, 0, len(rings))
+	for _, ring := range rings {
+		formatted = append(formatted, wktRing(ring))
+	}
+	return "(" + strings.Join(formatted, ",") + ")"
+}
+
+// wktRing formats the given positions as Well-Known Text, as "(x y,x y)".
+func wktRing(ring []position) string {
+	formatted := make([]string, 0, len(ring))
+	for _, p := range ring {
+		formatted = append(formatted, p.wkt())
+	}
+	return "(" + strings.Join(formatted, ",") + ")"
+}
+
+// wkt formats the position as Well-Known Text, as "x y".
+func (p position) wkt() string {
+	return strconv.FormatFloat(p[0], 'f', -1, 64) + " " + strconv.FormatFloat(p[1], 'f', -1, 64)
+}
+
+// wkb encodes the shape as Well-Known Binary.
+func (s shape) wkb() []byte {
+	buf := &bytes.Buffer{}
+	kind := wkbTypes[s.kind]
+	writeWKBHeader(buf, kind)
+	switch {
+	case kind == wkbPoint && s.empty():
+		writeWKBPosition(buf, position{math.NaN(), math.NaN()})
+	case kind == wkbPoint:
+		writeWKBPosition(buf, s.polygons[0][0][0])
+	case kind == wkbLineString:
+		writeWKBRing(buf, s.polygons[0][0])
+	case kind == wkbMultiPoint:
+		writeWKBUint32(buf, uint32(len(s.polygons[0][0])))
+		for _, p := range s.polygons[0][0] {
+			writeWKBHeader(buf, wkbPoint)
+			writeWKBPosition(buf, p)
+		}
+	case kind == wkbPolygon:
+		writeWKBPolygon(buf, s.polygons[0])
+	case kind == wkbMultiLineString:
+		writeWKBUint32(buf, uint32(len(s.polygons[0])))
+		for _, ring := range s.polygons[0] {
+			writeWKBHeader(buf, wkbLineString)
+			writeWKBRing(buf, ring)
+		}
+	default:
+		writeWKBUint32(buf, uint32(len(s.polygons)))
+		for _, polygon := range s.polygons {
+			writeWKBHeader(buf, wkbPolygon)
+			writeWKBPolygon(buf, polygon)
+		}
+	}
+	return buf.Bytes()
+}
+
+// writeWKBHeader writes the byte order and the given type of a WKB geometry.
+func writeWKBHeader(buf *bytes.Buffer, kind uint32) {
+	buf.WriteByte(wkbLittleEndian)
+	writeWKBUint32(buf, kind)
+}
+
+// writeWKBPolygon writes the given rings as WKB, prefixed by their number.
+func writeWKBPolygon(buf *bytes.Buffer, rings [][]position) {
+	writeWKBUint32(buf, uint32(len(rings)))
+	for _, ring := range rings {
+		writeWKBRing(buf, ring)
+	}
+}
+
+// writeWKBRing writes the given positions as WKB, prefixed by their number.
+func writeWKBRing(buf *bytes.Buffer, ring []position) {
+	writeWKBUint32(buf, uint32(len(ring)))
+	for _, p := range ring {
+		writeWKBPosition(buf, p)
+	}
+}
+
+// writeWKBPosition writes the given position as WKB.
+func writeWKBPosition(buf *bytes.Buffer, p position) {
+	_ = binary.Write(buf, binary.LittleEndian, p)
+}
+
+// writeWKBUint32 writes the given value as WKB.
+func writeWKBUint32(buf *bytes.Buffer, value uint32) {
+	_ = binary.Write(buf, binary.LittleEndian, value)
+}
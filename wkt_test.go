@@ -0,0 +1,123 @@
+package nominatim_test
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"github.com/diegohordi/nominatim"
+	"strings"
+	"testing"
+)
+
+func TestResult_WKT(t *testing.T) {
+	tests := []struct {
+		name    string
+		result  nominatim.Result
+		want    string
+		wantErr error
+	}{
+		{
+			name:   "should export the point without geometry",
+			result: nominatim.Result{Lat: "38.6916", Lon: "-9.2160"},
+			want:   "POINT(-9.216 38.6916)",
+		},
+		{
+			name:   "should export polygons",
+			result: nominatim.Result{GeoJSON: json.RawMessage(`{"type":"Polygon","coordinates":[[[0,0],[1,0],[1,1],[0,0]],[[0.2,0.2],[0.4,0.2],[0.2,0.4],[0.2,0.2]]]}`)},
+			want:   "POLYGON((0 0,1 0,1 1,0 0),(0.2 0.2,0.4 0.2,0.2 0.4,0.2 0.2))",
+		},
+		{
+			name:   "should export multi polygons",
+			result: nominatim.Result{GeoJSON: json.RawMessage(`{"type":"MultiPolygon","coordinates":[[[[0,0],[1,0],[1,1],[0,0]]],[[[2,2],[3,2],[3,3],[2,2]]]]}`)},
+			want:   "MULTIPOLYGON(((0 0,1 0,1 1,0 0)),((2 2,3 2,3 3,2 2)))",
+		},
+		{
+			name:   "should export line strings",
+			result: nominatim.Result{GeoJSON: json.RawMessage(`{"type":"LineString","coordinates":[[-9.1,38.7],[-9.2,38.8]]}`)},
+			want:   "LINESTRING(-9.1 38.7,-9.2 38.8)",
+		},
+		{
+			name:   "should export empty geometries",
+			result: nominatim.Result{GeoJSON: json.RawMessage(`{"type":"MultiPolygon","coordinates":[]}`)},
+			want:   "MULTIPOLYGON EMPTY",
+		},
+		{
+			name:    "should fail without geometry nor point",
+			result:  nominatim.Result{},
+			wantErr: nominatim.ErrNoGeometry,
+		},
+		{
+			name:    "should fail on unsupported geometries",
+			result:  nominatim.Result{GeoJSON: json.RawMessage(`{"type":"GeometryCollection","geometries":[]}`)},
+			wantErr: errors.New("unsupported"),
+		},
+		{
+			name:    "should fail on invalid positions",
+			result:  nominatim.Result{GeoJSON: json.RawMessage(`{"type":"LineString","coordinates":[[-9.1]]}`)},
+			wantErr: errors.New("invalid position"),
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := tt.result.WKT()
+			if (err != nil) != (tt.wantErr != nil) {
+				t.Fatalf("WKT() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, tt.wantErr) && !strings.Contains(err.Error(), tt.wantErr.Error()) {
+				t.Errorf("WKT() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("WKT() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResult_WKB(t *testing.T) {
+	const (
+		zero = "0000000000000000"
+		one  = "000000000000f03f"
+		two  = "0000000000000040"
+	)
+	tests := []struct {
+		name   string
+		result nominatim.Result
+		want   string
+	}{
+		{
+			name:   "should export the point without geometry",
+			result: nominatim.Result{Lat: "2", Lon: "1"},
+			want:   "0101000000" + one + two,
+		},
+		{
+			name:   "should export polygons",
+			result: nominatim.Result{GeoJSON: json.RawMessage(`{"type":"Polygon","coordinates":[[[0,0],[1,0],[1,1],[0,0]]]}`)},
+			want:   "0103000000" + "01000000" + "04000000" + zero + zero + one + zero + one + one + zero + zero,
+		},
+		{
+			name:   "should export multi points",
+			result: nominatim.Result{GeoJSON: json.RawMessage(`{"type":"MultiPoint","coordinates":[[1,2],[2,1]]}`)},
+			want:   "0104000000" + "02000000" + "0101000000" + one + two + "0101000000" + two + one,
+		},
+		{
+			name:   "should export multi polygons",
+			result: nominatim.Result{GeoJSON: json.RawMessage(`{"type":"MultiPolygon","coordinates":[[[[0,0],[1,1],[0,0]]]]}`)},
+			want:   "0106000000" + "01000000" + "0103000000" + "01000000" + "03000000" + zero + zero + one + one + zero + zero,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := tt.result.WKB()
+			if err != nil {
+				t.Fatalf("WKB() error = %v", err)
+			}
+			if hex.EncodeToString(got) != tt.want {
+				t.Errorf("WKB() got = %x, want %v", got, tt.want)
+			}
+		})
+	}
+}